@@ -6,6 +6,7 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/segmentio/parquet-go/internal/debug"
 )
@@ -198,8 +199,12 @@ func (buf *Buffer) Write(row interface{}) error {
 	buf.rowbuf = buf.rowbuf[:1]
 	defer clearRows(buf.rowbuf)
 
-	buf.rowbuf[0] = buf.schema.Deconstruct(buf.rowbuf[0], row)
-	_, err := buf.WriteRows(buf.rowbuf)
+	var err error
+	buf.rowbuf[0], err = buf.schema.deconstructRow(buf.rowbuf[0], row)
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteRows(buf.rowbuf)
 	return err
 }
 
@@ -313,6 +318,29 @@ func (b *buffer) unref() {
 	}
 }
 
+// adopt makes data the content of b if it was reallocated to a larger capacity
+// while decoding into b, so the memory returns to the pool when b is released
+// instead of being left to the garbage collector. Over time, this makes the
+// buffers taken from the pool large enough to decode pages without growing.
+//
+// The method does nothing if data overlaps with the memory of src, which would
+// otherwise be owned by two buffers.
+func (b *buffer) adopt(data, src []byte) {
+	if cap(data) > cap(b.data) && !overlaps(data, src) {
+		b.data = data
+	}
+}
+
+func overlaps(a, b []byte) bool {
+	if cap(a) == 0 || cap(b) == 0 {
+		return false
+	}
+	a, b = a[:cap(a)], b[:cap(b)]
+	aStart := uintptr(unsafe.Pointer(&a[0]))
+	bStart := uintptr(unsafe.Pointer(&b[0]))
+	return aStart < bStart+uintptr(len(b)) && bStart < aStart+uintptr(len(a))
+}
+
 func monitorBufferRelease(b *buffer) {
 	if rc := b.refCount(); rc != 0 {
 		log.Printf("PARQUETGODEBUG: buffer garbage collected with non-zero reference count\n%s", string(b.stack))
@@ -431,51 +459,103 @@ var (
 	buffers bufferPool
 )
 
+// bufferedPage is the page returned when decoding pages read from parquet
+// files; it ties the lifetime of the buffers that the page was decoded from
+// to the lifetime of the page.
+//
+// All the memory involved in decoding a page comes from the size-classed
+// buffer pool: the compressed page read from the file, the decompressed page,
+// the repetition and definition levels, and the decoded values and offsets.
+// Buffers that the decoded page does not reference (e.g. the compressed page
+// after decompression) are returned to the pool as soon as decoding completes,
+// the others are held by the page until the last reference to it is released.
+// When values are decoded in place, the values buffer is the compressed or
+// decompressed page itself.
+//
+// The bufferedPage values are pooled as well, and embed the storage for the
+// pages pairing values with their levels, so decoding a page does not allocate
+// these wrappers either.
 type bufferedPage struct {
 	Page
+	refc             uintptr
 	values           *buffer
 	offsets          *buffer
 	repetitionLevels *buffer
 	definitionLevels *buffer
+	optional         optionalPage
+	repeated         repeatedPage
 }
 
-func newBufferedPage(page Page, values, offsets, definitionLevels, repetitionLevels *buffer) *bufferedPage {
-	p := &bufferedPage{
-		Page:             page,
-		values:           values,
-		offsets:          offsets,
-		definitionLevels: definitionLevels,
-		repetitionLevels: repetitionLevels,
+var bufferedPages sync.Pool // *bufferedPage
+
+func newBufferedPage(page Page, values, offsets, repetitionLevels, definitionLevels *buffer) *bufferedPage {
+	p, _ := bufferedPages.Get().(*bufferedPage)
+	if p == nil {
+		p = new(bufferedPage)
 	}
+	p.Page = page
+	p.refc = 1
+	p.values = values
+	p.offsets = offsets
+	p.repetitionLevels = repetitionLevels
+	p.definitionLevels = definitionLevels
 	bufferRef(values)
 	bufferRef(offsets)
-	bufferRef(definitionLevels)
 	bufferRef(repetitionLevels)
+	bufferRef(definitionLevels)
 	return p
 }
 
+// setOptional wraps the page with the definition levels held by p.
+func (p *bufferedPage) setOptional(maxDefinitionLevel byte) {
+	p.optional = optionalPage{
+		base:               p.Page,
+		maxDefinitionLevel: maxDefinitionLevel,
+		definitionLevels:   p.definitionLevels.data,
+	}
+	p.Page = &p.optional
+}
+
+// setRepeated wraps the page with the repetition and definition levels held
+// by p.
+func (p *bufferedPage) setRepeated(maxRepetitionLevel, maxDefinitionLevel byte) {
+	p.repeated = repeatedPage{
+		base:               p.Page,
+		maxRepetitionLevel: maxRepetitionLevel,
+		maxDefinitionLevel: maxDefinitionLevel,
+		repetitionLevels:   p.repetitionLevels.data,
+		definitionLevels:   p.definitionLevels.data,
+	}
+	p.Page = &p.repeated
+}
+
 func (p *bufferedPage) Slice(i, j int64) Page {
 	return newBufferedPage(
 		p.Page.Slice(i, j),
 		p.values,
 		p.offsets,
-		p.definitionLevels,
 		p.repetitionLevels,
+		p.definitionLevels,
 	)
 }
 
 func (p *bufferedPage) Retain() {
+	atomic.AddUintptr(&p.refc, +1)
 	bufferRef(p.values)
 	bufferRef(p.offsets)
-	bufferRef(p.definitionLevels)
 	bufferRef(p.repetitionLevels)
+	bufferRef(p.definitionLevels)
 }
 
 func (p *bufferedPage) Release() {
 	bufferUnref(p.values)
 	bufferUnref(p.offsets)
-	bufferUnref(p.definitionLevels)
 	bufferUnref(p.repetitionLevels)
+	bufferUnref(p.definitionLevels)
+	if atomic.AddUintptr(&p.refc, ^uintptr(0)) == 0 {
+		*p = bufferedPage{}
+		bufferedPages.Put(p)
+	}
 }
 
 func bufferRef(buf *buffer) {
@@ -69,6 +69,9 @@ func bufferFuncOf[T any](t reflect.Type, schema *Schema) bufferFunc[T] {
 		return makeBufferFunc[T](t, schema)
 
 	case reflect.Pointer:
+		if isProtoMessage(t) {
+			return (*GenericBuffer[T]).writeRows
+		}
 		if e := t.Elem(); e.Kind() == reflect.Struct {
 			return makeBufferFunc[T](t, schema)
 		}
@@ -156,7 +159,11 @@ func (buf *GenericBuffer[T]) writeRows(rows []T) (int, error) {
 
 	schema := buf.base.Schema()
 	for i := range rows {
-		buf.base.rowbuf[i] = schema.Deconstruct(buf.base.rowbuf[i], &rows[i])
+		var err error
+		buf.base.rowbuf[i], err = schema.deconstructRow(buf.base.rowbuf[i], &rows[i])
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	return buf.base.WriteRows(buf.base.rowbuf)
@@ -54,3 +54,95 @@ func TestBufferPoolBucketIndexAndSizeOf(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferAdopt(t *testing.T) {
+	var p bufferPool
+	b := p.get(100)
+	defer b.unref()
+
+	src := make([]byte, 10000)
+	b.adopt(src[:10], src)
+	if cap(b.data) == cap(src) {
+		t.Error("buffer adopted memory overlapping with the source")
+	}
+
+	grown := append(b.data, make([]byte, 10000)...)
+	b.adopt(grown, src)
+	if cap(b.data) != cap(grown) {
+		t.Error("buffer did not adopt reallocated memory")
+	}
+
+	b.adopt(make([]byte, 10), src)
+	if cap(b.data) != cap(grown) {
+		t.Error("buffer adopted smaller memory")
+	}
+}
+
+func TestBufferedPageRetainRelease(t *testing.T) {
+	var p bufferPool
+	values := p.get(100)
+	levels := p.get(100)
+
+	page := newBufferedPage(newInt32Page(Int32Type, 0, 0, Int32Type.NewValues(nil, nil)), values, nil, nil, levels)
+	values.unref()
+	levels.unref()
+
+	page.Retain()
+	if rc := values.refCount(); rc != 2 {
+		t.Errorf("wrong reference count after retaining the page: %d", rc)
+	}
+
+	page.Release()
+	if rc := values.refCount(); rc != 1 {
+		t.Errorf("wrong reference count after releasing the page: %d", rc)
+	}
+	if page.Page == nil {
+		t.Fatal("page was reset while still being referenced")
+	}
+
+	page.Release()
+	if rc := levels.refCount(); rc != 0 {
+		t.Errorf("wrong reference count after releasing the page: %d", rc)
+	}
+	if page.Page != nil {
+		t.Error("page was not reset after releasing the last reference")
+	}
+}
+
+func TestBufferedPageLevels(t *testing.T) {
+	var p bufferPool
+	values := p.get(4 * 3)
+	repetitionLevels := p.get(4)
+	definitionLevels := p.get(4)
+	copy(repetitionLevels.data, []byte{0, 1, 0, 1})
+	copy(definitionLevels.data, []byte{1, 1, 0, 1})
+
+	base := newInt32Page(Int32Type, 0, 3, Int32Type.NewValues(values.data, nil))
+
+	optional := newBufferedPage(base, values, nil, nil, definitionLevels)
+	optional.setOptional(1)
+	if n := optional.NumRows(); n != 4 {
+		t.Errorf("wrong number of rows in optional page: want=4 got=%d", n)
+	}
+	if n := optional.NumNulls(); n != 1 {
+		t.Errorf("wrong number of nulls in optional page: want=1 got=%d", n)
+	}
+
+	repeated := newBufferedPage(base, values, nil, repetitionLevels, definitionLevels)
+	repeated.setRepeated(1, 1)
+	if n := repeated.NumRows(); n != 2 {
+		t.Errorf("wrong number of rows in repeated page: want=2 got=%d", n)
+	}
+
+	values.unref()
+	repetitionLevels.unref()
+	definitionLevels.unref()
+	optional.Release()
+	repeated.Release()
+
+	for _, b := range []*buffer{values, repetitionLevels, definitionLevels} {
+		if rc := b.refCount(); rc != 0 {
+			t.Errorf("wrong reference count after releasing the pages: %d", rc)
+		}
+	}
+}
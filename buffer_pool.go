@@ -157,6 +157,7 @@ func (buf *errorBuffer) Seek(int64, int) (int64, error)    { return 0, buf.err }
 var (
 	defaultColumnBufferPool  memoryBufferPool
 	defaultSortingBufferPool memoryBufferPool
+	defaultDedupeBufferPool  memoryBufferPool
 
 	_ io.ReaderFrom = (*errorBuffer)(nil)
 	_ io.WriterTo   = (*errorBuffer)(nil)
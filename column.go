@@ -70,6 +70,9 @@ func (c *Column) Encoding() encoding.Encoding { return c.encoding }
 // Compression returns the compression codecs used by this column.
 func (c *Column) Compression() compress.Codec { return c.compression }
 
+// ID returns the field ID of the column, or zero if it has none.
+func (c *Column) ID() int { return int(c.schema.FieldID) }
+
 // Path of the column in the parquet schema.
 func (c *Column) Path() []string { return c.path[1:] }
 
@@ -233,6 +236,16 @@ func (c *Column) setLevels(depth, repetition, definition, index int) (int, error
 		return -1, fmt.Errorf("cannot represent parquet columns with more than %d definition levels: %s", MaxDefinitionLevel, c.path)
 	}
 
+	// Files may declare logical types on groups which do not have the layout
+	// that the types require, this is reported here rather than when the
+	// layout of rows is computed from the schema.
+	switch {
+	case isList(c) && lookupListElement(c) == nil:
+		return -1, fmt.Errorf("column with logical type LIST is not composed of a repeated .list.element: %s", c.path)
+	case isMap(c) && lookupMapKeyValue(c) == nil:
+		return -1, fmt.Errorf("column with logical type MAP is not composed of a repeated .key_value group: %s", c.path)
+	}
+
 	switch schemaRepetitionTypeOf(c.schema) {
 	case format.Optional:
 		definition++
@@ -340,13 +353,14 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 					break
 				}
 			}
-			c.compression = LookupCompressionCodec(c.chunks[0].MetaData.Codec)
+			c.compression = file.lookupCompressionCodec(c.chunks[0].MetaData.Codec)
 		}
 
 		return c, nil
 	}
 
 	c.typ = &groupType{}
+	c.index = -1
 	c.columns = make([]*Column, numChildren)
 
 	for i := range c.columns {
@@ -362,6 +376,18 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 		}
 	}
 
+	// Groups retain their logical type when their layout matches the one
+	// defined by the parquet format, which allows nested lists and variants
+	// to be recognized in the schema of the file.
+	if lt := c.schema.LogicalType; lt != nil {
+		switch {
+		case lt.List != nil && isStandardList(c):
+			c.typ = (*listType)(lt.List)
+		case lt.Variant != nil && isStandardVariant(c):
+			c.typ = (*variantType)(lt.Variant)
+		}
+	}
+
 	return c, nil
 }
 
@@ -394,8 +420,10 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 						panic("DECIMAL using FIXED_LEN_BYTE_ARRAY must specify a length")
 					}
 					typ = FixedLenByteArrayType(int(*s.TypeLength))
+				case ByteArray:
+					typ = ByteArrayType
 				default:
-					panic("DECIMAL must be of type INT32, INT64, or FIXED_LEN_BYTE_ARRAY but got " + kind.String())
+					panic("DECIMAL must be of type INT32, INT64, BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY but got " + kind.String())
 				}
 				return &decimalType{
 					decimal: *lt.Decimal,
@@ -405,9 +433,9 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case lt.Date != nil:
 			return (*dateType)(lt.Date)
 		case lt.Time != nil:
-			return (*timeType)(lt.Time)
+			return &timeType{TimeType: *lt.Time}
 		case lt.Timestamp != nil:
-			return (*timestampType)(lt.Timestamp)
+			return &timestampType{TimestampType: *lt.Timestamp}
 		case lt.Integer != nil:
 			return (*intType)(lt.Integer)
 		case lt.Unknown != nil:
@@ -418,6 +446,14 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			return (*bsonType)(lt.Bson)
 		case lt.UUID != nil:
 			return (*uuidType)(lt.UUID)
+		case lt.Float16 != nil:
+			return (*float16Type)(lt.Float16)
+		case lt.Variant != nil:
+			return (*variantType)(lt.Variant)
+		case lt.Geometry != nil:
+			return (*geometryType)(lt.Geometry)
+		case lt.Geography != nil:
+			return (*geographyType)(lt.Geography)
 		}
 	}
 
@@ -468,13 +504,13 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Date:
 			return &dateType{}
 		case deprecated.TimeMillis:
-			return &timeType{IsAdjustedToUTC: true, Unit: Millisecond.TimeUnit()}
+			return &timeType{TimeType: format.TimeType{IsAdjustedToUTC: true, Unit: Millisecond.TimeUnit()}}
 		case deprecated.TimeMicros:
-			return &timeType{IsAdjustedToUTC: true, Unit: Microsecond.TimeUnit()}
+			return &timeType{TimeType: format.TimeType{IsAdjustedToUTC: true, Unit: Microsecond.TimeUnit()}}
 		case deprecated.TimestampMillis:
-			return &timestampType{IsAdjustedToUTC: true, Unit: Millisecond.TimeUnit()}
+			return &timestampType{TimestampType: format.TimestampType{IsAdjustedToUTC: true, Unit: Millisecond.TimeUnit()}}
 		case deprecated.TimestampMicros:
-			return &timestampType{IsAdjustedToUTC: true, Unit: Microsecond.TimeUnit()}
+			return &timestampType{TimestampType: format.TimestampType{IsAdjustedToUTC: true, Unit: Microsecond.TimeUnit()}}
 		case deprecated.Uint8:
 			return &unsignedIntTypes[0]
 		case deprecated.Uint16:
@@ -496,7 +532,9 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Bson:
 			return &bsonType{}
 		case deprecated.Interval:
-			// TODO
+			if s.Type != nil && Kind(*s.Type) == FixedLenByteArray && s.TypeLength != nil && *s.TypeLength == 12 {
+				return intervalType{}
+			}
 		}
 	}
 
@@ -539,9 +577,30 @@ func schemaRepetitionTypeOf(s *format.SchemaElement) format.FieldRepetitionType
 }
 
 func (c *Column) decompress(compressedPageData []byte, uncompressedPageSize int32) (page *buffer, err error) {
+	// The codecs stop decompressing once the output exceeds the limit, which
+	// protects from pages holding more data than their header declares after
+	// the declared size was checked against the read limits.
+	limit, limitName := int(uncompressedPageSize), "UncompressedPageSize"
+	if uncompressedPageSize < 0 {
+		// The size of pages passed to the exported decoding methods is unknown,
+		// the buffer grows as needed during decompression, up to the page size
+		// limit of the file if any.
+		uncompressedPageSize = int32(2 * len(compressedPageData))
+		limit, limitName = -1, "MaxPageSize"
+		if c.file != nil && c.file.config.Limits.MaxPageSize > 0 {
+			limit = int(c.file.config.Limits.MaxPageSize)
+		}
+	}
 	page = buffers.get(int(uncompressedPageSize))
-	page.data, err = c.compression.Decode(page.data, compressedPageData)
+	if limit < 0 {
+		page.data, err = c.compression.Decode(page.data, compressedPageData)
+	} else {
+		page.data, err = compress.DecodeLimit(c.compression, page.data, compressedPageData, limit)
+	}
 	if err != nil {
+		if err == compress.ErrDecodeLimit {
+			err = &LimitError{Limit: limitName, Value: int64(limit) + 1, Max: int64(limit)}
+		}
 		page.unref()
 		page = nil
 	}
@@ -651,15 +710,32 @@ func (c *Column) decodeDataPageV2(header DataPageHeaderV2, page *buffer, dict Di
 		pageData = page.data
 	}
 
-	numValues -= int(header.NumNulls())
+	// Levels are required to reconstruct the rows of nested columns, a page
+	// which omits them cannot be decoded.
+	if c.maxRepetitionLevel > 0 && repetitionLevels == nil {
+		return nil, fmt.Errorf("missing repetition levels of data page v2: %w", io.ErrUnexpectedEOF)
+	}
+	if c.maxDefinitionLevel > 0 && definitionLevels == nil {
+		return nil, fmt.Errorf("missing definition levels of data page v2: %w", io.ErrUnexpectedEOF)
+	}
+
+	numNulls := int(header.NumNulls())
+	if numNulls < 0 || numNulls > numValues {
+		return nil, fmt.Errorf("invalid number of nulls in data page v2: %d/%d", numNulls, numValues)
+	}
+	numValues -= numNulls
 	return c.decodeDataPage(header, numValues, repetitionLevels, definitionLevels, page, pageData, dict)
 }
 
 func (c *Column) decodeDataPage(header DataPageHeader, numValues int, repetitionLevels, definitionLevels, page *buffer, data []byte, dict Dictionary) (Page, error) {
 	pageEncoding := LookupEncoding(header.Encoding())
 	pageType := c.Type()
+	indexed := isDictionaryEncoding(pageEncoding)
 
-	if isDictionaryEncoding(pageEncoding) {
+	if indexed {
+		if dict == nil {
+			return nil, fmt.Errorf("decoding dictionary-encoded page: %w", ErrMissingDictionary)
+		}
 		// In some legacy configurations, the PLAIN_DICTIONARY encoding is used
 		// on data page headers to indicate that the page contains indexes into
 		// the dictionary page, but the page is still encoded using the RLE
@@ -694,25 +770,40 @@ func (c *Column) decodeDataPage(header DataPageHeader, numValues int, repetition
 		return nil, err
 	}
 
-	newPage := pageType.NewPage(c.Index(), numValues, values)
+	// The decoded values may not have fit in the buffers if the size estimate
+	// was too low, the buffers take ownership of the reallocated memory so it
+	// is reused when reading the next pages.
+	decodedValues, decodedOffsets := values.Data()
+	if vbuf != page {
+		vbuf.adopt(decodedValues, data)
+	}
+	if obuf != nil {
+		obuf.adopt(unsafecast.Uint32ToBytes(decodedOffsets), data)
+	}
+
+	if n := countValues(values); n < numValues {
+		return nil, fmt.Errorf("decoded %d values but the page header declares %d: %w", n, numValues, io.ErrUnexpectedEOF)
+	}
+	if indexed {
+		if err := checkDictionaryIndexes(values.Int32()[:numValues], dict.Len()); err != nil {
+			return nil, err
+		}
+	}
+
+	newPage := newBufferedPage(
+		pageType.NewPage(c.Index(), numValues, values),
+		vbuf,
+		obuf,
+		repetitionLevels,
+		definitionLevels,
+	)
 	switch {
 	case c.maxRepetitionLevel > 0:
-		newPage = newRepeatedPage(
-			newPage,
-			c.maxRepetitionLevel,
-			c.maxDefinitionLevel,
-			repetitionLevels.data,
-			definitionLevels.data,
-		)
+		newPage.setRepeated(c.maxRepetitionLevel, c.maxDefinitionLevel)
 	case c.maxDefinitionLevel > 0:
-		newPage = newOptionalPage(
-			newPage,
-			c.maxDefinitionLevel,
-			definitionLevels.data,
-		)
+		newPage.setOptional(c.maxDefinitionLevel)
 	}
-
-	return newBufferedPage(newPage, vbuf, obuf, repetitionLevels, definitionLevels), nil
+	return newPage, nil
 }
 
 func decodeLevelsV1(enc encoding.Encoding, numValues int, data []byte) (*buffer, []byte, error) {
@@ -729,6 +820,9 @@ func decodeLevelsV1(enc encoding.Encoding, numValues int, data []byte) (*buffer,
 }
 
 func decodeLevelsV2(enc encoding.Encoding, numValues int, data []byte, length int64) (*buffer, []byte, error) {
+	if length > int64(len(data)) {
+		return nil, data, io.ErrUnexpectedEOF
+	}
 	levels, err := decodeLevels(enc, numValues, data[:length])
 	return levels, data[length:], err
 }
@@ -750,6 +844,38 @@ func decodeLevels(enc encoding.Encoding, numValues int, data []byte) (levels *bu
 	return levels, err
 }
 
+// countValues returns the number of values held in values. Boolean values are
+// bit-packed, the count is rounded up to a multiple of 8.
+func countValues(values encoding.Values) int {
+	data, offsets := values.Data()
+	switch values.Kind() {
+	case encoding.Boolean:
+		return 8 * len(data)
+	case encoding.Int32, encoding.Float:
+		return len(data) / 4
+	case encoding.Int64, encoding.Double:
+		return len(data) / 8
+	case encoding.Int96:
+		return len(data) / 12
+	case encoding.ByteArray:
+		return len(offsets) - 1
+	case encoding.FixedLenByteArray:
+		if _, size := values.FixedLenByteArray(); size > 0 {
+			return len(data) / size
+		}
+	}
+	return 0
+}
+
+func checkDictionaryIndexes(indexes []int32, dictLen int) error {
+	for _, i := range indexes {
+		if i < 0 || int(i) >= dictLen {
+			return fmt.Errorf("dictionary index out of range: %d not in [0:%d]", i, dictLen)
+		}
+	}
+	return nil
+}
+
 func skipLevelsV2(data []byte, length int64) ([]byte, error) {
 	if length >= int64(len(data)) {
 		return data, io.ErrUnexpectedEOF
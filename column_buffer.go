@@ -1660,6 +1660,72 @@ func (col *fixedLenByteArrayColumnBuffer) ReadValuesAt(values []Value, offset in
 	}
 }
 
+type float16ColumnBuffer struct{ fixedLenByteArrayColumnBuffer }
+
+func newFloat16ColumnBuffer(typ Type, columnIndex int16, numValues int32) *float16ColumnBuffer {
+	return &float16ColumnBuffer{*newFixedLenByteArrayColumnBuffer(typ, columnIndex, numValues)}
+}
+
+func (col *float16ColumnBuffer) Clone() ColumnBuffer {
+	return &float16ColumnBuffer{*col.fixedLenByteArrayColumnBuffer.Clone().(*fixedLenByteArrayColumnBuffer)}
+}
+
+func (col *float16ColumnBuffer) ColumnIndex() ColumnIndex {
+	return float16ColumnIndex{col.page()}
+}
+
+func (col *float16ColumnBuffer) Pages() Pages { return onePage(col.Page()) }
+
+func (col *float16ColumnBuffer) Page() Page { return col.page() }
+
+func (col *float16ColumnBuffer) page() *float16Page {
+	return &float16Page{col.fixedLenByteArrayPage}
+}
+
+func (col *float16ColumnBuffer) Less(i, j int) bool {
+	return compareFloat16(float16Bits(col.index(i)), float16Bits(col.index(j))) < 0
+}
+
+type intervalColumnBuffer struct{ fixedLenByteArrayColumnBuffer }
+
+func newIntervalColumnBuffer(typ Type, columnIndex int16, numValues int32) *intervalColumnBuffer {
+	return &intervalColumnBuffer{*newFixedLenByteArrayColumnBuffer(typ, columnIndex, numValues)}
+}
+
+func (col *intervalColumnBuffer) Clone() ColumnBuffer {
+	return &intervalColumnBuffer{*col.fixedLenByteArrayColumnBuffer.Clone().(*fixedLenByteArrayColumnBuffer)}
+}
+
+func (col *intervalColumnBuffer) ColumnIndex() ColumnIndex { return undefinedOrderColumnIndex{} }
+
+func (col *intervalColumnBuffer) Pages() Pages { return onePage(col.Page()) }
+
+func (col *intervalColumnBuffer) Page() Page {
+	return &intervalPage{col.fixedLenByteArrayPage}
+}
+
+func (col *intervalColumnBuffer) Less(i, j int) bool {
+	return compareCalendarInterval(makeCalendarInterval(col.index(i)), makeCalendarInterval(col.index(j))) < 0
+}
+
+type geospatialColumnBuffer struct{ byteArrayColumnBuffer }
+
+func newGeospatialColumnBuffer(typ Type, columnIndex int16, numValues int32) *geospatialColumnBuffer {
+	return &geospatialColumnBuffer{*newByteArrayColumnBuffer(typ, columnIndex, numValues)}
+}
+
+func (col *geospatialColumnBuffer) Clone() ColumnBuffer {
+	return &geospatialColumnBuffer{*col.byteArrayColumnBuffer.Clone().(*byteArrayColumnBuffer)}
+}
+
+func (col *geospatialColumnBuffer) ColumnIndex() ColumnIndex { return undefinedOrderColumnIndex{} }
+
+func (col *geospatialColumnBuffer) Pages() Pages { return onePage(col.Page()) }
+
+func (col *geospatialColumnBuffer) Page() Page {
+	return &geospatialPage{*col.byteArrayColumnBuffer.Page().(*byteArrayPage)}
+}
+
 type uint32ColumnBuffer struct{ uint32Page }
 
 func newUint32ColumnBuffer(typ Type, columnIndex int16, numValues int32) *uint32ColumnBuffer {
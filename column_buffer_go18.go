@@ -4,6 +4,7 @@ package parquet
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/bits"
 	"reflect"
 	"time"
@@ -34,12 +35,24 @@ func writeRowsFuncOf(t reflect.Type, schema *Schema, path columnPath) writeRowsF
 	}
 
 	switch t {
-	case reflect.TypeOf(deprecated.Int96{}):
+	case reflect.TypeOf(deprecated.Int96{}), reflect.TypeOf(CalendarInterval{}):
 		return writeRowsFuncOfRequired(t, schema, path)
 	case reflect.TypeOf(time.Time{}):
 		return writeRowsFuncOfTime(t, schema, path)
 	}
 
+	if node := unwrapMarshalNode(lookupColumnPath(schema, path)); node != nil && node.gotype == t {
+		return writeRowsFuncOfMarshaler(t, node, schema, path)
+	}
+
+	if isNullSQLType(t) {
+		return writeRowsFuncOfNullSQL(t, schema, path)
+	}
+
+	if leaf, exists := schema.Lookup(path...); exists && hasStdlibMapping(t, leaf.Node.Type()) {
+		return writeRowsFuncOfStdlib(t, schema, path)
+	}
+
 	switch t.Kind() {
 	case reflect.Bool,
 		reflect.Int,
@@ -451,6 +464,7 @@ func writeRowsFuncOfTime(_ reflect.Type, schema *Schema, path columnPath) writeR
 	if lt != nil && lt.Timestamp != nil {
 		unit = lt.Timestamp.Unit
 	}
+	ts, _ := col.Node.Type().(*timestampType)
 
 	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
 		if rows.Len() == 0 {
@@ -462,6 +476,8 @@ func writeRowsFuncOfTime(_ reflect.Type, schema *Schema, path columnPath) writeR
 			t := times.Index(i)
 			var val int64
 			switch {
+			case ts != nil:
+				val = ts.valueOf(t)
 			case unit.Millis != nil:
 				val = t.UnixMilli()
 			case unit.Micros != nil:
@@ -479,3 +495,102 @@ func writeRowsFuncOfTime(_ reflect.Type, schema *Schema, path columnPath) writeR
 		return nil
 	}
 }
+
+func writeRowsFuncOfMarshaler(t reflect.Type, node *marshalNode, schema *Schema, path columnPath) writeRowsFunc {
+	columnIndex, numColumns := int16(-1), int16(0)
+	for i, column := range schema.columns {
+		if len(column) >= len(path) && columnPath(column[:len(path)]).equal(path) {
+			if columnIndex < 0 {
+				columnIndex = int16(i)
+			}
+			numColumns++
+		}
+	}
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		if rows.Len() == 0 {
+			for i := int16(0); i < numColumns; i++ {
+				columns[columnIndex+i].writeValues(rows, levels)
+			}
+			return nil
+		}
+
+		var row Row
+		var err error
+
+		for i := 0; i < rows.Len(); i++ {
+			row, err = node.marshal(row[:0], reflect.NewAt(t, rows.Index(i)).Elem())
+			if err != nil {
+				return fmt.Errorf("marshaling go value of type %s to parquet: %w", t, err)
+			}
+			for j := range row {
+				v := &row[j]
+				if err := rebaseMarshaledValue(v, columnIndex, numColumns, levels.repetitionDepth, levels.repetitionLevel, levels.definitionLevel); err != nil {
+					return err
+				}
+				if _, err := columns[^v.columnIndex].WriteValues(row[j : j+1]); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+func writeRowsFuncOfNullSQL(t reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
+	value, valid := t.Field(0), t.Field(1)
+	elemSize := uintptr(value.Type.Size())
+	writeRows := writeRowsFuncOf(value.Type, schema, path)
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		if rows.Len() == 0 {
+			return writeRows(columns, rows, levels)
+		}
+
+		for i := 0; i < rows.Len(); i++ {
+			p := rows.Index(i)
+			a := sparse.Array{}
+			elemLevels := levels
+			if *(*bool)(unsafe.Add(p, valid.Offset)) {
+				a = makeArray(unsafe.Add(p, value.Offset), 1, elemSize)
+				elemLevels.definitionLevel++
+			}
+			if err := writeRows(columns, a, elemLevels); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func writeRowsFuncOfStdlib(t reflect.Type, schema *Schema, path columnPath) writeRowsFunc {
+	column := schema.mapping.lookup(path)
+	columnIndex := column.columnIndex
+	typ := column.node.Type()
+
+	return func(columns []ColumnBuffer, rows sparse.Array, levels columnLevels) error {
+		if rows.Len() == 0 {
+			columns[columnIndex].writeValues(rows, levels)
+			return nil
+		}
+
+		values := make([]Value, 1)
+		for i := 0; i < rows.Len(); i++ {
+			v, _, err := makeStdlibValue(typ, reflect.NewAt(t, rows.Index(i)).Elem())
+			if err != nil {
+				return fmt.Errorf("converting go value of type %s to parquet: %w", t, err)
+			}
+			v.repetitionLevel = levels.repetitionLevel
+			v.definitionLevel = levels.definitionLevel
+			v.columnIndex = ^columnIndex
+			values[0] = v
+			if _, err := columns[columnIndex].WriteValues(values); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+//go:build go1.18
+
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/format"
+)
+
+func TestColumnDecodeDataPage(t *testing.T) {
+	type Row struct {
+		ID  int64  `parquet:"id"`
+		Opt *int32 `parquet:"opt,optional"`
+	}
+	rows := make([]Row, 100)
+	for i := range rows {
+		rows[i].ID = int64(i)
+	}
+
+	for _, version := range []int{1, 2} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := NewGenericWriter[Row](buffer, DataPageVersion(version), Compression(&Zstd))
+			if _, err := writer.Write(rows); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			f, err := OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			column := f.Root().Column("id")
+			metadata := f.Metadata().RowGroups[0].Columns[0].MetaData
+			reader := bufio.NewReader(io.NewSectionReader(f, metadata.DataPageOffset, metadata.TotalCompressedSize))
+			decoder := thrift.NewDecoder(new(thrift.CompactProtocol).NewReader(reader))
+
+			header := format.PageHeader{}
+			if err := decoder.Decode(&header); err != nil {
+				t.Fatal(err)
+			}
+			data := make([]byte, header.CompressedPageSize)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				t.Fatal(err)
+			}
+
+			var page Page
+			if version == 1 {
+				page, err = column.DecodeDataPageV1(DataPageHeaderV1{header.DataPageHeader}, data, nil)
+			} else {
+				page, err = column.DecodeDataPageV2(DataPageHeaderV2{header.DataPageHeaderV2}, data, nil)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer Release(page)
+
+			values := make([]Value, page.NumValues())
+			if n, err := page.Values().ReadValues(values); n != len(rows) {
+				t.Fatalf("reading values: %d/%d: %v", n, len(rows), err)
+			}
+			for i, v := range values {
+				if v.Int64() != rows[i].ID {
+					t.Fatalf("value %d mismatch: want=%d got=%d", i, rows[i].ID, v.Int64())
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package parquet
 
 import (
+	"encoding/binary"
+
 	"github.com/segmentio/parquet-go/deprecated"
 	"github.com/segmentio/parquet-go/encoding/plain"
 	"github.com/segmentio/parquet-go/format"
@@ -221,6 +223,34 @@ func (i fixedLenByteArrayColumnIndex) MaxValue(int) Value {
 func (i fixedLenByteArrayColumnIndex) IsAscending() bool  { return false }
 func (i fixedLenByteArrayColumnIndex) IsDescending() bool { return false }
 
+type float16ColumnIndex struct{ page *float16Page }
+
+func (i float16ColumnIndex) NumPages() int       { return 1 }
+func (i float16ColumnIndex) NullCount(int) int64 { return 0 }
+func (i float16ColumnIndex) NullPage(int) bool   { return false }
+func (i float16ColumnIndex) MinValue(int) Value {
+	min, _, _ := i.page.Bounds()
+	return min
+}
+func (i float16ColumnIndex) MaxValue(int) Value {
+	_, max, _ := i.page.Bounds()
+	return max
+}
+func (i float16ColumnIndex) IsAscending() bool  { return false }
+func (i float16ColumnIndex) IsDescending() bool { return false }
+
+// undefinedOrderColumnIndex has null min and max values, it is used by types
+// which have an undefined order (e.g. INTERVAL or GEOMETRY).
+type undefinedOrderColumnIndex struct{}
+
+func (i undefinedOrderColumnIndex) NumPages() int       { return 1 }
+func (i undefinedOrderColumnIndex) NullCount(int) int64 { return 0 }
+func (i undefinedOrderColumnIndex) NullPage(int) bool   { return false }
+func (i undefinedOrderColumnIndex) MinValue(int) Value  { return Value{} }
+func (i undefinedOrderColumnIndex) MaxValue(int) Value  { return Value{} }
+func (i undefinedOrderColumnIndex) IsAscending() bool   { return false }
+func (i undefinedOrderColumnIndex) IsDescending() bool  { return false }
+
 type uint32ColumnIndex struct{ page *uint32Page }
 
 func (i uint32ColumnIndex) NumPages() int       { return 1 }
@@ -272,6 +302,17 @@ type ColumnIndexer interface {
 	ColumnIndex() format.ColumnIndex
 }
 
+// noopColumnIndexer is the column indexer of types which have an undefined
+// column order, since the min and max values of their pages are meaningless.
+// Writers do not write column indexes for those types.
+type noopColumnIndexer struct{}
+
+func (noopColumnIndexer) Reset() {}
+
+func (noopColumnIndexer) IndexPage(int64, int64, Value, Value) {}
+
+func (noopColumnIndexer) ColumnIndex() format.ColumnIndex { return format.ColumnIndex{} }
+
 type baseColumnIndexer struct {
 	nullPages  []bool
 	nullCounts []int64
@@ -571,6 +612,55 @@ func (i *fixedLenByteArrayColumnIndexer) ColumnIndex() format.ColumnIndex {
 	)
 }
 
+// float16ColumnIndexer records NaN as bounds of pages which contain only NaN
+// values, since those are excluded when computing the page bounds.
+type float16ColumnIndexer struct {
+	baseColumnIndexer
+	minValues []uint16
+	maxValues []uint16
+}
+
+func newFloat16ColumnIndexer() *float16ColumnIndexer {
+	return new(float16ColumnIndexer)
+}
+
+func (i *float16ColumnIndexer) Reset() {
+	i.reset()
+	i.minValues = i.minValues[:0]
+	i.maxValues = i.maxValues[:0]
+}
+
+func (i *float16ColumnIndexer) IndexPage(numValues, numNulls int64, min, max Value) {
+	i.observe(numValues, numNulls)
+	minValue, maxValue := uint16(0), uint16(0)
+	switch {
+	case numValues == numNulls:
+	case min.isNull() || max.isNull():
+		minValue, maxValue = float16NaN, float16NaN
+	default:
+		minValue, maxValue = float16Bits(min.byteArray()), float16Bits(max.byteArray())
+	}
+	i.minValues = append(i.minValues, minValue)
+	i.maxValues = append(i.maxValues, maxValue)
+}
+
+func (i *float16ColumnIndexer) ColumnIndex() format.ColumnIndex {
+	return i.columnIndex(
+		splitFloat16Values(i.minValues),
+		splitFloat16Values(i.maxValues),
+		orderOfFloat16(i.minValues),
+		orderOfFloat16(i.maxValues),
+	)
+}
+
+func splitFloat16Values(values []uint16) [][]byte {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[2*i:], v)
+	}
+	return splitFixedLenByteArrays(data, 2)
+}
+
 type uint32ColumnIndexer struct {
 	baseColumnIndexer
 	minValues []uint32
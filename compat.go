@@ -0,0 +1,375 @@
+package parquet
+
+import (
+	"reflect"
+
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// normalizeNestedTypes returns views of the target and source schemas of a
+// conversion where the LIST and MAP groups using the layouts of older parquet
+// writers are presented with the layouts defined by the parquet format.
+//
+// The backward-compatibility rules for LIST and MAP groups are described at
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#backward-compatibility-rules
+//
+// Repeated fields which are not part of a LIST or MAP group are presented as
+// required LIST groups of required elements when the matching field of the
+// other schema is a LIST, which is how the parquet format says those fields
+// must be interpreted.
+//
+// The transformation only renames fields and inserts required groups, which
+// means that the repetition and definition levels of columns are preserved.
+// Convert uses it to match the columns of schemas using different layouts by
+// their path, for example when reading two-level lists written by Hive into a
+// Go slice.
+func normalizeNestedTypes(to, from Node, byFieldID bool) (Node, Node) {
+	to, from = normalizeLegacyTypes(to), normalizeLegacyTypes(from)
+	return normalizeRepeatedFields(to, from, byFieldID), normalizeRepeatedFields(from, to, byFieldID)
+}
+
+func normalizeLegacyTypes(node Node) Node {
+	if node.Leaf() {
+		return node
+	}
+	fields := node.Fields()
+	normalized := make([]Field, len(fields))
+	for i, field := range fields {
+		normalized[i] = &normalizedField{
+			Node:  normalizeLegacyField(field),
+			field: field,
+		}
+	}
+	return &normalizedGroup{Node: node, fields: normalized}
+}
+
+func normalizeLegacyField(field Field) Node {
+	if field.Leaf() {
+		return field
+	}
+
+	var node Node
+	switch {
+	case isListGroup(field):
+		node = normalizeList(field)
+	case isMapGroup(field):
+		node = normalizeMap(field)
+	}
+	if node == nil {
+		node = normalizeLegacyTypes(field)
+	}
+	return node
+}
+
+func normalizeRepeatedFields(node, other Node, byFieldID bool) Node {
+	if node.Leaf() {
+		return node
+	}
+	fields := node.Fields()
+	normalized := make([]Field, len(fields))
+	for i, field := range fields {
+		var match Field
+		if other != nil && !other.Leaf() {
+			if id := fieldIDOf(field); byFieldID && id != 0 {
+				match = fieldByID(other, id)
+			} else {
+				match = fieldByName(other, field.Name())
+			}
+		}
+
+		var n Node
+		if field.Repeated() && !isList(field) && match != nil && !match.Repeated() && isList(match) {
+			element := Required(field)
+			if fieldIDOf(element) != 0 {
+				element = FieldID(element, 0)
+			}
+			n = withFieldIDOf(field, List(normalizeRepeatedFields(element, listElementOf(match), byFieldID)))
+		} else {
+			n = normalizeRepeatedFields(field, match, byFieldID)
+		}
+
+		normalized[i] = &normalizedField{Node: n, field: field}
+	}
+	return &normalizedGroup{Node: node, fields: normalized}
+}
+
+func normalizeList(list Field) Node {
+	fields := list.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() {
+		return nil
+	}
+
+	repeated := fields[0]
+	element := Node(nil)
+
+	switch {
+	case repeated.Leaf():
+		// repeated int32 element;
+		element = Required(repeated)
+	case isRepeatedListElement(repeated, list.Name()):
+		// repeated group array { ... };
+		element = Required(normalizeLegacyTypes(repeated))
+	case len(repeated.Fields()) == 1 && !repeated.Fields()[0].Repeated():
+		// repeated group list { optional int32 element; };
+		element = normalizeLegacyField(repeated.Fields()[0])
+	default:
+		return nil
+	}
+
+	return withRepetitionOf(list, List(element))
+}
+
+func normalizeMap(m Field) Node {
+	fields := m.Fields()
+	if len(fields) != 1 || fields[0].Leaf() || !fields[0].Repeated() {
+		return nil
+	}
+
+	keyValue := fields[0].Fields()
+	if len(keyValue) != 2 || !keyValue[0].Required() {
+		return nil
+	}
+
+	key := normalizeLegacyField(keyValue[0])
+	value := normalizeLegacyField(keyValue[1])
+	return withRepetitionOf(m, Map(key, value))
+}
+
+func withRepetitionOf(of, node Node) Node {
+	switch {
+	case of.Optional():
+		node = Optional(node)
+	case of.Repeated():
+		node = Repeated(node)
+	}
+	return withFieldIDOf(of, node)
+}
+
+func withFieldIDOf(of, node Node) Node {
+	if id := fieldIDOf(of); id != 0 {
+		node = FieldID(node, id)
+	}
+	return node
+}
+
+// isListGroup returns true if node is a group annotated with the LIST logical
+// or converted type. Columns of parquet files only retain their LIST type when
+// they use the standard layout, so the annotation is looked up in the schema
+// element of the column.
+func isListGroup(node Node) bool {
+	logicalType, convertedType := annotationsOf(node)
+	return (logicalType != nil && logicalType.List != nil) ||
+		(convertedType != nil && *convertedType == deprecated.List)
+}
+
+// isMapGroup returns true if node is a group annotated with the MAP logical or
+// converted type, or with the MAP_KEY_VALUE converted type that older writers
+// used either on the map group or on its repeated group of key/value pairs.
+func isMapGroup(node Node) bool {
+	logicalType, convertedType := annotationsOf(node)
+	if logicalType != nil && logicalType.Map != nil {
+		return true
+	}
+	if convertedType != nil {
+		switch *convertedType {
+		case deprecated.Map, deprecated.MapKeyValue:
+			return true
+		}
+	}
+	if fields := node.Fields(); len(fields) == 1 && !fields[0].Leaf() {
+		_, convertedType = annotationsOf(fields[0])
+		return convertedType != nil && *convertedType == deprecated.MapKeyValue
+	}
+	return false
+}
+
+func annotationsOf(node Node) (*format.LogicalType, *deprecated.ConvertedType) {
+	if c, ok := node.(*Column); ok {
+		return c.schema.LogicalType, c.schema.ConvertedType
+	}
+	t := node.Type()
+	return t.LogicalType(), t.ConvertedType()
+}
+
+type normalizedGroup struct {
+	Node
+	fields []Field
+}
+
+func (g *normalizedGroup) Fields() []Field { return g.fields }
+
+func (g *normalizedGroup) unwrap() Node { return g.Node }
+
+type normalizedField struct {
+	Node
+	field Field
+}
+
+func (f *normalizedField) Name() string { return f.field.Name() }
+
+func (f *normalizedField) Value(base reflect.Value) reflect.Value { return f.field.Value(base) }
+
+func (f *normalizedField) unwrap() Node { return f.Node }
+
+// mapKeyValueType is the type of groups annotated with the MAP_KEY_VALUE
+// converted type, which is used by older writers in place of MAP.
+type mapKeyValueType struct{ groupType }
+
+func (mapKeyValueType) ConvertedType() *deprecated.ConvertedType {
+	convertedType := deprecated.MapKeyValue
+	return &convertedType
+}
+
+type mapKeyValueNode struct{ Group }
+
+func (mapKeyValueNode) Type() Type { return mapKeyValueType{} }
+
+// Compatibility is a set of query engines that the parquet files produced by a
+// writer must remain readable by. The zero value does not restrict the features
+// of the parquet format that writers use.
+//
+// Profiles can be combined, for example Spark2|Hive; the files then use the
+// layouts understood by all the engines of the set.
+type Compatibility int
+
+const (
+	// Spark2 is the profile of Apache Spark 2.x configured to read and write
+	// the legacy parquet format. Lists are written as two-level lists when
+	// their elements are required, and with a repeated group named "bag"
+	// containing an element named "array" otherwise.
+	Spark2 Compatibility = 1 << iota
+
+	// Hive is the profile of Apache Hive. Lists are written with a repeated
+	// group named "bag" containing an element named "array_element", and take
+	// precedence over the layout of the Spark2 profile when both are set.
+	Hive
+
+	// Impala is the profile of Apache Impala, which reads the standard layout
+	// of lists and maps but is subject to the other restrictions of the
+	// compatibility profiles.
+	Impala
+)
+
+// legacySchemaOf returns the schema of parquet files written with the
+// given compatibility profile for rows of schema:
+//
+//   - timestamp columns are written as INT96 values holding a Julian day and
+//     the nanoseconds elapsed in that day,
+//   - lists and maps use the legacy layouts of the Spark2 and Hive profiles.
+//
+// The transformation renames fields and changes the types of leaf columns but
+// preserves the order of columns and their repetition and definition levels,
+// rows are adapted to the returned schema with a Conversion. The schema is
+// returned unchanged if none of its columns were affected by the profile.
+func legacySchemaOf(schema *Schema, compat Compatibility) *Schema {
+	if compat == 0 {
+		return schema
+	}
+	root, changed := legacyGroupOf(schema, compat)
+	if !changed {
+		return schema
+	}
+	return NewSchema(schema.Name(), root)
+}
+
+func legacyGroupOf(node Node, compat Compatibility) (Node, bool) {
+	fields := node.Fields()
+	legacy := make([]Field, len(fields))
+	changed := false
+	for i, field := range fields {
+		n, fieldChanged := legacyNodeOf(field, compat)
+		legacy[i] = &normalizedField{Node: n, field: field}
+		changed = changed || fieldChanged
+	}
+	if !changed {
+		return node, false
+	}
+	return &normalizedGroup{Node: node, fields: legacy}, true
+}
+
+func legacyNodeOf(node Node, compat Compatibility) (Node, bool) {
+	switch {
+	case node.Leaf():
+		if !isTimestamp(node.Type()) {
+			return node, false
+		}
+		// INT96 values cannot use the encodings of INT64 columns, only the
+		// compression codec of the column is retained.
+		int96 := Leaf(int96TimestampType{})
+		if codec := node.Compression(); codec != nil {
+			int96 = Compressed(int96, codec)
+		}
+		return withRepetitionOf(node, int96), true
+
+	case compat&(Spark2|Hive) != 0 && isList(node):
+		element, _ := legacyNodeOf(listElementOf(node), compat)
+		switch {
+		case compat&Hive != 0:
+			// repeated group bag { optional int32 array_element; };
+			element = Group{"array_element": element}
+			return withRepetitionOf(node, listNode{Group{"bag": Repeated(element)}}), true
+		case element.Required():
+			// repeated int32 array;
+			return withRepetitionOf(node, listNode{Group{"array": Repeated(element)}}), true
+		default:
+			// repeated group bag { optional int32 array; };
+			element = Group{"array": element}
+			return withRepetitionOf(node, listNode{Group{"bag": Repeated(element)}}), true
+		}
+
+	case compat&(Spark2|Hive) != 0 && isMap(node):
+		// repeated group map (MAP_KEY_VALUE) { required binary key; optional int32 value; };
+		keyValue := mapKeyValueOf(node).Fields()
+		key, _ := legacyNodeOf(keyValue[0], compat)
+		value, _ := legacyNodeOf(keyValue[1], compat)
+		keyValueGroup := mapKeyValueNode{Group{"key": key, "value": value}}
+		return withRepetitionOf(node, mapNode{Group{"map": Repeated(keyValueGroup)}}), true
+
+	default:
+		return legacyGroupOf(node, compat)
+	}
+}
+
+func isTimestamp(t Type) bool {
+	logicalType := t.LogicalType()
+	return logicalType != nil && logicalType.Timestamp != nil && t.Kind() == Int64
+}
+
+// int96TimestampType is the type of timestamp columns written as INT96 values
+// by writers configured with a compatibility profile.
+type int96TimestampType struct{ int96Type }
+
+func (t int96TimestampType) ConvertValue(val Value, typ Type) (Value, error) {
+	if isTimestamp(typ) && !val.IsNull() {
+		return convertTimestampToInt96(val, typ.LogicalType().Timestamp.Unit)
+	}
+	return t.int96Type.ConvertValue(val, typ)
+}
+
+// isLegacyEncoding returns true if the encoding can be read by the query engines
+// of compatibility profiles. Other encodings are replaced with PLAIN.
+func isLegacyEncoding(enc encoding.Encoding) bool {
+	switch enc.Encoding() {
+	case format.Plain, format.PlainDictionary, format.RLEDictionary:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasSignedSortOrder returns true if values of type t are ordered like the
+// deprecated min and max statistics of parquet files, which older readers use
+// to filter row groups.
+func hasSignedSortOrder(t Type) bool {
+	switch t.Kind() {
+	case Boolean, Float, Double:
+		return true
+	case Int32, Int64:
+		logicalType := t.LogicalType()
+		return logicalType == nil || logicalType.Integer == nil || logicalType.Integer.IsSigned
+	default:
+		return false
+	}
+}
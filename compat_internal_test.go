@@ -0,0 +1,262 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+type legacyInt32List struct {
+	Values []int32 `parquet:"values,list"`
+}
+
+type legacyNullableInt32List struct {
+	Values []*int32 `parquet:"values,list"`
+}
+
+type legacyInt32Slice struct {
+	Values []int32 `parquet:"values"`
+}
+
+type legacyStructList struct {
+	Values []struct {
+		A int32 `parquet:"a"`
+	} `parquet:"values,list"`
+}
+
+type legacyStringMap struct {
+	Values map[string]int32 `parquet:"values"`
+}
+
+func TestConvertLegacyNestedTypes(t *testing.T) {
+	one, two := int32(1), int32(2)
+
+	tests := []struct {
+		scenario string
+		schema   Node
+		value    interface{}
+	}{
+		{
+			scenario: "two-level list",
+			schema: Group{
+				"values": Optional(listNode{Group{"array": Repeated(Int(32))}}),
+			},
+			value: legacyInt32List{Values: []int32{1, 2, 3}},
+		},
+
+		{
+			scenario: "list with repeated group named array",
+			schema: Group{
+				"values": listNode{Group{"array": Repeated(Group{"a": Int(32)})}},
+			},
+			value: legacyStructList{Values: []struct {
+				A int32 `parquet:"a"`
+			}{{A: 1}, {A: 2}}},
+		},
+
+		{
+			scenario: "list with repeated group named after the list",
+			schema: Group{
+				"values": listNode{Group{"values_tuple": Repeated(Group{"a": Int(32)})}},
+			},
+			value: legacyStructList{Values: []struct {
+				A int32 `parquet:"a"`
+			}{{A: 1}, {A: 2}}},
+		},
+
+		{
+			scenario: "list with non-standard field names",
+			schema: Group{
+				"values": Optional(listNode{Group{"bag": Repeated(Group{"array_element": Optional(Int(32))})}}),
+			},
+			value: legacyNullableInt32List{Values: []*int32{&one, nil, &two}},
+		},
+
+		{
+			scenario: "unannotated repeated field into list",
+			schema: Group{
+				"values": Repeated(Int(32)),
+			},
+			value: legacyInt32List{Values: []int32{1, 2, 3}},
+		},
+
+		{
+			scenario: "list into unannotated repeated field",
+			schema: Group{
+				"values": Optional(List(Optional(Int(32)))),
+			},
+			value: legacyInt32Slice{Values: []int32{1, 2, 3}},
+		},
+
+		{
+			scenario: "map with non-standard field names",
+			schema: Group{
+				"values": Optional(mapNode{Group{"map": Repeated(Group{"keys": String(), "values": Int(32)})}}),
+			},
+			value: legacyStringMap{Values: map[string]int32{"A": 1, "B": 2}},
+		},
+
+		{
+			scenario: "map annotated with MAP_KEY_VALUE",
+			schema: Group{
+				"values": mapKeyValueNode{Group{"map": Repeated(Group{"key": String(), "value": Int(32)})}},
+			},
+			value: legacyStringMap{Values: map[string]int32{"A": 1, "B": 2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			// Rows are written to a file with the legacy schema by converting
+			// them from the schema of the Go value, then read back into a new
+			// Go value from the legacy file schema.
+			schema := SchemaOf(test.value)
+			legacy := NewSchema("legacy", test.schema)
+
+			conv, err := Convert(legacy, schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := []Row{schema.Deconstruct(nil, test.value)}
+			if _, err := conv.Convert(rows); err != nil {
+				t.Fatal(err)
+			}
+
+			buffer := new(bytes.Buffer)
+			writer := NewWriter(buffer, legacy)
+			if _, err := writer.WriteRows(rows); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := NewReader(bytes.NewReader(buffer.Bytes()))
+			value := reflect.New(reflect.TypeOf(test.value))
+			if err := reader.Read(value.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(value.Elem().Interface(), test.value) {
+				t.Errorf("value mismatch:\nwant = %+v\ngot  = %+v", test.value, value.Elem().Interface())
+			}
+		})
+	}
+}
+
+type compatibilityRow struct {
+	Time   time.Time        `parquet:"time"`
+	Values []int32          `parquet:"values,list"`
+	Names  []*string        `parquet:"names,list"`
+	Tags   map[string]int32 `parquet:"tags"`
+	Delta  int64            `parquet:"delta,delta"`
+}
+
+func TestWriterCompatibilityProfile(t *testing.T) {
+	name := "A"
+	row := compatibilityRow{
+		Time:   time.Date(1969, 7, 20, 20, 17, 40, 123456789, time.UTC),
+		Values: []int32{1, 2, 3},
+		Names:  []*string{&name, nil},
+		Tags:   map[string]int32{"A": 1, "B": 2},
+		Delta:  -42,
+	}
+
+	tests := []struct {
+		profile Compatibility
+		values  string
+		names   string
+		tags    string
+	}{
+		{Spark2, "values.array", "names.bag.array", "tags.map.key"},
+		{Hive, "values.bag.array_element", "names.bag.array_element", "tags.map.key"},
+		{Impala, "values.list.element", "names.list.element", "tags.key_value.key"},
+		{Spark2 | Hive, "values.bag.array_element", "names.bag.array_element", "tags.map.key"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(int(test.profile)), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := NewWriter(buffer, CompatibilityProfile(test.profile), DataPageVersion(2))
+			if err := writer.Write(row); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			paths := make(map[string]format.ColumnMetaData)
+			for _, columnChunk := range f.Metadata().RowGroups[0].Columns {
+				paths[strings.Join(columnChunk.MetaData.PathInSchema, ".")] = columnChunk.MetaData
+			}
+			for _, path := range []string{"time", test.values, test.names, test.tags, "delta"} {
+				if _, ok := paths[path]; !ok {
+					t.Errorf("column %q not found in %v", path, paths)
+				}
+			}
+
+			if typ := paths["time"].Type; typ != format.Int96 {
+				t.Errorf("time column written as %s instead of INT96", typ)
+			}
+			for path, metadata := range paths {
+				for _, encoding := range metadata.Encoding {
+					switch encoding {
+					case format.Plain, format.RLE, format.RLEDictionary:
+					default:
+						t.Errorf("column %q uses encoding %s", path, encoding)
+					}
+				}
+				for _, stats := range metadata.EncodingStats {
+					if stats.PageType == format.DataPageV2 {
+						t.Errorf("column %q has data pages in version 2", path)
+					}
+				}
+			}
+			if stats := paths["delta"].Statistics; !bytes.Equal(stats.Min, stats.MinValue) || stats.Min == nil {
+				t.Errorf("deprecated min statistic not set: %+v", stats)
+			}
+
+			var got compatibilityRow
+			if err := NewReader(bytes.NewReader(buffer.Bytes())).Read(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Time.Equal(row.Time) {
+				t.Errorf("time mismatch: want=%s got=%s", row.Time, got.Time)
+			}
+			got.Time = row.Time
+			if !reflect.DeepEqual(got, row) {
+				t.Errorf("row mismatch:\nwant = %+v\ngot  = %+v", row, got)
+			}
+		})
+	}
+}
+
+func TestWriterConfigurationError(t *testing.T) {
+	errConfig := errors.New("invalid configuration")
+
+	writer := NewWriter(new(bytes.Buffer), SchemaOf(compatibilityRow{}), CompatibilityProfile(Hive))
+	writer.writer.err = errConfig
+
+	if err := writer.Write(compatibilityRow{}); !errors.Is(err, errConfig) {
+		t.Errorf("Write: %v", err)
+	}
+	if _, err := writer.WriteRows([]Row{{}}); !errors.Is(err, errConfig) {
+		t.Errorf("WriteRows: %v", err)
+	}
+	if err := writer.Flush(); !errors.Is(err, errConfig) {
+		t.Errorf("Flush: %v", err)
+	}
+	if err := writer.Close(); !errors.Is(err, errConfig) {
+		t.Errorf("Close: %v", err)
+	}
+}
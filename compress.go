@@ -1,12 +1,20 @@
 package parquet
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/compress/brotli"
 	"github.com/segmentio/parquet-go/compress/gzip"
 	"github.com/segmentio/parquet-go/compress/lz4"
+	"github.com/segmentio/parquet-go/compress/lz4hadoop"
+	"github.com/segmentio/parquet-go/compress/lzo"
 	"github.com/segmentio/parquet-go/compress/snappy"
 	"github.com/segmentio/parquet-go/compress/uncompressed"
 	"github.com/segmentio/parquet-go/compress/zstd"
@@ -42,23 +50,77 @@ var (
 		Level: lz4.DefaultLevel,
 	}
 
+	// Lz4 is the deprecated LZ4 parquet compression codec, which frames LZ4
+	// blocks like the Hadoop codecs. New files should use Lz4Raw instead.
+	Lz4 = lz4hadoop.Codec{
+		Level: lz4hadoop.DefaultLevel,
+	}
+
+	// Lzo is the LZO parquet compression codec.
+	Lzo lzo.Codec
+
 	// Table of compression codecs indexed by their code in the parquet format.
 	compressionCodecs = [...]compress.Codec{
 		format.Uncompressed: &Uncompressed,
 		format.Snappy:       &Snappy,
 		format.Gzip:         &Gzip,
+		format.LZO:          &Lzo,
 		format.Brotli:       &Brotli,
+		format.Lz4:          &Lz4,
 		format.Zstd:         &Zstd,
 		format.Lz4Raw:       &Lz4Raw,
 	}
+
+	// Compression codecs registered by the application, they take precedence
+	// over the built-in codecs. The map is copied on write so lookups do not
+	// need to synchronize, the mutex serializes the registrations.
+	registeredCompressionCodecsMutex sync.Mutex
+	registeredCompressionCodecs      atomic.Value // map[format.CompressionCodec]compress.Codec
 )
 
+// RegisterCompressionCodec installs codec as the implementation of its
+// compression codec code, replacing any built-in or previously registered
+// codec with the same code.
+//
+// Registered codecs are used by LookupCompressionCodec, and therefore when
+// reading files with OpenFile. They can also be selected in "parquet" struct
+// tags using the lower case version of the codec name returned by its String
+// method. The function panics if the name is already used by a codec
+// registered for a different code.
+//
+// The function is safe to call concurrently, but it is usually called during
+// program initialization, before files are read or written.
+func RegisterCompressionCodec(codec compress.Codec) {
+	code, name := codec.CompressionCodec(), codec.String()
+	registeredCompressionCodecsMutex.Lock()
+	defer registeredCompressionCodecsMutex.Unlock()
+
+	current := loadRegisteredCompressionCodecs()
+	codecs := make(map[format.CompressionCodec]compress.Codec, len(current)+1)
+	for c, other := range current {
+		if c != code && strings.EqualFold(other.String(), name) {
+			panic("cannot register compression codec " + name + " for " + code.String() + ": the name is already registered for " + c.String())
+		}
+		codecs[c] = other
+	}
+	codecs[code] = codec
+	registeredCompressionCodecs.Store(codecs)
+}
+
+func loadRegisteredCompressionCodecs() map[format.CompressionCodec]compress.Codec {
+	codecs, _ := registeredCompressionCodecs.Load().(map[format.CompressionCodec]compress.Codec)
+	return codecs
+}
+
 // LookupCompressionCodec returns the compression codec associated with the
 // given code.
 //
 // The function never returns nil. If the encoding is not supported,
 // an "unsupported" codec is returned.
 func LookupCompressionCodec(codec format.CompressionCodec) compress.Codec {
+	if c := lookupRegisteredCompressionCodec(codec); c != nil {
+		return c
+	}
 	if codec >= 0 && int(codec) < len(compressionCodecs) {
 		if c := compressionCodecs[codec]; c != nil {
 			return c
@@ -67,6 +129,21 @@ func LookupCompressionCodec(codec format.CompressionCodec) compress.Codec {
 	return &unsupported{codec}
 }
 
+func lookupRegisteredCompressionCodec(codec format.CompressionCodec) compress.Codec {
+	return loadRegisteredCompressionCodecs()[codec]
+}
+
+// lookupCompressionCodecByName returns the registered compression codec with
+// the given name, or nil if none was found.
+func lookupCompressionCodecByName(name string) compress.Codec {
+	for _, codec := range loadRegisteredCompressionCodecs() {
+		if strings.EqualFold(codec.String(), name) {
+			return codec
+		}
+	}
+	return nil
+}
+
 type unsupported struct {
 	codec format.CompressionCodec
 }
@@ -94,3 +171,98 @@ func (u *unsupported) error() error {
 func isCompressed(c compress.Codec) bool {
 	return c != nil && c.CompressionCodec() != format.Uncompressed
 }
+
+// zstdDictionaryKey is the key of the file metadata holding the zstd dictionary
+// used to compress the pages of the file, encoded in base64.
+const zstdDictionaryKey = "parquet-go.zstd.dictionary"
+
+// zstdDictionary rewrites the ZSTD compression codecs of the columns written
+// to a file so they use a shared dictionary, maintaining a codec per level.
+type zstdDictionary struct {
+	dict   []byte
+	codecs map[zstd.Level]*zstd.Codec
+}
+
+func (d *zstdDictionary) codecOf(codec compress.Codec) compress.Codec {
+	if len(d.dict) == 0 || codec.CompressionCodec() != format.Zstd {
+		return codec
+	}
+	level := zstd.Level(0)
+	if c, ok := codec.(*zstd.Codec); ok {
+		if len(c.Dictionary) != 0 {
+			// The application supplied its own dictionary for this column.
+			return codec
+		}
+		level = c.Level
+	}
+	c := d.codecs[level]
+	if c == nil {
+		c = &zstd.Codec{Level: level, Dictionary: d.dict}
+		if d.codecs == nil {
+			d.codecs = make(map[zstd.Level]*zstd.Codec)
+		}
+		d.codecs[level] = c
+	}
+	return c
+}
+
+// zstdDictionarySample returns the plain encoded values of page, or of its
+// dictionary if it has one which was not already sampled.
+func zstdDictionarySample(page Page, dictionaries *[]Dictionary) ([]byte, error) {
+	if dict := page.Dictionary(); dict != nil {
+		for _, d := range *dictionaries {
+			if d == dict {
+				return nil, nil
+			}
+		}
+		*dictionaries = append(*dictionaries, dict)
+		page = dict.Page()
+	}
+	return page.Type().Encode(nil, page.Data(), &Plain)
+}
+
+func encodeZstdDictionary(dict []byte) string {
+	return base64.StdEncoding.EncodeToString(dict)
+}
+
+func decodeZstdDictionary(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// TrainZstdDictionary trains a zstd dictionary of at most size bytes from the
+// content of pages, which are usually read from a column of a sample of files
+// to be written with the dictionary.
+//
+// The function reads pages until enough samples were collected, or io.EOF is
+// returned. Pages using a dictionary contribute the values of the dictionary,
+// since the indexes of data pages do not benefit from it.
+//
+// The returned dictionary can be passed to ZstdDictionary to compress the
+// columns of a file, or to zstd.Codec to configure it out of band.
+func TrainZstdDictionary(pages Pages, size int) ([]byte, error) {
+	var samples [][]byte
+	var dictionaries []Dictionary
+	total, limit := 0, 32*size
+
+	for total < limit {
+		page, err := pages.ReadPage()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		sample, err := zstdDictionarySample(page, &dictionaries)
+		Release(page)
+		if err != nil {
+			return nil, err
+		}
+		if len(sample) != 0 {
+			samples = append(samples, sample)
+			total += len(sample)
+		}
+	}
+
+	return zstd.TrainDictionary(samples, size)
+}
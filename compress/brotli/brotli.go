@@ -44,9 +44,15 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 }
 
 func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
-	return c.r.Decode(dst, src, func(r io.Reader) (compress.Reader, error) {
-		return reader{brotli.NewReader(r)}, nil
-	})
+	return c.r.Decode(dst, src, newReader)
+}
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	return c.r.DecodeLimit(dst, src, limit, newReader)
+}
+
+func newReader(r io.Reader) (compress.Reader, error) {
+	return reader{brotli.NewReader(r)}, nil
 }
 
 type reader struct{ *brotli.Reader }
@@ -6,6 +6,7 @@ package compress
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"sync"
 
@@ -36,6 +37,36 @@ type Codec interface {
 	Decode(dst, src []byte) ([]byte, error)
 }
 
+// ErrDecodeLimit is returned by DecodeLimit when the uncompressed data exceeds
+// the limit.
+var ErrDecodeLimit = errors.New("uncompressed data exceeds the decode limit")
+
+// The LimitDecoder interface is implemented by codecs which can bound the size
+// of the data they decompress, stopping before memory is allocated for more
+// than the limit.
+type LimitDecoder interface {
+	// Writes the uncompressed version of src to dst and returns it, or returns
+	// ErrDecodeLimit if the uncompressed data is larger than limit bytes.
+	DecodeLimit(dst, src []byte, limit int) ([]byte, error)
+}
+
+// DecodeLimit writes the uncompressed version of src to dst and returns it,
+// returning ErrDecodeLimit if the uncompressed data is larger than limit bytes.
+//
+// All the codecs of the compress sub-packages implement LimitDecoder. Other
+// codecs are decoded with their Decode method, the size of the output being
+// checked after decompression.
+func DecodeLimit(codec Codec, dst, src []byte, limit int) ([]byte, error) {
+	if d, ok := codec.(LimitDecoder); ok {
+		return d.DecodeLimit(dst, src, limit)
+	}
+	dst, err := codec.Decode(dst, src)
+	if err == nil && len(dst) > limit {
+		err = ErrDecodeLimit
+	}
+	return dst, err
+}
+
 type Reader interface {
 	io.ReadCloser
 	Reset(io.Reader) error
@@ -94,6 +125,13 @@ type reader struct {
 }
 
 func (d *Decompressor) Decode(dst, src []byte, newReader func(io.Reader) (Reader, error)) ([]byte, error) {
+	return d.DecodeLimit(dst, src, -1, newReader)
+}
+
+// DecodeLimit is like Decode but returns ErrDecodeLimit if the uncompressed
+// data exceeds limit bytes. A negative limit means that the size of the
+// uncompressed data is not limited.
+func (d *Decompressor) DecodeLimit(dst, src []byte, limit int, newReader func(io.Reader) (Reader, error)) ([]byte, error) {
 	r, _ := d.readers.Get().(*reader)
 	if r != nil {
 		r.input.Reset(src)
@@ -117,7 +155,7 @@ func (d *Decompressor) Decode(dst, src []byte, newReader func(io.Reader) (Reader
 	}()
 
 	if cap(dst) == 0 {
-		dst = make([]byte, 0, 2*len(src))
+		dst = make([]byte, 0, limitSize(2*len(src), limit))
 	} else {
 		dst = dst[:0]
 	}
@@ -126,6 +164,10 @@ func (d *Decompressor) Decode(dst, src []byte, newReader func(io.Reader) (Reader
 		n, err := r.reader.Read(dst[len(dst):cap(dst)])
 		dst = dst[:len(dst)+n]
 
+		if limit >= 0 && len(dst) > limit {
+			return dst[:limit], ErrDecodeLimit
+		}
+
 		if err != nil {
 			if err == io.EOF {
 				err = nil
@@ -134,9 +176,21 @@ func (d *Decompressor) Decode(dst, src []byte, newReader func(io.Reader) (Reader
 		}
 
 		if len(dst) == cap(dst) {
-			tmp := make([]byte, len(dst), 2*len(dst))
+			tmp := make([]byte, len(dst), limitSize(2*len(dst), limit))
 			copy(tmp, dst)
 			dst = tmp
 		}
 	}
 }
+
+// limitSize returns size capped to one byte past limit, which is enough to
+// detect that the uncompressed data exceeds the limit.
+func limitSize(size, limit int) int {
+	if size < bytes.MinRead {
+		size = bytes.MinRead
+	}
+	if limit >= 0 && size > limit+1 {
+		size = limit + 1
+	}
+	return size
+}
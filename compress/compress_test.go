@@ -9,6 +9,8 @@ import (
 	"github.com/segmentio/parquet-go/compress/brotli"
 	"github.com/segmentio/parquet-go/compress/gzip"
 	"github.com/segmentio/parquet-go/compress/lz4"
+	"github.com/segmentio/parquet-go/compress/lz4hadoop"
+	"github.com/segmentio/parquet-go/compress/lzo"
 	"github.com/segmentio/parquet-go/compress/snappy"
 	"github.com/segmentio/parquet-go/compress/uncompressed"
 	"github.com/segmentio/parquet-go/compress/zstd"
@@ -47,6 +49,16 @@ var tests = [...]struct {
 		scenario: "lz4",
 		codec:    new(lz4.Codec),
 	},
+
+	{
+		scenario: "lz4hadoop",
+		codec:    new(lz4hadoop.Codec),
+	},
+
+	{
+		scenario: "lzo",
+		codec:    new(lzo.Codec),
+	},
 }
 
 var testdata = bytes.Repeat([]byte("1234567890qwertyuiopasdfghjklzxcvbnm"), 10e3)
@@ -81,6 +93,39 @@ func TestCompressionCodec(t *testing.T) {
 	}
 }
 
+func TestDecodeLimit(t *testing.T) {
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if _, ok := test.codec.(compress.LimitDecoder); !ok {
+				t.Fatal("codec does not implement compress.LimitDecoder")
+			}
+
+			buffer, err := test.codec.Encode(nil, testdata)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := compress.DecodeLimit(test.codec, nil, buffer, len(testdata))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(testdata, output) {
+				t.Error("content mismatch after decompressing with a limit equal to the size of the data")
+			}
+
+			for _, limit := range []int{0, 100, len(testdata) - 1} {
+				output, err := compress.DecodeLimit(test.codec, nil, buffer, limit)
+				if err != compress.ErrDecodeLimit {
+					t.Errorf("limit=%d: wrong error: want=%v got=%v", limit, compress.ErrDecodeLimit, err)
+				}
+				if cap(output) > len(testdata)/2 && limit < len(testdata)/4 {
+					t.Errorf("limit=%d: decoder allocated %d bytes", limit, cap(output))
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkEncode(b *testing.B) {
 	buffer := make([]byte, 0, len(testdata))
 
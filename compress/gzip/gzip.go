@@ -44,13 +44,19 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 }
 
 func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
-	return c.r.Decode(dst, src, func(r io.Reader) (compress.Reader, error) {
-		z, err := gzip.NewReader(r)
-		if err != nil {
-			return nil, err
-		}
-		return &reader{Reader: z}, nil
-	})
+	return c.r.Decode(dst, src, newReader)
+}
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	return c.r.DecodeLimit(dst, src, limit, newReader)
+}
+
+func newReader(r io.Reader) (compress.Reader, error) {
+	z, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{Reader: z}, nil
 }
 
 type reader struct {
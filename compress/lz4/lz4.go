@@ -3,6 +3,7 @@ package lz4
 
 import (
 	"github.com/pierrec/lz4/v4"
+	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/format"
 )
 
@@ -75,6 +76,35 @@ func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
 	}
 }
 
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	// The output buffer grows like in Decode, but never past one byte more
+	// than the limit; a block which does not decompress in a buffer of this
+	// size is either corrupted or larger than the limit.
+	size := 3 * len(src)
+
+	for {
+		if size > limit+1 {
+			size = limit + 1
+		}
+		if size < 1 {
+			size = 1
+		}
+		dst = reserveAtLeast(dst, size)
+
+		n, err := lz4.UncompressBlock(src, dst)
+		switch {
+		case err == nil && n > limit:
+			return dst[:0], compress.ErrDecodeLimit
+		case err == nil:
+			return dst[:n], nil
+		case len(dst) > limit:
+			return dst[:0], compress.ErrDecodeLimit
+		}
+
+		size = 2 * len(dst)
+	}
+}
+
 func reserveAtLeast(b []byte, n int) []byte {
 	if cap(b) < n {
 		b = make([]byte, n)
@@ -0,0 +1,120 @@
+// Package lz4hadoop implements the deprecated LZ4 parquet compression codec,
+// which uses the LZ4 block format within the block framing of Hadoop.
+//
+// Older versions of parquet-cpp wrote raw LZ4 blocks without the framing when
+// using the LZ4 codec, the decoder falls back to reading the data as a single
+// LZ4 block when it is not a valid sequence of Hadoop blocks.
+package lz4hadoop
+
+import (
+	"github.com/pierrec/lz4/v4"
+	"github.com/segmentio/parquet-go/compress"
+	lz4raw "github.com/segmentio/parquet-go/compress/lz4"
+	"github.com/segmentio/parquet-go/format"
+	"github.com/segmentio/parquet-go/internal/hadoop"
+)
+
+type Level = lz4raw.Level
+
+const (
+	Fast   = lz4raw.Fast
+	Level1 = lz4raw.Level1
+	Level2 = lz4raw.Level2
+	Level3 = lz4raw.Level3
+	Level4 = lz4raw.Level4
+	Level5 = lz4raw.Level5
+	Level6 = lz4raw.Level6
+	Level7 = lz4raw.Level7
+	Level8 = lz4raw.Level8
+	Level9 = lz4raw.Level9
+)
+
+const (
+	DefaultLevel = Fast
+)
+
+type Codec struct {
+	Level Level
+}
+
+func (c *Codec) String() string {
+	return "LZ4"
+}
+
+func (c *Codec) CompressionCodec() format.CompressionCodec {
+	return format.Lz4
+}
+
+func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
+	codec := lz4raw.Codec{Level: c.Level}
+	return hadoop.Encode(dst, src, codec.Encode)
+}
+
+func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
+	out, err := hadoop.Decode(dst, src, decodeBlock)
+	if err != nil {
+		return decodeRaw(out, src, hadoop.MaxCompressionRatio*len(src))
+	}
+	return out, nil
+}
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	out, err := hadoop.DecodeLimit(dst, src, limit, decodeBlock)
+	if err == nil {
+		return out, nil
+	}
+
+	// The fallback to raw LZ4 blocks only grows the output buffer to one byte
+	// past the limit; when the block does not fit, it is either corrupted or
+	// larger than the limit, which the limit error reports since the framing
+	// may have been rejected for the same reason.
+	maxSize, capped := hadoop.MaxCompressionRatio*len(src), false
+	if maxSize > limit {
+		maxSize, capped = limit+1, true
+	}
+
+	out, rawErr := decodeRaw(out, src, maxSize)
+	switch {
+	case rawErr == nil && len(out) > limit:
+		return out[:0], compress.ErrDecodeLimit
+	case rawErr == nil:
+		return out, nil
+	case capped || err == compress.ErrDecodeLimit:
+		return out[:0], compress.ErrDecodeLimit
+	default:
+		return out[:0], rawErr
+	}
+}
+
+func decodeBlock(dst, src []byte) (int, error) {
+	return lz4.UncompressBlock(src, dst)
+}
+
+// decodeRaw decodes src as a single LZ4 block. The size of the uncompressed
+// data is unknown, the output buffer is grown until the block fits or exceeds
+// maxSize.
+func decodeRaw(dst, src []byte, maxSize int) ([]byte, error) {
+	size := 3 * len(src)
+	if size > maxSize {
+		size = maxSize
+	}
+
+	for {
+		if cap(dst) < size {
+			dst = make([]byte, size)
+		}
+		dst = dst[:cap(dst)]
+
+		n, err := lz4.UncompressBlock(src, dst)
+		if err == nil {
+			return dst[:n], nil
+		}
+		if len(dst) >= maxSize {
+			return dst[:0], err
+		}
+		size = 2 * len(dst)
+		if size > maxSize {
+			size = maxSize
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package lz4hadoop_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/pierrec/lz4/v4"
+	"github.com/segmentio/parquet-go/compress/lz4hadoop"
+)
+
+var testdata = bytes.Repeat([]byte("1234567890qwertyuiopasdfghjklzxcvbnm"), 10e3)
+
+func compressBlock(t *testing.T, data []byte) []byte {
+	t.Helper()
+	block := make([]byte, lz4.CompressBlockBound(len(data)))
+	n, err := lz4.CompressBlock(data, block, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return block[:n]
+}
+
+func TestDecodeMultipleChunks(t *testing.T) {
+	// Hadoop may split the uncompressed data of a block in multiple chunks
+	// which are compressed independently.
+	chunk1 := compressBlock(t, testdata[:1000])
+	chunk2 := compressBlock(t, testdata[1000:3000])
+
+	data := binary.BigEndian.AppendUint32(nil, 3000)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(chunk1)))
+	data = append(data, chunk1...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(chunk2)))
+	data = append(data, chunk2...)
+
+	codec := new(lz4hadoop.Codec)
+	got, err := codec.Decode(nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, testdata[:3000]) {
+		t.Error("content mismatch after decompressing")
+	}
+}
+
+func TestDecodeRawBlock(t *testing.T) {
+	// Older versions of parquet-cpp wrote LZ4 blocks without the framing.
+	codec := new(lz4hadoop.Codec)
+	got, err := codec.Decode(nil, compressBlock(t, testdata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, testdata) {
+		t.Error("content mismatch after decompressing")
+	}
+}
+
+func TestDecodeCorrupted(t *testing.T) {
+	codec := new(lz4hadoop.Codec)
+	if _, err := codec.Decode(nil, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 1, 0}); err == nil {
+		t.Error("decoding corrupted data did not fail")
+	}
+}
+
+func TestEncodeMultipleBlocks(t *testing.T) {
+	codec := new(lz4hadoop.Codec)
+	compressed, err := codec.Encode(nil, testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The uncompressed size of the first block must not exceed the buffer size
+	// of the Hadoop decompressors.
+	if size := binary.BigEndian.Uint32(compressed); size > 256*1024 {
+		t.Errorf("first block is too large: %d", size)
+	}
+
+	decompressed, err := codec.Decode(nil, compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, testdata) {
+		t.Error("content mismatch after compressing and decompressing")
+	}
+}
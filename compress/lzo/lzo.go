@@ -0,0 +1,34 @@
+// Package lzo implements the LZO parquet compression codec.
+//
+// The codec uses the LZO1X format within the block framing of Hadoop, which is
+// how the LzoCodec of hadoop-lzo used by parquet-mr writes compressed pages.
+// Both the compressor and decompressor are implemented in Go.
+package lzo
+
+import (
+	"github.com/segmentio/parquet-go/format"
+	"github.com/segmentio/parquet-go/internal/hadoop"
+)
+
+type Codec struct {
+}
+
+func (c *Codec) String() string {
+	return "LZO"
+}
+
+func (c *Codec) CompressionCodec() format.CompressionCodec {
+	return format.LZO
+}
+
+func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
+	return hadoop.Encode(dst, src, compress)
+}
+
+func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
+	return hadoop.Decode(dst, src, decompress)
+}
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	return hadoop.DecodeLimit(dst, src, limit, decompress)
+}
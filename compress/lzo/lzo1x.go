@@ -0,0 +1,298 @@
+package lzo
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// The LZO1X format is a sequence of instructions copying literals from the
+// input, or matches from the previously decompressed data. The first byte of
+// each instruction determines its kind:
+//
+//	0-15:    literal run, or match of 2 or 3 bytes after literals (M1)
+//	16-31:   match with a distance of 16385-49151 bytes (M4)
+//	32-63:   match with a distance of 1-16384 bytes (M3)
+//	64-255:  match of 3-8 bytes with a distance of 1-2048 bytes (M2)
+//
+// The two low bits of the last distance byte of matches hold the number of
+// literals (0-3) copied after the match, longer literal runs use their own
+// instruction. The stream ends with a M4 match of distance zero.
+//
+// http://www.oberhumer.com/opensource/lzo/
+const (
+	m2MaxOffset = 0x0800
+	m3MaxOffset = 0x4000
+	m4MaxOffset = 0xbfff
+
+	m2MaxLen = 8
+	m3MaxLen = 33
+	m4MaxLen = 9
+
+	m3Marker = 32
+	m4Marker = 16
+)
+
+var (
+	errInputOverrun      = errors.New("lzo: input overrun")
+	errOutputOverrun     = errors.New("lzo: output overrun")
+	errLookbehindOverrun = errors.New("lzo: lookbehind overrun")
+	errInputNotConsumed  = errors.New("lzo: input not consumed")
+)
+
+// decompress decompresses the LZO1X data of src to dst and returns the number
+// of bytes written. An error is returned if src is malformed, or if dst is too
+// short to hold the decompressed data.
+func decompress(dst, src []byte) (int, error) {
+	ip, op, state := 0, 0, 0
+
+	copyLiterals := func(n int) error {
+		if n > len(src)-ip {
+			return errInputOverrun
+		}
+		if n > len(dst)-op {
+			return errOutputOverrun
+		}
+		op += copy(dst[op:], src[ip:ip+n])
+		ip += n
+		return nil
+	}
+
+	readLength := func(length int) (int, error) {
+		for ip < len(src) && src[ip] == 0 {
+			length += 255
+			ip++
+		}
+		if ip == len(src) {
+			return 0, errInputOverrun
+		}
+		length += int(src[ip])
+		ip++
+		return length, nil
+	}
+
+	if len(src) > 0 && src[0] > 17 {
+		// The stream starts with a literal run of length src[0]-17.
+		n := int(src[0]) - 17
+		ip++
+		if err := copyLiterals(n); err != nil {
+			return op, err
+		}
+		if state = n; state > 4 {
+			state = 4
+		}
+	}
+
+	for {
+		if ip == len(src) {
+			return op, errInputOverrun
+		}
+		t := int(src[ip])
+		ip++
+
+		var length, distance, next int
+		var err error
+
+		switch {
+		case t >= 64: // M2
+			if ip == len(src) {
+				return op, errInputOverrun
+			}
+			length = (t >> 5) + 1
+			distance = 1 + ((t >> 2) & 7) + int(src[ip])<<3
+			next = t & 3
+			ip++
+
+		case t >= 32: // M3
+			if length = t & 31; length == 0 {
+				if length, err = readLength(31); err != nil {
+					return op, err
+				}
+			}
+			length += 2
+			if len(src)-ip < 2 {
+				return op, errInputOverrun
+			}
+			v := int(binary.LittleEndian.Uint16(src[ip:]))
+			distance = 1 + (v >> 2)
+			next = v & 3
+			ip += 2
+
+		case t >= 16: // M4
+			if length = t & 7; length == 0 {
+				if length, err = readLength(7); err != nil {
+					return op, err
+				}
+			}
+			length += 2
+			if len(src)-ip < 2 {
+				return op, errInputOverrun
+			}
+			v := int(binary.LittleEndian.Uint16(src[ip:]))
+			distance = (t&8)<<11 + (v >> 2)
+			next = v & 3
+			ip += 2
+
+			if distance == 0 { // end of stream
+				if ip != len(src) {
+					return op, errInputNotConsumed
+				}
+				return op, nil
+			}
+			distance += m3MaxOffset
+
+		case state == 0: // literal run
+			length = t
+			if length == 0 {
+				if length, err = readLength(15); err != nil {
+					return op, err
+				}
+			}
+			if err := copyLiterals(length + 3); err != nil {
+				return op, err
+			}
+			state = 4
+			continue
+
+		default: // M1
+			if ip == len(src) {
+				return op, errInputOverrun
+			}
+			if state == 4 {
+				length = 3
+				distance = 1 + m2MaxOffset + (t >> 2) + int(src[ip])<<2
+			} else {
+				length = 2
+				distance = 1 + (t >> 2) + int(src[ip])<<2
+			}
+			next = t & 3
+			ip++
+		}
+
+		if distance > op {
+			return op, errLookbehindOverrun
+		}
+		if length > len(dst)-op {
+			return op, errOutputOverrun
+		}
+		// The match may overlap with the output when the distance is shorter
+		// than the length, the bytes must be copied one at a time.
+		for i := op - distance; length > 0; length-- {
+			dst[op] = dst[i]
+			op++
+			i++
+		}
+
+		if err := copyLiterals(next); err != nil {
+			return op, err
+		}
+		state = next
+	}
+}
+
+const (
+	hashBits  = 14
+	tableSize = 1 << hashBits
+)
+
+var tables sync.Pool // *[tableSize]int32
+
+// compress writes the LZO1X compressed version of src to dst and returns it.
+// The compression uses a greedy search for matches of at least 4 bytes, like
+// the LZO1X-1 algorithm.
+func compress(dst, src []byte) ([]byte, error) {
+	table, _ := tables.Get().(*[tableSize]int32)
+	if table == nil {
+		table = new([tableSize]int32)
+	} else {
+		*table = [tableSize]int32{}
+	}
+	defer tables.Put(table)
+
+	dst = dst[:0]
+	ii := 0 // start of the pending literals
+	ip := 0
+
+	for ip <= len(src)-4 {
+		v := binary.LittleEndian.Uint32(src[ip:])
+		h := (v * 0x1e35a7bd) >> (32 - hashBits)
+		// Positions are stored with an offset of one so the zero value of the
+		// table represents empty slots.
+		m := int(table[h]) - 1
+		table[h] = int32(ip + 1)
+
+		if m < 0 || ip-m > m4MaxOffset || binary.LittleEndian.Uint32(src[m:]) != v {
+			// Skip faster over data which does not compress well.
+			ip += 1 + (ip-ii)>>5
+			continue
+		}
+
+		length := 4
+		for ip+length < len(src) && src[m+length] == src[ip+length] {
+			length++
+		}
+
+		dst = appendLiterals(dst, src[ii:ip])
+		dst = appendMatch(dst, ip-m, length)
+		ip += length
+		ii = ip
+	}
+
+	dst = appendLiterals(dst, src[ii:])
+	return append(dst, m4Marker|1, 0, 0), nil
+}
+
+func appendLiterals(dst, literals []byte) []byte {
+	switch n := len(literals); {
+	case n == 0:
+		return dst
+	case len(dst) == 0 && n <= 238:
+		dst = append(dst, byte(17+n))
+	case n <= 3:
+		// The number of literals is stored in the low bits of the previous
+		// match instruction.
+		dst[len(dst)-2] |= byte(n)
+	case n <= 18:
+		dst = append(dst, byte(n-3))
+	default:
+		dst = append(dst, 0)
+		dst = appendLength(dst, n-18)
+	}
+	return append(dst, literals...)
+}
+
+func appendMatch(dst []byte, distance, length int) []byte {
+	switch {
+	case length <= m2MaxLen && distance <= m2MaxOffset:
+		distance--
+		return append(dst, byte((length-1)<<5|(distance&7)<<2), byte(distance>>3))
+
+	case distance <= m3MaxOffset:
+		distance--
+		if length <= m3MaxLen {
+			dst = append(dst, byte(m3Marker|(length-2)))
+		} else {
+			dst = append(dst, m3Marker)
+			dst = appendLength(dst, length-m3MaxLen)
+		}
+
+	default:
+		distance -= m3MaxOffset
+		marker := byte(m4Marker | (distance>>11)&8)
+		if length <= m4MaxLen {
+			dst = append(dst, marker|byte(length-2))
+		} else {
+			dst = append(dst, marker)
+			dst = appendLength(dst, length-m4MaxLen)
+		}
+	}
+	return append(dst, byte(distance<<2), byte(distance>>6))
+}
+
+func appendLength(dst []byte, n int) []byte {
+	for n > 255 {
+		dst = append(dst, 0)
+		n -= 255
+	}
+	return append(dst, byte(n))
+}
@@ -0,0 +1,130 @@
+package lzo_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/segmentio/parquet-go/compress/lzo"
+)
+
+// frame wraps LZO1X data in a single Hadoop block of the given uncompressed
+// size.
+func frame(size int, data []byte) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(size))
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     []byte
+		want     []byte
+	}{
+		{
+			scenario: "literals",
+			data:     []byte{17 + 5, 'h', 'e', 'l', 'l', 'o', 0x11, 0, 0},
+			want:     []byte("hello"),
+		},
+
+		{
+			scenario: "overlapping match with distance 4",
+			// 4 literals, M2 match of 8 bytes at distance 4, 2 trailing literals
+			data: []byte{17 + 4, 'a', 'b', 'c', 'd', (8-1)<<5 | 3<<2 | 2, 0, 'x', 'y', 0x11, 0, 0},
+			want: []byte("abcdabcdabcdxy"),
+		},
+
+		{
+			scenario: "long literal run",
+			// the literal run uses the extended length encoding: 18+255+2
+			data: append(append([]byte{0, 0, 2}, bytes.Repeat([]byte{'z'}, 275)...), 0x11, 0, 0),
+			want: bytes.Repeat([]byte{'z'}, 275),
+		},
+
+		{
+			scenario: "long match with distance 1",
+			// 1 literal, M3 match of 33+255+1 bytes at distance 1
+			data: []byte{17 + 1, 'a', 32, 0, 1, 0, 0, 0x11, 0, 0},
+			want: bytes.Repeat([]byte{'a'}, 1+33+255+1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			codec := new(lzo.Codec)
+			got, err := codec.Decode(nil, frame(len(test.want), test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("decoded data mismatch:\nwant = %q\ngot  = %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		{},                                   // missing end of stream
+		{17 + 5, 'h', 'e', 0x11, 0, 0},       // truncated literals
+		{17 + 1, 'a', 64, 1, 0x11, 0, 0},     // match before the start of the output
+		{17 + 1, 'a', 0x11, 0, 0, 0},         // trailing data
+		{17 + 1, 'a', (8-1)<<5 | 0, 0, 0x11}, // truncated end of stream
+	} {
+		codec := new(lzo.Codec)
+		if _, err := codec.Decode(nil, frame(64, data)); err == nil {
+			t.Errorf("decoding %v did not fail", data)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	prng := rand.New(rand.NewSource(0))
+	random := make([]byte, 300e3)
+	prng.Read(random)
+
+	// Repeat sequences at distances covering the ranges of all match kinds.
+	repeated := make([]byte, 0, 400e3)
+	for len(repeated) < 300e3 {
+		n := 1 + prng.Intn(40e3)
+		if n > len(repeated) {
+			repeated = append(repeated, random[:n]...)
+		} else {
+			offset := len(repeated) - n
+			repeated = append(repeated, repeated[offset:offset+1+prng.Intn(n)]...)
+		}
+	}
+
+	for _, test := range []struct {
+		scenario string
+		data     []byte
+	}{
+		{"empty", nil},
+		{"one byte", []byte{1}},
+		{"three bytes", []byte{1, 2, 3}},
+		{"zeros", make([]byte, 200e3)},
+		{"random", random},
+		{"repeated", repeated},
+		{"text", bytes.Repeat([]byte("1234567890qwertyuiopasdfghjklzxcvbnm"), 10e3)},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			codec := new(lzo.Codec)
+
+			compressed, err := codec.Encode(nil, test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decompressed, err := codec.Decode(nil, compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(decompressed, test.data) {
+				t.Error("data mismatch after compressing and decompressing")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package snappy
 
 import (
 	"github.com/klauspost/compress/snappy"
+	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/format"
 )
 
@@ -29,3 +30,16 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
 	return snappy.Decode(dst, src)
 }
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	// The snappy block format starts with the length of the uncompressed data,
+	// which is the size of the buffer allocated by snappy.Decode.
+	n, err := snappy.DecodedLen(src)
+	if err != nil {
+		return dst[:0], err
+	}
+	if n > limit {
+		return dst[:0], compress.ErrDecodeLimit
+	}
+	return snappy.Decode(dst, src)
+}
@@ -4,6 +4,7 @@
 package uncompressed
 
 import (
+	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/format"
 )
 
@@ -25,3 +26,10 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
 	return append(dst[:0], src...), nil
 }
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	if len(src) > limit {
+		return dst[:0], compress.ErrDecodeLimit
+	}
+	return c.Decode(dst, src)
+}
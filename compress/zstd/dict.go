@@ -0,0 +1,350 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"sort"
+
+	"github.com/klauspost/compress/huff0"
+)
+
+const (
+	// MinDictionarySize is the smallest dictionary size accepted by
+	// TrainDictionary.
+	MinDictionarySize = 256
+
+	// DefaultDictionarySize is a dictionary size which works well for pages of
+	// a few kilobytes, it is the default of the reference zstd implementation.
+	DefaultDictionarySize = 112640
+
+	// Size of the sequences of bytes used to measure how frequently a segment
+	// of the samples repeats across samples.
+	dmerSize = 8
+
+	// Size of the segments of samples copied to the dictionary content.
+	segmentSize = 64
+
+	// Limit to the amount of samples used to train a dictionary, relative to
+	// the dictionary size.
+	maxSamplesRatio = 32
+)
+
+var (
+	errDictionarySize  = errors.New("zstd: dictionary size is too small")
+	errNotEnoughSample = errors.New("zstd: not enough samples to train a dictionary")
+)
+
+// Magic number and repeat offsets of zstd dictionaries.
+//
+// https://github.com/facebook/zstd/blob/dev/doc/zstd_compression_format.md#dictionary-format
+var (
+	dictMagic   = [4]byte{0x37, 0xa4, 0x30, 0xec}
+	dictOffsets = [3]uint32{1, 4, 8}
+)
+
+// Default distributions of the literal lengths, match lengths, and offsets
+// FSE tables of the zstd format, and their accuracy.
+var (
+	literalLengthsAccuracy = 6
+	literalLengthsNorm     = [...]int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}
+
+	matchLengthsAccuracy = 6
+	matchLengthsNorm     = [...]int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}
+
+	offsetsAccuracy = 5
+	offsetsNorm     = [...]int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}
+)
+
+// TrainDictionary builds a zstd dictionary of at most size bytes from the
+// given samples, which are typically the uncompressed content of pages of a
+// column.
+//
+// The dictionary content is made of the segments of samples which repeat the
+// most across samples, the entropy tables are derived from the samples as
+// well. Samples beyond 32 times the dictionary size are ignored.
+func TrainDictionary(samples [][]byte, size int) ([]byte, error) {
+	if size < MinDictionarySize {
+		return nil, errDictionarySize
+	}
+
+	limit := maxSamplesRatio * size
+	total := 0
+	for i, sample := range samples {
+		if total += len(sample); total > limit {
+			samples = append(samples[:i:i], sample[:len(sample)-(total-limit)])
+			break
+		}
+	}
+
+	header, err := appendEntropyTables(nil, samples)
+	if err != nil {
+		return nil, err
+	}
+
+	content := selectSegments(samples, size-(len(header)+8))
+	if len(content) < int(dictOffsets[2]) {
+		return nil, errNotEnoughSample
+	}
+
+	dict := make([]byte, 0, 8+len(header)+len(content))
+	dict = append(dict, dictMagic[:]...)
+	dict = binary.LittleEndian.AppendUint32(dict, dictionaryID(content))
+	dict = append(dict, header...)
+	return append(dict, content...), nil
+}
+
+// dictionaryID derives the dictionary ID from its content, within the range
+// of IDs that the zstd format reserves for private use.
+func dictionaryID(content []byte) uint32 {
+	const minID, maxID = 1 << 15, 1 << 31
+	return minID + crc32.ChecksumIEEE(content)%(maxID-minID)
+}
+
+// appendEntropyTables appends the Huffman table of literals, the FSE tables of
+// offsets, match lengths, and literal lengths, and the repeat offsets of a
+// dictionary to b.
+func appendEntropyTables(b []byte, samples [][]byte) ([]byte, error) {
+	// The Huffman table is built from the distribution of bytes in samples,
+	// with all byte values included so the table can encode any literal.
+	literals := make([]byte, 0, huff0.BlockSizeMax)
+	for i := 0; i < 256; i++ {
+		literals = append(literals, byte(i))
+	}
+	for _, sample := range samples {
+		if n := cap(literals) - len(literals); len(sample) > n {
+			literals = append(literals, sample[:n]...)
+			break
+		}
+		literals = append(literals, sample...)
+	}
+
+	table, err := huffmanTable(literals)
+	if err != nil {
+		// The samples may not compress with Huffman coding (e.g. random
+		// bytes), fall back to a distribution favoring small byte values.
+		literals = append(literals[:256], make([]byte, 4096)...)
+		if table, err = huffmanTable(literals); err != nil {
+			return b, err
+		}
+	}
+	b = append(b, table...)
+
+	// There are no sequences in the samples to derive the distributions of
+	// offsets and lengths from, the default distributions are used instead.
+	b = appendNCount(b, offsetsNorm[:], offsetsAccuracy)
+	b = appendNCount(b, matchLengthsNorm[:], matchLengthsAccuracy)
+	b = appendNCount(b, literalLengthsNorm[:], literalLengthsAccuracy)
+
+	for _, offset := range dictOffsets {
+		b = binary.LittleEndian.AppendUint32(b, offset)
+	}
+	return b, nil
+}
+
+func huffmanTable(literals []byte) ([]byte, error) {
+	s := &huff0.Scratch{TableLog: 11}
+	if _, _, err := huff0.Compress1X(literals, s); err != nil {
+		return nil, err
+	}
+	return s.OutTable, nil
+}
+
+// appendNCount appends the representation of the normalized distribution
+// norm of a FSE table of the given accuracy to b.
+//
+// https://github.com/facebook/zstd/blob/dev/doc/zstd_compression_format.md#fse-table-description
+func appendNCount(b []byte, norm []int16, accuracy int) []byte {
+	const minAccuracy = 5
+	tableSize := 1 << accuracy
+	remaining := tableSize + 1
+	threshold := tableSize
+	nbBits := accuracy + 1
+
+	bitStream := uint32(accuracy - minAccuracy)
+	bitCount := 4
+
+	flush := func() {
+		b = append(b, byte(bitStream), byte(bitStream>>8))
+		bitStream >>= 16
+		bitCount -= 16
+	}
+
+	previousIs0 := false
+	for symbol := 0; symbol < len(norm) && remaining > 1; {
+		if previousIs0 {
+			start := symbol
+			for symbol < len(norm) && norm[symbol] == 0 {
+				symbol++
+			}
+			if symbol == len(norm) {
+				break
+			}
+			for symbol >= start+24 {
+				start += 24
+				bitStream += 0xFFFF << bitCount
+				bitCount += 16
+				flush()
+			}
+			for symbol >= start+3 {
+				start += 3
+				bitStream += 3 << bitCount
+				bitCount += 2
+			}
+			bitStream += uint32(symbol-start) << bitCount
+			bitCount += 2
+			if bitCount > 16 {
+				flush()
+			}
+		}
+
+		count := int(norm[symbol])
+		symbol++
+		max := (2*threshold - 1) - remaining
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		count++ // +1 for extra accuracy
+		if count >= threshold {
+			count += max
+		}
+		bitStream += uint32(count) << bitCount
+		bitCount += nbBits
+		if count < max {
+			bitCount--
+		}
+		previousIs0 = count == 1
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+		if bitCount > 16 {
+			flush()
+		}
+	}
+
+	for ; bitCount > 0; bitCount -= 8 {
+		b = append(b, byte(bitStream))
+		bitStream >>= 8
+	}
+	return b
+}
+
+// selectSegments returns the dictionary content built from the segments of
+// samples which contain the byte sequences found in the largest number of
+// samples, the size of the content does not exceed size.
+//
+// The algorithm is a simplified version of the COVER algorithm of the
+// reference implementation: the samples are divided in epochs, and the best
+// segment of each epoch is added to the dictionary. The byte sequences of
+// selected segments do not contribute to the score of other segments.
+func selectSegments(samples [][]byte, size int) []byte {
+	if size <= 0 {
+		return nil
+	}
+
+	// Count the number of samples that each sequence of bytes appears in.
+	type dmerCount struct {
+		count  int32
+		sample int32
+	}
+	counts := make(map[uint64]dmerCount)
+	for i, sample := range samples {
+		for j := 0; j+dmerSize <= len(sample); j++ {
+			dmer := binary.LittleEndian.Uint64(sample[j:])
+			c, ok := counts[dmer]
+			if !ok || c.sample != int32(i) {
+				counts[dmer] = dmerCount{count: c.count + 1, sample: int32(i)}
+			}
+		}
+	}
+
+	total := 0
+	for _, sample := range samples {
+		if len(sample) >= segmentSize {
+			total += len(sample) - segmentSize + 1
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	numEpochs := (size + segmentSize - 1) / segmentSize
+	epochSize := (total + numEpochs - 1) / numEpochs
+
+	type segment struct {
+		data  []byte
+		score int
+	}
+	segments := make([]segment, 0, numEpochs)
+	sampleIndex, offset := 0, 0
+
+	for epoch := 0; epoch < numEpochs && sampleIndex < len(samples); epoch++ {
+		best := segment{}
+
+		// Scan the segments starting at the next epochSize positions, which
+		// may span multiple samples.
+		for n := epochSize; n > 0 && sampleIndex < len(samples); {
+			sample := samples[sampleIndex]
+			end := len(sample) - segmentSize + 1
+			if offset >= end {
+				sampleIndex, offset = sampleIndex+1, 0
+				continue
+			}
+
+			score := 0
+			for j := offset; j < offset+segmentSize-dmerSize+1; j++ {
+				score += int(counts[binary.LittleEndian.Uint64(sample[j:])].count)
+			}
+			for {
+				if score > best.score {
+					best = segment{data: sample[offset : offset+segmentSize], score: score}
+				}
+				if offset, n = offset+1, n-1; offset == end || n == 0 {
+					break
+				}
+				score -= int(counts[binary.LittleEndian.Uint64(sample[offset-1:])].count)
+				score += int(counts[binary.LittleEndian.Uint64(sample[offset+segmentSize-dmerSize:])].count)
+			}
+		}
+
+		// Segments made of sequences found in a single sample do not help
+		// compress other pages.
+		if best.score <= segmentSize-dmerSize+1 {
+			continue
+		}
+		for j := 0; j+dmerSize <= len(best.data); j++ {
+			counts[binary.LittleEndian.Uint64(best.data[j:])] = dmerCount{sample: -1}
+		}
+		segments = append(segments, best)
+	}
+
+	// Offsets to the end of the dictionary are the cheapest to encode, the
+	// best segments are placed last.
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].score < segments[j].score
+	})
+
+	content := make([]byte, 0, size)
+	for _, s := range segments {
+		if len(content)+len(s.data) > size {
+			break
+		}
+		content = append(content, s.data...)
+	}
+	return content
+}
@@ -0,0 +1,100 @@
+package zstd_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/segmentio/parquet-go/compress/zstd"
+)
+
+// pages generates small pages of short JSON documents, similar to the content
+// of a column holding JSON strings.
+func pages(prng *rand.Rand, n int) [][]byte {
+	pages := make([][]byte, n)
+	for i := range pages {
+		var page []byte
+		for j := 0; j < 10; j++ {
+			page = fmt.Appendf(page, `{"user_id":%d,"event":"page_view","properties":{"path":"/products/%d","referrer":"https://www.example.com/"}}`,
+				prng.Int63(), prng.Intn(1000))
+		}
+		pages[i] = page
+	}
+	return pages
+}
+
+func TestTrainDictionary(t *testing.T) {
+	prng := rand.New(rand.NewSource(0))
+
+	dict, err := zstd.TrainDictionary(pages(prng, 1000), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dict) > 4096 {
+		t.Errorf("dictionary is larger than requested: %d > 4096", len(dict))
+	}
+
+	withDict := &zstd.Codec{Dictionary: dict}
+	withoutDict := &zstd.Codec{}
+	sizeWithDict, sizeWithoutDict := 0, 0
+
+	for _, page := range pages(prng, 100) {
+		compressed, err := withDict.Encode(nil, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizeWithDict += len(compressed)
+
+		decompressed, err := withDict.Decode(nil, compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decompressed) != string(page) {
+			t.Fatal("content mismatch after compressing and decompressing")
+		}
+
+		if _, err := withoutDict.Decode(nil, compressed); err == nil {
+			t.Error("decompressing without the dictionary did not fail")
+		}
+
+		compressed, err = withoutDict.Encode(nil, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizeWithoutDict += len(compressed)
+
+		// Pages compressed without the dictionary can be decompressed by the
+		// codec using one.
+		decompressed, err = withDict.Decode(nil, compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decompressed) != string(page) {
+			t.Fatal("content mismatch after compressing and decompressing")
+		}
+	}
+
+	if sizeWithDict >= sizeWithoutDict {
+		t.Errorf("compression did not improve with the dictionary: %d >= %d", sizeWithDict, sizeWithoutDict)
+	}
+	t.Logf("compressed size: %d with dictionary, %d without", sizeWithDict, sizeWithoutDict)
+}
+
+func TestTrainDictionaryRandomSamples(t *testing.T) {
+	prng := rand.New(rand.NewSource(0))
+	samples := make([][]byte, 100)
+	for i := range samples {
+		samples[i] = make([]byte, 1000)
+		prng.Read(samples[i])
+	}
+	// Random samples have no repeated segments to build a dictionary from.
+	if _, err := zstd.TrainDictionary(samples, 4096); err == nil {
+		t.Error("training a dictionary from random samples did not fail")
+	}
+}
+
+func TestTrainDictionaryInvalidSize(t *testing.T) {
+	if _, err := zstd.TrainDictionary(pages(rand.New(rand.NewSource(0)), 10), 16); err == nil {
+		t.Error("training a dictionary of 16 bytes did not fail")
+	}
+}
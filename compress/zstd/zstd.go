@@ -2,9 +2,12 @@
 package zstd
 
 import (
+	"bytes"
+	"io"
 	"sync"
 
 	"github.com/klauspost/compress/zstd"
+	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/format"
 )
 
@@ -33,9 +36,24 @@ const (
 	DefaultLevel = SpeedDefault
 )
 
+// LevelFromZstd returns the compression level closest to the given level of
+// the reference zstd implementation (1-22).
+func LevelFromZstd(level int) Level {
+	return zstd.EncoderLevelFromZstd(level)
+}
+
 type Codec struct {
 	Level Level
 
+	// Dictionary is a zstd dictionary used to compress and decompress pages.
+	// Dictionaries improve the compression of small pages holding similar
+	// values, see TrainDictionary to build one.
+	//
+	// The same dictionary must be used to decompress the pages, pages which
+	// were compressed without a dictionary can still be decompressed when it
+	// is set.
+	Dictionary []byte
+
 	encoders sync.Pool // *zstd.Encoder
 	decoders sync.Pool // *zstd.Decoder
 }
@@ -52,12 +70,16 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 	e, _ := c.encoders.Get().(*zstd.Encoder)
 	if e == nil {
 		var err error
-		e, err = zstd.NewWriter(nil,
+		options := []zstd.EOption{
 			zstd.WithEncoderConcurrency(1),
 			zstd.WithEncoderLevel(c.level()),
 			zstd.WithZeroFrames(true),
 			zstd.WithEncoderCRC(false),
-		)
+		}
+		if len(c.Dictionary) != 0 {
+			options = append(options, zstd.WithEncoderDict(c.Dictionary))
+		}
+		e, err = zstd.NewWriter(nil, options...)
 		if err != nil {
 			return dst[:0], err
 		}
@@ -67,18 +89,56 @@ func (c *Codec) Encode(dst, src []byte) ([]byte, error) {
 }
 
 func (c *Codec) Decode(dst, src []byte) ([]byte, error) {
+	d, err := c.decoder()
+	if err != nil {
+		return dst[:0], err
+	}
+	defer c.decoders.Put(d)
+	return d.DecodeAll(src, dst[:0])
+}
+
+func (c *Codec) DecodeLimit(dst, src []byte, limit int) ([]byte, error) {
+	d, err := c.decoder()
+	if err != nil {
+		return dst[:0], err
+	}
+	defer c.decoders.Put(d)
+
+	// DecodeAll grows the output to the size of the frames, which may be
+	// arbitrarily larger than the limit. Streaming the frames block by block
+	// allows stopping as soon as one byte past the limit has been produced.
+	//
+	// A bytes.Reader is used because the decoder detects bytes.Buffer inputs
+	// and calls DecodeAll on them.
+	if err := d.Reset(bytes.NewReader(src)); err != nil {
+		return dst[:0], err
+	}
+	defer d.Reset(nil)
+
+	buf := bytes.NewBuffer(dst[:0])
+	_, err = buf.ReadFrom(io.LimitReader(d, int64(limit)+1))
+	dst = buf.Bytes()
+	if err != nil {
+		return dst[:0], err
+	}
+	if len(dst) > limit {
+		return dst[:0], compress.ErrDecodeLimit
+	}
+	return dst, nil
+}
+
+func (c *Codec) decoder() (*zstd.Decoder, error) {
 	d, _ := c.decoders.Get().(*zstd.Decoder)
 	if d == nil {
-		var err error
-		d, err = zstd.NewReader(nil,
+		options := []zstd.DOption{
 			zstd.WithDecoderConcurrency(1),
-		)
-		if err != nil {
-			return dst[:0], err
 		}
+		if len(c.Dictionary) != 0 {
+			options = append(options, zstd.WithDecoderDicts(c.Dictionary))
+		}
+		return zstd.NewReader(nil, options...)
 	}
-	defer c.decoders.Put(d)
-	return d.DecodeAll(src, dst[:0])
+	return d, nil
 }
 
 func (c *Codec) level() Level {
@@ -6,6 +6,8 @@ import (
 	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/segmentio/parquet-go/compress"
 )
@@ -29,6 +31,10 @@ const (
 	DefaultSkipBloomFilters     = false
 	DefaultMaxRowsPerRowGroup   = math.MaxInt64
 	DefaultReadMode             = ReadModeSync
+	DefaultCSVComma             = ','
+	DefaultCSVSampleSize        = 1000
+	DefaultCSVTimestampFormat   = time.RFC3339Nano
+	DefaultDedupeMaxKeys        = 1024 * 1024
 )
 
 const (
@@ -95,6 +101,36 @@ type FileConfig struct {
 	ReadBufferSize   int
 	ReadMode         ReadMode
 	Schema           *Schema
+	Limits           ReadLimits
+}
+
+// ReadLimits carries limits to the resources that reading a parquet file may
+// consume. Programs reading files from untrusted sources should configure them
+// since a crafted file may declare arbitrarily large sizes and counts in its
+// metadata and page headers.
+//
+// The limits are checked against the values declared in the file, before
+// allocating memory for them. Violations are reported by errors of type
+// *LimitError. Zero values mean that there are no limits.
+type ReadLimits struct {
+	// Maximum size of the file footer, and of the page index of the file.
+	MaxFooterSize int64
+	// Maximum size of pages after decompression. Pages may not declare more
+	// values than this size either, since decoding them allocates memory
+	// proportional to the number of values.
+	MaxPageSize int64
+	// Maximum number of bytes decompressed when reading the pages of a column
+	// chunk. Seeking within the column chunk resets the count.
+	MaxChunkSize int64
+	// Maximum number of values of dictionary pages.
+	MaxDictionaryValues int64
+	// Maximum nesting depth of columns, the columns of a flat schema have a
+	// depth of one.
+	MaxSchemaDepth int
+	// Maximum number of leaf columns in the schema.
+	MaxColumns int
+	// Maximum number of rows of the file and of the pages it contains.
+	MaxRows int64
 }
 
 // DefaultFileConfig returns a new FileConfig value initialized with the
@@ -135,12 +171,22 @@ func (c *FileConfig) ConfigureFile(config *FileConfig) {
 		ReadBufferSize:   coalesceInt(c.ReadBufferSize, config.ReadBufferSize),
 		ReadMode:         ReadMode(coalesceInt(int(c.ReadMode), int(config.ReadMode))),
 		Schema:           coalesceSchema(c.Schema, config.Schema),
+		Limits:           coalesceReadLimits(c.Limits, config.Limits),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *FileConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*FileConfig).Limits."
+	return errorInvalidConfiguration(
+		validateNonNegativeInt64(baseName+"MaxFooterSize", c.Limits.MaxFooterSize),
+		validateNonNegativeInt64(baseName+"MaxPageSize", c.Limits.MaxPageSize),
+		validateNonNegativeInt64(baseName+"MaxChunkSize", c.Limits.MaxChunkSize),
+		validateNonNegativeInt64(baseName+"MaxDictionaryValues", c.Limits.MaxDictionaryValues),
+		validateNonNegativeInt64(baseName+"MaxSchemaDepth", int64(c.Limits.MaxSchemaDepth)),
+		validateNonNegativeInt64(baseName+"MaxColumns", int64(c.Limits.MaxColumns)),
+		validateNonNegativeInt64(baseName+"MaxRows", c.Limits.MaxRows),
+	)
 }
 
 // The ReaderConfig type carries configuration options for parquet readers.
@@ -200,19 +246,22 @@ func (c *ReaderConfig) Validate() error {
 //		CreatedBy: "my test program",
 //	})
 type WriterConfig struct {
-	CreatedBy            string
-	ColumnPageBuffers    BufferPool
-	ColumnIndexSizeLimit int
-	PageBufferSize       int
-	WriteBufferSize      int
-	DataPageVersion      int
-	DataPageStatistics   bool
-	MaxRowsPerRowGroup   int64
-	KeyValueMetadata     map[string]string
-	Schema               *Schema
-	BloomFilters         []BloomFilterColumn
-	Compression          compress.Codec
-	Sorting              SortingConfig
+	CreatedBy             string
+	ColumnPageBuffers     BufferPool
+	ColumnIndexSizeLimit  int
+	PageBufferSize        int
+	WriteBufferSize       int
+	DataPageVersion       int
+	DataPageStatistics    bool
+	MinCompressionSavings int
+	MaxRowsPerRowGroup    int64
+	KeyValueMetadata      map[string]string
+	Schema                *Schema
+	BloomFilters          []BloomFilterColumn
+	Compression           compress.Codec
+	ZstdDictionary        []byte
+	Sorting               SortingConfig
+	Compatibility         Compatibility
 }
 
 // DefaultWriterConfig returns a new WriterConfig value initialized with the
@@ -264,19 +313,22 @@ func (c *WriterConfig) ConfigureWriter(config *WriterConfig) {
 	}
 
 	*config = WriterConfig{
-		CreatedBy:            coalesceString(c.CreatedBy, config.CreatedBy),
-		ColumnPageBuffers:    coalesceBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
-		ColumnIndexSizeLimit: coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
-		PageBufferSize:       coalesceInt(c.PageBufferSize, config.PageBufferSize),
-		WriteBufferSize:      coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
-		DataPageVersion:      coalesceInt(c.DataPageVersion, config.DataPageVersion),
-		DataPageStatistics:   config.DataPageStatistics,
-		MaxRowsPerRowGroup:   config.MaxRowsPerRowGroup,
-		KeyValueMetadata:     keyValueMetadata,
-		Schema:               coalesceSchema(c.Schema, config.Schema),
-		BloomFilters:         coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
-		Compression:          coalesceCompression(c.Compression, config.Compression),
-		Sorting:              coalesceSortingConfig(c.Sorting, config.Sorting),
+		CreatedBy:             coalesceString(c.CreatedBy, config.CreatedBy),
+		ColumnPageBuffers:     coalesceBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
+		ColumnIndexSizeLimit:  coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
+		PageBufferSize:        coalesceInt(c.PageBufferSize, config.PageBufferSize),
+		WriteBufferSize:       coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
+		DataPageVersion:       coalesceInt(c.DataPageVersion, config.DataPageVersion),
+		DataPageStatistics:    config.DataPageStatistics,
+		MinCompressionSavings: coalesceInt(c.MinCompressionSavings, config.MinCompressionSavings),
+		MaxRowsPerRowGroup:    config.MaxRowsPerRowGroup,
+		KeyValueMetadata:      keyValueMetadata,
+		Schema:                coalesceSchema(c.Schema, config.Schema),
+		BloomFilters:          coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
+		Compression:           coalesceCompression(c.Compression, config.Compression),
+		ZstdDictionary:        coalesceBytes(c.ZstdDictionary, config.ZstdDictionary),
+		Sorting:               coalesceSortingConfig(c.Sorting, config.Sorting),
+		Compatibility:         coalesceCompatibility(c.Compatibility, config.Compatibility),
 	}
 }
 
@@ -288,6 +340,7 @@ func (c *WriterConfig) Validate() error {
 		validatePositiveInt(baseName+"ColumnIndexSizeLimit", c.ColumnIndexSizeLimit),
 		validatePositiveInt(baseName+"PageBufferSize", c.PageBufferSize),
 		validateOneOfInt(baseName+"DataPageVersion", c.DataPageVersion, 1, 2),
+		validateRangeInt(baseName+"MinCompressionSavings", c.MinCompressionSavings, 0, 100),
 		c.Sorting.Validate(),
 	)
 }
@@ -404,6 +457,203 @@ func (c *SortingConfig) ConfigureSorting(config *SortingConfig) {
 	*config = coalesceSortingConfig(*c, *config)
 }
 
+// The DedupeConfig type carries configuration options for hash-based
+// deduplication of parquet rows.
+//
+// DedupeConfig implements the DedupeOption interface so it can be used
+// directly as argument to the HashDedupeRowReader function when needed,
+// for example:
+//
+//	reader := parquet.HashDedupeRowReader(rows, keyColumns, &parquet.DedupeConfig{
+//		Buffers: parquet.NewFileBufferPool("", "dedupe.*"),
+//	})
+type DedupeConfig struct {
+	Buffers BufferPool
+	MaxKeys int
+}
+
+// DefaultDedupeConfig returns a new DedupeConfig value initialized with the
+// default deduplication configuration.
+func DefaultDedupeConfig() *DedupeConfig {
+	return &DedupeConfig{
+		Buffers: &defaultDedupeBufferPool,
+		MaxKeys: DefaultDedupeMaxKeys,
+	}
+}
+
+// NewDedupeConfig constructs a new deduplication configuration applying the
+// options passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewDedupeConfig(options ...DedupeOption) (*DedupeConfig, error) {
+	config := DefaultDedupeConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *DedupeConfig) Validate() error {
+	const baseName = "parquet.(*DedupeConfig)."
+	return errorInvalidConfiguration(
+		validateNotNil(baseName+"Buffers", c.Buffers),
+		validatePositiveInt(baseName+"MaxKeys", c.MaxKeys),
+	)
+}
+
+// Apply applies the given list of options to c.
+func (c *DedupeConfig) Apply(options ...DedupeOption) {
+	for _, opt := range options {
+		opt.ConfigureDedupe(c)
+	}
+}
+
+// ConfigureDedupe applies configuration options from c to config.
+func (c *DedupeConfig) ConfigureDedupe(config *DedupeConfig) {
+	*config = DedupeConfig{
+		Buffers: coalesceBufferPool(c.Buffers, config.Buffers),
+		MaxKeys: coalesceInt(c.MaxKeys, config.MaxKeys),
+	}
+}
+
+// The CSVConfig type carries configuration options for CSV readers and
+// writers.
+//
+// CSVConfig implements the CSVOption interface so it can be used directly as
+// argument to the NewCSVReader and NewCSVWriter functions when needed, for
+// example:
+//
+//	reader, err := parquet.NewCSVReader(input, &parquet.CSVConfig{
+//		SampleSize: 100,
+//	})
+type CSVConfig struct {
+	Comma           rune
+	SampleSize      int
+	NullString      string
+	TimestampFormat string
+	Schema          *Schema
+}
+
+// DefaultCSVConfig returns a new CSVConfig value initialized with the default
+// CSV configuration.
+func DefaultCSVConfig() *CSVConfig {
+	return &CSVConfig{
+		Comma:           DefaultCSVComma,
+		SampleSize:      DefaultCSVSampleSize,
+		TimestampFormat: DefaultCSVTimestampFormat,
+	}
+}
+
+// NewCSVConfig constructs a new CSV configuration applying the options passed
+// as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewCSVConfig(options ...CSVOption) (*CSVConfig, error) {
+	config := DefaultCSVConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *CSVConfig) Validate() error {
+	const baseName = "parquet.(*CSVConfig)."
+	return errorInvalidConfiguration(
+		validatePositiveInt(baseName+"SampleSize", c.SampleSize),
+		validateCSVComma(baseName+"Comma", c.Comma),
+	)
+}
+
+// Apply applies the given list of options to c.
+func (c *CSVConfig) Apply(options ...CSVOption) {
+	for _, opt := range options {
+		opt.ConfigureCSV(c)
+	}
+}
+
+// ConfigureCSV applies configuration options from c to config.
+func (c *CSVConfig) ConfigureCSV(config *CSVConfig) {
+	comma := config.Comma
+	if c.Comma != 0 {
+		comma = c.Comma
+	}
+	*config = CSVConfig{
+		Comma:           comma,
+		SampleSize:      coalesceInt(c.SampleSize, config.SampleSize),
+		NullString:      coalesceString(c.NullString, config.NullString),
+		TimestampFormat: coalesceString(c.TimestampFormat, config.TimestampFormat),
+		Schema:          coalesceSchema(c.Schema, config.Schema),
+	}
+}
+
+// The JSONSchemaConfig type carries configuration options for the inference of
+// schemas from JSON documents.
+//
+// JSONSchemaConfig implements the JSONSchemaOption interface so it can be used
+// directly as argument to the InferJSONSchema function when needed, for
+// example:
+//
+//	schema, err := parquet.InferJSONSchema(input, &parquet.JSONSchemaConfig{
+//		HeterogeneousObjectsAsJSON: true,
+//	})
+type JSONSchemaConfig struct {
+	HeterogeneousObjectsAsJSON bool
+}
+
+// DefaultJSONSchemaConfig returns a new JSONSchemaConfig value initialized with
+// the default JSON schema inference configuration.
+func DefaultJSONSchemaConfig() *JSONSchemaConfig {
+	return &JSONSchemaConfig{}
+}
+
+// Apply applies the given list of options to c.
+func (c *JSONSchemaConfig) Apply(options ...JSONSchemaOption) {
+	for _, opt := range options {
+		opt.ConfigureJSONSchema(c)
+	}
+}
+
+// ConfigureJSONSchema applies configuration options from c to config.
+func (c *JSONSchemaConfig) ConfigureJSONSchema(config *JSONSchemaConfig) {
+	*config = JSONSchemaConfig{
+		HeterogeneousObjectsAsJSON: c.HeterogeneousObjectsAsJSON,
+	}
+}
+
+// The JSONLinesReaderConfig type carries configuration options for readers of
+// JSON Lines.
+//
+// JSONLinesReaderConfig implements the JSONLinesReaderOption interface so it
+// can be used directly as argument to the NewJSONLinesReader function when
+// needed, for example:
+//
+//	reader := parquet.NewJSONLinesReader(input, schema, &parquet.JSONLinesReaderConfig{
+//		ScalarsAsStrings: true,
+//	})
+type JSONLinesReaderConfig struct {
+	ScalarsAsStrings bool
+}
+
+// DefaultJSONLinesReaderConfig returns a new JSONLinesReaderConfig value
+// initialized with the default JSON Lines reader configuration.
+func DefaultJSONLinesReaderConfig() *JSONLinesReaderConfig {
+	return &JSONLinesReaderConfig{}
+}
+
+// Apply applies the given list of options to c.
+func (c *JSONLinesReaderConfig) Apply(options ...JSONLinesReaderOption) {
+	for _, opt := range options {
+		opt.ConfigureJSONLinesReader(c)
+	}
+}
+
+// ConfigureJSONLinesReader applies configuration options from c to config.
+func (c *JSONLinesReaderConfig) ConfigureJSONLinesReader(config *JSONLinesReaderConfig) {
+	*config = JSONLinesReaderConfig{
+		ScalarsAsStrings: c.ScalarsAsStrings,
+	}
+}
+
 // FileOption is an interface implemented by types that carry configuration
 // options for parquet files.
 type FileOption interface {
@@ -434,6 +684,30 @@ type SortingOption interface {
 	ConfigureSorting(*SortingConfig)
 }
 
+// DedupeOption is an interface implemented by types that carry configuration
+// options for the deduplication of parquet rows.
+type DedupeOption interface {
+	ConfigureDedupe(*DedupeConfig)
+}
+
+// CSVOption is an interface implemented by types that carry configuration
+// options for CSV readers and writers.
+type CSVOption interface {
+	ConfigureCSV(*CSVConfig)
+}
+
+// JSONSchemaOption is an interface implemented by types that carry
+// configuration options for the inference of schemas from JSON documents.
+type JSONSchemaOption interface {
+	ConfigureJSONSchema(*JSONSchemaConfig)
+}
+
+// JSONLinesReaderOption is an interface implemented by types that carry
+// configuration options for readers of JSON Lines.
+type JSONLinesReaderOption interface {
+	ConfigureJSONLinesReader(*JSONLinesReaderConfig)
+}
+
 // SkipPageIndex is a file configuration option which prevents automatically
 // reading the page index when opening a parquet file, when set to true. This is
 // useful as an optimization when programs know that they will not need to
@@ -485,6 +759,15 @@ func FileSchema(schema *Schema) FileOption {
 	return fileOption(func(config *FileConfig) { config.Schema = schema })
 }
 
+// FileReadLimits configures limits to the resources consumed when reading a
+// parquet file. Programs opening files from untrusted sources should use this
+// option to prevent crafted files from causing unbounded memory allocations.
+//
+// Defaults to no limits.
+func FileReadLimits(limits ReadLimits) FileOption {
+	return fileOption(func(config *FileConfig) { config.Limits = limits })
+}
+
 // PageBufferSize configures the size of column page buffers on parquet writers.
 //
 // Note that the page buffer size refers to the in-memory buffers where pages
@@ -575,6 +858,21 @@ func DataPageStatistics(enabled bool) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.DataPageStatistics = enabled })
 }
 
+// MinCompressionSavings creates a configuration option which defines the
+// minimum percentage of the size of data pages that compression must save for
+// the pages to be written compressed.
+//
+// Data pages in version 2 which do not compress well enough are written
+// uncompressed, which saves readers the cost of decompressing them. This is
+// useful for columns holding values which are already compressed or random,
+// like images or encrypted blobs. The option has no effect on data pages in
+// version 1, which are always compressed with the codec of their column.
+//
+// Defaults to zero, which compresses all pages.
+func MinCompressionSavings(percent int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.MinCompressionSavings = percent })
+}
+
 // KeyValueMetadata creates a configuration option which adds key/value metadata
 // to add to the metadata of parquet files.
 //
@@ -616,6 +914,45 @@ func Compression(codec compress.Codec) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.Compression = codec })
 }
 
+// ZstdDictionary creates a configuration option which sets the dictionary used
+// to compress the pages of columns using the ZSTD compression codec, for
+// example:
+//
+//	dict, err := parquet.TrainZstdDictionary(samplePages, zstd.DefaultDictionarySize)
+//	...
+//	writer := parquet.NewGenericWriter[RowType](output,
+//		parquet.Compression(&parquet.Zstd),
+//		parquet.ZstdDictionary(dict),
+//	)
+//
+// The dictionary is stored in the key/value metadata of the file, so OpenFile
+// uses it to decompress the pages without further configuration. Columns with
+// a zstd.Codec already configured with a dictionary keep using it, it must then
+// be supplied out of band to readers, for example with RegisterCompressionCodec.
+func ZstdDictionary(dict []byte) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.ZstdDictionary = dict })
+}
+
+// CompatibilityProfile creates a configuration option which restricts the
+// features of the parquet format used by writers to the ones supported by the
+// query engines of the given profile, for example:
+//
+//	writer := parquet.NewGenericWriter[RowType](output,
+//		parquet.CompatibilityProfile(parquet.Spark2|parquet.Hive),
+//	)
+//
+// Writers configured with a compatibility profile write timestamps as INT96
+// values and lists and maps with the legacy layouts of the profile. Data pages
+// are written in version 1 with statistics, the deprecated min and max values
+// are set in the statistics of column chunks, and columns configured with DELTA
+// or BYTE_STREAM_SPLIT encodings are PLAIN encoded instead.
+//
+// The rows written to the writer keep using the schema of the writer, they are
+// converted to the schema of the file when they are written.
+func CompatibilityProfile(profile Compatibility) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Compatibility = profile })
+}
+
 // SortingWriterConfig is a writer option which applies configuration specific
 // to sorting writers.
 func SortingWriterConfig(options ...SortingOption) WriterOption {
@@ -672,6 +1009,86 @@ func DropDuplicatedRows(drop bool) SortingOption {
 	return sortingOption(func(config *SortingConfig) { config.DropDuplicatedRows = drop })
 }
 
+// DedupeBuffers creates a configuration option which sets the pool of buffers
+// that rows are spilled to when the set of keys held in memory by a hash-based
+// deduplication reader is full. Using NewFileBufferPool allows spilling rows to
+// disk.
+//
+// Defaults to using in-memory buffers.
+func DedupeBuffers(buffers BufferPool) DedupeOption {
+	return dedupeOption(func(config *DedupeConfig) { config.Buffers = buffers })
+}
+
+// DedupeMaxKeys configures the maximum number of distinct keys that hash-based
+// deduplication readers hold in memory before spilling rows to buffers.
+//
+// Defaults to 1048576.
+func DedupeMaxKeys(numKeys int) DedupeOption {
+	return dedupeOption(func(config *DedupeConfig) { config.MaxKeys = numKeys })
+}
+
+// CSVComma configures the field delimiter of CSV readers and writers.
+//
+// Defaults to ','.
+func CSVComma(comma rune) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.Comma = comma })
+}
+
+// CSVSampleSize configures the number of records that CSV readers buffer to
+// infer the schema of the input when no explicit schema was configured.
+//
+// Defaults to 1000.
+func CSVSampleSize(numRecords int) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.SampleSize = numRecords })
+}
+
+// CSVNullString configures the string representing null values in CSV
+// records. CSV readers produce null values for fields equal to this string,
+// and CSV writers use it to render null values.
+//
+// Defaults to the empty string.
+func CSVNullString(null string) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.NullString = null })
+}
+
+// CSVTimestampFormat configures the layout used to format timestamps in CSV
+// records, using the syntax of the standard time package. CSV readers attempt
+// to parse timestamps with this layout before trying common layouts such as
+// RFC 3339.
+//
+// Defaults to time.RFC3339Nano.
+func CSVTimestampFormat(layout string) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.TimestampFormat = layout })
+}
+
+// CSVSchema configures the schema of rows read from CSV records, disabling
+// schema inference. Columns of the CSV header are matched with leaf columns of
+// the schema by their path, where names are separated by dots.
+func CSVSchema(schema *Schema) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.Schema = schema })
+}
+
+// HeterogeneousObjectsAsJSON configures JSON schema inference to represent
+// objects which do not have the same set of keys in all samples as JSON
+// columns, instead of groups where the keys missing from some of the samples
+// are optional fields. This is useful when objects are used as maps with
+// arbitrary keys, which would otherwise produce a column per key.
+//
+// Defaults to false.
+func HeterogeneousObjectsAsJSON(enable bool) JSONSchemaOption {
+	return jsonSchemaOption(func(config *JSONSchemaConfig) { config.HeterogeneousObjectsAsJSON = enable })
+}
+
+// ScalarsAsStrings configures JSON Lines readers to accept numbers and
+// booleans as values of STRING and ENUM columns, which are then stored in
+// their JSON representation. This is required to read documents with values
+// of mixed types, which InferJSONSchema widens to STRING columns.
+//
+// Defaults to false.
+func ScalarsAsStrings(enable bool) JSONLinesReaderOption {
+	return jsonLinesReaderOption(func(config *JSONLinesReaderConfig) { config.ScalarsAsStrings = enable })
+}
+
 type fileOption func(*FileConfig)
 
 func (opt fileOption) ConfigureFile(config *FileConfig) { opt(config) }
@@ -680,6 +1097,18 @@ type readerOption func(*ReaderConfig)
 
 func (opt readerOption) ConfigureReader(config *ReaderConfig) { opt(config) }
 
+type csvOption func(*CSVConfig)
+
+func (opt csvOption) ConfigureCSV(config *CSVConfig) { opt(config) }
+
+type jsonSchemaOption func(*JSONSchemaConfig)
+
+func (opt jsonSchemaOption) ConfigureJSONSchema(config *JSONSchemaConfig) { opt(config) }
+
+type jsonLinesReaderOption func(*JSONLinesReaderConfig)
+
+func (opt jsonLinesReaderOption) ConfigureJSONLinesReader(config *JSONLinesReaderConfig) { opt(config) }
+
 type writerOption func(*WriterConfig)
 
 func (opt writerOption) ConfigureWriter(config *WriterConfig) { opt(config) }
@@ -692,6 +1121,10 @@ type sortingOption func(*SortingConfig)
 
 func (opt sortingOption) ConfigureSorting(config *SortingConfig) { opt(config) }
 
+type dedupeOption func(*DedupeConfig)
+
+func (opt dedupeOption) ConfigureDedupe(config *DedupeConfig) { opt(config) }
+
 func coalesceInt(i1, i2 int) int {
 	if i1 != 0 {
 		return i1
@@ -749,6 +1182,18 @@ func coalesceSortingConfig(c1, c2 SortingConfig) SortingConfig {
 	}
 }
 
+func coalesceReadLimits(l1, l2 ReadLimits) ReadLimits {
+	return ReadLimits{
+		MaxFooterSize:       coalesceInt64(l1.MaxFooterSize, l2.MaxFooterSize),
+		MaxPageSize:         coalesceInt64(l1.MaxPageSize, l2.MaxPageSize),
+		MaxChunkSize:        coalesceInt64(l1.MaxChunkSize, l2.MaxChunkSize),
+		MaxDictionaryValues: coalesceInt64(l1.MaxDictionaryValues, l2.MaxDictionaryValues),
+		MaxSchemaDepth:      coalesceInt(l1.MaxSchemaDepth, l2.MaxSchemaDepth),
+		MaxColumns:          coalesceInt(l1.MaxColumns, l2.MaxColumns),
+		MaxRows:             coalesceInt64(l1.MaxRows, l2.MaxRows),
+	}
+}
+
 func coalesceBloomFilters(f1, f2 []BloomFilterColumn) []BloomFilterColumn {
 	if f1 != nil {
 		return f1
@@ -756,6 +1201,13 @@ func coalesceBloomFilters(f1, f2 []BloomFilterColumn) []BloomFilterColumn {
 	return f2
 }
 
+func coalesceCompatibility(c1, c2 Compatibility) Compatibility {
+	if c1 != 0 {
+		return c1
+	}
+	return c2
+}
+
 func coalesceCompression(c1, c2 compress.Codec) compress.Codec {
 	if c1 != nil {
 		return c1
@@ -777,6 +1229,13 @@ func validatePositiveInt64(optionName string, optionValue int64) error {
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateNonNegativeInt64(optionName string, optionValue int64) error {
+	if optionValue >= 0 {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
 func validateOneOfInt(optionName string, optionValue int, supportedValues ...int) error {
 	for _, value := range supportedValues {
 		if value == optionValue {
@@ -786,6 +1245,20 @@ func validateOneOfInt(optionName string, optionValue int, supportedValues ...int
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateRangeInt(optionName string, optionValue, min, max int) error {
+	if optionValue >= min && optionValue <= max {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
+func validateCSVComma(optionName string, optionValue rune) error {
+	if optionValue == '\r' || optionValue == '\n' || optionValue == '"' || optionValue == utf8.RuneError || !utf8.ValidRune(optionValue) {
+		return errorInvalidOptionValue(optionName, optionValue)
+	}
+	return nil
+}
+
 func validateNotNil(optionName string, optionValue interface{}) error {
 	if optionValue != nil {
 		return nil
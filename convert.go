@@ -61,18 +61,27 @@ type conversion struct {
 	columns []conversionColumn
 	schema  *Schema
 	buffers sync.Pool
+	// Reassembly of the shredded variant columns of the source schema into
+	// variant columns of the target schema.
+	variants []*variantReassembly
 	// This field is used to size the column buffers held in the sync.Pool since
 	// they are intended to store the source rows being converted from.
 	numberOfSourceColumns int
 }
 
 type conversionBuffer struct {
-	columns [][]Value
+	columns  [][]Value
+	variants [][2][]Value
 }
 
 type conversionColumn struct {
 	sourceIndex   int
 	convertValues conversionFunc
+	// When the column is the metadata (0) or value (1) of a variant reassembled
+	// from shredded columns, variantIndex is the index of the reassembly in
+	// the conversion, and -1 otherwise.
+	variantIndex  int
+	variantColumn int
 }
 
 type conversionFunc func([]Value) error
@@ -83,7 +92,7 @@ func convertToSelf(column []Value) error { return nil }
 func convertToType(targetType, sourceType Type) conversionFunc {
 	return func(column []Value) error {
 		for i, v := range column {
-			v, err := sourceType.ConvertValue(v, targetType)
+			v, err := targetType.ConvertValue(v, sourceType)
 			if err != nil {
 				return err
 			}
@@ -153,7 +162,8 @@ func (c *conversion) getBuffer() *conversionBuffer {
 	b, _ := c.buffers.Get().(*conversionBuffer)
 	if b == nil {
 		b = &conversionBuffer{
-			columns: make([][]Value, c.numberOfSourceColumns),
+			columns:  make([][]Value, c.numberOfSourceColumns),
+			variants: make([][2][]Value, len(c.variants)),
 		}
 		values := make([]Value, c.numberOfSourceColumns)
 		for i := range b.columns {
@@ -183,9 +193,19 @@ func (c *conversion) Convert(rows []Row) (int, error) {
 		})
 		row = row[:0]
 
+		for i, v := range c.variants {
+			metadata, values, err := v.reassembleValues(source.variants[i][0][:0], source.variants[i][1][:0], source.columns)
+			if err != nil {
+				return n, err
+			}
+			source.variants[i] = [2][]Value{metadata, values}
+		}
+
 		for columnIndex, conv := range c.columns {
 			columnOffset := len(row)
-			if conv.sourceIndex < 0 {
+			if conv.variantIndex >= 0 {
+				row = append(row, source.variants[conv.variantIndex][conv.variantColumn]...)
+			} else if conv.sourceIndex < 0 {
 				// When there is no source column, we put a single value as
 				// placeholder in the column. This is a condition where the
 				// target contained a column which did not exist at had not
@@ -241,22 +261,53 @@ func (id identity) Schema() *Schema                 { return id.schema }
 // The returned function is intended to be used to append the converted source
 // row to the destination buffer.
 func Convert(to, from Node) (conv Conversion, err error) {
+	return convert(to, from, false)
+}
+
+// ConvertByFieldID is like Convert but matches the columns of the source and
+// target schemas by field ID instead of by name.
+//
+// Fields of the target schema which have a field ID are matched with the field
+// of the source schema that has the same ID at the same level of the schema
+// tree, regardless of their names. Fields which have no field ID are matched
+// by name. This allows conversions to track columns that have been renamed or
+// reordered, as long as their field ID was preserved.
+func ConvertByFieldID(to, from Node) (conv Conversion, err error) {
+	return convert(to, from, true)
+}
+
+func convert(to, from Node, byFieldID bool) (conv Conversion, err error) {
 	schema, _ := to.(*Schema)
 	if schema == nil {
 		schema = NewSchema("", to)
 	}
 
-	if nodesAreEqual(to, from) {
+	if !byFieldID && nodesAreEqual(to, from) {
 		return identity{schema}, nil
 	}
 
+	// The columns are matched on views of the schemas where LIST and MAP
+	// groups have the standard layout, which allows conversions between
+	// schemas written with the legacy layouts of older parquet writers and
+	// schemas generated from Go types. The views have the same leaf columns
+	// and levels as the original schemas.
+	to, from = normalizeNestedTypes(to, from, byFieldID)
+
 	targetMapping, targetColumns := columnMappingOf(to)
 	sourceMapping, sourceColumns := columnMappingOf(from)
 	columns := make([]conversionColumn, len(targetColumns))
 
 	for i, path := range targetColumns {
 		targetColumn := targetMapping.lookup(path)
-		sourceColumn := sourceMapping.lookup(path)
+		sourcePath, ok := path, true
+		if byFieldID {
+			sourcePath, ok = sourcePathByFieldID(to, from, path)
+		}
+
+		sourceColumn := leafColumn{}
+		if ok {
+			sourceColumn = sourceMapping.lookup(sourcePath)
+		}
 
 		conversions := []conversionFunc{}
 		if sourceColumn.node != nil {
@@ -279,7 +330,7 @@ func Convert(to, from Node) (conv Conversion, err error) {
 
 			for j := 0; j < len(path); j++ {
 				targetNode = fieldByName(targetNode, path[j])
-				sourceNode = fieldByName(sourceNode, path[j])
+				sourceNode = fieldByName(sourceNode, sourcePath[j])
 
 				targetRepetitionLevel, targetDefinitionLevel = applyFieldRepetitionType(
 					fieldRepetitionTypeOf(targetNode),
@@ -308,7 +359,7 @@ func Convert(to, from Node) (conv Conversion, err error) {
 		} else {
 			targetType := targetColumn.node.Type()
 			targetKind := targetType.Kind()
-			sourceColumn = sourceMapping.lookupClosest(path)
+			sourceColumn = sourceMapping.lookupClosest(sourcePath)
 			if sourceColumn.node != nil {
 				conversions = append(conversions,
 					convertToZero(targetKind),
@@ -323,17 +374,114 @@ func Convert(to, from Node) (conv Conversion, err error) {
 		columns[i] = conversionColumn{
 			sourceIndex:   int(sourceColumn.columnIndex),
 			convertValues: multiConversionFunc(conversions),
+			variantIndex:  -1,
 		}
 	}
 
+	variants, err := convertVariants(to, from, byFieldID, columns, targetMapping, sourceMapping)
+	if err != nil {
+		return nil, err
+	}
+
 	c := &conversion{
 		columns:               columns,
 		schema:                schema,
+		variants:              variants,
 		numberOfSourceColumns: len(sourceColumns),
 	}
 	return c, nil
 }
 
+// convertVariants sets up the reassembly of variant columns of the target
+// schema which are shredded in the source schema. The metadata and value
+// columns of the target variant are both produced by the reassembly, and share
+// the levels of the source metadata column.
+func convertVariants(to, from Node, byFieldID bool, columns []conversionColumn, targetMapping, sourceMapping columnMappingGroup) ([]*variantReassembly, error) {
+	var variants []*variantReassembly
+	var err error
+
+	forEachVariantPath(to, nil, func(path columnPath, target Node) {
+		if err != nil || fieldByName(target, "typed_value") != nil {
+			return
+		}
+		sourcePath, ok := path, true
+		if byFieldID {
+			sourcePath, ok = sourcePathByFieldID(to, from, path)
+		}
+		if !ok {
+			return
+		}
+		source := lookupColumnPath(from, sourcePath)
+		if source == nil || !isShreddedVariant(source) {
+			return
+		}
+
+		var r *variantReassembly
+		if r, err = newVariantReassembly(source, sourcePath, sourceMapping); err != nil {
+			return
+		}
+
+		metadata := targetMapping.lookup(path.append("metadata")).columnIndex
+		value := targetMapping.lookup(path.append("value")).columnIndex
+		columns[metadata].variantIndex = len(variants)
+		columns[metadata].variantColumn = 0
+		columns[value] = conversionColumn{
+			sourceIndex:   columns[metadata].sourceIndex,
+			convertValues: columns[metadata].convertValues,
+			variantIndex:  len(variants),
+			variantColumn: 1,
+		}
+		if fieldByName(source, "value") != nil {
+			columns[value].sourceIndex = int(sourceMapping.lookup(sourcePath.append("value")).columnIndex)
+		}
+		variants = append(variants, r)
+	})
+
+	return variants, err
+}
+
+func forEachVariantPath(node Node, path columnPath, do func(columnPath, Node)) {
+	if isVariant(node) {
+		do(path, node)
+		return
+	}
+	for _, field := range node.Fields() {
+		if !field.Leaf() {
+			forEachVariantPath(field, path.append(field.Name()), do)
+		}
+	}
+}
+
+// sourcePathByFieldID translates the path of a column in the target schema to
+// the path of the column with the same field IDs in the source schema. When no
+// source column matches, the returned path holds the names of the fields that
+// could be matched followed by the remaining names of the target path, and ok
+// is false.
+func sourcePathByFieldID(to, from Node, path columnPath) (sourcePath columnPath, ok bool) {
+	sourcePath = make(columnPath, len(path))
+	copy(sourcePath, path)
+	targetNode, sourceNode := to, from
+
+	for i, name := range path {
+		targetField := fieldByName(targetNode, name)
+		sourceField := Field(nil)
+
+		if id := fieldIDOf(targetField); id != 0 {
+			sourceField = fieldByID(sourceNode, id)
+		} else {
+			sourceField = fieldByName(sourceNode, name)
+		}
+		if sourceField == nil {
+			return sourcePath, false
+		}
+
+		sourcePath[i] = sourceField.Name()
+		targetNode, sourceNode = targetField, sourceField
+	}
+
+	return sourcePath, true
+}
+
 func isDirectLevelMapping(levels []byte) bool {
 	for i, level := range levels {
 		if level != byte(i) {
@@ -436,6 +584,16 @@ func maskMissingRowGroupColumns(r RowGroup, numColumns int, conv Conversion) Row
 		}
 	}
 
+	// Reassembling shredded variants requires reading all the columns of the
+	// source variant groups.
+	if c, ok := conv.(*conversion); ok {
+		for _, v := range c.variants {
+			for j := v.columnIndex; j < v.columnIndex+v.numColumns; j++ {
+				columns[j] = rowGroupColumns[j]
+			}
+		}
+	}
+
 	return &rowGroup{
 		schema:  r.Schema(),
 		numRows: numRows,
@@ -961,6 +1119,62 @@ func convertStringToTimeMicros(v Value, tz *time.Location) (Value, error) {
 	return v.convertToInt64(microseconds), nil
 }
 
+func convertBooleanToFloat16(v Value) (Value, error) {
+	var f float32
+	if v.boolean() {
+		f = 1
+	}
+	return v.convertToFloat16(f), nil
+}
+
+func convertInt32ToFloat16(v Value) (Value, error) {
+	return v.convertToFloat16(float32(v.int32())), nil
+}
+
+func convertInt64ToFloat16(v Value) (Value, error) {
+	return v.convertToFloat16(float32(v.int64())), nil
+}
+
+func convertFloatToFloat16(v Value) (Value, error) {
+	return v.convertToFloat16(v.float()), nil
+}
+
+func convertDoubleToFloat16(v Value) (Value, error) {
+	return v.convertToFloat16(float32(v.double())), nil
+}
+
+func convertStringToFloat16(v Value) (Value, error) {
+	f, err := strconv.ParseFloat(v.string(), 32)
+	if err != nil {
+		return v, conversionError(v, "STRING", "FLOAT16", err)
+	}
+	return v.convertToFloat16(float32(f)), nil
+}
+
+func convertFloat16ToFloat(v Value) (Value, error) {
+	return v.convertToFloat(v.Float16()), nil
+}
+
+func convertFloat16ToDouble(v Value) (Value, error) {
+	return v.convertToDouble(float64(v.Float16())), nil
+}
+
+func convertFloat16ToString(v Value) (Value, error) {
+	return v.convertToByteArray(strconv.AppendFloat(nil, float64(v.Float16()), 'g', -1, 32)), nil
+}
+
+func convertStringToInterval(v Value) (Value, error) {
+	i, err := ParseCalendarInterval(v.string())
+	if err != nil {
+		return v, conversionError(v, "STRING", "INTERVAL", err)
+	}
+	return v.convertToFixedLenByteArray(makeCalendarIntervalBytes(i)), nil
+}
+
+func convertIntervalToString(v Value) (Value, error) {
+	return v.convertToByteArray(v.Interval().appendString(nil)), nil
+}
+
 func convertDateToTimestamp(v Value, u format.TimeUnit, tz *time.Location) (Value, error) {
 	t := unixEpoch.AddDate(0, 0, int(v.int32()))
 	d := timeUnitDuration(u)
@@ -991,17 +1205,13 @@ func convertTimestampToDate(v Value, u format.TimeUnit, tz *time.Location) (Valu
 	return v.convertToInt32(int32(d)), nil
 }
 
-func convertTimestampToTimeMillis(v Value, u format.TimeUnit, sourceZone, targetZone *time.Location) (Value, error) {
-	t := timestamp(v, u, sourceZone)
-	m := nearestMidnightLessThan(t)
-	milliseconds := t.In(targetZone).Sub(m).Milliseconds()
+func convertTimestampToTimeMillis(v Value, wallClock time.Time) (Value, error) {
+	milliseconds := wallClock.Sub(nearestMidnightLessThan(wallClock)).Milliseconds()
 	return v.convertToInt32(int32(milliseconds)), nil
 }
 
-func convertTimestampToTimeMicros(v Value, u format.TimeUnit, sourceZone, targetZone *time.Location) (Value, error) {
-	t := timestamp(v, u, sourceZone)
-	m := nearestMidnightLessThan(t)
-	microseconds := t.In(targetZone).Sub(m).Microseconds()
+func convertTimestampToTimeMicros(v Value, wallClock time.Time) (Value, error) {
+	microseconds := wallClock.Sub(nearestMidnightLessThan(wallClock)).Microseconds()
 	return v.convertToInt64(int64(microseconds)), nil
 }
 
@@ -1014,6 +1224,34 @@ func convertTimestampToTimestamp(v Value, sourceUnit, targetUnit format.TimeUnit
 
 const nanosecondsPerDay = 24 * 60 * 60 * 1e9
 
+// julianDayOfUnixEpoch is the Julian day of 1970-01-01. Timestamps written as
+// INT96 values by older writers hold the nanoseconds elapsed in the day in the
+// first 8 bytes, and the Julian day in the last 4 bytes.
+const julianDayOfUnixEpoch = 2440588
+
+func convertTimestampToInt96(v Value, u format.TimeUnit) (Value, error) {
+	scale := timeUnitDuration(u).Nanoseconds()
+	unitsPerDay := nanosecondsPerDay / scale
+	days, units := v.int64()/unitsPerDay, v.int64()%unitsPerDay
+	if units < 0 {
+		days, units = days-1, units+unitsPerDay
+	}
+	nanos := units * scale
+	return v.convertToInt96(deprecated.Int96{
+		0: uint32(nanos),
+		1: uint32(nanos >> 32),
+		2: uint32(days + julianDayOfUnixEpoch),
+	}), nil
+}
+
+func convertInt96ToTimestamp(v Value, u format.TimeUnit) (Value, error) {
+	i96 := v.int96()
+	nanos := int64(i96[1])<<32 | int64(i96[0])
+	days := int64(i96[2]) - julianDayOfUnixEpoch
+	scale := timeUnitDuration(u).Nanoseconds()
+	return v.convertToInt64(days*(nanosecondsPerDay/scale) + nanos/scale), nil
+}
+
 func daysSinceUnixEpoch(t time.Time) int {
 	return int(t.Sub(unixEpoch).Hours()) / 24
 }
@@ -1023,10 +1261,28 @@ func nearestMidnightLessThan(t time.Time) time.Time {
 	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
 }
 
+// wallClockIn returns the time in loc which has the same wall clock as t.
+func wallClockIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	return time.Date(year, month, day, hour, min, sec, t.Nanosecond(), loc)
+}
+
 func timestamp(v Value, u format.TimeUnit, tz *time.Location) time.Time {
 	return unixEpoch.In(tz).Add(time.Duration(v.int64()) * timeUnitDuration(u))
 }
 
+func timestampValue(t time.Time, u format.TimeUnit) int64 {
+	switch {
+	case u.Millis != nil:
+		return t.UnixMilli()
+	case u.Micros != nil:
+		return t.UnixMicro()
+	default:
+		return t.UnixNano()
+	}
+}
+
 func timeUnitDuration(unit format.TimeUnit) time.Duration {
 	switch {
 	case unit.Millis != nil:
@@ -506,6 +506,10 @@ func TestConvertValue(t *testing.T) {
 		t.Errorf("converted value mismatch:\nwant = %+v\ngot  = %+v", ns, nsVal.Int64())
 	}
 
+	utcPlus2 := time.FixedZone("UTC+2", 2*3600)
+	localUsType := parquet.LocalTimestamp(parquet.Microsecond, utcPlus2).Type()
+	localMsType := parquet.LocalTimestamp(parquet.Millisecond, utcPlus2).Type()
+
 	var timestampConversionTests = [...]struct {
 		scenario  string
 		fromType  parquet.Type
@@ -1124,6 +1128,54 @@ func TestConvertValue(t *testing.T) {
 			toValue:   parquet.Int64Value(85413123456),
 		},
 
+		{
+			scenario:  "utc timestamp to local timestamp",
+			fromType:  parquet.Timestamp(parquet.Microsecond).Type(),
+			fromValue: parquet.Int64Value(1670888613123456),
+			toType:    localUsType,
+			toValue:   parquet.Int64Value(1670895813123456),
+		},
+
+		{
+			scenario:  "local timestamp to utc timestamp",
+			fromType:  localUsType,
+			fromValue: parquet.Int64Value(1670895813123456),
+			toType:    parquet.Timestamp(parquet.Millisecond).Type(),
+			toValue:   parquet.Int64Value(1670888613123),
+		},
+
+		{
+			scenario:  "local timestamp to local timestamp",
+			fromType:  localMsType,
+			fromValue: parquet.Int64Value(1670888613123),
+			toType:    parquet.TimestampAdjusted(parquet.Microsecond, false).Type(),
+			toValue:   parquet.Int64Value(1670888613123000),
+		},
+
+		{
+			scenario:  "utc timestamp to local time",
+			fromType:  parquet.Timestamp(parquet.Microsecond).Type(),
+			fromValue: parquet.Int64Value(1670888613123456),
+			toType:    parquet.LocalTime(parquet.Millisecond, utcPlus2).Type(),
+			toValue:   parquet.Int32Value(6213123),
+		},
+
+		{
+			scenario:  "local timestamp to local time",
+			fromType:  localUsType,
+			fromValue: parquet.Int64Value(1670888613123456),
+			toType:    parquet.TimeAdjusted(parquet.Microsecond, false).Type(),
+			toValue:   parquet.Int64Value(85413123456),
+		},
+
+		{
+			scenario:  "local timestamp to utc time",
+			fromType:  localUsType,
+			fromValue: parquet.Int64Value(1670895813123456),
+			toType:    parquet.Time(parquet.Microsecond).Type(),
+			toValue:   parquet.Int64Value(85413123456),
+		},
+
 		{
 			scenario:  "micros to nanos",
 			fromType:  usType,
@@ -1190,3 +1242,90 @@ func TestConvertValue(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertTypes(t *testing.T) {
+	tests := []struct {
+		scenario string
+		from     parquet.Node
+		to       parquet.Node
+		value    parquet.Value
+		want     parquet.Value
+	}{
+		{
+			scenario: "int32 to int64",
+			from:     parquet.Int(32),
+			to:       parquet.Int(64),
+			value:    parquet.Int32Value(42),
+			want:     parquet.Int64Value(42),
+		},
+		{
+			scenario: "int64 to int32",
+			from:     parquet.Int(64),
+			to:       parquet.Int(32),
+			value:    parquet.Int64Value(42),
+			want:     parquet.Int32Value(42),
+		},
+		{
+			scenario: "float to double",
+			from:     parquet.Leaf(parquet.FloatType),
+			to:       parquet.Leaf(parquet.DoubleType),
+			value:    parquet.FloatValue(1.5),
+			want:     parquet.DoubleValue(1.5),
+		},
+		{
+			scenario: "int32 to string",
+			from:     parquet.Int(32),
+			to:       parquet.String(),
+			value:    parquet.Int32Value(42),
+			want:     parquet.ByteArrayValue([]byte("42")),
+		},
+		{
+			scenario: "string to int64",
+			from:     parquet.String(),
+			to:       parquet.Int(64),
+			value:    parquet.ByteArrayValue([]byte("42")),
+			want:     parquet.Int64Value(42),
+		},
+		{
+			scenario: "boolean to int32",
+			from:     parquet.Leaf(parquet.BooleanType),
+			to:       parquet.Int(32),
+			value:    parquet.BooleanValue(true),
+			want:     parquet.Int32Value(1),
+		},
+		{
+			scenario: "timestamp millis to micros",
+			from:     parquet.Timestamp(parquet.Millisecond),
+			to:       parquet.Timestamp(parquet.Microsecond),
+			value:    parquet.Int64Value(42),
+			want:     parquet.Int64Value(42000),
+		},
+		{
+			scenario: "date to timestamp",
+			from:     parquet.Date(),
+			to:       parquet.Timestamp(parquet.Millisecond),
+			value:    parquet.Int32Value(1),
+			want:     parquet.Int64Value(24 * 3600 * 1000),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			from := parquet.NewSchema("test", parquet.Group{"value": test.from})
+			to := parquet.NewSchema("test", parquet.Group{"value": test.to})
+
+			conv, err := parquet.Convert(to, from)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows := []parquet.Row{{test.value}}
+			if _, err := conv.Convert(rows); err != nil {
+				t.Fatal(err)
+			}
+			if got := rows[0][0]; !parquet.Equal(got, test.want) || got.Kind() != test.want.Kind() {
+				t.Errorf("converted value mismatch: want=%v got=%v", test.want, got)
+			}
+		})
+	}
+}
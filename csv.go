@@ -0,0 +1,529 @@
+package parquet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVReader is an implementation of RowReader which reads parquet rows from
+// CSV records.
+//
+// The first record of the input is expected to be a header containing the
+// names of the columns. When no schema is configured with the CSVSchema
+// option, the reader buffers the first records of the input (see
+// CSVSampleSize) and infers the schema from their values. Each CSV column is
+// then mapped to a top-level column of the first type that can represent all
+// the sampled values, in this order of preference:
+//
+//	boolean          true or false (case insensitive)
+//	INT64            integers
+//	DOUBLE           integers and floating point numbers
+//	DATE             dates formatted as "2006-01-02"
+//	TIMESTAMP        timestamps (microsecond precision), or dates
+//	STRING           any value
+//
+// Columns where null values were sampled (see CSVNullString) are optional.
+// Note that the fields of groups are sorted by name, the order of columns in
+// the inferred schema may therefore differ from the order of the CSV header.
+//
+// Because CSVReader implements RowReaderWithSchema, the rows can be streamed
+// directly to a parquet writer, for example:
+//
+//	reader, err := parquet.NewCSVReader(input)
+//	if err != nil {
+//		...
+//	}
+//	writer := parquet.NewWriter(output, reader.Schema())
+//	if _, err := parquet.CopyRows(writer, reader); err != nil {
+//		...
+//	}
+//	if err := writer.Close(); err != nil {
+//		...
+//	}
+type CSVReader struct {
+	reader  *csv.Reader
+	config  *CSVConfig
+	schema  *Schema
+	columns []csvColumn
+	sample  []csvRecord
+	layouts []string
+	err     error
+}
+
+type csvColumn struct {
+	name               string
+	field              int
+	typ                Type
+	maxDefinitionLevel byte
+}
+
+type csvRecord struct {
+	line   int
+	fields []string
+}
+
+// NewCSVReader constructs a reader of parquet rows from the CSV records read
+// from input.
+//
+// The function reads the CSV header, and the records sampled to infer the
+// schema if none was configured, so it may return errors caused by reading
+// from input, or if the configuration or schema was invalid.
+func NewCSVReader(input io.Reader, options ...CSVOption) (*CSVReader, error) {
+	config, err := NewCSVConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &CSVReader{
+		reader:  csv.NewReader(input),
+		config:  config,
+		schema:  config.Schema,
+		layouts: csvTimestampLayouts(config.TimestampFormat),
+	}
+	r.reader.Comma = config.Comma
+
+	header, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = errors.New("csv input is missing a header")
+		}
+		return nil, err
+	}
+	header = append([]string(nil), header...)
+
+	seen := make(map[string]struct{}, len(header))
+	for _, name := range header {
+		if name == "" {
+			return nil, errors.New("csv header contains an empty column name")
+		}
+		if _, dup := seen[name]; dup {
+			return nil, fmt.Errorf("csv header contains duplicate column name %q", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	if r.schema == nil {
+		if r.schema, err = r.inferSchema(header); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := make(map[string]int, len(header))
+	for i, name := range header {
+		fields[name] = i
+	}
+
+	forEachLeafColumnOf(r.schema, func(leaf leafColumn) {
+		if err != nil {
+			return
+		}
+		name := leaf.path.String()
+		field, ok := fields[name]
+		switch {
+		case leaf.maxRepetitionLevel > 0:
+			err = fmt.Errorf("csv cannot represent repeated column %q", name)
+		case !ok && leaf.maxDefinitionLevel == 0:
+			err = fmt.Errorf("csv header is missing required column %q", name)
+		case !ok:
+			field = -1
+		}
+		delete(fields, name)
+		r.columns = append(r.columns, csvColumn{
+			name:               name,
+			field:              field,
+			typ:                leaf.node.Type(),
+			maxDefinitionLevel: leaf.maxDefinitionLevel,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range header {
+		if _, ok := fields[name]; ok {
+			return nil, fmt.Errorf("csv column %q does not exist in the schema", name)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *CSVReader) inferSchema(header []string) (*Schema, error) {
+	types := make([]csvType, len(header))
+	nullable := make([]bool, len(header))
+
+	for len(r.sample) < r.config.SampleSize {
+		record, err := r.readRecord()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		for i, value := range record.fields {
+			if value == r.config.NullString {
+				nullable[i] = true
+			} else {
+				types[i] = types[i].merge(r.typeOf(value))
+			}
+		}
+		r.sample = append(r.sample, record)
+	}
+
+	group := make(Group, len(header))
+	for i, name := range header {
+		node := types[i].node()
+		if nullable[i] || types[i] == csvNull {
+			node = Optional(node)
+		}
+		group[name] = node
+	}
+	return NewSchema("csv", group), nil
+}
+
+func (r *CSVReader) typeOf(value string) csvType {
+	// Numbers are tested first so columns of 0 and 1 are inferred as integers,
+	// booleans then use the predicate that ReadRows parses them with.
+	if len(value) > 0 && strings.IndexByte("+-.0123456789", value[0]) >= 0 {
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return csvInt64
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return csvDouble
+		}
+	}
+	if _, err := strconv.ParseBool(value); err == nil {
+		return csvBoolean
+	}
+	if len(value) == len("2006-01-02") {
+		if _, err := time.Parse("2006-01-02", value); err == nil {
+			return csvDate
+		}
+	}
+	if _, err := r.parseTimestamp(value); err == nil {
+		return csvTimestamp
+	}
+	return csvString
+}
+
+func (r *CSVReader) parseTimestamp(value string) (t time.Time, err error) {
+	for _, layout := range r.layouts {
+		if t, err = time.Parse(layout, value); err == nil {
+			break
+		}
+	}
+	return t, err
+}
+
+func (r *CSVReader) readRecord() (csvRecord, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		return csvRecord{}, err
+	}
+	line, _ := r.reader.FieldPos(0)
+	return csvRecord{line: line, fields: fields}, nil
+}
+
+// Schema returns the schema of rows read from r.
+func (r *CSVReader) Schema() *Schema { return r.schema }
+
+// ReadRows reads rows from r, one row per CSV record. The method returns
+// io.EOF when the end of the input has been reached.
+func (r *CSVReader) ReadRows(rows []Row) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for i := range rows {
+		var record csvRecord
+
+		if len(r.sample) > 0 {
+			record, r.sample[0] = r.sample[0], csvRecord{}
+			r.sample = r.sample[1:]
+		} else {
+			var err error
+			if record, err = r.readRecord(); err != nil {
+				r.err = err
+				return i, err
+			}
+		}
+
+		row := rows[i][:0]
+
+		for columnIndex, column := range r.columns {
+			v := Value{}
+			s := r.config.NullString
+
+			if column.field >= 0 {
+				s = record.fields[column.field]
+			}
+
+			// Required columns cannot be null, the null string is parsed as
+			// a regular value for those (e.g. an empty string).
+			if s == r.config.NullString && column.maxDefinitionLevel > 0 {
+				v.definitionLevel = column.maxDefinitionLevel - 1
+			} else {
+				var err error
+				if v, err = r.parseValue(s, column.typ); err != nil {
+					r.err = fmt.Errorf("csv line %d: column %q: %w", record.line, column.name, err)
+					return i, r.err
+				}
+				v.definitionLevel = column.maxDefinitionLevel
+			}
+
+			v.columnIndex = ^int16(columnIndex)
+			row = append(row, v)
+		}
+
+		rows[i] = row
+	}
+
+	return len(rows), nil
+}
+
+func (r *CSVReader) parseValue(s string, t Type) (Value, error) {
+	if lt := t.LogicalType(); lt != nil && lt.Timestamp != nil {
+		ts, err := r.parseTimestamp(s)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueInt64(timestampValue(ts, lt.Timestamp.Unit)), nil
+	}
+	return parseJSONValue(jsonValueOfString(s, t), t)
+}
+
+func csvTimestampLayouts(layout string) []string {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	}
+	for i, l := range layouts {
+		if l == layout {
+			layouts = append(layouts[:i], layouts[i+1:]...)
+			break
+		}
+	}
+	return append([]string{layout}, layouts...)
+}
+
+type csvType int
+
+const (
+	csvNull csvType = iota
+	csvBoolean
+	csvInt64
+	csvDouble
+	csvDate
+	csvTimestamp
+	csvString
+)
+
+func (t csvType) merge(other csvType) csvType {
+	switch {
+	case t == other, other == csvNull:
+		return t
+	case t == csvNull:
+		return other
+	case t > other:
+		t, other = other, t
+	}
+	switch {
+	case t == csvInt64 && other == csvDouble:
+		return csvDouble
+	case t == csvDate && other == csvTimestamp:
+		return csvTimestamp
+	default:
+		return csvString
+	}
+}
+
+func (t csvType) node() Node {
+	switch t {
+	case csvBoolean:
+		return Leaf(BooleanType)
+	case csvInt64:
+		return Int(64)
+	case csvDouble:
+		return Leaf(DoubleType)
+	case csvDate:
+		return Date()
+	case csvTimestamp:
+		return Timestamp(Microsecond)
+	default:
+		return String()
+	}
+}
+
+// CSVWriter is an implementation of RowWriter which writes parquet rows as CSV
+// records.
+//
+// CSV records are flat, the writer only outputs columns that are not repeated,
+// using their path with names separated by dots as column names in the CSV
+// header. Values are formatted using their logical types: strings and JSON
+// documents are written verbatim, timestamps are formatted with the layout
+// configured by CSVTimestampFormat, and other types use the same text
+// representation as JSONLinesWriter (e.g. dates as "2006-01-02", decimals as
+// "-12.34", byte arrays encoded in base64). Null values are written as the
+// string configured by CSVNullString.
+type CSVWriter struct {
+	writer  *csv.Writer
+	config  *CSVConfig
+	schema  *Schema
+	fields  []int
+	types   []Type
+	header  []string
+	record  []string
+	buffer  []byte
+	started bool
+}
+
+// NewCSVWriter constructs a writer of CSV records to output.
+//
+// The schema of rows written to the CSV writer is configured with the
+// CSVSchema option, or by the first row group passed to WriteRowGroup.
+//
+// The function panics if the writer configuration is invalid.
+func NewCSVWriter(output io.Writer, options ...CSVOption) *CSVWriter {
+	config, err := NewCSVConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	w := &CSVWriter{
+		writer: csv.NewWriter(output),
+		config: config,
+	}
+	w.writer.Comma = config.Comma
+	if config.Schema != nil {
+		w.configure(config.Schema)
+	}
+	return w
+}
+
+func (w *CSVWriter) configure(schema *Schema) {
+	w.schema = schema
+	w.fields = w.fields[:0]
+	w.types = w.types[:0]
+	w.header = w.header[:0]
+
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		if leaf.maxRepetitionLevel > 0 {
+			w.fields = append(w.fields, -1)
+			return
+		}
+		w.fields = append(w.fields, len(w.header))
+		w.types = append(w.types, leaf.node.Type())
+		w.header = append(w.header, leaf.path.String())
+	})
+
+	w.record = make([]string, len(w.header))
+}
+
+// Schema returns the schema of rows written by w.
+//
+// The returned value will be nil if no schema has yet been configured on w.
+func (w *CSVWriter) Schema() *Schema { return w.schema }
+
+// WriteRows writes rows to w, one CSV record per row.
+func (w *CSVWriter) WriteRows(rows []Row) (int, error) {
+	if w.schema == nil {
+		return 0, ErrRowGroupSchemaMissing
+	}
+	if err := w.writeHeader(); err != nil {
+		return 0, err
+	}
+
+	for i, row := range rows {
+		for j := range w.record {
+			w.record[j] = w.config.NullString
+		}
+
+		for _, v := range row {
+			c := v.Column()
+			if c < 0 || c >= len(w.fields) {
+				return i, fmt.Errorf("csv: row value has column index %d out of range for schema with %d columns", c, len(w.fields))
+			}
+			f := w.fields[c]
+			if f < 0 || v.IsNull() {
+				continue
+			}
+			b, err := w.appendValue(w.buffer[:0], v, w.types[f])
+			if err != nil {
+				return i, fmt.Errorf("csv: column %q: %w", w.header[f], err)
+			}
+			w.buffer = b
+			w.record[f] = string(b)
+		}
+
+		if err := w.writer.Write(w.record); err != nil {
+			return i, err
+		}
+	}
+
+	return len(rows), nil
+}
+
+// WriteRowGroup writes the rows of rowGroup to w.
+//
+// If no schema was configured on w, the schema of the row group is used,
+// otherwise the method returns ErrRowGroupSchemaMismatch if the schemas of w
+// and the row group differ.
+func (w *CSVWriter) WriteRowGroup(rowGroup RowGroup) (int64, error) {
+	rowGroupSchema := rowGroup.Schema()
+	switch {
+	case rowGroupSchema == nil:
+		return 0, ErrRowGroupSchemaMissing
+	case w.schema == nil:
+		w.configure(rowGroupSchema)
+	case !nodesAreEqual(w.schema, rowGroupSchema):
+		return 0, ErrRowGroupSchemaMismatch
+	}
+	rows := rowGroup.Rows()
+	defer rows.Close()
+	return CopyRows(w, rows)
+}
+
+// Flush writes any buffered data to the underlying output.
+func (w *CSVWriter) Flush() error {
+	if w.schema != nil {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	return w.writer.Write(w.header)
+}
+
+func (w *CSVWriter) appendValue(b []byte, v Value, t Type) ([]byte, error) {
+	lt := t.LogicalType()
+	switch {
+	case lt == nil:
+	case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil:
+		return append(b, v.byteArray()...), nil
+	case lt.Timestamp != nil:
+		return timestamp(v, lt.Timestamp.Unit, time.UTC).AppendFormat(b, w.config.TimestampFormat), nil
+	}
+	// Values that appendJSONValue renders as JSON strings never contain
+	// characters that need escaping, so stripping the quotes yields their
+	// text representation.
+	n := len(b)
+	b, err := appendJSONValue(b, v, t)
+	if err == nil && len(b) > n && b[n] == '"' {
+		b = append(b[:n], b[n+1:len(b)-1]...)
+	}
+	return b, err
+}
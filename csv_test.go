@@ -0,0 +1,153 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestCSVReaderInferSchema(t *testing.T) {
+	const input = `name,age,score,active,birthday,created_at,note
+Alice,42,1.5,true,2000-01-02,2022-03-04T05:06:07Z,
+Bob,,2,FALSE,2001-02-03,2022-03-04 05:06:07.5,hello
+Carol,7,3,false,,2022-03-04,"a, b"
+`
+
+	r, err := parquet.NewCSVReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expectedSchema = `message csv {
+	required boolean active;
+	optional int64 age (INT(64,true));
+	optional int32 birthday (DATE);
+	required int64 created_at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required binary name (STRING);
+	optional binary note (STRING);
+	required double score;
+}`
+
+	if s := r.Schema().String(); s != expectedSchema {
+		t.Fatalf("wrong schema:\nwant:\n%s\ngot:\n%s", expectedSchema, s)
+	}
+
+	buffer := parquet.NewBuffer(r.Schema())
+	if _, err := parquet.CopyRows(buffer, r); err != nil {
+		t.Fatal(err)
+	}
+	if n := buffer.NumRows(); n != 3 {
+		t.Fatalf("wrong number of rows: want=3 got=%d", n)
+	}
+
+	output := new(bytes.Buffer)
+	w := parquet.NewCSVWriter(output,
+		parquet.CSVNullString("NULL"),
+		parquet.CSVTimestampFormat("2006-01-02 15:04:05.999"),
+	)
+	if _, err := w.WriteRowGroup(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	const expectedOutput = `active,age,birthday,created_at,name,note,score
+true,42,2000-01-02,2022-03-04 05:06:07,Alice,NULL,1.5
+false,NULL,2001-02-03,2022-03-04 05:06:07.5,Bob,hello,2
+false,7,NULL,2022-03-04 00:00:00,Carol,"a, b",3
+`
+
+	if s := output.String(); s != expectedOutput {
+		t.Fatalf("wrong CSV output:\nwant:\n%s\ngot:\n%s", expectedOutput, s)
+	}
+}
+
+func TestCSVReaderInferBoolean(t *testing.T) {
+	const input = `a,b,c
+true,T,tRuE
+False,f,false
+`
+
+	r, err := parquet.NewCSVReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expectedSchema = `message csv {
+	required boolean a;
+	required boolean b;
+	required binary c (STRING);
+}`
+
+	if s := r.Schema().String(); s != expectedSchema {
+		t.Fatalf("wrong schema:\nwant:\n%s\ngot:\n%s", expectedSchema, s)
+	}
+
+	rows := make([]parquet.Row, 2)
+	if n, err := r.ReadRows(rows); n != 2 {
+		t.Fatalf("wrong number of rows: want=2 got=%d (%v)", n, err)
+	}
+	for i, want := range []bool{true, false} {
+		for j, v := range rows[i][:2] {
+			if v.Boolean() != want {
+				t.Errorf("row %d column %d: want=%t got=%t", i, j, want, v.Boolean())
+			}
+		}
+	}
+}
+
+func TestCSVReaderSchema(t *testing.T) {
+	type record struct {
+		ID    int32   `parquet:"id"`
+		Price int64   `parquet:"price,decimal(2:9)"`
+		Tags  *string `parquet:"tags,optional"`
+	}
+
+	schema := parquet.SchemaOf(new(record))
+
+	r, err := parquet.NewCSVReader(strings.NewReader("price;id\n1.25;1\n-3;2\n"),
+		parquet.CSVSchema(schema),
+		parquet.CSVComma(';'),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := make([]parquet.Row, 3)
+	n, err := r.ReadRows(rows)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("wrong number of rows: want=2 got=%d", n)
+	}
+
+	for i, want := range []record{{ID: 1, Price: 125}, {ID: 2, Price: -300}} {
+		var got record
+		if err := schema.Reconstruct(&got, rows[i]); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("row %d mismatch: want=%+v got=%+v", i, want, got)
+		}
+	}
+
+	r, err = parquet.NewCSVReader(strings.NewReader("id,price\n1,2\nx,3\n"), parquet.CSVSchema(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.ReadRows(rows)
+	if err == nil || !strings.Contains(err.Error(), `csv line 3: column "id"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = parquet.NewCSVReader(strings.NewReader("id,other\n"), parquet.CSVSchema(schema))
+	if err == nil {
+		t.Fatal("expected an error for a missing required column")
+	}
+}
@@ -1,5 +1,15 @@
 package parquet
 
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go/hashprobe"
+	"github.com/segmentio/parquet-go/hashprobe/wyhash"
+)
+
 // DedupeRowReader constructs a row reader which drops duplicated consecutive
 // rows, according to the comparator function passed as argument.
 //
@@ -109,3 +119,389 @@ func (d *dedupe) deduplicate(rows []Row, compare func(Row, Row) int) int {
 	d.lastRow = append(d.lastRow[:0], lastRow...)
 	return len(d.uniq)
 }
+
+// HashDedupeRowReader constructs a row reader which drops duplicated rows,
+// where two rows are considered duplicates if the values of their key columns
+// are equal. Unlike DedupeRowReader, the input does not need to be sorted.
+//
+// The keyColumns argument lists the indexes of the leaf columns that rows are
+// compared on; all columns are compared when it is empty. Values are compared
+// on their binary representation and levels, which means that null values are
+// equal to each other. The indexes are passed as a slice because the variadic
+// parameter holds the options, for example:
+//
+//	rows := parquet.HashDedupeRowReader(reader, []int{0, 2},
+//		parquet.DedupeMaxKeys(1e6),
+//		parquet.DedupeBuffers(parquet.NewFileBufferPool("", "dedupe.*")),
+//	)
+//
+// The reader retains the keys of rows it produced in a hash table. When the
+// table holds the maximum number of keys configured with DedupeMaxKeys, rows
+// with keys that are not in the table are spilled to partitions allocated from
+// the pool configured with DedupeBuffers, based on the hash of their keys.
+// The partitions are deduplicated one at a time after the input was consumed,
+// which means that the order of rows is only retained when all keys fit in
+// memory. In both cases, the first row seen for each key is the one produced
+// by the reader.
+//
+// The returned reader implements io.Closer, applications that stop reading
+// rows before reaching io.EOF should close it to release the spill buffers.
+func HashDedupeRowReader(reader RowReader, keyColumns []int, options ...DedupeOption) RowReader {
+	config, err := NewDedupeConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	d := &hashDedupeRowReader{
+		input:   reader,
+		reader:  reader,
+		buffers: config.Buffers,
+		maxKeys: config.MaxKeys,
+		table:   hashprobe.NewBytesTable(0, hashprobeTableMaxLoad),
+	}
+
+	for _, columnIndex := range keyColumns {
+		if columnIndex < 0 || columnIndex > MaxColumnIndex {
+			panic(fmt.Sprintf("key column index out of range: %d", columnIndex))
+		}
+		if columnIndex >= len(d.columns) {
+			d.columns = append(d.columns, make([]bool, columnIndex+1-len(d.columns))...)
+		}
+		d.columns[columnIndex] = true
+	}
+
+	return d
+}
+
+const (
+	// Number of partitions that rows are spilled to when the set of keys held
+	// in memory is full. Partitions which still have too many distinct keys to
+	// fit in memory are split again when they are deduplicated, using a
+	// different hash seed.
+	dedupeSpillPartitions = 16
+	// Size of the buffers used to read and write spilled rows.
+	dedupeSpillBufferSize = 32 * 1024
+)
+
+type hashDedupeRowReader struct {
+	input   RowReader
+	reader  RowReader
+	columns []bool
+	buffers BufferPool
+	maxKeys int
+	table   *hashprobe.BytesTable
+	keys    []byte
+	offsets []uint32
+	indexes []int32
+	// The partition currently being deduplicated, and its level, which is zero
+	// while rows are read from the input.
+	spill *dedupeSpill
+	level int
+	// Partitions that rows are spilled to, and partitions that are waiting to
+	// be deduplicated.
+	partitions [dedupeSpillPartitions]*dedupeSpill
+	spills     []*dedupeSpill
+}
+
+func (d *hashDedupeRowReader) ReadRows(rows []Row) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	for {
+		if d.reader == nil {
+			if err := d.nextSpill(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := d.reader.ReadRows(rows)
+		n, spillErr := d.deduplicate(rows[:n])
+		if spillErr != nil {
+			return n, spillErr
+		}
+
+		if err != nil {
+			if err != io.EOF {
+				return n, err
+			}
+			if err := d.endOfSpill(); err != nil {
+				return n, err
+			}
+		}
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (d *hashDedupeRowReader) Close() error {
+	if d.spill != nil {
+		d.buffers.PutBuffer(d.spill.buffer)
+		d.spill = nil
+	}
+	for i, s := range d.partitions {
+		if s != nil {
+			d.buffers.PutBuffer(s.buffer)
+			d.partitions[i] = nil
+		}
+	}
+	for i, s := range d.spills {
+		d.buffers.PutBuffer(s.buffer)
+		d.spills[i] = nil
+	}
+	d.spills = d.spills[:0]
+	d.input, d.reader = nil, nil
+	d.table.Reset()
+	return nil
+}
+
+// deduplicate moves the rows which were not seen before to the front of the
+// rows slice and returns how many there were. When the table of keys is full,
+// rows with keys that are not in the table are spilled.
+func (d *hashDedupeRowReader) deduplicate(rows []Row) (int, error) {
+	d.keys = d.keys[:0]
+	d.offsets = append(d.offsets[:0], 0)
+
+	for _, row := range rows {
+		d.keys = d.appendKey(d.keys, row)
+		d.offsets = append(d.offsets, uint32(len(d.keys)))
+	}
+
+	if cap(d.indexes) < len(rows) {
+		d.indexes = make([]int32, len(rows))
+	}
+
+	indexes := d.indexes[:len(rows)]
+	numRows := 0
+	nextIndex := int32(d.table.Len())
+	i := 0
+
+	// Keys are inserted in chunks no larger than the remaining capacity of
+	// the table, so the number of keys never exceeds the limit.
+	for i < len(rows) && d.table.Len() < d.maxKeys {
+		j := i + (d.maxKeys - d.table.Len())
+		if j > len(rows) {
+			j = len(rows)
+		}
+
+		d.table.Probe(d.keys, d.offsets[i:j+1:j+1], indexes[i:j:j])
+
+		for k, index := range indexes[i:j] {
+			if index == nextIndex {
+				rows[numRows], rows[i+k] = rows[i+k], rows[numRows]
+				numRows++
+				nextIndex++
+			}
+		}
+
+		i = j
+	}
+
+	if i < len(rows) {
+		d.table.Lookup(d.keys, d.offsets[i:], indexes[i:])
+
+		for k, index := range indexes[i:] {
+			if index < 0 {
+				key := d.keys[d.offsets[i+k]:d.offsets[i+k+1]]
+				if err := d.spillRow(rows[i+k], key); err != nil {
+					return numRows, err
+				}
+			}
+		}
+	}
+
+	return numRows, nil
+}
+
+func (d *hashDedupeRowReader) appendKey(b []byte, row Row) []byte {
+	for _, value := range row {
+		if d.columns != nil {
+			columnIndex := value.Column()
+			if columnIndex < 0 || columnIndex >= len(d.columns) || !d.columns[columnIndex] {
+				continue
+			}
+		}
+		b = appendDedupeValue(b, value)
+	}
+	return b
+}
+
+func (d *hashDedupeRowReader) spillRow(row Row, key []byte) error {
+	hash := wyhash.HashBytes(key, uintptr(d.level))
+	partition := &d.partitions[hash%dedupeSpillPartitions]
+
+	if *partition == nil {
+		*partition = &dedupeSpill{
+			level:  d.level + 1,
+			buffer: d.buffers.GetBuffer(),
+		}
+		(*partition).writer = bufio.NewWriterSize((*partition).buffer, dedupeSpillBufferSize)
+	}
+
+	return (*partition).writeRow(row)
+}
+
+// endOfSpill is called when the current source of rows is exhausted, it
+// releases the partition that was being deduplicated and queues the partitions
+// that rows were spilled to.
+func (d *hashDedupeRowReader) endOfSpill() error {
+	if d.spill != nil {
+		d.buffers.PutBuffer(d.spill.buffer)
+		d.spill = nil
+	}
+
+	d.reader = nil
+
+	for i, s := range d.partitions {
+		if s != nil {
+			d.partitions[i] = nil
+			d.spills = append(d.spills, s)
+
+			if err := s.writer.Flush(); err != nil {
+				return err
+			}
+			if _, err := s.buffer.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+
+			s.writer = nil
+			s.reader = bufio.NewReaderSize(s.buffer, dedupeSpillBufferSize)
+		}
+	}
+
+	return nil
+}
+
+func (d *hashDedupeRowReader) nextSpill() error {
+	if len(d.spills) == 0 {
+		return io.EOF
+	}
+
+	s := d.spills[0]
+	d.spills[0] = nil
+	d.spills = d.spills[1:]
+
+	// Keys of the partition could not have been seen by the previous source,
+	// otherwise the rows would not have been spilled, so the table can start
+	// empty.
+	d.table.Reset()
+	d.spill, d.reader, d.level = s, s, s.level
+	return nil
+}
+
+// dedupeSpill is a partition of rows spilled to a buffer. Rows are encoded as
+// their number of values followed by the values, see appendDedupeValue.
+type dedupeSpill struct {
+	level  int
+	buffer io.ReadWriteSeeker
+	writer *bufio.Writer
+	reader *bufio.Reader
+	bytes  []byte
+}
+
+func (s *dedupeSpill) writeRow(row Row) error {
+	s.bytes = binary.AppendUvarint(s.bytes[:0], uint64(len(row)))
+	for _, value := range row {
+		s.bytes = appendDedupeValue(s.bytes, value)
+	}
+	_, err := s.writer.Write(s.bytes)
+	return err
+}
+
+func (s *dedupeSpill) ReadRows(rows []Row) (int, error) {
+	// The byte arrays of values read from the partition reference this buffer,
+	// which makes them valid until the next call to ReadRows.
+	s.bytes = s.bytes[:0]
+
+	for i := range rows {
+		numValues, err := binary.ReadUvarint(s.reader)
+		if err != nil {
+			return i, err
+		}
+
+		row := rows[i][:0]
+
+		for j := uint64(0); j < numValues; j++ {
+			v, err := s.readValue()
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return i, err
+			}
+			row = append(row, v)
+		}
+
+		rows[i] = row
+	}
+
+	return len(rows), nil
+}
+
+func (s *dedupeSpill) readValue() (Value, error) {
+	var header [5]byte
+
+	if _, err := io.ReadFull(s.reader, header[:]); err != nil {
+		return Value{}, err
+	}
+
+	var v Value
+	var err error
+
+	if kind := int8(header[0]); kind != 0 {
+		size := uint64(0)
+
+		switch Kind(^kind) {
+		case Boolean:
+			size = 1
+		case Int32, Float:
+			size = 4
+		case Int64, Double:
+			size = 8
+		case Int96:
+			size = 12
+		default:
+			if size, err = binary.ReadUvarint(s.reader); err != nil {
+				return Value{}, err
+			}
+		}
+
+		offset := len(s.bytes)
+		s.bytes = append(s.bytes, make([]byte, size)...)
+		data := s.bytes[offset:]
+
+		if _, err := io.ReadFull(s.reader, data); err != nil {
+			return Value{}, err
+		}
+		if v, err = parseValue(Kind(^kind), data); err != nil {
+			return Value{}, err
+		}
+	}
+
+	v.repetitionLevel = header[1]
+	v.definitionLevel = header[2]
+	v.columnIndex = int16(binary.LittleEndian.Uint16(header[3:]))
+	return v, nil
+}
+
+// appendDedupeValue appends the encoding of v to b, made of a header holding
+// the kind, levels and column index of the value, followed by the binary
+// representation of the value. The size of byte arrays is prefixed to their
+// content so the encoding can be decoded, and so that the concatenation of the
+// encoding of values can be used as keys.
+func appendDedupeValue(b []byte, v Value) []byte {
+	b = append(b,
+		byte(v.kind),
+		v.repetitionLevel,
+		v.definitionLevel,
+		byte(v.columnIndex),
+		byte(v.columnIndex>>8),
+	)
+	switch v.Kind() {
+	case ByteArray, FixedLenByteArray:
+		b = binary.AppendUvarint(b, v.u64)
+	}
+	return v.AppendBytes(b)
+}
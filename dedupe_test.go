@@ -3,7 +3,9 @@
 package parquet_test
 
 import (
+	"io"
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/segmentio/parquet-go"
@@ -105,3 +107,112 @@ func TestDedupeRowWriter(t *testing.T) {
 	n, _ := reader.Read(rows)
 	assertRowsEqual(t, dedupeRows, rows[:n])
 }
+
+func TestHashDedupeRowReader(t *testing.T) {
+	type Row struct {
+		Key   int32  `parquet:"key"`
+		Value string `parquet:"value"`
+	}
+
+	tests := []struct {
+		scenario   string
+		keyColumns []int
+		options    []parquet.DedupeOption
+		makeRow    func(int) Row
+	}{
+		{
+			scenario:   "key column in memory",
+			keyColumns: []int{0},
+			makeRow: func(i int) Row {
+				return Row{Key: int32((i * 7919) % 300), Value: strconv.Itoa(i)}
+			},
+		},
+
+		{
+			scenario: "all columns in memory",
+			makeRow: func(i int) Row {
+				k := int32((i * 7919) % 300)
+				return Row{Key: k, Value: strconv.Itoa(int(k) * 2)}
+			},
+		},
+
+		{
+			scenario:   "key column spilled to memory buffers",
+			keyColumns: []int{0},
+			options: []parquet.DedupeOption{
+				parquet.DedupeMaxKeys(10),
+			},
+			makeRow: func(i int) Row {
+				return Row{Key: int32((i * 7919) % 300), Value: strconv.Itoa(i)}
+			},
+		},
+
+		{
+			scenario: "all columns spilled to files",
+			options: []parquet.DedupeOption{
+				parquet.DedupeBuffers(parquet.NewFileBufferPool(t.TempDir(), "dedupe.*")),
+				parquet.DedupeMaxKeys(7),
+			},
+			makeRow: func(i int) Row {
+				k := int32((i * 7919) % 300)
+				return Row{Key: k, Value: strconv.Itoa(int(k) * 2)}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			rows := make([]Row, 1000)
+			for i := range rows {
+				rows[i] = test.makeRow(i)
+			}
+
+			dedupeMap := make(map[int32]Row, len(rows))
+			for _, row := range rows {
+				if _, exists := dedupeMap[row.Key]; !exists {
+					dedupeMap[row.Key] = row
+				}
+			}
+
+			dedupeRows := make([]Row, 0, len(dedupeMap))
+			for _, row := range dedupeMap {
+				dedupeRows = append(dedupeRows, row)
+			}
+
+			sort.Slice(dedupeRows, func(i, j int) bool {
+				return dedupeRows[i].Key < dedupeRows[j].Key
+			})
+
+			buffer1 := parquet.NewRowBuffer[Row]()
+			buffer1.Write(rows)
+
+			buffer1Rows := buffer1.Rows()
+			defer buffer1Rows.Close()
+
+			buffer2 := parquet.NewRowBuffer[Row](
+				&parquet.RowGroupConfig{
+					Sorting: parquet.SortingConfig{
+						SortingColumns: []parquet.SortingColumn{
+							parquet.Ascending("key"),
+						},
+					},
+				},
+			)
+
+			reader := parquet.HashDedupeRowReader(buffer1Rows, test.keyColumns, test.options...)
+			defer reader.(io.Closer).Close()
+
+			if _, err := parquet.CopyRows(buffer2, reader); err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Sort(buffer2)
+
+			output := parquet.NewGenericRowGroupReader[Row](buffer2)
+			defer output.Close()
+
+			n, _ := output.Read(rows)
+			assertRowsEqual(t, dedupeRows, rows[:n])
+		})
+	}
+}
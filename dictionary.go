@@ -672,8 +672,7 @@ func (d *doubleDictionary) Page() Page {
 
 type byteArrayDictionary struct {
 	byteArrayPage
-	table map[string]int32
-	alloc allocator
+	table *hashprobe.BytesTable
 }
 
 func newByteArrayDictionary(typ Type, columnIndex int16, numValues int32, data encoding.Values) *byteArrayDictionary {
@@ -711,34 +710,40 @@ func (d *byteArrayDictionary) Insert(indexes []int32, values []Value) {
 }
 
 func (d *byteArrayDictionary) init() {
+	const chunkSize = insertsTargetCacheFootprint / 4
 	numValues := d.len()
-	d.table = make(map[string]int32, numValues)
+	d.table = hashprobe.NewBytesTable(numValues, hashprobeTableMaxLoad)
+
+	if numValues > 0 {
+		indexes := make([]int32, min(numValues, chunkSize))
 
-	for i := 0; i < numValues; i++ {
-		d.table[string(d.index(i))] = int32(len(d.table))
+		for i := 0; i < numValues; i += chunkSize {
+			j := min(i+chunkSize, numValues)
+			d.table.Probe(d.values, d.offsets[i:j+1:j+1], indexes[:j-i])
+		}
 	}
 }
 
 func (d *byteArrayDictionary) insert(indexes []int32, rows sparse.Array) {
+	const chunkSize = insertsTargetCacheFootprint / 4
+
 	if d.table == nil {
 		d.init()
 	}
 
 	values := rows.StringArray()
 
-	for i := range indexes {
-		value := values.Index(i)
+	for i := 0; i < values.Len(); i += chunkSize {
+		j := min(i+chunkSize, values.Len())
 
-		index, exists := d.table[value]
-		if !exists {
-			value = d.alloc.copyString(value)
-			index = int32(len(d.table))
-			d.table[value] = index
-			d.values = append(d.values, value...)
-			d.offsets = append(d.offsets, uint32(len(d.values)))
+		if d.table.ProbeArray(values.Slice(i, j), indexes[i:j:j]) > 0 {
+			for k, index := range indexes[i:j] {
+				if index == int32(d.len()) {
+					d.values = append(d.values, values.Index(i+k)...)
+					d.offsets = append(d.offsets, uint32(len(d.values)))
+				}
+			}
 		}
-
-		indexes[i] = index
 	}
 }
 
@@ -782,10 +787,9 @@ func (d *byteArrayDictionary) Bounds(indexes []int32) (min, max Value) {
 func (d *byteArrayDictionary) Reset() {
 	d.offsets = d.offsets[:1]
 	d.values = d.values[:0]
-	for k := range d.table {
-		delete(d.table, k)
+	if d.table != nil {
+		d.table.Reset()
 	}
-	d.alloc.reset()
 }
 
 func (d *byteArrayDictionary) Page() Page {
@@ -907,6 +911,74 @@ func (d *fixedLenByteArrayDictionary) Page() Page {
 	return &d.fixedLenByteArrayPage
 }
 
+type float16Dictionary struct{ fixedLenByteArrayDictionary }
+
+func newFloat16Dictionary(typ Type, columnIndex int16, numValues int32, values encoding.Values) *float16Dictionary {
+	return &float16Dictionary{*newFixedLenByteArrayDictionary(typ, columnIndex, numValues, values)}
+}
+
+func (d *float16Dictionary) Type() Type { return newIndexedType(d.typ, d) }
+
+// Bounds returns null values if all the indexed values were NaN, which are
+// excluded from the bounds.
+func (d *float16Dictionary) Bounds(indexes []int32) (min, max Value) {
+	var minValue, maxValue float32
+	var minIndex, maxIndex int32
+	var found bool
+
+	for _, i := range indexes {
+		h := float16Bits(d.index(i))
+		if isFloat16NaN(h) {
+			continue
+		}
+		f := float16ToFloat32(h)
+		switch {
+		case !found:
+			minValue, maxValue, minIndex, maxIndex, found = f, f, i, i, true
+		case f < minValue:
+			minValue, minIndex = f, i
+		case f > maxValue:
+			maxValue, maxIndex = f, i
+		}
+	}
+
+	if found {
+		min = d.makeValueBytes(d.index(minIndex))
+		max = d.makeValueBytes(d.index(maxIndex))
+		if minValue == 0 {
+			min = d.makeValueBytes(makeFloat16Bytes(float16NegativeZero))
+		}
+		if maxValue == 0 {
+			max = d.makeValueBytes(makeFloat16Bytes(float16PositiveZero))
+		}
+	}
+	return min, max
+}
+
+type intervalDictionary struct{ fixedLenByteArrayDictionary }
+
+func newIntervalDictionary(typ Type, columnIndex int16, numValues int32, values encoding.Values) *intervalDictionary {
+	return &intervalDictionary{*newFixedLenByteArrayDictionary(typ, columnIndex, numValues, values)}
+}
+
+func (d *intervalDictionary) Type() Type { return newIndexedType(d.typ, d) }
+
+// Bounds always returns null values since the order of INTERVAL values is
+// undefined.
+func (d *intervalDictionary) Bounds(indexes []int32) (min, max Value) { return }
+
+type geospatialDictionary struct{ byteArrayDictionary }
+
+func newGeospatialDictionary(typ Type, columnIndex int16, numValues int32, values encoding.Values) *geospatialDictionary {
+	return &geospatialDictionary{*newByteArrayDictionary(typ, columnIndex, numValues, values)}
+}
+
+func (d *geospatialDictionary) Type() Type { return newIndexedType(d.typ, d) }
+
+// Bounds always returns null values since the order of GEOMETRY and GEOGRAPHY
+// values is undefined.
+func (d *geospatialDictionary) Bounds(indexes []int32) (min, max Value) { return }
+
 type uint32Dictionary struct {
 	uint32Page
 	table *hashprobe.Uint32Table
@@ -1281,8 +1353,12 @@ func (page *indexedPage) Values() ValueReader { return &indexedPageValues{page:
 func (page *indexedPage) Bounds() (min, max Value, ok bool) {
 	if ok = len(page.values) > 0; ok {
 		min, max = page.typ.dict.Bounds(page.values)
-		min.columnIndex = page.columnIndex
-		max.columnIndex = page.columnIndex
+		// Dictionaries may exclude some values from the bounds (e.g. NaN
+		// in FLOAT16 columns), in which case the page has no bounds.
+		if ok = !min.isNull(); ok {
+			min.columnIndex = page.columnIndex
+			max.columnIndex = page.columnIndex
+		}
 	}
 	return min, max, ok
 }
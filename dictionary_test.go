@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -200,6 +201,29 @@ func BenchmarkDictionary(b *testing.B) {
 	}
 }
 
+func TestByteArrayDictionaryInsertNothing(t *testing.T) {
+	values := parquet.ByteArrayType.NewValues([]byte("ab"), []uint32{0, 1, 2})
+	dict := parquet.ByteArrayType.NewDictionary(0, 2, values)
+
+	// Inserting no values initializes the dictionary from the values it was
+	// created with, which must not depend on the size of the input.
+	dict.Insert(nil, nil)
+
+	indexes := make([]int32, 3)
+	dict.Insert(indexes, []parquet.Value{
+		parquet.ByteArrayValue([]byte("b")),
+		parquet.ByteArrayValue([]byte("c")),
+		parquet.ByteArrayValue([]byte("a")),
+	})
+
+	if want := []int32{1, 2, 0}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("wrong indexes: want=%v got=%v", want, indexes)
+	}
+	if n := dict.Len(); n != 3 {
+		t.Errorf("wrong dictionary length: want=3 got=%d", n)
+	}
+}
+
 func TestIssue312(t *testing.T) {
 	node := parquet.String()
 	node = parquet.Encoded(node, &parquet.RLEDictionary)
@@ -2,6 +2,9 @@ package parquet
 
 import (
 	"math/bits"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/segmentio/parquet-go/encoding"
 	"github.com/segmentio/parquet-go/encoding/bitpacked"
@@ -36,13 +39,30 @@ var (
 	// DeltaBinaryPacked is the delta binary packed parquet encoding.
 	DeltaBinaryPacked delta.BinaryPackedEncoding
 
+	// DeltaBinaryPackedMonotonic is the delta binary packed parquet encoding
+	// configured for columns of monotonically increasing values, like
+	// timestamps or identifiers.
+	//
+	// The deltas of these columns are small and regular, larger blocks and
+	// mini blocks produce fewer headers and amortize the cost of processing
+	// them. In the benchmarks of the encoding/delta package, blocks of 1024
+	// values split in 4 mini blocks encode timestamps at the same speed and to
+	// the same size as the default configuration, and decode 3-4x faster.
+	// Columns with irregular gaps between values may compress less since each
+	// mini block is packed with the bit width of its largest delta.
+	DeltaBinaryPackedMonotonic = delta.BinaryPackedEncoding{
+		BlockSize:     1024,
+		NumMiniBlocks: 4,
+	}
+
 	// DeltaLengthByteArray is the delta length byte array parquet encoding.
 	DeltaLengthByteArray delta.LengthByteArrayEncoding
 
 	// DeltaByteArray is the delta byte array parquet encoding.
 	DeltaByteArray delta.ByteArrayEncoding
 
-	// ByteStreamSplit is an encoding for floating-point data.
+	// ByteStreamSplit is an encoding for floating-point, integer and fixed
+	// length byte array data.
 	ByteStreamSplit bytestreamsplit.Encoding
 
 	// Table indexing the encodings supported by this package.
@@ -58,6 +78,12 @@ var (
 		format.ByteStreamSplit:      &ByteStreamSplit,
 	}
 
+	// Encodings registered by the application, they take precedence over the
+	// built-in encodings. The map is copied on write so lookups do not need to
+	// synchronize, the mutex serializes the registrations.
+	registeredEncodingsMutex sync.Mutex
+	registeredEncodings      atomic.Value // map[format.Encoding]encoding.Encoding
+
 	// Table indexing RLE encodings for repetition and definition levels of
 	// all supported bit widths.
 	levelEncodingsRLE = [...]rle.Encoding{
@@ -91,11 +117,47 @@ func isDictionaryFormat(encoding format.Encoding) bool {
 	return encoding == format.PlainDictionary || encoding == format.RLEDictionary
 }
 
+// RegisterEncoding installs enc as the implementation of its encoding code,
+// replacing any built-in or previously registered encoding with the same code.
+//
+// Registered encodings are used by LookupEncoding, and therefore when reading
+// files with OpenFile. They can also be selected in "parquet" struct tags
+// using the lower case version of the encoding name returned by its String
+// method. The function panics if the name is already used by an encoding
+// registered for a different code.
+//
+// The function is safe to call concurrently, but it is usually called during
+// program initialization, before files are read or written.
+func RegisterEncoding(enc encoding.Encoding) {
+	code, name := enc.Encoding(), enc.String()
+	registeredEncodingsMutex.Lock()
+	defer registeredEncodingsMutex.Unlock()
+
+	current := loadRegisteredEncodings()
+	encodings := make(map[format.Encoding]encoding.Encoding, len(current)+1)
+	for e, other := range current {
+		if e != code && strings.EqualFold(other.String(), name) {
+			panic("cannot register encoding " + name + " for " + code.String() + ": the name is already registered for " + e.String())
+		}
+		encodings[e] = other
+	}
+	encodings[code] = enc
+	registeredEncodings.Store(encodings)
+}
+
+func loadRegisteredEncodings() map[format.Encoding]encoding.Encoding {
+	encodings, _ := registeredEncodings.Load().(map[format.Encoding]encoding.Encoding)
+	return encodings
+}
+
 // LookupEncoding returns the parquet encoding associated with the given code.
 //
 // The function never returns nil. If the encoding is not supported,
 // encoding.NotSupported is returned.
 func LookupEncoding(enc format.Encoding) encoding.Encoding {
+	if e := lookupRegisteredEncoding(enc); e != nil {
+		return e
+	}
 	if enc >= 0 && int(enc) < len(encodings) {
 		if e := encodings[enc]; e != nil {
 			return e
@@ -104,6 +166,21 @@ func LookupEncoding(enc format.Encoding) encoding.Encoding {
 	return encoding.NotSupported{}
 }
 
+func lookupRegisteredEncoding(enc format.Encoding) encoding.Encoding {
+	return loadRegisteredEncodings()[enc]
+}
+
+// lookupEncodingByName returns the registered encoding with the given name, or
+// nil if none was found.
+func lookupEncodingByName(name string) encoding.Encoding {
+	for _, enc := range loadRegisteredEncodings() {
+		if strings.EqualFold(enc.String(), name) {
+			return enc
+		}
+	}
+	return nil
+}
+
 func lookupLevelEncoding(enc format.Encoding, max byte) encoding.Encoding {
 	i := bits.Len8(max) - 1
 	switch enc {
@@ -8,6 +8,10 @@ import (
 
 // This encoder implements a version of the Byte Stream Split encoding as described
 // in https://github.com/apache/parquet-format/blob/master/Encodings.md#byte-stream-split-byte_stream_split--9
+//
+// The encoding applies to FLOAT, DOUBLE, INT32, INT64 and FIXED_LEN_BYTE_ARRAY
+// values; the integer types share the byte layout and the optimized code paths
+// of the floating point types of the same size.
 type Encoding struct {
 	encoding.NotSupported
 }
@@ -20,6 +24,18 @@ func (e *Encoding) Encoding() format.Encoding {
 	return format.ByteStreamSplit
 }
 
+func (e *Encoding) EncodeInt32(dst []byte, src []int32) ([]byte, error) {
+	dst = resize(dst, 4*len(src))
+	encodeFloat(dst, unsafecast.Int32ToBytes(src))
+	return dst, nil
+}
+
+func (e *Encoding) EncodeInt64(dst []byte, src []int64) ([]byte, error) {
+	dst = resize(dst, 8*len(src))
+	encodeDouble(dst, unsafecast.Int64ToBytes(src))
+	return dst, nil
+}
+
 func (e *Encoding) EncodeFloat(dst []byte, src []float32) ([]byte, error) {
 	dst = resize(dst, 4*len(src))
 	encodeFloat(dst, unsafecast.Float32ToBytes(src))
@@ -32,6 +48,45 @@ func (e *Encoding) EncodeDouble(dst []byte, src []float64) ([]byte, error) {
 	return dst, nil
 }
 
+func (e *Encoding) EncodeFixedLenByteArray(dst []byte, src []byte, size int) ([]byte, error) {
+	if size <= 0 || size > encoding.MaxFixedLenByteArraySize {
+		return dst[:0], encoding.Error(e, encoding.ErrInvalidArgument)
+	}
+	if (len(src) % size) != 0 {
+		return dst[:0], encoding.ErrEncodeInvalidInputSize(e, "FIXED_LEN_BYTE_ARRAY", len(src))
+	}
+	dst = resize(dst, len(src))
+	// Values of 4 and 8 bytes have the same layout as FLOAT and DOUBLE, which
+	// lets them use the optimized code paths.
+	switch size {
+	case 4:
+		encodeFloat(dst, src)
+	case 8:
+		encodeDouble(dst, src)
+	default:
+		encodeFixedLenByteArray(dst, src, size)
+	}
+	return dst, nil
+}
+
+func (e *Encoding) DecodeInt32(dst []int32, src []byte) ([]int32, error) {
+	if (len(src) % 4) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "INT32", len(src))
+	}
+	buf := resize(unsafecast.Int32ToBytes(dst), len(src))
+	decodeFloat(buf, src)
+	return unsafecast.BytesToInt32(buf), nil
+}
+
+func (e *Encoding) DecodeInt64(dst []int64, src []byte) ([]int64, error) {
+	if (len(src) % 8) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "INT64", len(src))
+	}
+	buf := resize(unsafecast.Int64ToBytes(dst), len(src))
+	decodeDouble(buf, src)
+	return unsafecast.BytesToInt64(buf), nil
+}
+
 func (e *Encoding) DecodeFloat(dst []float32, src []byte) ([]float32, error) {
 	if (len(src) % 4) != 0 {
 		return dst, encoding.ErrDecodeInvalidInputSize(e, "FLOAT", len(src))
@@ -50,6 +105,47 @@ func (e *Encoding) DecodeDouble(dst []float64, src []byte) ([]float64, error) {
 	return unsafecast.BytesToFloat64(buf), nil
 }
 
+func (e *Encoding) DecodeFixedLenByteArray(dst []byte, src []byte, size int) ([]byte, error) {
+	if size <= 0 || size > encoding.MaxFixedLenByteArraySize {
+		return dst, encoding.Error(e, encoding.ErrInvalidArgument)
+	}
+	if (len(src) % size) != 0 {
+		return dst, encoding.ErrDecodeInvalidInputSize(e, "FIXED_LEN_BYTE_ARRAY", len(src))
+	}
+	dst = resize(dst, len(src))
+	switch size {
+	case 4:
+		decodeFloat(dst, src)
+	case 8:
+		decodeDouble(dst, src)
+	default:
+		decodeFixedLenByteArray(dst, src, size)
+	}
+	return dst, nil
+}
+
+// encodeFixedLenByteArray splits the bytes of values of the given size in
+// streams, the k-th byte of all values is written to the k-th stream.
+func encodeFixedLenByteArray(dst, src []byte, size int) {
+	n := len(src) / size
+	for k := 0; k < size; k++ {
+		stream := dst[k*n : (k+1)*n]
+		for i := range stream {
+			stream[i] = src[i*size+k]
+		}
+	}
+}
+
+func decodeFixedLenByteArray(dst, src []byte, size int) {
+	n := len(src) / size
+	for k := 0; k < size; k++ {
+		stream := src[k*n : (k+1)*n]
+		for i, b := range stream {
+			dst[i*size+k] = b
+		}
+	}
+}
+
 func resize(buf []byte, size int) []byte {
 	if cap(buf) < size {
 		buf = make([]byte, size, 2*size)
@@ -4,6 +4,7 @@
 package bytestreamsplit_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/segmentio/parquet-go/encoding/bytestreamsplit"
@@ -11,6 +12,14 @@ import (
 	"github.com/segmentio/parquet-go/encoding/test"
 )
 
+func FuzzEncodeInt32(f *testing.F) {
+	fuzz.EncodeInt32(f, new(bytestreamsplit.Encoding))
+}
+
+func FuzzEncodeInt64(f *testing.F) {
+	fuzz.EncodeInt64(f, new(bytestreamsplit.Encoding))
+}
+
 func FuzzEncodeFloat(f *testing.F) {
 	fuzz.EncodeFloat(f, new(bytestreamsplit.Encoding))
 }
@@ -19,6 +28,14 @@ func FuzzEncodeDouble(f *testing.F) {
 	fuzz.EncodeDouble(f, new(bytestreamsplit.Encoding))
 }
 
+func TestEncodeInt32(t *testing.T) {
+	test.EncodeInt32(t, new(bytestreamsplit.Encoding), 0, 100, 32)
+}
+
+func TestEncodeInt64(t *testing.T) {
+	test.EncodeInt64(t, new(bytestreamsplit.Encoding), 0, 100, 64)
+}
+
 func TestEncodeFloat(t *testing.T) {
 	test.EncodeFloat(t, new(bytestreamsplit.Encoding), 0, 100)
 }
@@ -26,3 +43,36 @@ func TestEncodeFloat(t *testing.T) {
 func TestEncodeDouble(t *testing.T) {
 	test.EncodeDouble(t, new(bytestreamsplit.Encoding), 0, 100)
 }
+
+func TestEncodeFixedLenByteArray(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 4, 8, 12, 16} {
+		enc := new(bytestreamsplit.Encoding)
+
+		for n := 0; n <= 100; n++ {
+			values := make([]byte, n*size)
+			for i := range values {
+				values[i] = byte(i)
+			}
+
+			buf, err := enc.EncodeFixedLenByteArray(nil, values, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k := 0; k < size; k++ {
+				for i := 0; i < n; i++ {
+					if buf[k*n+i] != values[i*size+k] {
+						t.Fatalf("size=%d n=%d: byte %d of value %d is not at the expected position in the encoded output", size, n, k, i)
+					}
+				}
+			}
+
+			out, err := enc.DecodeFixedLenByteArray(nil, buf, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(values, out) {
+				t.Fatalf("size=%d n=%d: decoded values mismatch:\nwant = %v\ngot  = %v", size, n, values, out)
+			}
+		}
+	}
+}
@@ -15,8 +15,35 @@ import (
 
 type BinaryPackedEncoding struct {
 	encoding.NotSupported
+
+	// BlockSize is the number of values in each block of the encoded output,
+	// it must be a multiple of 128. Zero means DefaultBlockSize.
+	//
+	// Larger blocks amortize the cost of block headers, which is beneficial
+	// to columns where deltas are regular, like monotonically increasing
+	// timestamps or identifiers.
+	BlockSize int
+
+	// NumMiniBlocks is the number of mini blocks that each block is split
+	// into, the number of values in each mini block must be a multiple of 32.
+	// Zero means DefaultNumMiniBlocks.
+	//
+	// Each mini block is packed with the bit width of its largest delta,
+	// smaller mini blocks adapt better to irregular deltas at the cost of
+	// storing more bit widths.
+	NumMiniBlocks int
 }
 
+const (
+	// DefaultBlockSize is the number of values in blocks of encodings that do
+	// not configure a block size.
+	DefaultBlockSize = blockSize
+
+	// DefaultNumMiniBlocks is the number of mini blocks in blocks of encodings
+	// that do not configure a number of mini blocks.
+	DefaultNumMiniBlocks = numMiniBlocks
+)
+
 func (e *BinaryPackedEncoding) String() string {
 	return "DELTA_BINARY_PACKED"
 }
@@ -26,11 +53,19 @@ func (e *BinaryPackedEncoding) Encoding() format.Encoding {
 }
 
 func (e *BinaryPackedEncoding) EncodeInt32(dst []byte, src []int32) ([]byte, error) {
-	return encodeInt32(dst[:0], src), nil
+	blockSize, numMiniBlocks, err := e.blockSize()
+	if err != nil {
+		return dst[:0], e.wrap(err)
+	}
+	return encodeInt32(dst[:0], src, blockSize, numMiniBlocks), nil
 }
 
 func (e *BinaryPackedEncoding) EncodeInt64(dst []byte, src []int64) ([]byte, error) {
-	return encodeInt64(dst[:0], src), nil
+	blockSize, numMiniBlocks, err := e.blockSize()
+	if err != nil {
+		return dst[:0], e.wrap(err)
+	}
+	return encodeInt64(dst[:0], src, blockSize, numMiniBlocks), nil
 }
 
 func (e *BinaryPackedEncoding) DecodeInt32(dst []int32, src []byte) ([]int32, error) {
@@ -45,6 +80,17 @@ func (e *BinaryPackedEncoding) DecodeInt64(dst []int64, src []byte) ([]int64, er
 	return unsafecast.BytesToInt64(buf), e.wrap(err)
 }
 
+func (e *BinaryPackedEncoding) blockSize() (blockSize, numMiniBlocks int, err error) {
+	blockSize, numMiniBlocks = e.BlockSize, e.NumMiniBlocks
+	if blockSize == 0 {
+		blockSize = DefaultBlockSize
+	}
+	if numMiniBlocks == 0 {
+		numMiniBlocks = DefaultNumMiniBlocks
+	}
+	return blockSize, numMiniBlocks, validateBlockSize(blockSize, numMiniBlocks)
+}
+
 func (e *BinaryPackedEncoding) wrap(err error) error {
 	if err != nil {
 		err = encoding.Error(e, err)
@@ -52,6 +98,9 @@ func (e *BinaryPackedEncoding) wrap(err error) error {
 	return err
 }
 
+// The encoder works on chunks of blockSize values split into numMiniBlocks
+// units of miniBlockSize values, which are the building blocks of larger
+// blocks and mini blocks when the encoding is configured with other sizes.
 const (
 	blockSize     = 128
 	numMiniBlocks = 4
@@ -62,11 +111,11 @@ const (
 	// 65K+ values should be enough for any valid use case.
 	maxSupportedBlockSize = 65536
 
-	maxHeaderLength32    = 4 * binary.MaxVarintLen64
-	maxMiniBlockLength32 = binary.MaxVarintLen64 + numMiniBlocks + (4 * blockSize)
-
-	maxHeaderLength64    = 8 * binary.MaxVarintLen64
-	maxMiniBlockLength64 = binary.MaxVarintLen64 + numMiniBlocks + (8 * blockSize)
+	maxHeaderLength32 = 4 * binary.MaxVarintLen64
+	maxHeaderLength64 = 8 * binary.MaxVarintLen64
+	// Encoding routines may write past the end of the packed values, output
+	// buffers are sized with enough padding to account for it.
+	maxBlockPadding = 32
 )
 
 var (
@@ -74,7 +123,15 @@ var (
 	encodeInt64 = encodeInt64Default
 )
 
-func encodeInt32Default(dst []byte, src []int32) []byte {
+func maxBlockLength32(valuesPerBlock, miniBlocksPerBlock int) int {
+	return binary.MaxVarintLen64 + miniBlocksPerBlock + (4 * valuesPerBlock) + maxBlockPadding
+}
+
+func maxBlockLength64(valuesPerBlock, miniBlocksPerBlock int) int {
+	return binary.MaxVarintLen64 + miniBlocksPerBlock + (8 * valuesPerBlock) + maxBlockPadding
+}
+
+func encodeInt32Default(dst []byte, src []int32, valuesPerBlock, miniBlocksPerBlock int) []byte {
 	totalValues := len(src)
 	firstValue := int32(0)
 	if totalValues > 0 {
@@ -83,34 +140,63 @@ func encodeInt32Default(dst []byte, src []int32) []byte {
 
 	n := len(dst)
 	dst = resize(dst, n+maxHeaderLength32)
-	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], blockSize, numMiniBlocks, totalValues, int64(firstValue))]
+	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], valuesPerBlock, miniBlocksPerBlock, totalValues, int64(firstValue))]
 
 	if totalValues < 2 {
 		return dst
 	}
 
+	buffer := [1][blockSize]int32{}
+	blocks := makeBlocksInt32(&buffer, valuesPerBlock)
+	bitWidthsBuffer := [2 * numMiniBlocks]byte{}
+	unitBitWidths, bitWidths := makeBitWidths(&bitWidthsBuffer, valuesPerBlock, miniBlocksPerBlock)
 	lastValue := firstValue
-	for i := 1; i < len(src); i += blockSize {
-		block := [blockSize]int32{}
-		blockLength := copy(block[:], src[i:])
 
-		lastValue = blockDeltaInt32(&block, lastValue)
-		minDelta := blockMinInt32(&block)
-		blockSubInt32(&block, minDelta)
-		blockClearInt32(&block, blockLength)
+	for i := 1; i < len(src); i += valuesPerBlock {
+		values := src[i:]
+		if len(values) > valuesPerBlock {
+			values = values[:valuesPerBlock]
+		}
+
+		numBlocks := (len(values) + blockSize - 1) / blockSize
+		minDelta := int32(math.MaxInt32)
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockLength := copy(block[:], values[j*blockSize:])
+			lastValue = blockDeltaInt32(block, lastValue)
+			blockPadInt32(block, blockLength)
+			if delta := blockMinInt32(block); delta < minDelta {
+				minDelta = delta
+			}
+		}
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockSubInt32(block, minDelta)
+			blockClearInt32(block, len(values)-j*blockSize)
+			blockBitWidthsInt32((*[numMiniBlocks]byte)(unitBitWidths[j*numMiniBlocks:]), block)
+		}
 
-		bitWidths := [numMiniBlocks]byte{}
-		blockBitWidthsInt32(&bitWidths, &block)
+		miniBlockBitWidths(bitWidths, unitBitWidths, numBlocks)
 
 		n := len(dst)
-		dst = resize(dst, n+maxMiniBlockLength32+4)
+		dst = resize(dst, n+maxBlockLength32(valuesPerBlock, miniBlocksPerBlock))
 		n += encodeBlockHeader(dst[n:], int64(minDelta), bitWidths)
 
-		for i, bitWidth := range bitWidths {
+		unitsPerMiniBlock := len(unitBitWidths) / len(bitWidths)
+
+		for j, bitWidth := range bitWidths {
 			if bitWidth != 0 {
-				miniBlock := (*[miniBlockSize]int32)(block[i*miniBlockSize:])
-				encodeMiniBlockInt32(dst[n:], miniBlock, uint(bitWidth))
-				n += (miniBlockSize * int(bitWidth)) / 8
+				for k := j * unitsPerMiniBlock; k < (j+1)*unitsPerMiniBlock; k++ {
+					// Units past the last block hold no values, the output
+					// buffer is already zeroed so they are only skipped over.
+					if k < numBlocks*numMiniBlocks {
+						miniBlock := (*[miniBlockSize]int32)(blocks[k/numMiniBlocks][(k%numMiniBlocks)*miniBlockSize:])
+						encodeMiniBlockInt32(dst[n:], miniBlock, uint(bitWidth))
+					}
+					n += (miniBlockSize * int(bitWidth)) / 8
+				}
 			}
 		}
 
@@ -120,7 +206,7 @@ func encodeInt32Default(dst []byte, src []int32) []byte {
 	return dst
 }
 
-func encodeInt64Default(dst []byte, src []int64) []byte {
+func encodeInt64Default(dst []byte, src []int64, valuesPerBlock, miniBlocksPerBlock int) []byte {
 	totalValues := len(src)
 	firstValue := int64(0)
 	if totalValues > 0 {
@@ -129,34 +215,63 @@ func encodeInt64Default(dst []byte, src []int64) []byte {
 
 	n := len(dst)
 	dst = resize(dst, n+maxHeaderLength64)
-	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], blockSize, numMiniBlocks, totalValues, firstValue)]
+	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], valuesPerBlock, miniBlocksPerBlock, totalValues, firstValue)]
 
 	if totalValues < 2 {
 		return dst
 	}
 
+	buffer := [1][blockSize]int64{}
+	blocks := makeBlocksInt64(&buffer, valuesPerBlock)
+	bitWidthsBuffer := [2 * numMiniBlocks]byte{}
+	unitBitWidths, bitWidths := makeBitWidths(&bitWidthsBuffer, valuesPerBlock, miniBlocksPerBlock)
 	lastValue := firstValue
-	for i := 1; i < len(src); i += blockSize {
-		block := [blockSize]int64{}
-		blockLength := copy(block[:], src[i:])
 
-		lastValue = blockDeltaInt64(&block, lastValue)
-		minDelta := blockMinInt64(&block)
-		blockSubInt64(&block, minDelta)
-		blockClearInt64(&block, blockLength)
+	for i := 1; i < len(src); i += valuesPerBlock {
+		values := src[i:]
+		if len(values) > valuesPerBlock {
+			values = values[:valuesPerBlock]
+		}
 
-		bitWidths := [numMiniBlocks]byte{}
-		blockBitWidthsInt64(&bitWidths, &block)
+		numBlocks := (len(values) + blockSize - 1) / blockSize
+		minDelta := int64(math.MaxInt64)
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockLength := copy(block[:], values[j*blockSize:])
+			lastValue = blockDeltaInt64(block, lastValue)
+			blockPadInt64(block, blockLength)
+			if delta := blockMinInt64(block); delta < minDelta {
+				minDelta = delta
+			}
+		}
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockSubInt64(block, minDelta)
+			blockClearInt64(block, len(values)-j*blockSize)
+			blockBitWidthsInt64((*[numMiniBlocks]byte)(unitBitWidths[j*numMiniBlocks:]), block)
+		}
+
+		miniBlockBitWidths(bitWidths, unitBitWidths, numBlocks)
 
 		n := len(dst)
-		dst = resize(dst, n+maxMiniBlockLength64+8)
+		dst = resize(dst, n+maxBlockLength64(valuesPerBlock, miniBlocksPerBlock))
 		n += encodeBlockHeader(dst[n:], minDelta, bitWidths)
 
-		for i, bitWidth := range bitWidths {
+		unitsPerMiniBlock := len(unitBitWidths) / len(bitWidths)
+
+		for j, bitWidth := range bitWidths {
 			if bitWidth != 0 {
-				miniBlock := (*[miniBlockSize]int64)(block[i*miniBlockSize:])
-				encodeMiniBlockInt64(dst[n:], miniBlock, uint(bitWidth))
-				n += (miniBlockSize * int(bitWidth)) / 8
+				for k := j * unitsPerMiniBlock; k < (j+1)*unitsPerMiniBlock; k++ {
+					// Units past the last block hold no values, the output
+					// buffer is already zeroed so they are only skipped over.
+					if k < numBlocks*numMiniBlocks {
+						miniBlock := (*[miniBlockSize]int64)(blocks[k/numMiniBlocks][(k%numMiniBlocks)*miniBlockSize:])
+						encodeMiniBlockInt64(dst[n:], miniBlock, uint(bitWidth))
+					}
+					n += (miniBlockSize * int(bitWidth)) / 8
+				}
 			}
 		}
 
@@ -174,12 +289,65 @@ func encodeBinaryPackedHeader(dst []byte, blockSize, numMiniBlocks, totalValues
 	return n
 }
 
-func encodeBlockHeader(dst []byte, minDelta int64, bitWidths [numMiniBlocks]byte) (n int) {
+func encodeBlockHeader(dst []byte, minDelta int64, bitWidths []byte) (n int) {
 	n += binary.PutVarint(dst, int64(minDelta))
-	n += copy(dst[n:], bitWidths[:])
+	n += copy(dst[n:], bitWidths)
 	return n
 }
 
+// makeBitWidths returns slices to hold the bit widths of each unit of
+// miniBlockSize values, and of each mini block of a block. The buffer is used
+// as backing array when the block has the default size.
+func makeBitWidths(buffer *[2 * numMiniBlocks]byte, valuesPerBlock, miniBlocksPerBlock int) (unitBitWidths, bitWidths []byte) {
+	if valuesPerBlock == blockSize && miniBlocksPerBlock == numMiniBlocks {
+		return buffer[:numMiniBlocks], buffer[numMiniBlocks:]
+	}
+	b := make([]byte, valuesPerBlock/miniBlockSize+miniBlocksPerBlock)
+	return b[:valuesPerBlock/miniBlockSize], b[valuesPerBlock/miniBlockSize:]
+}
+
+// miniBlockBitWidths sets the bit width of each mini block to the largest bit
+// width of the units it is made of. Units past the first numBlocks hold no
+// values and are given a bit width of zero.
+func miniBlockBitWidths(bitWidths, unitBitWidths []byte, numBlocks int) {
+	clear := unitBitWidths[numBlocks*numMiniBlocks:]
+	for i := range clear {
+		clear[i] = 0
+	}
+
+	unitsPerMiniBlock := len(unitBitWidths) / len(bitWidths)
+
+	for i := range bitWidths {
+		bitWidth := byte(0)
+
+		for _, w := range unitBitWidths[i*unitsPerMiniBlock : (i+1)*unitsPerMiniBlock] {
+			if w > bitWidth {
+				bitWidth = w
+			}
+		}
+
+		bitWidths[i] = bitWidth
+	}
+}
+
+func makeBlocksInt32(buffer *[1][blockSize]int32, valuesPerBlock int) [][blockSize]int32 {
+	if valuesPerBlock == blockSize {
+		return buffer[:]
+	}
+	return make([][blockSize]int32, valuesPerBlock/blockSize)
+}
+
+// blockPadInt32 sets the deltas past the end of a partial block to the first
+// delta, so they do not lower the minimum delta of the block.
+func blockPadInt32(block *[blockSize]int32, blockLength int) {
+	if blockLength < blockSize {
+		pad := block[blockLength:]
+		for i := range pad {
+			pad[i] = block[0]
+		}
+	}
+}
+
 func blockClearInt32(block *[blockSize]int32, blockLength int) {
 	if blockLength < blockSize {
 		clear := block[blockLength:]
@@ -228,6 +396,24 @@ func blockBitWidthsInt32(bitWidths *[numMiniBlocks]byte, block *[blockSize]int32
 	}
 }
 
+func makeBlocksInt64(buffer *[1][blockSize]int64, valuesPerBlock int) [][blockSize]int64 {
+	if valuesPerBlock == blockSize {
+		return buffer[:]
+	}
+	return make([][blockSize]int64, valuesPerBlock/blockSize)
+}
+
+// blockPadInt64 sets the deltas past the end of a partial block to the first
+// delta, so they do not lower the minimum delta of the block.
+func blockPadInt64(block *[blockSize]int64, blockLength int) {
+	if blockLength < blockSize {
+		pad := block[blockLength:]
+		for i := range pad {
+			pad[i] = block[0]
+		}
+	}
+}
+
 func blockClearInt64(block *[blockSize]int64, blockLength int) {
 	if blockLength < blockSize {
 		clear := block[blockLength:]
@@ -434,21 +620,30 @@ func decodeBinaryPackedHeader(src []byte) (blockSize, numMiniBlocks, totalValues
 	}
 	i += n
 
+	if err = validateBlockSize(blockSize, numMiniBlocks); err == nil {
+		if totalValues < 0 {
+			err = fmt.Errorf("invalid total number of values is negative (%d)", totalValues)
+		} else if totalValues > math.MaxInt32 {
+			err = fmt.Errorf("too many values: %d", totalValues)
+		}
+	}
+
+	return blockSize, numMiniBlocks, totalValues, firstValue, src[i:], err
+}
+
+func validateBlockSize(blockSize, numMiniBlocks int) error {
 	if numMiniBlocks == 0 {
-		err = fmt.Errorf("invalid number of mini block (%d)", numMiniBlocks)
+		return fmt.Errorf("invalid number of mini block (%d)", numMiniBlocks)
 	} else if (blockSize <= 0) || (blockSize%128) != 0 {
-		err = fmt.Errorf("invalid block size is not a multiple of 128 (%d)", blockSize)
+		return fmt.Errorf("invalid block size is not a multiple of 128 (%d)", blockSize)
 	} else if blockSize > maxSupportedBlockSize {
-		err = fmt.Errorf("invalid block size is too large (%d)", blockSize)
-	} else if miniBlockSize := blockSize / numMiniBlocks; (numMiniBlocks <= 0) || (miniBlockSize%32) != 0 {
-		err = fmt.Errorf("invalid mini block size is not a multiple of 32 (%d)", miniBlockSize)
-	} else if totalValues < 0 {
-		err = fmt.Errorf("invalid total number of values is negative (%d)", totalValues)
-	} else if totalValues > math.MaxInt32 {
-		err = fmt.Errorf("too many values: %d", totalValues)
+		return fmt.Errorf("invalid block size is too large (%d)", blockSize)
+	} else if (numMiniBlocks <= 0) || (blockSize%numMiniBlocks) != 0 {
+		return fmt.Errorf("invalid block size is not a multiple of the number of mini blocks (%d/%d)", blockSize, numMiniBlocks)
+	} else if miniBlockSize := blockSize / numMiniBlocks; (miniBlockSize % 32) != 0 {
+		return fmt.Errorf("invalid mini block size is not a multiple of 32 (%d)", miniBlockSize)
 	}
-
-	return blockSize, numMiniBlocks, totalValues, firstValue, src[i:], err
+	return nil
 }
 
 func decodeBinaryPackedBlock(src []byte, numMiniBlocks int) (minDelta int64, bitWidths, next []byte, err error) {
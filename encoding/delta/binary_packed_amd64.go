@@ -3,6 +3,8 @@
 package delta
 
 import (
+	"math"
+
 	"github.com/segmentio/parquet-go/internal/unsafecast"
 	"golang.org/x/sys/cpu"
 )
@@ -38,6 +40,9 @@ func encodeMiniBlockInt32x2bitsAVX2(dst *byte, src *[miniBlockSize]int32)
 //go:noescape
 func encodeMiniBlockInt32x3to16bitsAVX2(dst *byte, src *[miniBlockSize]int32, bitWidth uint)
 
+//go:noescape
+func encodeMiniBlockInt32x17to31bitsAVX2(dst *byte, src *[miniBlockSize]int32, bitWidth uint)
+
 //go:noescape
 func encodeMiniBlockInt32x32bitsAVX2(dst *byte, src *[miniBlockSize]int32)
 
@@ -56,11 +61,11 @@ func encodeMiniBlockInt32AVX2(dst *byte, src *[miniBlockSize]int32, bitWidth uin
 	case bitWidth <= 16:
 		encodeMiniBlockInt32x3to16bitsAVX2(dst, src, bitWidth)
 	default:
-		encodeMiniBlockInt32Default(dst, src, bitWidth)
+		encodeMiniBlockInt32x17to31bitsAVX2(dst, src, bitWidth)
 	}
 }
 
-func encodeInt32AVX2(dst []byte, src []int32) []byte {
+func encodeInt32AVX2(dst []byte, src []int32, valuesPerBlock, miniBlocksPerBlock int) []byte {
 	totalValues := len(src)
 	firstValue := int32(0)
 	if totalValues > 0 {
@@ -69,34 +74,63 @@ func encodeInt32AVX2(dst []byte, src []int32) []byte {
 
 	n := len(dst)
 	dst = resize(dst, n+maxHeaderLength32)
-	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], blockSize, numMiniBlocks, totalValues, int64(firstValue))]
+	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], valuesPerBlock, miniBlocksPerBlock, totalValues, int64(firstValue))]
 
 	if totalValues < 2 {
 		return dst
 	}
 
+	buffer := [1][blockSize]int32{}
+	blocks := makeBlocksInt32(&buffer, valuesPerBlock)
+	bitWidthsBuffer := [2 * numMiniBlocks]byte{}
+	unitBitWidths, bitWidths := makeBitWidths(&bitWidthsBuffer, valuesPerBlock, miniBlocksPerBlock)
 	lastValue := firstValue
-	for i := 1; i < len(src); i += blockSize {
-		block := [blockSize]int32{}
-		blockLength := copy(block[:], src[i:])
 
-		lastValue = blockDeltaInt32AVX2(&block, lastValue)
-		minDelta := blockMinInt32AVX2(&block)
-		blockSubInt32AVX2(&block, minDelta)
-		blockClearInt32(&block, blockLength)
+	for i := 1; i < len(src); i += valuesPerBlock {
+		values := src[i:]
+		if len(values) > valuesPerBlock {
+			values = values[:valuesPerBlock]
+		}
 
-		bitWidths := [numMiniBlocks]byte{}
-		blockBitWidthsInt32AVX2(&bitWidths, &block)
+		numBlocks := (len(values) + blockSize - 1) / blockSize
+		minDelta := int32(math.MaxInt32)
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockLength := copy(block[:], values[j*blockSize:])
+			lastValue = blockDeltaInt32AVX2(block, lastValue)
+			blockPadInt32(block, blockLength)
+			if delta := blockMinInt32AVX2(block); delta < minDelta {
+				minDelta = delta
+			}
+		}
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockSubInt32AVX2(block, minDelta)
+			blockClearInt32(block, len(values)-j*blockSize)
+			blockBitWidthsInt32AVX2((*[numMiniBlocks]byte)(unitBitWidths[j*numMiniBlocks:]), block)
+		}
+
+		miniBlockBitWidths(bitWidths, unitBitWidths, numBlocks)
 
 		n := len(dst)
-		dst = resize(dst, n+maxMiniBlockLength32+16)
+		dst = resize(dst, n+maxBlockLength32(valuesPerBlock, miniBlocksPerBlock))
 		n += encodeBlockHeader(dst[n:], int64(minDelta), bitWidths)
 
-		for i, bitWidth := range bitWidths {
+		unitsPerMiniBlock := len(unitBitWidths) / len(bitWidths)
+
+		for j, bitWidth := range bitWidths {
 			if bitWidth != 0 {
-				miniBlock := (*[miniBlockSize]int32)(block[i*miniBlockSize:])
-				encodeMiniBlockInt32AVX2(&dst[n], miniBlock, uint(bitWidth))
-				n += (miniBlockSize * int(bitWidth)) / 8
+				for k := j * unitsPerMiniBlock; k < (j+1)*unitsPerMiniBlock; k++ {
+					// Units past the last block hold no values, the output
+					// buffer is already zeroed so they are only skipped over.
+					if k < numBlocks*numMiniBlocks {
+						miniBlock := (*[miniBlockSize]int32)(blocks[k/numMiniBlocks][(k%numMiniBlocks)*miniBlockSize:])
+						encodeMiniBlockInt32AVX2(&dst[n], miniBlock, uint(bitWidth))
+					}
+					n += (miniBlockSize * int(bitWidth)) / 8
+				}
 			}
 		}
 
@@ -130,6 +164,9 @@ func encodeMiniBlockInt64x2bitsAVX2(dst *byte, src *[miniBlockSize]int64)
 //go:noescape
 func encodeMiniBlockInt64x64bitsAVX2(dst *byte, src *[miniBlockSize]int64)
 
+//go:noescape
+func narrowMiniBlockInt64AVX2(dst *[miniBlockSize]int32, src *[miniBlockSize]int64)
+
 func encodeMiniBlockInt64(dst []byte, src *[miniBlockSize]int64, bitWidth uint) {
 	encodeMiniBlockInt64Default(&dst[0], src, bitWidth)
 }
@@ -142,12 +179,19 @@ func encodeMiniBlockInt64AVX2(dst *byte, src *[miniBlockSize]int64, bitWidth uin
 		encodeMiniBlockInt64x2bitsAVX2(dst, src)
 	case bitWidth == 64:
 		encodeMiniBlockInt64x64bitsAVX2(dst, src)
+	case bitWidth <= 32:
+		// Values of up to 32 bits are packed the same way regardless of the
+		// size of the integers holding them, the mini block is narrowed so we
+		// can use the vectorized routines designed for 32 bits integers.
+		miniBlock := [miniBlockSize]int32{}
+		narrowMiniBlockInt64AVX2(&miniBlock, src)
+		encodeMiniBlockInt32AVX2(dst, &miniBlock, bitWidth)
 	default:
 		encodeMiniBlockInt64Default(dst, src, bitWidth)
 	}
 }
 
-func encodeInt64AVX2(dst []byte, src []int64) []byte {
+func encodeInt64AVX2(dst []byte, src []int64, valuesPerBlock, miniBlocksPerBlock int) []byte {
 	totalValues := len(src)
 	firstValue := int64(0)
 	if totalValues > 0 {
@@ -156,34 +200,63 @@ func encodeInt64AVX2(dst []byte, src []int64) []byte {
 
 	n := len(dst)
 	dst = resize(dst, n+maxHeaderLength64)
-	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], blockSize, numMiniBlocks, totalValues, int64(firstValue))]
+	dst = dst[:n+encodeBinaryPackedHeader(dst[n:], valuesPerBlock, miniBlocksPerBlock, totalValues, firstValue)]
 
 	if totalValues < 2 {
 		return dst
 	}
 
+	buffer := [1][blockSize]int64{}
+	blocks := makeBlocksInt64(&buffer, valuesPerBlock)
+	bitWidthsBuffer := [2 * numMiniBlocks]byte{}
+	unitBitWidths, bitWidths := makeBitWidths(&bitWidthsBuffer, valuesPerBlock, miniBlocksPerBlock)
 	lastValue := firstValue
-	for i := 1; i < len(src); i += blockSize {
-		block := [blockSize]int64{}
-		blockLength := copy(block[:], src[i:])
 
-		lastValue = blockDeltaInt64AVX2(&block, lastValue)
-		minDelta := blockMinInt64AVX2(&block)
-		blockSubInt64AVX2(&block, minDelta)
-		blockClearInt64(&block, blockLength)
+	for i := 1; i < len(src); i += valuesPerBlock {
+		values := src[i:]
+		if len(values) > valuesPerBlock {
+			values = values[:valuesPerBlock]
+		}
+
+		numBlocks := (len(values) + blockSize - 1) / blockSize
+		minDelta := int64(math.MaxInt64)
 
-		bitWidths := [numMiniBlocks]byte{}
-		blockBitWidthsInt64AVX2(&bitWidths, &block)
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockLength := copy(block[:], values[j*blockSize:])
+			lastValue = blockDeltaInt64AVX2(block, lastValue)
+			blockPadInt64(block, blockLength)
+			if delta := blockMinInt64AVX2(block); delta < minDelta {
+				minDelta = delta
+			}
+		}
+
+		for j := 0; j < numBlocks; j++ {
+			block := &blocks[j]
+			blockSubInt64AVX2(block, minDelta)
+			blockClearInt64(block, len(values)-j*blockSize)
+			blockBitWidthsInt64AVX2((*[numMiniBlocks]byte)(unitBitWidths[j*numMiniBlocks:]), block)
+		}
+
+		miniBlockBitWidths(bitWidths, unitBitWidths, numBlocks)
 
 		n := len(dst)
-		dst = resize(dst, n+maxMiniBlockLength64+16)
-		n += encodeBlockHeader(dst[n:], int64(minDelta), bitWidths)
+		dst = resize(dst, n+maxBlockLength64(valuesPerBlock, miniBlocksPerBlock))
+		n += encodeBlockHeader(dst[n:], minDelta, bitWidths)
+
+		unitsPerMiniBlock := len(unitBitWidths) / len(bitWidths)
 
-		for i, bitWidth := range bitWidths {
+		for j, bitWidth := range bitWidths {
 			if bitWidth != 0 {
-				miniBlock := (*[miniBlockSize]int64)(block[i*miniBlockSize:])
-				encodeMiniBlockInt64AVX2(&dst[n], miniBlock, uint(bitWidth))
-				n += (miniBlockSize * int(bitWidth)) / 8
+				for k := j * unitsPerMiniBlock; k < (j+1)*unitsPerMiniBlock; k++ {
+					// Units past the last block hold no values, the output
+					// buffer is already zeroed so they are only skipped over.
+					if k < numBlocks*numMiniBlocks {
+						miniBlock := (*[miniBlockSize]int64)(blocks[k/numMiniBlocks][(k%numMiniBlocks)*miniBlockSize:])
+						encodeMiniBlockInt64AVX2(&dst[n], miniBlock, uint(bitWidth))
+					}
+					n += (miniBlockSize * int(bitWidth)) / 8
+				}
 			}
 		}
 
@@ -413,6 +413,76 @@ loop:
     VZEROUPPER
     RET
 
+// encodeMiniBlockInt32x17to31bitsAVX2 packs 32 bit integers into values of 17
+// to 31 bits in the output buffer.
+//
+// The algorithm follows the same approach as the one used for 3 to 16 bits
+// values, but since only two values fit in each 64 bits word, it needs one
+// more step to merge the partial results. Each iteration processes 8 values,
+// which produce exactly bitWidth bytes of output, but up to 256 bits may be
+// written to the output buffer. The encodeInt32AVX2 method adds enough padding
+// when sizing the output buffer to account for this requirement.
+//
+// The first step pairs values into the 64 bits words of the YMM register
+// (VPSRLQ, VPBLENDD, VPSLLVQ, VPOR), then the pairs are merged into 128 bits
+// sequences in each lane (VPUNPCKHQDQ, VPSLLVQ, VPSRLVQ), and finally the upper
+// lane is shifted by 4 x bitWidth bits and merged with the lower lane (VPERMQ,
+// VPSLLVQ, VPSRLVQ) to compose the 256 bits written to the output buffer.
+//
+// func encodeMiniBlockInt32x17to31bitsAVX2(dst *byte, src *[miniBlockSize]int32, bitWidth uint)
+TEXT ·encodeMiniBlockInt32x17to31bitsAVX2(SB), NOSPLIT, $0-24
+    MOVQ dst+0(FP), AX
+    MOVQ src+8(FP), BX
+    MOVQ bitWidth+16(FP), CX
+
+    VPBROADCASTQ bitWidth+16(FP), Y6 // [1*bitWidth...]
+    VPSLLQ $1, Y6, Y7                // [2*bitWidth...]
+    VPSLLQ $2, Y6, Y12               // [4*bitWidth...]
+
+    VPBROADCASTQ sixtyfour<>(SB), Y10
+    VPSUBQ Y7, Y10, Y11  // [64-2*bitWidth...]
+    VPSUBQ Y10, Y12, Y12 // [4*bitWidth-64...]
+    VPSUBQ Y12, Y10, Y13 // [128-4*bitWidth...]
+
+    // Shift counts of 64 zero the 64 bits words they apply to.
+    VPBLENDD $0b11001100, Y10, Y7, Y8   // [2*bitWidth,64,2*bitWidth,64]
+    VPBLENDD $0b11001100, Y11, Y10, Y9  // [64,64-2*bitWidth,64,64-2*bitWidth]
+    VPBLENDD $0b11000011, Y10, Y12, Y14 // [64,4*bitWidth-64,4*bitWidth-64,64]
+    VPBLENDD $0b00001111, Y10, Y13, Y15 // [64,64,128-4*bitWidth,128-4*bitWidth]
+
+    VPXOR Y5, Y5, Y5
+    XORQ SI, SI
+loop:
+    VMOVDQU (BX)(SI*4), Y0
+    VPSRLQ $32, Y0, Y1
+    VPBLENDD $0b10101010, Y5, Y0, Y0
+    VPSLLVQ Y6, Y1, Y1
+    VPOR Y1, Y0, Y0 // [p0,p1,p2,p3]
+
+    VPUNPCKHQDQ Y0, Y0, Y1 // [p1,p1,p3,p3]
+    VPSLLVQ Y8, Y1, Y2
+    VPSRLVQ Y9, Y1, Y3
+    VPBLENDD $0b11001100, Y5, Y0, Y0
+    VPOR Y2, Y0, Y0
+    VPOR Y3, Y0, Y0 // [a0,a1,b0,b1]
+
+    VPERMQ $0b00111000, Y0, Y1 // [a0,b0,b1,a0]
+    VPERMQ $0b11100000, Y0, Y2 // [a0,a0,b0,b1]
+    VPSLLVQ Y14, Y1, Y1
+    VPSRLVQ Y15, Y2, Y2
+    VPBLENDD $0b11110000, Y5, Y0, Y0
+    VPOR Y1, Y0, Y0
+    VPOR Y2, Y0, Y0
+
+    VMOVDQU Y0, (AX)
+
+    ADDQ CX, AX
+    ADDQ $8, SI
+    CMPQ SI, $miniBlockSize
+    JNE loop
+    VZEROUPPER
+    RET
+
 GLOBL sixtyfour<>(SB), RODATA|NOPTR, $32
 DATA sixtyfour<>+0(SB)/8, $64
 DATA sixtyfour<>+8(SB)/8, $64
@@ -875,6 +945,27 @@ TEXT ·encodeMiniBlockInt64x2bitsAVX2(SB), NOSPLIT, $0-16
     VZEROUPPER
     RET
 
+// narrowMiniBlockInt64AVX2 truncates the 64 bits integers of a mini block to
+// 32 bits, which is used to pack values of up to 32 bits with the vectorized
+// routines designed for 32 bits integers.
+//
+// func narrowMiniBlockInt64AVX2(dst *[miniBlockSize]int32, src *[miniBlockSize]int64)
+TEXT ·narrowMiniBlockInt64AVX2(SB), NOSPLIT, $0-16
+    MOVQ dst+0(FP), AX
+    MOVQ src+8(FP), BX
+    XORQ SI, SI
+loop:
+    VMOVDQU 0(BX)(SI*8), Y0
+    VMOVDQU 32(BX)(SI*8), Y1
+    VSHUFPS $0b10001000, Y1, Y0, Y0
+    VPERMQ $0b11011000, Y0, Y0
+    VMOVDQU Y0, (AX)(SI*4)
+    ADDQ $8, SI
+    CMPQ SI, $miniBlockSize
+    JNE loop
+    VZEROUPPER
+    RET
+
 // func encodeMiniBlockInt64x64bitsAVX2(dst *byte, src *[miniBlockSize]int64)
 TEXT ·encodeMiniBlockInt64x64bitsAVX2(SB), NOSPLIT, $0-16
     MOVQ dst+0(FP), AX
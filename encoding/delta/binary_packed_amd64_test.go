@@ -3,6 +3,9 @@
 package delta
 
 import (
+	"bytes"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"golang.org/x/sys/cpu"
@@ -77,6 +80,48 @@ func TestBlockDeltaInt64AVX2(t *testing.T) {
 	testBlockDeltaInt64(t, blockDeltaInt64AVX2)
 }
 
+func TestEncodeInt32AVX2(t *testing.T) {
+	requireAVX2(t)
+	prng := rand.New(rand.NewSource(0))
+
+	for _, size := range [][2]int{{128, 4}, {128, 1}, {512, 16}, {1024, 4}} {
+		for _, bitWidth := range []uint{0, 1, 2, 5, 16, 17, 24, 31, 32} {
+			t.Run(fmt.Sprintf("blockSize=%d,numMiniBlocks=%d,bitWidth=%d", size[0], size[1], bitWidth), func(t *testing.T) {
+				src := make([]int32, 3*size[0]+prng.Intn(size[0]))
+				for i := range src {
+					src[i] = int32(prng.Uint32() >> (32 - bitWidth))
+				}
+				want := encodeInt32Default(nil, src, size[0], size[1])
+				got := encodeInt32AVX2(nil, src, size[0], size[1])
+				if !bytes.Equal(want, got) {
+					t.Error("output mismatch between the default and AVX2 encoders")
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeInt64AVX2(t *testing.T) {
+	requireAVX2(t)
+	prng := rand.New(rand.NewSource(0))
+
+	for _, size := range [][2]int{{128, 4}, {128, 1}, {512, 16}, {1024, 4}} {
+		for _, bitWidth := range []uint{0, 1, 2, 5, 16, 17, 31, 32, 33, 63, 64} {
+			t.Run(fmt.Sprintf("blockSize=%d,numMiniBlocks=%d,bitWidth=%d", size[0], size[1], bitWidth), func(t *testing.T) {
+				src := make([]int64, 3*size[0]+prng.Intn(size[0]))
+				for i := range src {
+					src[i] = int64(prng.Uint64() >> (64 - bitWidth))
+				}
+				want := encodeInt64Default(nil, src, size[0], size[1])
+				got := encodeInt64AVX2(nil, src, size[0], size[1])
+				if !bytes.Equal(want, got) {
+					t.Error("output mismatch between the default and AVX2 encoders")
+				}
+			})
+		}
+	}
+}
+
 func TestBlockMinInt64AVX2(t *testing.T) {
 	requireAVX2(t)
 	testBlockMinInt64(t, blockMinInt64AVX2)
@@ -117,7 +117,7 @@ func testEncodeMiniBlockInt32(t *testing.T, f func([]byte, *[miniBlockSize]int32
 			got := [4*miniBlockSize + 32]byte{}
 			src := [miniBlockSize]int32{}
 			for i := range src {
-				src[i] = int32(i) & int32((1<<bitWidth)-1)
+				src[i] = int32(uint32(i)*0x9E3779B9) & int32((1<<bitWidth)-1)
 			}
 
 			want := [4*miniBlockSize + 32]byte{}
@@ -296,7 +296,7 @@ func testEncodeMiniBlockInt64(t *testing.T, f func([]byte, *[miniBlockSize]int64
 			got := [8*miniBlockSize + 64]byte{}
 			src := [miniBlockSize]int64{}
 			for i := range src {
-				src[i] = int64(i) & int64((1<<bitWidth)-1)
+				src[i] = int64(uint64(i)*0x9E3779B97F4A7C15) & int64((1<<bitWidth)-1)
 			}
 
 			want := [8*miniBlockSize + 64]byte{}
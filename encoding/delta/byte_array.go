@@ -56,8 +56,8 @@ func (e *ByteArrayEncoding) EncodeByteArray(dst []byte, src []byte, offsets []ui
 	}
 
 	dst = dst[:0]
-	dst = encodeInt32(dst, prefix.values)
-	dst = encodeInt32(dst, length.values)
+	dst = encodeInt32(dst, prefix.values, blockSize, numMiniBlocks)
+	dst = encodeInt32(dst, length.values, blockSize, numMiniBlocks)
 	dst = resize(dst, len(dst)+totalSize)
 
 	if len(offsets) > 0 {
@@ -109,8 +109,8 @@ func (e *ByteArrayEncoding) EncodeFixedLenByteArray(dst []byte, src []byte, size
 	}
 
 	dst = dst[:0]
-	dst = encodeInt32(dst, prefix.values)
-	dst = encodeInt32(dst, length.values)
+	dst = encodeInt32(dst, prefix.values, blockSize, numMiniBlocks)
+	dst = encodeInt32(dst, length.values, blockSize, numMiniBlocks)
 	dst = resize(dst, len(dst)+totalSize)
 
 	b := dst[len(dst)-totalSize:]
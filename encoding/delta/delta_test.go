@@ -5,7 +5,9 @@ package delta_test
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go/encoding/delta"
 	"github.com/segmentio/parquet-go/encoding/fuzz"
@@ -58,3 +60,137 @@ func TestEncodeInt64(t *testing.T) {
 		})
 	}
 }
+
+var blockSizes = [...]delta.BinaryPackedEncoding{
+	{BlockSize: 128, NumMiniBlocks: 1},
+	{BlockSize: 256, NumMiniBlocks: 2},
+	{BlockSize: 512, NumMiniBlocks: 16},
+	{BlockSize: 1024, NumMiniBlocks: 8},
+}
+
+func TestEncodeInt32BlockSizes(t *testing.T) {
+	for i := range blockSizes {
+		enc := &blockSizes[i]
+		for _, bitWidth := range []uint{0, 1, 7, 16, 17, 31, 32} {
+			t.Run(fmt.Sprintf("blockSize=%d,numMiniBlocks=%d,bitWidth=%d", enc.BlockSize, enc.NumMiniBlocks, bitWidth), func(t *testing.T) {
+				test.EncodeInt32(t, enc, enc.BlockSize-2, 2*enc.BlockSize+2, bitWidth)
+			})
+		}
+	}
+}
+
+func TestEncodeInt64BlockSizes(t *testing.T) {
+	for i := range blockSizes {
+		enc := &blockSizes[i]
+		for _, bitWidth := range []uint{0, 1, 7, 16, 17, 31, 32, 33, 64} {
+			t.Run(fmt.Sprintf("blockSize=%d,numMiniBlocks=%d,bitWidth=%d", enc.BlockSize, enc.NumMiniBlocks, bitWidth), func(t *testing.T) {
+				test.EncodeInt64(t, enc, enc.BlockSize-2, 2*enc.BlockSize+2, bitWidth)
+			})
+		}
+	}
+}
+
+func TestEncodeInvalidBlockSizes(t *testing.T) {
+	for _, enc := range []delta.BinaryPackedEncoding{
+		{BlockSize: 100},
+		{BlockSize: 128, NumMiniBlocks: 3},
+		{BlockSize: 128, NumMiniBlocks: 8},
+		{BlockSize: 1152, NumMiniBlocks: 35},
+		{BlockSize: 131072},
+		{BlockSize: -128},
+		{NumMiniBlocks: -1},
+	} {
+		if _, err := enc.EncodeInt64(nil, []int64{1, 2, 3}); err == nil {
+			t.Errorf("encoding with block size %d and %d mini blocks did not fail", enc.BlockSize, enc.NumMiniBlocks)
+		}
+	}
+}
+
+// monotonicBlockSizes are the configurations compared by the benchmarks of
+// monotonically increasing columns.
+var monotonicBlockSizes = [...]delta.BinaryPackedEncoding{
+	{BlockSize: 128, NumMiniBlocks: 4},
+	{BlockSize: 256, NumMiniBlocks: 4},
+	{BlockSize: 512, NumMiniBlocks: 4},
+	{BlockSize: 512, NumMiniBlocks: 8},
+	{BlockSize: 1024, NumMiniBlocks: 4},
+	{BlockSize: 1024, NumMiniBlocks: 8},
+	{BlockSize: 1024, NumMiniBlocks: 16},
+	{BlockSize: 2048, NumMiniBlocks: 8},
+	{BlockSize: 4096, NumMiniBlocks: 16},
+}
+
+// monotonicTimestamps generates timestamps in nanoseconds of events occurring
+// about every millisecond, with some jitter.
+func monotonicTimestamps(n int) []int64 {
+	prng := rand.New(rand.NewSource(0))
+	values := make([]int64, n)
+	value := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	for i := range values {
+		value += int64(time.Millisecond) + prng.Int63n(int64(100*time.Microsecond))
+		values[i] = value
+	}
+	return values
+}
+
+// monotonicIDs generates identifiers which mostly increase by one, with gaps
+// of a few values.
+func monotonicIDs(n int) []int64 {
+	prng := rand.New(rand.NewSource(0))
+	values := make([]int64, n)
+	value := int64(1e9)
+	for i := range values {
+		value++
+		if prng.Intn(16) == 0 {
+			value += prng.Int63n(64)
+		}
+		values[i] = value
+	}
+	return values
+}
+
+func BenchmarkEncodeInt64Monotonic(b *testing.B) {
+	for _, column := range []struct {
+		name   string
+		values []int64
+	}{
+		{"timestamps", monotonicTimestamps(10e3)},
+		{"ids", monotonicIDs(10e3)},
+	} {
+		for i := range monotonicBlockSizes {
+			enc := &monotonicBlockSizes[i]
+			b.Run(fmt.Sprintf("%s/blockSize=%d,numMiniBlocks=%d", column.name, enc.BlockSize, enc.NumMiniBlocks), func(b *testing.B) {
+				buffer, _ := enc.EncodeInt64(nil, column.values)
+				b.SetBytes(8 * int64(len(column.values)))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					buffer, _ = enc.EncodeInt64(buffer, column.values)
+				}
+				b.ReportMetric(float64(8*len(buffer))/float64(len(column.values)), "bits/value")
+			})
+		}
+	}
+}
+
+func BenchmarkDecodeInt64Monotonic(b *testing.B) {
+	for _, column := range []struct {
+		name   string
+		values []int64
+	}{
+		{"timestamps", monotonicTimestamps(10e3)},
+		{"ids", monotonicIDs(10e3)},
+	} {
+		for i := range monotonicBlockSizes {
+			enc := &monotonicBlockSizes[i]
+			b.Run(fmt.Sprintf("%s/blockSize=%d,numMiniBlocks=%d", column.name, enc.BlockSize, enc.NumMiniBlocks), func(b *testing.B) {
+				buffer, _ := enc.EncodeInt64(nil, column.values)
+				values, _ := enc.DecodeInt64(nil, buffer)
+				b.SetBytes(8 * int64(len(column.values)))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					values, _ = enc.DecodeInt64(values, buffer)
+				}
+			})
+		}
+	}
+}
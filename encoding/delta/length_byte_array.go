@@ -29,7 +29,7 @@ func (e *LengthByteArrayEncoding) EncodeByteArray(dst []byte, src []byte, offset
 	encodeByteArrayLengths(length.values, offsets)
 
 	dst = dst[:0]
-	dst = encodeInt32(dst, length.values)
+	dst = encodeInt32(dst, length.values, blockSize, numMiniBlocks)
 	dst = append(dst, src...)
 	return dst, nil
 }
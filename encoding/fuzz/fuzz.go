@@ -101,24 +101,23 @@ func EncodeByteArray(f *testing.F, e encoding.Encoding) {
 			return dst, nil
 		},
 
-		func(dst []string, src []byte, prng *rand.Rand) []string {
-			limit := len(src)/10 + 1
-
-			for i := 0; i < len(src); {
-				n := prng.Intn(limit) + 1
-				r := len(src) - i
-				if n > r {
-					n = r
-				}
-				dst = append(dst, unsafecast.BytesToString(src[i:i+n]))
-				i += n
-			}
-
-			return dst
-		},
+		generateByteArray,
 	)
 }
 
+// Generate appends to dst the values of type T that the bytes of the fuzzer
+// input src represent. It can be used to produce the values of columns in fuzz
+// tests which are not about a specific encoding.
+func Generate[T comparable](dst []T, src []byte) []T {
+	return generate(dst, src, nil)
+}
+
+// GenerateByteArray splits the fuzzer input src in byte arrays of random
+// lengths, and appends them to dst. The byte arrays share the memory of src.
+func GenerateByteArray(dst []string, src []byte, prng *rand.Rand) []string {
+	return generateByteArray(dst, src, prng)
+}
+
 type encodingFunc[T comparable] func(encoding.Encoding, []byte, []T) ([]byte, error)
 
 type decodingFunc[T comparable] func(encoding.Encoding, []T, []byte) ([]T, error)
@@ -172,3 +171,19 @@ func equal[T comparable](a, b []T) bool {
 func generate[T comparable](dst []T, src []byte, prng *rand.Rand) []T {
 	return append(dst[:0], unsafecast.Slice[T](src)...)
 }
+
+func generateByteArray(dst []string, src []byte, prng *rand.Rand) []string {
+	limit := len(src)/10 + 1
+
+	for i := 0; i < len(src); {
+		n := prng.Intn(limit) + 1
+		r := len(src) - i
+		if n > r {
+			n = r
+		}
+		dst = append(dst, unsafecast.BytesToString(src[i:i+n]))
+		i += n
+	}
+
+	return dst
+}
@@ -358,6 +358,9 @@ func decodeInt32(dst, src []byte, bitWidth uint) ([]byte, error) {
 		if bitpacked {
 			offset := len(dst)
 			length := int(count * bitWidth)
+			if i+length > len(src) {
+				return dst, fmt.Errorf("decoding bit-packed block of %d values: %w", 8*count, io.ErrUnexpectedEOF)
+			}
 			dst = resize(dst, offset+4*8*int(count))
 
 			// The bitpack.UnpackInt32 function requires the input to be padded
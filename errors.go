@@ -38,6 +38,10 @@ var (
 	// which does not use a dictionary encoding.
 	ErrUnexpectedDictionaryPage = errors.New("unexpected dictionary page")
 
+	// ErrMissingDictionary is an error returned when a page reader encounters
+	// a dictionary-encoded data page in a column which has no dictionary.
+	ErrMissingDictionary = errors.New("missing dictionary")
+
 	// ErrMissingPageHeader is an error returned when a page reader encounters
 	// a malformed page header which is missing page-type-specific information.
 	ErrMissingPageHeader = errors.New("missing page header")
@@ -55,12 +59,37 @@ var (
 	// file with more than MaxRowGroups row groups.
 	ErrTooManyRowGroups = errors.New("the limit of 32767 row groups has been reached")
 
+	// ErrLimitExceeded is an error returned when reading a parquet file which
+	// exceeds one of the limits configured with FileReadLimits. The errors are
+	// of type *LimitError and wrap ErrLimitExceeded.
+	ErrLimitExceeded = errors.New("parquet file exceeds read limits")
+
 	// ErrConversion is used to indicate that a conversion betwen two values
 	// cannot be done because there are no rules to translate between their
 	// physical types.
 	ErrInvalidConversion = errors.New("invalid conversion between parquet values")
 )
 
+// LimitError is the error type returned when a parquet file declares a size or
+// count which exceeds one of its ReadLimits, or when a page decompresses to
+// more data than the size declared in its header.
+type LimitError struct {
+	// Name of the ReadLimits field which was exceeded (e.g. "MaxPageSize"), or
+	// "UncompressedPageSize" when a page exceeds its declared size.
+	Limit string
+	// Value declared in the file. For decompressed data, decoding stops after
+	// the limit was exceeded so this is a lower bound of the actual size.
+	Value int64
+	// Value of the limit.
+	Max int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s: %d > %d", ErrLimitExceeded, e.Limit, e.Value, e.Max)
+}
+
+func (e *LimitError) Unwrap() error { return ErrLimitExceeded }
+
 type errno int
 
 const (
@@ -0,0 +1,157 @@
+package parquet_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type fieldIDRow struct {
+	Name  string `parquet:"name,id(1)"`
+	Value int64  `parquet:"value,optional,id(2)"`
+	Tags  []struct {
+		Key string `parquet:"key,id(4)"`
+	} `parquet:"tags,id(3)"`
+}
+
+func TestFieldIDStructTag(t *testing.T) {
+	schema := parquet.SchemaOf(new(fieldIDRow))
+
+	const expected = `message fieldIDRow {
+	required binary name (STRING) = 1;
+	optional int64 value (INT(64,true)) = 2;
+	repeated group tags = 3 {
+		required binary key (STRING) = 4;
+	}
+}`
+	if s := schema.String(); s != expected {
+		t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	parsed, err := parquet.ParseSchema(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := parsed.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Write(&fieldIDRow{Name: "A", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := f.Schema().String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+	if id := f.Root().Column("tags").ID(); id != 3 {
+		t.Errorf("wrong field id for column tags: %d", id)
+	}
+}
+
+func TestFieldIDInvalidTag(t *testing.T) {
+	for _, tag := range []string{"id", "id()", "id(0)", "id(-1)", "id(x)", "id(1),id(2)"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: expected a panic", tag)
+				}
+			}()
+			parquet.SchemaOf(reflect.New(reflect.StructOf([]reflect.StructField{{
+				Name: "A",
+				Type: reflect.TypeOf(int32(0)),
+				Tag:  reflect.StructTag(`parquet:"a,` + tag + `"`),
+			}})).Interface())
+		}()
+	}
+}
+
+func TestConvertByFieldID(t *testing.T) {
+	from := parquet.NewSchema("from", parquet.Group{
+		"a": parquet.FieldID(parquet.String(), 1),
+		"b": parquet.FieldID(parquet.Int(64), 2),
+		"c": parquet.Int(32),
+	})
+	to := parquet.NewSchema("to", parquet.Group{
+		"renamed": parquet.FieldID(parquet.Int(64), 2),
+		"x":       parquet.FieldID(parquet.String(), 1),
+		"c":       parquet.Int(32),
+		"z":       parquet.FieldID(parquet.Int(32), 9),
+	})
+
+	newRow := func() parquet.Row {
+		return parquet.Row{
+			parquet.ValueOf("hello").Level(0, 0, 0),
+			parquet.ValueOf(int64(42)).Level(0, 0, 1),
+			parquet.ValueOf(int32(7)).Level(0, 0, 2),
+		}
+	}
+
+	tests := []struct {
+		scenario string
+		convert  func(to, from parquet.Node) (parquet.Conversion, error)
+		want     parquet.Row
+	}{
+		{
+			scenario: "by field id",
+			convert:  parquet.ConvertByFieldID,
+			want: parquet.Row{
+				parquet.ValueOf(int32(7)).Level(0, 0, 0),
+				parquet.ValueOf(int64(42)).Level(0, 0, 1),
+				parquet.ValueOf("hello").Level(0, 0, 2),
+				parquet.ValueOf(int32(0)).Level(0, 0, 3),
+			},
+		},
+		{
+			scenario: "by name",
+			convert:  parquet.Convert,
+			want: parquet.Row{
+				parquet.ValueOf(int32(7)).Level(0, 0, 0),
+				parquet.ValueOf(int64(0)).Level(0, 0, 1),
+				parquet.ValueOf("").Level(0, 0, 2),
+				parquet.ValueOf(int32(0)).Level(0, 0, 3),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			conv, err := test.convert(to, from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := []parquet.Row{newRow()}
+			if _, err := conv.Convert(rows); err != nil {
+				t.Fatal(err)
+			}
+			if !rows[0].Equal(test.want) {
+				t.Errorf("\nwant: %+v\ngot:  %+v", test.want, rows[0])
+			}
+		})
+	}
+}
+
+func TestFieldIDWrappedNode(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"a": parquet.Optional(parquet.Compressed(parquet.FieldID(parquet.Int(32), 7), &parquet.Snappy)),
+		"b": parquet.FieldID(parquet.Repeated(parquet.String()), 8),
+	})
+
+	const expected = `message test {
+	optional int32 a (INT(32,true)) = 7;
+	repeated binary b (STRING) = 8;
+}`
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"hash/crc32"
@@ -11,6 +12,8 @@ import (
 	"sync"
 
 	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/compress/zstd"
 	"github.com/segmentio/parquet-go/format"
 )
 
@@ -32,6 +35,7 @@ type File struct {
 	offsetIndexes []format.OffsetIndex
 	rowGroups     []RowGroup
 	config        *FileConfig
+	zstd          *zstd.Codec
 }
 
 // OpenFile opens a parquet file and reads the content between offset 0 and the given
@@ -66,6 +70,12 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	}
 
 	footerSize := int64(binary.LittleEndian.Uint32(b[:4]))
+	if footerSize > size-12 {
+		return nil, fmt.Errorf("invalid footer size of parquet file: %d/%d", footerSize, size)
+	}
+	if err := checkLimit("MaxFooterSize", c.Limits.MaxFooterSize, footerSize); err != nil {
+		return nil, fmt.Errorf("reading footer of parquet file: %w", err)
+	}
 	footerData := make([]byte, footerSize)
 
 	if cast, ok := f.reader.(interface{ SetFooterSection(offset, length int64) }); ok {
@@ -74,12 +84,24 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	if _, err := f.reader.ReadAt(footerData, size-(footerSize+8)); err != nil {
 		return nil, fmt.Errorf("reading footer of parquet file: %w", err)
 	}
-	if err := thrift.Unmarshal(&f.protocol, footerData, &f.metadata); err != nil {
+	if err := unmarshalMetadata(&f.protocol, footerData, &f.metadata); err != nil {
 		return nil, fmt.Errorf("reading parquet file metadata: %w", err)
 	}
 	if len(f.metadata.Schema) == 0 {
 		return nil, ErrMissingRootColumn
 	}
+	if err := checkFileMetaData(&f.metadata, &c.Limits); err != nil {
+		return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+	}
+
+	sortKeyValueMetadata(f.metadata.KeyValueMetadata)
+	if value, ok := f.Lookup(zstdDictionaryKey); ok {
+		dict, err := decodeZstdDictionary(value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding zstd dictionary of parquet file: %w", err)
+		}
+		f.zstd = &zstd.Codec{Dictionary: dict}
+	}
 
 	if !c.SkipPageIndex {
 		if f.columnIndexes, f.offsetIndexes, err = f.ReadPageIndex(); err != nil {
@@ -149,10 +171,145 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 		}
 	}
 
-	sortKeyValueMetadata(f.metadata.KeyValueMetadata)
 	return f, nil
 }
 
+// unmarshalMetadata is like thrift.Unmarshal but rejects the binary values,
+// lists, sets and maps declaring more elements than there are bytes remaining
+// in b. The decoder allocates memory for the declared number of elements before
+// decoding them, this bounds the memory allocated for corrupted sizes to a
+// multiple of the size of the metadata, which was checked against the read
+// limits.
+func unmarshalMetadata(p thrift.Protocol, b []byte, v interface{}) error {
+	input := bytes.NewReader(b)
+	reader := &boundedReader{thriftReader: p.NewReader(input), input: input}
+
+	if err := thrift.NewDecoder(reader).Decode(v); err != nil {
+		return err
+	}
+	if n := input.Len(); n != 0 {
+		return fmt.Errorf("unexpected trailing bytes at the end of thrift input: %d", n)
+	}
+	return nil
+}
+
+// boundedReader is a thrift.Reader which checks the sizes of the values read
+// from input, every element being encoded in at least one byte.
+type boundedReader struct {
+	thriftReader
+	input *bytes.Reader
+}
+
+// thriftReader allows embedding thrift.Reader, which has a Reader method that
+// would conflict with the name of the embedded field.
+type thriftReader = thrift.Reader
+
+func (r *boundedReader) checkSize(typ string, size int) error {
+	if size < 0 || size > r.input.Len() {
+		return fmt.Errorf("invalid thrift %s size: %d exceeds the remaining %d bytes of input", typ, size, r.input.Len())
+	}
+	return nil
+}
+
+func (r *boundedReader) ReadLength() (int, error) {
+	n, err := r.thriftReader.ReadLength()
+	if err == nil {
+		err = r.checkSize("binary", n)
+	}
+	return n, err
+}
+
+func (r *boundedReader) ReadBytes() ([]byte, error) {
+	n, err := r.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r.input, b)
+	return b, err
+}
+
+func (r *boundedReader) ReadString() (string, error) {
+	b, err := r.ReadBytes()
+	return string(b), err
+}
+
+func (r *boundedReader) ReadList() (thrift.List, error) {
+	l, err := r.thriftReader.ReadList()
+	if err == nil {
+		err = r.checkSize("list", int(l.Size))
+	}
+	return l, err
+}
+
+func (r *boundedReader) ReadSet() (thrift.Set, error) {
+	s, err := r.thriftReader.ReadSet()
+	if err == nil {
+		err = r.checkSize("set", int(s.Size))
+	}
+	return s, err
+}
+
+func (r *boundedReader) ReadMap() (thrift.Map, error) {
+	m, err := r.thriftReader.ReadMap()
+	if err == nil {
+		err = r.checkSize("map", int(m.Size))
+	}
+	return m, err
+}
+
+// checkFileMetaData validates the schema and row counts of metadata against the
+// given limits. The schema is walked without recursion since its depth is not
+// known to be reasonable yet.
+func checkFileMetaData(metadata *format.FileMetaData, limits *ReadLimits) error {
+	// Number of children remaining to visit in each group of the path from the
+	// root to the current schema element.
+	remaining := make([]int32, 0, 16)
+	numColumns := 0
+
+	for i := range metadata.Schema {
+		if i > 0 && len(remaining) == 0 {
+			return fmt.Errorf("schema element at index %d is not part of the root group", i)
+		}
+		if len(remaining) > 0 {
+			remaining[len(remaining)-1]--
+		}
+
+		switch numChildren := metadata.Schema[i].NumChildren; {
+		case numChildren < 0:
+			return fmt.Errorf("schema element at index %d has a negative number of children: %d", i, numChildren)
+		case numChildren > 0:
+			remaining = append(remaining, numChildren)
+			if err := checkLimit("MaxSchemaDepth", int64(limits.MaxSchemaDepth), int64(len(remaining))); err != nil {
+				return err
+			}
+		default:
+			numColumns++
+			if err := checkLimit("MaxColumns", int64(limits.MaxColumns), int64(numColumns)); err != nil {
+				return err
+			}
+		}
+
+		for len(remaining) > 0 && remaining[len(remaining)-1] == 0 {
+			remaining = remaining[:len(remaining)-1]
+		}
+	}
+
+	if len(remaining) > 0 {
+		return fmt.Errorf("schema is missing %d children of group at depth %d", remaining[len(remaining)-1], len(remaining))
+	}
+
+	if err := checkLimit("MaxRows", limits.MaxRows, metadata.NumRows); err != nil {
+		return err
+	}
+	for i := range metadata.RowGroups {
+		if err := checkLimit("MaxRows", limits.MaxRows, metadata.RowGroups[i].NumRows); err != nil {
+			return fmt.Errorf("row group at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // ReadPageIndex reads the page index section of the parquet file f.
 //
 // If the file did not contain a page index, the method returns two empty slices
@@ -184,8 +341,8 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 		return nil, nil, nil
 	}
 
-	columnIndexOffset := f.metadata.RowGroups[0].Columns[0].ColumnIndexOffset
-	offsetIndexOffset := f.metadata.RowGroups[0].Columns[0].OffsetIndexOffset
+	columnIndexOffset := int64(0)
+	offsetIndexOffset := int64(0)
 	columnIndexLength := int64(0)
 	offsetIndexLength := int64(0)
 
@@ -202,6 +359,15 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 	}
 
 	forEachColumnChunk(func(_, _ int, c *format.ColumnChunk) error {
+		// The index sections start at the offset of the first column chunk
+		// which has an index, some columns may not have one (e.g. columns
+		// of types with undefined order).
+		if columnIndexOffset == 0 {
+			columnIndexOffset = c.ColumnIndexOffset
+		}
+		if offsetIndexOffset == 0 {
+			offsetIndexOffset = c.OffsetIndexOffset
+		}
 		columnIndexLength += int64(c.ColumnIndexLength)
 		offsetIndexLength += int64(c.OffsetIndexLength)
 		return nil
@@ -210,6 +376,14 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 	if columnIndexLength == 0 && offsetIndexLength == 0 {
 		return nil, nil, nil
 	}
+	for _, length := range [2]int64{columnIndexLength, offsetIndexLength} {
+		if length < 0 || length > f.size {
+			return nil, nil, fmt.Errorf("invalid page index size of parquet file: %d/%d", length, f.size)
+		}
+		if err := checkLimit("MaxFooterSize", f.config.Limits.MaxFooterSize, length); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	numRowGroups := len(f.metadata.RowGroups)
 	numColumns := len(f.metadata.RowGroups[0].Columns)
@@ -237,8 +411,11 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 			if c.ColumnIndexOffset > 0 {
 				offset := c.ColumnIndexOffset - columnIndexOffset
 				length := int64(c.ColumnIndexLength)
+				if offset < 0 || length < 0 || offset+length > int64(len(columnIndexData)) {
+					return fmt.Errorf("invalid column index section: rowGroup=%d columnChunk=%d/%d: offset=%d length=%d", i, j, numColumns, offset, length)
+				}
 				buffer := columnIndexData[offset : offset+length]
-				if err := thrift.Unmarshal(&f.protocol, buffer, &columnIndexes[(i*numColumns)+j]); err != nil {
+				if err := unmarshalMetadata(&f.protocol, buffer, &columnIndexes[(i*numColumns)+j]); err != nil {
 					return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 				}
 			}
@@ -263,8 +440,11 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 			if c.OffsetIndexOffset > 0 {
 				offset := c.OffsetIndexOffset - offsetIndexOffset
 				length := int64(c.OffsetIndexLength)
+				if offset < 0 || length < 0 || offset+length > int64(len(offsetIndexData)) {
+					return fmt.Errorf("invalid offset index section: rowGroup=%d columnChunk=%d/%d: offset=%d length=%d", i, j, numColumns, offset, length)
+				}
 				buffer := offsetIndexData[offset : offset+length]
-				if err := thrift.Unmarshal(&f.protocol, buffer, &offsetIndexes[(i*numColumns)+j]); err != nil {
+				if err := unmarshalMetadata(&f.protocol, buffer, &offsetIndexes[(i*numColumns)+j]); err != nil {
 					return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 				}
 			}
@@ -335,6 +515,15 @@ func (f *File) Lookup(key string) (value string, ok bool) {
 	return lookupKeyValueMetadata(f.metadata.KeyValueMetadata, key)
 }
 
+// lookupCompressionCodec is like LookupCompressionCodec but uses the zstd
+// dictionary of the file if it has one.
+func (f *File) lookupCompressionCodec(codec format.CompressionCodec) compress.Codec {
+	if codec == format.Zstd && f.zstd != nil {
+		return f.zstd
+	}
+	return LookupCompressionCodec(codec)
+}
+
 func (f *File) hasIndexes() bool {
 	return f.columnIndexes != nil && f.offsetIndexes != nil
 }
@@ -501,7 +690,8 @@ type filePages struct {
 	skip       int64
 	dictionary Dictionary
 
-	bufferSize int
+	bufferSize   int
+	decompressed int64
 }
 
 func (f *filePages) init(c *fileColumnChunk) {
@@ -509,6 +699,7 @@ func (f *filePages) init(c *fileColumnChunk) {
 	f.baseOffset = c.chunk.MetaData.DataPageOffset
 	f.dataOffset = f.baseOffset
 	f.bufferSize = c.file.config.ReadBufferSize
+	f.decompressed = 0
 
 	if c.chunk.MetaData.DictionaryPageOffset != 0 {
 		f.baseOffset = c.chunk.MetaData.DictionaryPageOffset
@@ -529,6 +720,10 @@ func (f *filePages) ReadPage() (Page, error) {
 	defer putPageHeader(header)
 
 	for {
+		// Optional fields absent from the encoded header are not written to
+		// by the decoder, the values of the previous header must be cleared.
+		*header = format.PageHeader{}
+
 		if err := f.decoder.Decode(header); err != nil {
 			return nil, err
 		}
@@ -597,6 +792,9 @@ func (f *filePages) readDictionary() error {
 	if err := decoder.Decode(header); err != nil {
 		return err
 	}
+	if err := f.checkPageHeader(header); err != nil {
+		return err
+	}
 
 	page := buffers.get(int(header.CompressedPageSize))
 	defer page.unref()
@@ -647,7 +845,61 @@ func (f *filePages) readDataPageV2(header *format.PageHeader, page *buffer) (Pag
 	return f.chunk.column.decodeDataPageV2(DataPageHeaderV2{header.DataPageHeaderV2}, page, f.dictionary, header.UncompressedPageSize)
 }
 
+// checkPageHeader validates the sizes and counts declared in header against
+// the limits of the file, before memory gets allocated to read and decode the
+// page.
+func (f *filePages) checkPageHeader(header *format.PageHeader) error {
+	file := f.chunk.file
+	limits := &file.config.Limits
+
+	if header.CompressedPageSize < 0 || int64(header.CompressedPageSize) > file.size {
+		return fmt.Errorf("invalid compressed page size: %d", header.CompressedPageSize)
+	}
+	if header.UncompressedPageSize < 0 {
+		return fmt.Errorf("invalid uncompressed page size: %d", header.UncompressedPageSize)
+	}
+	if err := checkLimit("MaxPageSize", limits.MaxPageSize, int64(header.UncompressedPageSize)); err != nil {
+		return err
+	}
+
+	f.decompressed += int64(header.UncompressedPageSize)
+	if err := checkLimit("MaxChunkSize", limits.MaxChunkSize, f.decompressed); err != nil {
+		return err
+	}
+
+	var numValues, numRows int32
+	// Page headers are reused, only the header matching the page type is
+	// relevant. Missing headers are reported when decoding the page.
+	switch {
+	case header.Type == format.DataPage && header.DataPageHeader != nil:
+		numValues = header.DataPageHeader.NumValues
+	case header.Type == format.DataPageV2 && header.DataPageHeaderV2 != nil:
+		h := header.DataPageHeaderV2
+		numValues, numRows = h.NumValues, h.NumRows
+		if h.RepetitionLevelsByteLength < 0 || h.DefinitionLevelsByteLength < 0 {
+			return fmt.Errorf("invalid levels size: repetition=%d definition=%d", h.RepetitionLevelsByteLength, h.DefinitionLevelsByteLength)
+		}
+	case header.Type == format.DictionaryPage && header.DictionaryPageHeader != nil:
+		numValues = header.DictionaryPageHeader.NumValues
+		if err := checkLimit("MaxDictionaryValues", limits.MaxDictionaryValues, int64(numValues)); err != nil {
+			return err
+		}
+	}
+
+	if numValues < 0 || numRows < 0 {
+		return fmt.Errorf("invalid page value counts: values=%d rows=%d", numValues, numRows)
+	}
+	if err := checkLimit("MaxPageSize", limits.MaxPageSize, int64(numValues)); err != nil {
+		return err
+	}
+	return checkLimit("MaxRows", limits.MaxRows, int64(numRows))
+}
+
 func (f *filePages) readPage(header *format.PageHeader, reader *bufio.Reader) (*buffer, error) {
+	if err := f.checkPageHeader(header); err != nil {
+		return nil, err
+	}
+
 	page := buffers.get(int(header.CompressedPageSize))
 	defer page.unref()
 
@@ -705,6 +957,7 @@ func (f *filePages) SeekToRow(rowIndex int64) (err error) {
 		f.index = index
 	}
 	f.rbuf.Reset(&f.section)
+	f.decompressed = 0
 	return err
 }
 
@@ -777,7 +1030,7 @@ func getPageHeader() *format.PageHeader {
 
 func putPageHeader(h *format.PageHeader) {
 	if h != nil {
-		h.CRC = 0
+		*h = format.PageHeader{}
 		pageHeaderPool.Put(h)
 	}
 }
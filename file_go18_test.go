@@ -0,0 +1,283 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/encoding/fuzz"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type limitsRow struct {
+	ID   int64    `parquet:"id"`
+	Name string   `parquet:"name,dict"`
+	Tags []string `parquet:"tags,list"`
+}
+
+func writeLimitsFile(t testing.TB, ids []int64, names []string, options ...parquet.WriterOption) []byte {
+	rows := make([]limitsRow, len(ids))
+	for i := range rows {
+		rows[i].ID = ids[i]
+		if len(names) > 0 {
+			rows[i].Name = names[i%len(names)]
+			rows[i].Tags = names[i%len(names):][:1]
+		}
+	}
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[limitsRow](buffer, append(options, parquet.PageBufferSize(256))...)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// readLimitsFile opens the file, then reads all its pages and rows.
+func readLimitsFile(data []byte, limits parquet.ReadLimits) error {
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), parquet.FileReadLimits(limits))
+	if err != nil {
+		return err
+	}
+	for _, rowGroup := range f.RowGroups() {
+		for _, chunk := range rowGroup.ColumnChunks() {
+			if err := readPages(chunk.Pages()); err != nil {
+				return err
+			}
+		}
+	}
+	reader := parquet.NewReader(f)
+	defer reader.Close()
+	rows := make([]parquet.Row, 100)
+	for {
+		if _, err := reader.ReadRows(rows); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return err
+		}
+	}
+}
+
+func readPages(pages parquet.Pages) error {
+	defer pages.Close()
+	for {
+		page, err := pages.ReadPage()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return err
+		}
+		parquet.Release(page)
+	}
+}
+
+func TestOpenFileLimits(t *testing.T) {
+	ids := make([]int64, 1000)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	names := []string{"Luke", "Leia", "Han", "Chewbacca", "Obi-Wan", "Yoda"}
+	data := writeLimitsFile(t, ids, names)
+
+	if err := readLimitsFile(data, parquet.ReadLimits{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		limit  string
+		limits parquet.ReadLimits
+	}{
+		{"MaxFooterSize", parquet.ReadLimits{MaxFooterSize: 64}},
+		{"MaxPageSize", parquet.ReadLimits{MaxPageSize: 64}},
+		{"MaxChunkSize", parquet.ReadLimits{MaxChunkSize: 1024}},
+		{"MaxDictionaryValues", parquet.ReadLimits{MaxDictionaryValues: 5}},
+		{"MaxSchemaDepth", parquet.ReadLimits{MaxSchemaDepth: 2}},
+		{"MaxColumns", parquet.ReadLimits{MaxColumns: 2}},
+		{"MaxRows", parquet.ReadLimits{MaxRows: 999}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.limit, func(t *testing.T) {
+			err := readLimitsFile(data, test.limits)
+			if !errors.Is(err, parquet.ErrLimitExceeded) {
+				t.Fatalf("error does not wrap ErrLimitExceeded: %v", err)
+			}
+			var limitError *parquet.LimitError
+			if !errors.As(err, &limitError) {
+				t.Fatalf("error is not a *LimitError: %v", err)
+			}
+			if limitError.Limit != test.limit {
+				t.Errorf("wrong limit exceeded: want=%s got=%s", test.limit, limitError.Limit)
+			}
+			if limitError.Value <= limitError.Max {
+				t.Errorf("value does not exceed the limit: %d <= %d", limitError.Value, limitError.Max)
+			}
+		})
+	}
+}
+
+func TestOpenFilePageExceedsDeclaredSize(t *testing.T) {
+	codecs := []compress.Codec{
+		&parquet.Snappy,
+		&parquet.Gzip,
+		&parquet.Brotli,
+		&parquet.Zstd,
+		&parquet.Lz4Raw,
+	}
+
+	for _, codec := range codecs {
+		t.Run(codec.String(), func(t *testing.T) {
+			data := writeLimitsFile(t, make([]int64, 1000), nil, parquet.Compression(codec))
+			f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Rewrite the header of the first page to declare an uncompressed
+			// size smaller than the size of its data.
+			offset := f.Metadata().RowGroups[0].Columns[0].MetaData.DataPageOffset
+			input := bytes.NewReader(data[offset:])
+			protocol := new(thrift.CompactProtocol)
+			header := format.PageHeader{}
+			if err := thrift.NewDecoder(protocol.NewReader(input)).Decode(&header); err != nil {
+				t.Fatal(err)
+			}
+			headerSize := len(data[offset:]) - input.Len()
+
+			header.UncompressedPageSize = 64
+			b, err := thrift.Marshal(protocol, &header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b) != headerSize {
+				t.Fatalf("page header size changed: %d != %d", len(b), headerSize)
+			}
+			copy(data[offset:], b)
+
+			err = readLimitsFile(data, parquet.ReadLimits{})
+			var limitError *parquet.LimitError
+			if !errors.As(err, &limitError) {
+				t.Fatalf("error is not a *LimitError: %v", err)
+			}
+			if limitError.Limit != "UncompressedPageSize" || limitError.Max != 64 {
+				t.Errorf("wrong limit exceeded: %s: %d > %d", limitError.Limit, limitError.Value, limitError.Max)
+			}
+		})
+	}
+}
+
+func TestOpenFileInvalidFooterSize(t *testing.T) {
+	data := writeLimitsFile(t, []int64{1, 2, 3}, nil)
+	// Declare a footer of 4 GiB, larger than the file.
+	copy(data[len(data)-8:], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	if _, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("opening a file with an invalid footer size did not fail")
+	}
+}
+
+// makeFileWithFooter returns a parquet file made of the magic bytes and the
+// thrift encoded footer passed as argument.
+func makeFileWithFooter(footer []byte) []byte {
+	data := append([]byte("PAR1"), footer...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(footer)))
+	return append(data, "PAR1"...)
+}
+
+// The footer declares a schema of math.MaxInt32 elements, the decoder must not
+// allocate memory for them before seeing that the footer cannot hold them.
+var footerWithInvalidListSize = []byte{
+	0x29,                         // field 2 (schema): list
+	0xFC,                         // list of structs, size follows
+	0xFF, 0xFF, 0xFF, 0xFF, 0x07, // size: math.MaxInt32
+	0x00,
+}
+
+func TestOpenFileInvalidListSize(t *testing.T) {
+	data := makeFileWithFooter(footerWithInvalidListSize)
+
+	if _, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("opening a file with an invalid list size did not fail")
+	}
+}
+
+func TestFileConfigInvalidLimits(t *testing.T) {
+	if _, err := parquet.NewFileConfig(parquet.FileReadLimits(parquet.ReadLimits{MaxPageSize: -1})); err == nil {
+		t.Error("configuring negative limits did not fail")
+	}
+}
+
+func FuzzOpenFile(f *testing.F) {
+	f.Add(writeLimitsFile(f, []int64{1, 2, 3}, []string{"A", "B"}))
+	f.Add(makeFileWithFooter(footerWithInvalidListSize))
+
+	limits := parquet.ReadLimits{
+		MaxFooterSize:       4096,
+		MaxPageSize:         4096,
+		MaxChunkSize:        64 * 1024,
+		MaxDictionaryValues: 1024,
+		MaxSchemaDepth:      3,
+		MaxColumns:          3,
+		MaxRows:             1024,
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := readLimitsFile(data, limits)
+
+		var limitError *parquet.LimitError
+		if errors.As(err, &limitError) && limitError.Value <= limitError.Max {
+			t.Errorf("value does not exceed the limit: %s: %d <= %d", limitError.Limit, limitError.Value, limitError.Max)
+		}
+	})
+}
+
+func FuzzOpenFileLimits(f *testing.F) {
+	f.Add([]byte("Hello World!"), int64(0), uint32(0), byte(0))
+	f.Add(bytes.Repeat([]byte("parquet"), 100), int64(1), uint32(4), byte(0x80))
+	f.Add(bytes.Repeat([]byte{0, 1, 2, 3}, 200), int64(2), uint32(60), byte(0xFF))
+
+	limits := parquet.ReadLimits{
+		MaxFooterSize:       4096,
+		MaxPageSize:         4096,
+		MaxChunkSize:        64 * 1024,
+		MaxDictionaryValues: 1024,
+		MaxSchemaDepth:      3,
+		MaxColumns:          3,
+		MaxRows:             1024,
+	}
+	prng := rand.New(rand.NewSource(0))
+
+	f.Fuzz(func(t *testing.T, input []byte, seed int64, offset uint32, mask byte) {
+		prng.Seed(seed)
+		ids := fuzz.Generate[int64](nil, input)
+		names := fuzz.GenerateByteArray(nil, input, prng)
+		options := []parquet.WriterOption{parquet.DataPageVersion(1 + int(seed&1))}
+		if seed&2 != 0 {
+			options = append(options, parquet.Compression(&parquet.Snappy))
+		}
+		data := writeLimitsFile(t, ids, names, options...)
+
+		// Corrupt one byte of the file, which may be part of the metadata or
+		// of a page header and declare arbitrary sizes or counts.
+		data[int(offset)%len(data)] ^= mask
+
+		err := readLimitsFile(data, limits)
+
+		var limitError *parquet.LimitError
+		if errors.As(err, &limitError) && limitError.Value <= limitError.Max {
+			t.Errorf("value does not exceed the limit: %s: %d <= %d", limitError.Limit, limitError.Value, limitError.Max)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package parquet_test
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -145,3 +147,137 @@ func TestFileKeyValueMetadata(t *testing.T) {
 		}
 	}
 }
+
+type fileReadPagesRow struct {
+	ID   int64    `parquet:"id"`
+	Name string   `parquet:"name,delta"`
+	Opt  *int32   `parquet:"opt,optional"`
+	Tags []string `parquet:"tags"`
+	Dict string   `parquet:"dict,dict"`
+}
+
+func makeFileReadPagesRows(n int) []fileReadPagesRow {
+	rows := make([]fileReadPagesRow, n)
+	for i := range rows {
+		rows[i] = fileReadPagesRow{
+			ID:   int64(i),
+			Name: fmt.Sprintf("name-%d", i),
+			Tags: []string{"a", "b"},
+			Dict: fmt.Sprint(i % 10),
+		}
+		if i%2 == 0 {
+			opt := int32(i)
+			rows[i].Opt = &opt
+		}
+	}
+	return rows
+}
+
+func TestFileReadPages(t *testing.T) {
+	rows := makeFileReadPagesRows(10000)
+
+	for _, version := range []int{1, 2} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			f, err := createParquetFile(makeRows(rows),
+				parquet.DataPageVersion(version),
+				parquet.Compression(&parquet.Snappy),
+				parquet.PageBufferSize(4096),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Read the file twice so the second pass uses the buffers returned
+			// to the pools by the first one.
+			for i := 0; i < 2; i++ {
+				reader := parquet.NewReader(f)
+				for j, want := range rows {
+					var got fileReadPagesRow
+					if err := reader.Read(&got); err != nil {
+						t.Fatalf("reading row %d: %v", j, err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", j, want, got)
+					}
+				}
+				reader.Close()
+			}
+		})
+	}
+}
+
+func BenchmarkFileReadPages(b *testing.B) {
+	for _, version := range []int{1, 2} {
+		b.Run(fmt.Sprintf("v%d", version), func(b *testing.B) {
+			f, err := createParquetFile(makeRows(makeFileReadPagesRows(10000)),
+				parquet.DataPageVersion(version),
+				parquet.Compression(&parquet.Snappy),
+				parquet.PageBufferSize(16*1024),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+			columns := f.RowGroups()[0].ColumnChunks()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for _, column := range columns {
+					pages := column.Pages()
+					for {
+						p, err := pages.ReadPage()
+						if err != nil {
+							if err != io.EOF {
+								b.Fatal(err)
+							}
+							break
+						}
+						parquet.Release(p)
+					}
+					pages.Close()
+				}
+			}
+		})
+	}
+}
+
+func TestFileReadPagesOmittedHeaderFields(t *testing.T) {
+	readRows := func(f *parquet.File) []parquet.Row {
+		rows := make([]parquet.Row, f.NumRows())
+		reader := parquet.NewReader(f)
+		defer reader.Close()
+		n, err := reader.ReadRows(rows)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		return rows[:n]
+	}
+
+	testdata, err := os.Open("testdata/datapage_v2.snappy.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testdata.Close()
+	s, err := testdata.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(testdata, s.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := readRows(f)
+
+	// The page headers of the uncompressed file set IsCompressed to false,
+	// the pages of the testdata file omit the field, which must then default
+	// to true rather than retain the value of previously decoded headers.
+	uncompressed, err := createParquetFile(makeRows(makeFileReadPagesRows(100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	readRows(uncompressed)
+
+	if got := readRows(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows mismatch after reading uncompressed pages:\nwant = %+v\ngot  = %+v", want, got)
+	}
+}
@@ -0,0 +1,156 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const (
+	float16NaN          = 0x7E00
+	float16PositiveZero = 0x0000
+	float16NegativeZero = 0x8000
+)
+
+// float16ToFloat32 converts the IEEE 754 half-precision value passed as
+// argument to a single-precision value; the conversion is exact.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0: // zero or subnormal
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	case 0x1F: // infinity or NaN
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+	}
+}
+
+// float32ToFloat16 converts the single-precision value passed as argument to
+// the nearest IEEE 754 half-precision value, rounding ties to even. Values
+// outside of the range of half-precision numbers become infinities.
+func float32ToFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int32(b>>23) & 0xFF
+	mant := b & 0x7FFFFF
+
+	if exp == 0xFF {
+		if mant != 0 {
+			return sign | float16NaN | uint16(mant>>13)
+		}
+		return sign | 0x7C00
+	}
+
+	e := exp - 127 + 15
+	switch {
+	case e >= 0x1F:
+		return sign | 0x7C00
+	case e <= 0:
+		shift := uint32(14 - e)
+		if shift > 24 {
+			return sign
+		}
+		m := mant | 0x800000
+		h := m >> shift
+		r := m & (1<<shift - 1)
+		half := uint32(1) << (shift - 1)
+		if r > half || (r == half && h&1 != 0) {
+			h++
+		}
+		return sign | uint16(h)
+	default:
+		h := uint32(e)<<10 | mant>>13
+		r := mant & 0x1FFF
+		if r > 0x1000 || (r == 0x1000 && h&1 != 0) {
+			h++ // may carry into the exponent, which rounds up to infinity
+		}
+		return sign | uint16(h)
+	}
+}
+
+func isFloat16NaN(h uint16) bool {
+	return h&0x7C00 == 0x7C00 && h&0x3FF != 0
+}
+
+// float16Bits returns the half-precision value held in the 2 bytes little
+// endian representation of b, or NaN if b does not have the right length.
+func float16Bits(b []byte) uint16 {
+	if len(b) != 2 {
+		return float16NaN
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+// compareFloat16 compares half-precision values, NaNs are ordered after all
+// other values and both zeros compare equal.
+func compareFloat16(h1, h2 uint16) int {
+	switch nan1, nan2 := isFloat16NaN(h1), isFloat16NaN(h2); {
+	case nan1 && nan2:
+		return 0
+	case nan1:
+		return +1
+	case nan2:
+		return -1
+	default:
+		return compareFloat32(float16ToFloat32(h1), float16ToFloat32(h2))
+	}
+}
+
+// boundsFloat16 returns the min and max half-precision values in data, which
+// holds a sequence of 2 bytes little endian values.
+//
+// NaNs are ignored, ok is false if data contained only NaNs. When the min or
+// max are zero, they are returned as -0 and +0 respectively so that range
+// checks work regardless of the sign of zeros in the data.
+func boundsFloat16(data []byte) (min, max uint16, ok bool) {
+	var minValue, maxValue float32
+
+	for i := 0; i+1 < len(data); i += 2 {
+		h := binary.LittleEndian.Uint16(data[i:])
+		if isFloat16NaN(h) {
+			continue
+		}
+		f := float16ToFloat32(h)
+		if !ok {
+			min, max, minValue, maxValue, ok = h, h, f, f, true
+			continue
+		}
+		if f < minValue {
+			min, minValue = h, f
+		}
+		if f > maxValue {
+			max, maxValue = h, f
+		}
+	}
+
+	if ok {
+		if minValue == 0 {
+			min = float16NegativeZero
+		}
+		if maxValue == 0 {
+			max = float16PositiveZero
+		}
+	}
+	return min, max, ok
+}
+
+func orderOfFloat16(data []uint16) int {
+	values := make([]float32, len(data))
+	for i, h := range data {
+		values[i] = float16ToFloat32(h)
+	}
+	return orderOfFloat32(values)
+}
+
+func makeFloat16Bytes(h uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, h)
+	return b
+}
@@ -0,0 +1,209 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestFloat16Value(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		b := []byte{byte(i), byte(i >> 8)}
+		f := parquet.FixedLenByteArrayValue(b).Float16()
+		if math.IsNaN(float64(f)) {
+			continue
+		}
+		if v := parquet.Float16Value(f); !bytes.Equal(v.ByteArray(), b) {
+			t.Fatalf("%04x: float16 value of %g does not round trip: got %x", i, f, v.ByteArray())
+		}
+	}
+
+	tests := []struct {
+		input float32
+		bits  uint16
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3C00},
+		{-2, 0xC000},
+		{65504, 0x7BFF},
+		{65520, 0x7C00}, // rounds to +Inf
+		{1e-8, 0x0000},  // underflows to zero
+		{5.960464477539063e-08, 0x0001},
+		{float32(math.Inf(-1)), 0xFC00},
+		{1.0009765625, 0x3C01},
+		{1.00048828125, 0x3C00}, // ties to even
+	}
+
+	for _, test := range tests {
+		v := parquet.Float16Value(test.input)
+		if bits := binary.LittleEndian.Uint16(v.ByteArray()); bits != test.bits {
+			t.Errorf("float16 value of %g: want=%04x got=%04x", test.input, test.bits, bits)
+		}
+	}
+
+	if f := parquet.Float16Value(float32(math.NaN())).Float16(); !math.IsNaN(float64(f)) {
+		t.Errorf("NaN is not preserved: got %g", f)
+	}
+}
+
+func TestFloat16Compare(t *testing.T) {
+	typ := parquet.Float16().Type()
+	nan := parquet.Float16Value(float32(math.NaN()))
+	negZero := parquet.Float16Value(float32(math.Copysign(0, -1)))
+	posZero := parquet.Float16Value(0)
+
+	tests := []struct {
+		a, b parquet.Value
+		cmp  int
+	}{
+		{parquet.Float16Value(-1), parquet.Float16Value(1), -1},
+		{parquet.Float16Value(2), parquet.Float16Value(1), +1},
+		{parquet.Float16Value(-2), parquet.Float16Value(-1), -1},
+		{negZero, posZero, 0},
+		{nan, parquet.Float16Value(float32(math.Inf(+1))), +1},
+		{parquet.Float16Value(1), nan, -1},
+		{nan, nan, 0},
+	}
+
+	for _, test := range tests {
+		if cmp := typ.Compare(test.a, test.b); cmp != test.cmp {
+			t.Errorf("compare(%g, %g): want=%d got=%d", test.a.Float16(), test.b.Float16(), test.cmp, cmp)
+		}
+	}
+}
+
+func TestFloat16Statistics(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"value": parquet.Optional(parquet.Float16()),
+	})
+
+	values := []float32{
+		float32(math.NaN()),
+		1.5,
+		0,
+		-0.5,
+		float32(math.NaN()),
+		2.25,
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for _, v := range values {
+		row := parquet.Row{parquet.Float16Value(v).Level(0, 1, 0)}
+		if _, err := writer.WriteRows([]parquet.Row{row}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := writer.WriteRows([]parquet.Row{{parquet.Value{}.Level(0, 0, 0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := f.Schema().String(); s != `message test {
+	optional fixed_len_byte_array(2) value (FLOAT16);
+}` {
+		t.Errorf("wrong schema:\n%s", s)
+	}
+
+	stats := f.Metadata().RowGroups[0].Columns[0].MetaData.Statistics
+	if !bytes.Equal(stats.MinValue, parquet.Float16Value(-0.5).ByteArray()) {
+		t.Errorf("wrong min value: %x", stats.MinValue)
+	}
+	if !bytes.Equal(stats.MaxValue, parquet.Float16Value(2.25).ByteArray()) {
+		t.Errorf("wrong max value: %x", stats.MaxValue)
+	}
+
+	columnIndex := f.ColumnIndexes()[0]
+	if !bytes.Equal(columnIndex.MinValues[0], parquet.Float16Value(-0.5).ByteArray()) {
+		t.Errorf("wrong column index min value: %x", columnIndex.MinValues[0])
+	}
+	if !bytes.Equal(columnIndex.MaxValues[0], parquet.Float16Value(2.25).ByteArray()) {
+		t.Errorf("wrong column index max value: %x", columnIndex.MaxValues[0])
+	}
+
+	rows := make([]parquet.Row, len(values)+1)
+	n, _ := f.RowGroups()[0].Rows().ReadRows(rows)
+	if n != len(rows) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), n)
+	}
+	for i, v := range values {
+		got := rows[i][0].Float16()
+		if got != v && !(math.IsNaN(float64(got)) && math.IsNaN(float64(v))) {
+			t.Errorf("row %d: want=%g got=%g", i, v, got)
+		}
+	}
+	if !rows[len(values)][0].IsNull() {
+		t.Errorf("last row is not null: %v", rows[len(values)][0])
+	}
+}
+
+func TestFloat16ZeroBounds(t *testing.T) {
+	page := parquet.Float16().Type().NewColumnBuffer(0, 0)
+	page.WriteValues([]parquet.Value{
+		parquet.Float16Value(0),
+		parquet.Float16Value(float32(math.Copysign(0, -1))),
+	})
+
+	min, max, ok := page.Page().Bounds()
+	if !ok {
+		t.Fatal("page has no bounds")
+	}
+	if b := min.ByteArray(); !bytes.Equal(b, []byte{0x00, 0x80}) {
+		t.Errorf("min value must be -0: %x", b)
+	}
+	if b := max.ByteArray(); !bytes.Equal(b, []byte{0x00, 0x00}) {
+		t.Errorf("max value must be +0: %x", b)
+	}
+
+	page.Reset()
+	page.WriteValues([]parquet.Value{parquet.Float16Value(float32(math.NaN()))})
+	if _, _, ok := page.Page().Bounds(); ok {
+		t.Error("page of NaN values must not have bounds")
+	}
+}
+
+func TestFloat16Convert(t *testing.T) {
+	float16Type := parquet.Float16().Type()
+
+	v, err := float16Type.ConvertValue(parquet.FloatValue(1.5), parquet.FloatType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := v.Float16(); f != 1.5 {
+		t.Errorf("wrong float16 value converted from float: %g", f)
+	}
+
+	v, err = parquet.DoubleType.ConvertValue(parquet.Float16Value(-3.25), float16Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := v.Double(); d != -3.25 {
+		t.Errorf("wrong double value converted from float16: %g", d)
+	}
+
+	to := parquet.NewSchema("test", parquet.Group{"value": parquet.Float16()})
+	from := parquet.NewSchema("test", parquet.Group{"value": parquet.Leaf(parquet.FloatType)})
+
+	conv, err := parquet.Convert(to, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []parquet.Row{{parquet.FloatValue(0.25)}}
+	if _, err := conv.Convert(rows); err != nil {
+		t.Fatal(err)
+	}
+	if f := rows[0][0].Float16(); f != 0.25 {
+		t.Errorf("wrong float16 value converted from row: %g", f)
+	}
+}
@@ -99,19 +99,21 @@ type Statistics struct {
 }
 
 // Empty structs to use as logical type annotations.
-type StringType struct{} // allowed for BINARY, must be encoded with UTF-8
-type UUIDType struct{}   // allowed for FIXED[16], must encode raw UUID bytes
-type MapType struct{}    // see see LogicalTypes.md
-type ListType struct{}   // see LogicalTypes.md
-type EnumType struct{}   // allowed for BINARY, must be encoded with UTF-8
-type DateType struct{}   // allowed for INT32
-
-func (*StringType) String() string { return "STRING" }
-func (*UUIDType) String() string   { return "UUID" }
-func (*MapType) String() string    { return "MAP" }
-func (*ListType) String() string   { return "LIST" }
-func (*EnumType) String() string   { return "ENUM" }
-func (*DateType) String() string   { return "DATE" }
+type StringType struct{}  // allowed for BINARY, must be encoded with UTF-8
+type UUIDType struct{}    // allowed for FIXED[16], must encode raw UUID bytes
+type MapType struct{}     // see see LogicalTypes.md
+type ListType struct{}    // see LogicalTypes.md
+type EnumType struct{}    // allowed for BINARY, must be encoded with UTF-8
+type DateType struct{}    // allowed for INT32
+type Float16Type struct{} // allowed for FIXED[2], must encode raw FLOAT16 bytes
+
+func (*StringType) String() string  { return "STRING" }
+func (*UUIDType) String() string    { return "UUID" }
+func (*MapType) String() string     { return "MAP" }
+func (*ListType) String() string    { return "LIST" }
+func (*EnumType) String() string    { return "ENUM" }
+func (*DateType) String() string    { return "DATE" }
+func (*Float16Type) String() string { return "FLOAT16" }
 
 // Logical type to annotate a column that is always null.
 //
@@ -122,6 +124,106 @@ type NullType struct{}
 
 func (*NullType) String() string { return "NULL" }
 
+// Variant logical type annotation
+//
+// Annotates a group containing a required binary metadata field and a binary
+// value field holding semi-structured values in the variant binary encoding,
+// and optionally a typed_value field when the values are shredded.
+//
+// See VariantEncoding.md and VariantShredding.md for details.
+type VariantType struct {
+	// Version of the variant specification that the values conform to.
+	SpecificationVersion int8 `thrift:"1,optional"`
+}
+
+func (t *VariantType) String() string {
+	if t.SpecificationVersion == 0 {
+		return "VARIANT"
+	}
+	return fmt.Sprintf("VARIANT(%d)", t.SpecificationVersion)
+}
+
+// Interpolation algorithm of the edges of GEOGRAPHY values, the edges of
+// GEOMETRY values are always interpolated linearly.
+type EdgeInterpolationAlgorithm int32
+
+const (
+	Spherical EdgeInterpolationAlgorithm = 0
+	Vincenty  EdgeInterpolationAlgorithm = 1
+	Thomas    EdgeInterpolationAlgorithm = 2
+	Andoyer   EdgeInterpolationAlgorithm = 3
+	Karney    EdgeInterpolationAlgorithm = 4
+)
+
+func (a EdgeInterpolationAlgorithm) String() string {
+	switch a {
+	case Spherical:
+		return "SPHERICAL"
+	case Vincenty:
+		return "VINCENTY"
+	case Thomas:
+		return "THOMAS"
+	case Andoyer:
+		return "ANDOYER"
+	case Karney:
+		return "KARNEY"
+	default:
+		return "EdgeInterpolationAlgorithm(?)"
+	}
+}
+
+// Default coordinate reference system of GEOMETRY and GEOGRAPHY values, with
+// longitude as x and latitude as y.
+const DefaultCRS = "OGC:CRS84"
+
+// Geometry logical type annotation
+//
+// Annotates BINARY columns holding geospatial features in the Well-Known
+// Binary (WKB) format, with coordinates in a Cartesian space.
+//
+// The crs is a string identifying the coordinate reference system of the
+// values, it defaults to OGC:CRS84 when omitted.
+//
+// Allowed for physical types: BINARY
+type GeometryType struct {
+	CRS string `thrift:"1,optional"`
+}
+
+func (t *GeometryType) String() string {
+	if t.CRS == "" {
+		return "GEOMETRY"
+	}
+	return fmt.Sprintf("GEOMETRY(%s)", t.CRS)
+}
+
+// Geography logical type annotation
+//
+// Annotates BINARY columns holding geospatial features in the Well-Known
+// Binary (WKB) format, with coordinates as longitudes and latitudes on an
+// ellipsoid. The algorithm determines how edges are interpolated between
+// vertices, it defaults to SPHERICAL when omitted.
+//
+// Allowed for physical types: BINARY
+type GeographyType struct {
+	CRS       string                      `thrift:"1,optional"`
+	Algorithm *EdgeInterpolationAlgorithm `thrift:"2,optional"`
+}
+
+func (t *GeographyType) String() string {
+	switch {
+	case t.Algorithm != nil:
+		crs := t.CRS
+		if crs == "" {
+			crs = DefaultCRS
+		}
+		return fmt.Sprintf("GEOGRAPHY(%s,%s)", crs, t.Algorithm)
+	case t.CRS != "":
+		return fmt.Sprintf("GEOGRAPHY(%s)", t.CRS)
+	default:
+		return "GEOGRAPHY"
+	}
+}
+
 // Decimal logical type annotation
 //
 // To maintain forward-compatibility in v1, implementations using this logical
@@ -245,6 +347,12 @@ type LogicalType struct { // union
 	Json    *JsonType `thrift:"12"` // use ConvertedType JSON
 	Bson    *BsonType `thrift:"13"` // use ConvertedType BSON
 	UUID    *UUIDType `thrift:"14"` // no compatible ConvertedType
+
+	Float16 *Float16Type `thrift:"15"` // no compatible ConvertedType
+	Variant *VariantType `thrift:"16"` // no compatible ConvertedType
+
+	Geometry  *GeometryType  `thrift:"17"` // no compatible ConvertedType
+	Geography *GeographyType `thrift:"18"` // no compatible ConvertedType
 }
 
 func (t *LogicalType) String() string {
@@ -275,6 +383,14 @@ func (t *LogicalType) String() string {
 		return t.Bson.String()
 	case t.UUID != nil:
 		return t.UUID.String()
+	case t.Float16 != nil:
+		return t.Float16.String()
+	case t.Variant != nil:
+		return t.Variant.String()
+	case t.Geometry != nil:
+		return t.Geometry.String()
+	case t.Geography != nil:
+		return t.Geography.String()
 	default:
 		return ""
 	}
@@ -743,6 +859,48 @@ type ColumnMetaData struct {
 
 	// Byte offset from beginning of file to Bloom filter data.
 	BloomFilterOffset int64 `thrift:"14,optional"`
+
+	// Optional statistics specific for GEOMETRY and GEOGRAPHY logical types.
+	GeospatialStatistics *GeospatialStatistics `thrift:"17,optional"`
+}
+
+// Bounding box for GEOMETRY or GEOGRAPHY type in the representation of min/max
+// value pair of coordinates from each axis.
+//
+// For GEOGRAPHY values, XMin may be greater than XMax when the bounding box
+// wraps around the antimeridian.
+type BoundingBox struct {
+	XMin float64  `thrift:"1,required"`
+	XMax float64  `thrift:"2,required"`
+	YMin float64  `thrift:"3,required"`
+	YMax float64  `thrift:"4,required"`
+	ZMin *float64 `thrift:"5,optional"`
+	ZMax *float64 `thrift:"6,optional"`
+	MMin *float64 `thrift:"7,optional"`
+	MMax *float64 `thrift:"8,optional"`
+}
+
+// Intersects returns true if the x and y ranges of the bounding box intersect
+// with the ranges [xmin, xmax] and [ymin, ymax].
+func (b *BoundingBox) Intersects(xmin, ymin, xmax, ymax float64) bool {
+	if b.YMin > ymax || b.YMax < ymin {
+		return false
+	}
+	if b.XMin > b.XMax { // wraps around the antimeridian
+		return b.XMin <= xmax || b.XMax >= xmin
+	}
+	return b.XMin <= xmax && b.XMax >= xmin
+}
+
+// Statistics specific to GEOMETRY and GEOGRAPHY logical types.
+type GeospatialStatistics struct {
+	// A bounding box of the geospatial instances.
+	BBox *BoundingBox `thrift:"1,optional"`
+
+	// Geospatial type codes of all instances, or an empty list if not known.
+	// The codes are the ones of the ISO WKB geometry types, for example 1 for
+	// Point, or 1003 for Polygon Z.
+	GeospatialTypes []int32 `thrift:"2,optional"`
 }
 
 type EncryptionWithFooterKey struct{}
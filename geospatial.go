@@ -0,0 +1,439 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// Geometry constructs a leaf node of GEOMETRY logical type, holding geospatial
+// features encoded in the Well-Known Binary (WKB) format, with coordinates in
+// the coordinate reference system crs. An empty crs means that the default
+// OGC:CRS84 reference system is used, with longitudes as x and latitudes as y.
+//
+// The sort order of geospatial values is undefined, no min and max statistics
+// are written for GEOMETRY columns, nor does the writer generate column
+// indexes for them. Instead, the writer computes the bounding box and the
+// geometry types of the values of each column chunk, which programs can
+// retrieve with GeospatialStatisticsOf to skip column chunks when searching
+// for values in a spatial region.
+//
+// https://github.com/apache/parquet-format/blob/master/Geospatial.md
+func Geometry(crs string) Node { return Leaf(&geometryType{CRS: crs}) }
+
+// Geography constructs a leaf node of GEOGRAPHY logical type, holding
+// geospatial features encoded in the Well-Known Binary (WKB) format, with
+// coordinates as longitudes and latitudes in the geographic coordinate
+// reference system crs, and edges interpolated with the given algorithm.
+//
+// As for GEOMETRY, an empty crs means that the default OGC:CRS84 reference
+// system is used, and no min and max statistics are written for the columns.
+// The bounding boxes computed by the writer cover the vertices of the values.
+//
+// https://github.com/apache/parquet-format/blob/master/Geospatial.md
+func Geography(crs string, algorithm format.EdgeInterpolationAlgorithm) Node {
+	t := &geographyType{CRS: crs}
+	if algorithm != format.Spherical {
+		t.Algorithm = &algorithm
+	}
+	return Leaf(t)
+}
+
+// GeospatialStatisticsOf returns the geospatial statistics of a column chunk
+// read from a parquet file, which are written for columns of GEOMETRY and
+// GEOGRAPHY logical types. The function returns nil if the column chunk has
+// no geospatial statistics.
+func GeospatialStatisticsOf(columnChunk ColumnChunk) *format.GeospatialStatistics {
+	if c, ok := columnChunk.(*fileColumnChunk); ok {
+		return c.chunk.MetaData.GeospatialStatistics
+	}
+	return nil
+}
+
+func isGeospatial(t Type) bool {
+	lt := t.LogicalType()
+	return lt != nil && (lt.Geometry != nil || lt.Geography != nil)
+}
+
+type geometryType format.GeometryType
+
+func (t *geometryType) String() string { return (*format.GeometryType)(t).String() }
+
+func (t *geometryType) Kind() Kind { return byteArrayType{}.Kind() }
+
+func (t *geometryType) Length() int { return byteArrayType{}.Length() }
+
+func (t *geometryType) EstimateSize(n int) int { return byteArrayType{}.EstimateSize(n) }
+
+func (t *geometryType) EstimateNumValues(n int) int { return byteArrayType{}.EstimateNumValues(n) }
+
+// Compare orders values by their binary representation. The parquet format
+// does not define an order for geospatial values, this order is only intended
+// to group equal values.
+func (t *geometryType) Compare(a, b Value) int { return byteArrayType{}.Compare(a, b) }
+
+// ColumnOrder returns nil because the parquet format does not define an order
+// for geospatial values, the bounding boxes of the geospatial statistics are
+// used instead of min and max values to prune column chunks.
+func (t *geometryType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *geometryType) PhysicalType() *format.Type { return byteArrayType{}.PhysicalType() }
+
+func (t *geometryType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geometry: (*format.GeometryType)(t)}
+}
+
+func (t *geometryType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geometryType) NewColumnIndexer(sizeLimit int) ColumnIndexer { return noopColumnIndexer{} }
+
+func (t *geometryType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newGeospatialDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *geometryType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newGeospatialColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t *geometryType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newGeospatialPage(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *geometryType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return byteArrayType{}.NewValues(values, offsets)
+}
+
+func (t *geometryType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return byteArrayType{}.Encode(dst, src, enc)
+}
+
+func (t *geometryType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return byteArrayType{}.Decode(dst, src, enc)
+}
+
+func (t *geometryType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return byteArrayType{}.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *geometryType) AssignValue(dst reflect.Value, src Value) error {
+	return byteArrayType{}.AssignValue(dst, src)
+}
+
+func (t *geometryType) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case byteArrayType, *geometryType:
+		return val, nil
+	default:
+		return val, invalidConversion(val, typ.String(), "GEOMETRY")
+	}
+}
+
+type geographyType format.GeographyType
+
+func (t *geographyType) String() string { return (*format.GeographyType)(t).String() }
+
+func (t *geographyType) Kind() Kind { return byteArrayType{}.Kind() }
+
+func (t *geographyType) Length() int { return byteArrayType{}.Length() }
+
+func (t *geographyType) EstimateSize(n int) int { return byteArrayType{}.EstimateSize(n) }
+
+func (t *geographyType) EstimateNumValues(n int) int { return byteArrayType{}.EstimateNumValues(n) }
+
+// Compare orders values by their binary representation, see
+// geometryType.Compare.
+func (t *geographyType) Compare(a, b Value) int { return byteArrayType{}.Compare(a, b) }
+
+// ColumnOrder returns nil, see geometryType.ColumnOrder.
+func (t *geographyType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *geographyType) PhysicalType() *format.Type { return byteArrayType{}.PhysicalType() }
+
+func (t *geographyType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geography: (*format.GeographyType)(t)}
+}
+
+func (t *geographyType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geographyType) NewColumnIndexer(sizeLimit int) ColumnIndexer { return noopColumnIndexer{} }
+
+func (t *geographyType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newGeospatialDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *geographyType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newGeospatialColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t *geographyType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newGeospatialPage(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *geographyType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return byteArrayType{}.NewValues(values, offsets)
+}
+
+func (t *geographyType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return byteArrayType{}.Encode(dst, src, enc)
+}
+
+func (t *geographyType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return byteArrayType{}.Decode(dst, src, enc)
+}
+
+func (t *geographyType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return byteArrayType{}.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *geographyType) AssignValue(dst reflect.Value, src Value) error {
+	return byteArrayType{}.AssignValue(dst, src)
+}
+
+func (t *geographyType) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case byteArrayType, *geographyType:
+		return val, nil
+	default:
+		return val, invalidConversion(val, typ.String(), "GEOGRAPHY")
+	}
+}
+
+// WKB geometry types, the codes of geometries with Z, M, or ZM coordinates are
+// offset by 1000, 2000, and 3000.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// wkbMaxDepth limits the nesting of geometry collections.
+const wkbMaxDepth = 32
+
+// geospatialBounds accumulates the geospatial statistics of the WKB values
+// written to a column chunk.
+type geospatialBounds struct {
+	xmin, xmax float64
+	ymin, ymax float64
+	zmin, zmax float64
+	mmin, mmax float64
+	types      []int32
+	// Set when a value could not be decoded, in which case the statistics
+	// are unknown.
+	invalid bool
+}
+
+func newGeospatialBounds() *geospatialBounds {
+	b := new(geospatialBounds)
+	b.reset()
+	return b
+}
+
+func (b *geospatialBounds) reset() {
+	inf := math.Inf(+1)
+	b.xmin, b.xmax = inf, -inf
+	b.ymin, b.ymax = inf, -inf
+	b.zmin, b.zmax = inf, -inf
+	b.mmin, b.mmax = inf, -inf
+	b.types = b.types[:0]
+	b.invalid = false
+}
+
+// updatePage updates the bounds with the values of page.
+func (b *geospatialBounds) updatePage(page Page) {
+	if b.invalid {
+		return
+	}
+	values := make([]Value, 64)
+	reader := page.Values()
+	for {
+		n, err := reader.ReadValues(values)
+		for _, v := range values[:n] {
+			if !v.IsNull() {
+				b.update(v.byteArray())
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				b.invalid = true
+			}
+			return
+		}
+	}
+}
+
+// update updates the bounds with the WKB value.
+func (b *geospatialBounds) update(wkb []byte) {
+	if rest, err := b.readGeometry(wkb, 0); err != nil || len(rest) != 0 {
+		b.invalid = true
+	}
+}
+
+func (b *geospatialBounds) readGeometry(wkb []byte, depth int) ([]byte, error) {
+	if len(wkb) < 5 {
+		return wkb, io.ErrUnexpectedEOF
+	}
+	var order binary.ByteOrder
+	switch wkb[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return wkb, fmt.Errorf("invalid WKB byte order: %d", wkb[0])
+	}
+
+	code := order.Uint32(wkb[1:])
+	kind, dims := code%1000, code/1000
+	if kind < wkbPoint || kind > wkbGeometryCollection || dims > 3 {
+		return wkb, fmt.Errorf("invalid WKB geometry type: %d", code)
+	}
+	if depth == 0 {
+		b.addType(int32(code))
+	}
+	hasZ, hasM := dims == 1 || dims == 3, dims == 2 || dims == 3
+	wkb = wkb[5:]
+
+	switch kind {
+	case wkbPoint:
+		return b.readPoints(wkb, order, 1, hasZ, hasM)
+	case wkbLineString:
+		return b.readLineString(wkb, order, hasZ, hasM)
+	case wkbPolygon:
+		n, wkb, err := readWKBCount(wkb, order)
+		for i := 0; i < n && err == nil; i++ {
+			wkb, err = b.readLineString(wkb, order, hasZ, hasM)
+		}
+		return wkb, err
+	default:
+		if depth == wkbMaxDepth {
+			return wkb, fmt.Errorf("WKB geometry has more than %d nested levels", wkbMaxDepth)
+		}
+		n, wkb, err := readWKBCount(wkb, order)
+		for i := 0; i < n && err == nil; i++ {
+			wkb, err = b.readGeometry(wkb, depth+1)
+		}
+		return wkb, err
+	}
+}
+
+func (b *geospatialBounds) readLineString(wkb []byte, order binary.ByteOrder, hasZ, hasM bool) ([]byte, error) {
+	n, wkb, err := readWKBCount(wkb, order)
+	if err != nil {
+		return wkb, err
+	}
+	return b.readPoints(wkb, order, n, hasZ, hasM)
+}
+
+func (b *geospatialBounds) readPoints(wkb []byte, order binary.ByteOrder, n int, hasZ, hasM bool) ([]byte, error) {
+	dims := 2
+	if hasZ {
+		dims++
+	}
+	if hasM {
+		dims++
+	}
+	size := 8 * dims
+	if n > len(wkb)/size {
+		return wkb, io.ErrUnexpectedEOF
+	}
+
+	for i := 0; i < n; i++ {
+		x := math.Float64frombits(order.Uint64(wkb[0:]))
+		y := math.Float64frombits(order.Uint64(wkb[8:]))
+		// Empty points are encoded with NaN coordinates.
+		if !math.IsNaN(x) && !math.IsNaN(y) {
+			b.xmin, b.xmax = math.Min(b.xmin, x), math.Max(b.xmax, x)
+			b.ymin, b.ymax = math.Min(b.ymin, y), math.Max(b.ymax, y)
+		}
+		offset := 16
+		if hasZ {
+			if z := math.Float64frombits(order.Uint64(wkb[offset:])); !math.IsNaN(z) {
+				b.zmin, b.zmax = math.Min(b.zmin, z), math.Max(b.zmax, z)
+			}
+			offset += 8
+		}
+		if hasM {
+			if m := math.Float64frombits(order.Uint64(wkb[offset:])); !math.IsNaN(m) {
+				b.mmin, b.mmax = math.Min(b.mmin, m), math.Max(b.mmax, m)
+			}
+		}
+		wkb = wkb[size:]
+	}
+
+	return wkb, nil
+}
+
+func readWKBCount(wkb []byte, order binary.ByteOrder) (int, []byte, error) {
+	if len(wkb) < 4 {
+		return 0, wkb, io.ErrUnexpectedEOF
+	}
+	n := order.Uint32(wkb)
+	// Each element occupies at least 4 bytes, which bounds the count by the
+	// length of the input and guards against overflows.
+	if uint64(n) > uint64(len(wkb)-4)/4 {
+		return 0, wkb, io.ErrUnexpectedEOF
+	}
+	return int(n), wkb[4:], nil
+}
+
+func (b *geospatialBounds) addType(code int32) {
+	i := sort.Search(len(b.types), func(i int) bool { return b.types[i] >= code })
+	if i < len(b.types) && b.types[i] == code {
+		return
+	}
+	b.types = append(b.types, 0)
+	copy(b.types[i+1:], b.types[i:])
+	b.types[i] = code
+}
+
+// statistics returns the geospatial statistics of the values seen since the
+// last reset, or nil if they are unknown.
+func (b *geospatialBounds) statistics() *format.GeospatialStatistics {
+	if b.invalid || len(b.types) == 0 {
+		return nil
+	}
+	stats := &format.GeospatialStatistics{
+		GeospatialTypes: make([]int32, len(b.types)),
+	}
+	copy(stats.GeospatialTypes, b.types)
+
+	// The bounding box is omitted when all values are empty.
+	if b.xmin <= b.xmax {
+		stats.BBox = &format.BoundingBox{
+			XMin: b.xmin,
+			XMax: b.xmax,
+			YMin: b.ymin,
+			YMax: b.ymax,
+		}
+		if b.zmin <= b.zmax {
+			stats.BBox.ZMin, stats.BBox.ZMax = newFloat64(b.zmin), newFloat64(b.zmax)
+		}
+		if b.mmin <= b.mmax {
+			stats.BBox.MMin, stats.BBox.MMax = newFloat64(b.mmin), newFloat64(b.mmax)
+		}
+	}
+	return stats
+}
+
+func newFloat64(v float64) *float64 { return &v }
+
+func lookupEdgeInterpolationAlgorithm(name string) (format.EdgeInterpolationAlgorithm, bool) {
+	for a := format.Spherical; a <= format.Karney; a++ {
+		if strings.EqualFold(name, a.String()) {
+			return a, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,310 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type wkbPoint struct{ x, y float64 }
+
+func appendWKBHeader(b []byte, code uint32) []byte {
+	b = append(b, 1) // little endian
+	return binary.LittleEndian.AppendUint32(b, code)
+}
+
+func appendWKBCoords(b []byte, coords ...float64) []byte {
+	for _, c := range coords {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c))
+	}
+	return b
+}
+
+func wkbPointOf(x, y float64) []byte {
+	return appendWKBCoords(appendWKBHeader(nil, 1), x, y)
+}
+
+func wkbLineStringOf(points ...wkbPoint) []byte {
+	b := appendWKBHeader(nil, 2)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(points)))
+	for _, p := range points {
+		b = appendWKBCoords(b, p.x, p.y)
+	}
+	return b
+}
+
+func wkbPolygonZOf(coords ...[3]float64) []byte {
+	b := appendWKBHeader(nil, 1003)
+	b = binary.LittleEndian.AppendUint32(b, 1)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(coords)))
+	for _, c := range coords {
+		b = appendWKBCoords(b, c[0], c[1], c[2])
+	}
+	return b
+}
+
+func wkbCollectionOf(geometries ...[]byte) []byte {
+	b := appendWKBHeader(nil, 7)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(geometries)))
+	for _, g := range geometries {
+		b = append(b, g...)
+	}
+	return b
+}
+
+type geospatialRow struct {
+	ID       int64  `parquet:"id"`
+	Location []byte `parquet:"location,optional,geometry"`
+	Area     string `parquet:"area,dict,geography(EPSG:4326,vincenty)"`
+}
+
+func TestGeospatialSchema(t *testing.T) {
+	const expected = `message geospatialRow {
+	required int64 id (INT(64,true));
+	optional binary location (GEOMETRY);
+	required binary area (GEOGRAPHY(EPSG:4326,VINCENTY));
+}`
+
+	schema := parquet.SchemaOf(new(geospatialRow))
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	for _, test := range []string{
+		expected,
+		`message m {
+	required binary a (GEOMETRY(OGC:CRS84));
+	required binary b (GEOGRAPHY);
+	required binary c (GEOGRAPHY(srid:4326));
+}`,
+	} {
+		parsed, err := parquet.ParseSchema(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := parsed.String(); s != test {
+			t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", test, s)
+		}
+	}
+
+	parsed, err := parquet.ParseSchema(`message m {
+	required binary a (GEOGRAPHY(crs=OGC:CRS84,algorithm=karney));
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const parsedExpected = `message m {
+	required binary a (GEOGRAPHY(OGC:CRS84,KARNEY));
+}`
+	if s := parsed.String(); s != parsedExpected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", parsedExpected, s)
+	}
+
+	for _, invalid := range []string{
+		`message m { required int32 a (GEOMETRY); }`,
+		`message m { required binary a (GEOMETRY(a,b)); }`,
+		`message m { required binary a (GEOGRAPHY(OGC:CRS84,straight)); }`,
+		`message m { required group a (GEOMETRY) { required binary b; } }`,
+	} {
+		if _, err := parquet.ParseSchema(invalid); err == nil {
+			t.Errorf("expected an error parsing %q", invalid)
+		}
+	}
+}
+
+func TestGeospatialStatistics(t *testing.T) {
+	rows := []geospatialRow{
+		{ID: 1, Location: wkbPointOf(-122.4, 37.8), Area: string(wkbPointOf(10, 20))},
+		{ID: 2, Location: nil, Area: string(wkbLineStringOf(wkbPoint{-5, 1}, wkbPoint{5, 2}))},
+		{ID: 3, Location: wkbLineStringOf(wkbPoint{2.35, 48.85}, wkbPoint{-0.12, 51.5}), Area: string(wkbPointOf(0, 0))},
+		{ID: 4, Location: wkbPolygonZOf([3]float64{0, 0, 5}, [3]float64{1, 0, 7}, [3]float64{1, 1, -3}, [3]float64{0, 0, 5}), Area: string(wkbPointOf(1, 1))},
+		{ID: 5, Location: wkbCollectionOf(wkbPointOf(math.NaN(), math.NaN()), wkbPointOf(3, -60)), Area: string(wkbPointOf(2, 2))},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(4))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroups := f.RowGroups()
+	if len(rowGroups) != 2 {
+		t.Fatalf("wrong number of row groups: want=2 got=%d", len(rowGroups))
+	}
+
+	z := func(v float64) *float64 { return &v }
+	expected := [][2]*format.GeospatialStatistics{
+		{
+			{
+				BBox: &format.BoundingBox{
+					XMin: -122.4, XMax: 2.35,
+					YMin: 0, YMax: 51.5,
+					ZMin: z(-3), ZMax: z(7),
+				},
+				GeospatialTypes: []int32{1, 2, 1003},
+			},
+			{
+				BBox:            &format.BoundingBox{XMin: -5, XMax: 10, YMin: 0, YMax: 20},
+				GeospatialTypes: []int32{1, 2},
+			},
+		},
+		{
+			{
+				BBox:            &format.BoundingBox{XMin: 3, XMax: 3, YMin: -60, YMax: -60},
+				GeospatialTypes: []int32{7},
+			},
+			{
+				BBox:            &format.BoundingBox{XMin: 2, XMax: 2, YMin: 2, YMax: 2},
+				GeospatialTypes: []int32{1},
+			},
+		},
+	}
+
+	for i, rowGroup := range rowGroups {
+		chunks := rowGroup.ColumnChunks()
+		if stats := parquet.GeospatialStatisticsOf(chunks[0]); stats != nil {
+			t.Errorf("row group %d: unexpected geospatial statistics on the id column: %+v", i, stats)
+		}
+		for j, want := range expected[i] {
+			got := parquet.GeospatialStatisticsOf(chunks[j+1])
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("row group %d, column %d: geospatial statistics mismatch\nwant: %+v\ngot:  %+v", i, j+1, want, got)
+				if got != nil && want.BBox != nil && got.BBox != nil {
+					t.Logf("bbox: want=%+v got=%+v", *want.BBox, *got.BBox)
+				}
+			}
+		}
+	}
+
+	for j, order := range f.Metadata().ColumnOrders[1:] {
+		if order.TypeOrder != nil {
+			t.Errorf("column %d: geospatial columns must not use the type defined order", j+1)
+		}
+	}
+	for _, rowGroup := range f.Metadata().RowGroups {
+		for j, chunk := range rowGroup.Columns[1:] {
+			if s := chunk.MetaData.Statistics; s.MinValue != nil || s.MaxValue != nil {
+				t.Errorf("column %d: min/max statistics written for geospatial column: %+v", j+1, s)
+			}
+			if chunk.ColumnIndexOffset != 0 {
+				t.Errorf("column %d: column index written for geospatial column", j+1)
+			}
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i := range rows {
+		got := geospatialRow{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows[i]) {
+			t.Errorf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, rows[i], got)
+		}
+	}
+}
+
+func TestGeospatialStatisticsInvalidWKB(t *testing.T) {
+	type row struct {
+		Location []byte `parquet:"location,geometry"`
+	}
+	truncated := wkbLineStringOf(wkbPoint{1, 2}, wkbPoint{3, 4})
+	truncated = truncated[:len(truncated)-1]
+
+	for _, value := range [][]byte{
+		{},
+		{2, 1, 0, 0, 0},
+		append(appendWKBHeader(nil, 8), make([]byte, 16)...),
+		truncated,
+		append(wkbPointOf(1, 2), 0),
+	} {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer)
+		for _, r := range []row{{Location: wkbPointOf(1, 2)}, {Location: value}} {
+			if err := writer.Write(&r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats := parquet.GeospatialStatisticsOf(f.RowGroups()[0].ColumnChunks()[0]); stats != nil {
+			t.Errorf("%x: expected no geospatial statistics for invalid WKB but got %+v", value, stats)
+		}
+	}
+}
+
+func TestGeospatialConvert(t *testing.T) {
+	from := parquet.NewSchema("test", parquet.Group{"value": parquet.Leaf(parquet.ByteArrayType)})
+	point := wkbPointOf(1, 2)
+
+	for _, node := range []parquet.Node{
+		parquet.Geometry(""),
+		parquet.Geography("", format.Spherical),
+	} {
+		t.Run(node.Type().String(), func(t *testing.T) {
+			to := parquet.NewSchema("test", parquet.Group{"value": node})
+
+			conv, err := parquet.Convert(to, from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := []parquet.Row{{parquet.ByteArrayValue(point)}}
+			if _, err := conv.Convert(rows); err != nil {
+				t.Fatal(err)
+			}
+			if got := rows[0][0].ByteArray(); !bytes.Equal(got, point) {
+				t.Errorf("converted value mismatch: want=%x got=%x", point, got)
+			}
+
+			_, err = node.Type().ConvertValue(parquet.Int32Value(1), parquet.Int32Type)
+			if !errors.Is(err, parquet.ErrInvalidConversion) {
+				t.Errorf("expected an invalid conversion error from INT32 but got %v", err)
+			}
+		})
+	}
+}
+
+func TestBoundingBoxIntersects(t *testing.T) {
+	box := &format.BoundingBox{XMin: -10, XMax: 10, YMin: 40, YMax: 50}
+	wrapped := &format.BoundingBox{XMin: 170, XMax: -170, YMin: -10, YMax: 10}
+
+	tests := []struct {
+		box                    *format.BoundingBox
+		xmin, ymin, xmax, ymax float64
+		intersects             bool
+	}{
+		{box, 0, 45, 1, 46, true},
+		{box, -20, 30, -10, 40, true},
+		{box, 11, 40, 20, 50, false},
+		{box, 0, 51, 1, 60, false},
+		{wrapped, 175, 0, 178, 1, true},
+		{wrapped, -179, 0, -175, 1, true},
+		{wrapped, 0, 0, 10, 1, false},
+	}
+
+	for _, test := range tests {
+		if test.box.Intersects(test.xmin, test.ymin, test.xmax, test.ymax) != test.intersects {
+			t.Errorf("%+v intersects [%g,%g]x[%g,%g]: want=%t", *test.box, test.xmin, test.xmax, test.ymin, test.ymax, test.intersects)
+		}
+	}
+}
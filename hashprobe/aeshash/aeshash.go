@@ -6,7 +6,10 @@
 // to call any other function will trigger a panic.
 package aeshash
 
-import "github.com/segmentio/parquet-go/sparse"
+import (
+	"github.com/segmentio/parquet-go/internal/unsafecast"
+	"github.com/segmentio/parquet-go/sparse"
+)
 
 func MultiHash32(hashes []uintptr, values []uint32, seed uintptr) {
 	MultiHashUint32Array(hashes, sparse.MakeUint32Array(values), seed)
@@ -19,3 +22,19 @@ func MultiHash64(hashes []uintptr, values []uint64, seed uintptr) {
 func MultiHash128(hashes []uintptr, values [][16]byte, seed uintptr) {
 	MultiHashUint128Array(hashes, sparse.MakeUint128Array(values), seed)
 }
+
+// MultiHashBytes computes the hashes of byte arrays stored contiguously in
+// values, the i-th byte array being values[offsets[i]:offsets[i+1]]. The
+// offsets slice must contain one more element than the hashes slice.
+func MultiHashBytes(hashes []uintptr, values []byte, offsets []uint32, seed uintptr) {
+	_ = offsets[:len(hashes)+1]
+	for i := range hashes {
+		hashes[i] = HashBytes(values[offsets[i]:offsets[i+1]], seed)
+	}
+}
+
+func MultiHashStringArray(hashes []uintptr, values sparse.StringArray, seed uintptr) {
+	for i := range hashes {
+		hashes[i] = HashBytes(unsafecast.StringToBytes(values.Index(i)), seed)
+	}
+}
@@ -3,6 +3,8 @@
 package aeshash
 
 import (
+	"unsafe"
+
 	"github.com/segmentio/parquet-go/sparse"
 	"golang.org/x/sys/cpu"
 )
@@ -24,6 +26,18 @@ func Hash64(value uint64, seed uintptr) uintptr
 //go:noescape
 func Hash128(value [16]byte, seed uintptr) uintptr
 
+// HashBytes computes the hash of a byte array of arbitrary length.
+//
+// The function uses the Go runtime's hashing of memory areas, which is based
+// on AES instructions when they are available.
+func HashBytes(value []byte, seed uintptr) uintptr {
+	return memhash(*(*unsafe.Pointer)(unsafe.Pointer(&value)), seed, uintptr(len(value)))
+}
+
+//go:noescape
+//go:linkname memhash runtime.memhash
+func memhash(data unsafe.Pointer, seed, size uintptr) uintptr
+
 //go:noescape
 func MultiHashUint32Array(hashes []uintptr, values sparse.Uint32Array, seed uintptr)
 
@@ -16,6 +16,8 @@ func Hash64(value uint64, seed uintptr) uintptr { panic(unsupported) }
 
 func Hash128(value [16]byte, seed uintptr) uintptr { panic(unsupported) }
 
+func HashBytes(value []byte, seed uintptr) uintptr { panic(unsupported) }
+
 func MultiHashUint32Array(hashes []uintptr, values sparse.Uint32Array, seed uintptr) {
 	panic(unsupported)
 }
@@ -159,6 +159,49 @@ func TestMultiHash128(t *testing.T) {
 	}
 }
 
+func TestHashBytes(t *testing.T) {
+	if !Enabled() {
+		t.Skip("AES hash not supported on this platform")
+	}
+
+	value := []byte("Hello World!")
+	h0 := runtime_memhash(unsafe.Pointer(&value[0]), 1, uintptr(len(value)))
+	h1 := HashBytes(value, 1)
+
+	if h0 != h1 {
+		t.Errorf("want=%016x got=%016x", h0, h1)
+	}
+}
+
+func TestMultiHashBytes(t *testing.T) {
+	if !Enabled() {
+		t.Skip("AES hash not supported on this platform")
+	}
+
+	const N = 10
+	hashes := [N]uintptr{}
+	values := []byte{}
+	offsets := []uint32{0}
+	seed := uintptr(128)
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < 7*i; j++ {
+			values = append(values, byte(i))
+		}
+		offsets = append(offsets, uint32(len(values)))
+	}
+
+	MultiHashBytes(hashes[:], values, offsets, seed)
+
+	for i := range hashes {
+		h := HashBytes(values[offsets[i]:offsets[i+1]], seed)
+
+		if h != hashes[i] {
+			t.Errorf("hash(%d): want=%016x got=%016x", i, h, hashes[i])
+		}
+	}
+}
+
 func benchmarkHashThroughput(b *testing.B, f func(seed uintptr) int) {
 	hashes := int64(0)
 	start := time.Now()
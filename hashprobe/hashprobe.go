@@ -781,3 +781,321 @@ func multiProbe128Default(table []byte, tableCap, tableLen int, hashes []uintptr
 
 	return tableLen
 }
+
+type BytesTable struct{ tableBytes }
+
+func NewBytesTable(cap int, maxLoad float64) *BytesTable {
+	return &BytesTable{makeTableBytes(cap, maxLoad)}
+}
+
+func (t *BytesTable) Reset() { t.reset() }
+
+func (t *BytesTable) Len() int { return t.len() }
+
+func (t *BytesTable) Cap() int { return t.cap }
+
+// Probe probes the byte arrays stored contiguously in keys, the i-th key being
+// keys[offsets[i]:offsets[i+1]]. The offsets slice must therefore contain one
+// more element than the number of keys.
+func (t *BytesTable) Probe(keys []byte, offsets []uint32, values []int32) int {
+	return t.probe(keys, offsets, values)
+}
+
+// Lookup is similar to Probe but never inserts keys in the table, the values
+// of keys which are not present are set to -1.
+func (t *BytesTable) Lookup(keys []byte, offsets []uint32, values []int32) {
+	t.lookup(keys, offsets, values)
+}
+
+func (t *BytesTable) ProbeArray(keys sparse.StringArray, values []int32) int {
+	return t.probeArray(keys, values)
+}
+
+// Index returns the key that was assigned the value i.
+//
+// The returned slice references memory owned by the table, it remains valid
+// until the table is reset.
+func (t *BytesTable) Index(i int32) []byte { return t.index(i) }
+
+// tableBytes is the generic implementation of probing tables for variable
+// length byte arrays.
+//
+// The table retains copies of the keys inserted, stored contiguously in a byte
+// buffer and delimited by their offsets, which means that the keys can be
+// retrieved from their value. The slots of the table hold the value associated
+// with the key plus one, using zero as a sentinel to determine whether a slot
+// is occupied, as well as the lower 32 bits of the key hash and the location
+// of the key in the buffer. A linear probing strategy is used to resolve
+// conflicts, the key bytes are only compared when the hashes and lengths
+// match, which avoids most of the memory loads of keys, and a slot fits in a
+// quarter of a CPU cache line.
+type tableBytes struct {
+	cap     int
+	maxLen  int
+	maxLoad float64
+	seed    uintptr
+	slots   []tableBytesSlot
+	keys    []byte
+	offsets []uint32
+}
+
+type tableBytesSlot struct {
+	hash   uint32
+	value  uint32
+	offset uint32
+	length uint32
+}
+
+func makeTableBytes(cap int, maxLoad float64) (t tableBytes) {
+	if maxLoad < 0 || maxLoad > 1 {
+		panic("max load of probing table must be a value between 0 and 1")
+	}
+	if cap < 8 {
+		cap = 8
+	}
+	t.init(cap, maxLoad)
+	t.offsets = make([]uint32, 1, cap+1)
+	return t
+}
+
+func (t *tableBytes) init(cap int, maxLoad float64) {
+	size, maxLen := tableSizeAndMaxLen(1, cap, maxLoad)
+	t.cap = size
+	t.maxLen = maxLen
+	t.maxLoad = maxLoad
+	t.seed = randSeed()
+	t.slots = make([]tableBytesSlot, size)
+}
+
+func (t *tableBytes) len() int { return len(t.offsets) - 1 }
+
+func (t *tableBytes) index(i int32) []byte {
+	j := t.offsets[i+0]
+	k := t.offsets[i+1]
+	return t.keys[j:k:k]
+}
+
+func (t *tableBytes) grow(totalValues int) {
+	t.init(totalValues, t.maxLoad)
+
+	hashes := make([]uintptr, probesPerLoop)
+	useAesHash := aeshash.Enabled()
+	modulo := uintptr(t.cap) - 1
+	numKeys := t.len()
+
+	for i := 0; i < numKeys; {
+		j := len(hashes) + i
+		n := len(hashes)
+
+		if j > numKeys {
+			j = numKeys
+			n = numKeys - i
+		}
+
+		h := hashes[:n:n]
+		o := t.offsets[i : j+1 : j+1]
+
+		if useAesHash {
+			aeshash.MultiHashBytes(h, t.keys, o, t.seed)
+		} else {
+			wyhash.MultiHashBytes(h, t.keys, o, t.seed)
+		}
+
+		for x, hash := range h {
+			slot := tableBytesSlot{
+				hash:   uint32(hash),
+				value:  uint32(i + x + 1),
+				offset: o[x],
+				length: o[x+1] - o[x],
+			}
+			for {
+				p := hash & modulo
+				if t.slots[p].value == 0 {
+					t.slots[p] = slot
+					break
+				}
+				hash++
+			}
+		}
+
+		i = j
+	}
+}
+
+func (t *tableBytes) reset() {
+	t.keys = t.keys[:0]
+	t.offsets = t.offsets[:1]
+
+	for i := range t.slots {
+		t.slots[i] = tableBytesSlot{}
+	}
+}
+
+func (t *tableBytes) probe(keys []byte, offsets []uint32, values []int32) int {
+	numKeys := len(offsets) - 1
+
+	if totalValues := t.len() + numKeys; totalValues > t.maxLen {
+		t.grow(totalValues)
+	}
+
+	var hashes [probesPerLoop]uintptr
+	var baseLength = t.len()
+	var useAesHash = aeshash.Enabled()
+
+	_ = values[:numKeys]
+
+	for i := 0; i < numKeys; {
+		j := len(hashes) + i
+		n := len(hashes)
+
+		if j > numKeys {
+			j = numKeys
+			n = numKeys - i
+		}
+
+		k := offsets[i : j+1 : j+1]
+		v := values[i:j:j]
+		h := hashes[:n:n]
+
+		if useAesHash {
+			aeshash.MultiHashBytes(h, keys, k, t.seed)
+		} else {
+			wyhash.MultiHashBytes(h, keys, k, t.seed)
+		}
+
+		for x, hash := range h {
+			v[x] = t.probeKey(hash, keys[k[x]:k[x+1]])
+		}
+
+		i = j
+	}
+
+	return t.len() - baseLength
+}
+
+func (t *tableBytes) lookup(keys []byte, offsets []uint32, values []int32) {
+	numKeys := len(offsets) - 1
+
+	var hashes [probesPerLoop]uintptr
+	var useAesHash = aeshash.Enabled()
+
+	_ = values[:numKeys]
+
+	for i := 0; i < numKeys; {
+		j := len(hashes) + i
+		n := len(hashes)
+
+		if j > numKeys {
+			j = numKeys
+			n = numKeys - i
+		}
+
+		k := offsets[i : j+1 : j+1]
+		v := values[i:j:j]
+		h := hashes[:n:n]
+
+		if useAesHash {
+			aeshash.MultiHashBytes(h, keys, k, t.seed)
+		} else {
+			wyhash.MultiHashBytes(h, keys, k, t.seed)
+		}
+
+		for x, hash := range h {
+			v[x] = t.lookupKey(hash, keys[k[x]:k[x+1]])
+		}
+
+		i = j
+	}
+}
+
+func (t *tableBytes) probeArray(keys sparse.StringArray, values []int32) int {
+	numKeys := keys.Len()
+
+	if totalValues := t.len() + numKeys; totalValues > t.maxLen {
+		t.grow(totalValues)
+	}
+
+	var hashes [probesPerLoop]uintptr
+	var baseLength = t.len()
+	var useAesHash = aeshash.Enabled()
+
+	_ = values[:numKeys]
+
+	for i := 0; i < numKeys; {
+		j := len(hashes) + i
+		n := len(hashes)
+
+		if j > numKeys {
+			j = numKeys
+			n = numKeys - i
+		}
+
+		k := keys.Slice(i, j)
+		v := values[i:j:j]
+		h := hashes[:n:n]
+
+		if useAesHash {
+			aeshash.MultiHashStringArray(h, k, t.seed)
+		} else {
+			wyhash.MultiHashStringArray(h, k, t.seed)
+		}
+
+		for x, hash := range h {
+			v[x] = t.probeKey(hash, unsafecast.StringToBytes(k.Index(x)))
+		}
+
+		i = j
+	}
+
+	return t.len() - baseLength
+}
+
+func (t *tableBytes) probeKey(hash uintptr, key []byte) int32 {
+	modulo := uintptr(t.cap) - 1
+	tag := uint32(hash)
+
+	for {
+		slot := &t.slots[hash&modulo]
+
+		if slot.value == 0 {
+			*slot = tableBytesSlot{
+				hash:   tag,
+				value:  uint32(len(t.offsets)),
+				offset: uint32(len(t.keys)),
+				length: uint32(len(key)),
+			}
+			t.keys = append(t.keys, key...)
+			t.offsets = append(t.offsets, uint32(len(t.keys)))
+			return int32(slot.value - 1)
+		}
+
+		if slot.hash == tag && slot.length == uint32(len(key)) {
+			if string(t.keys[slot.offset:slot.offset+slot.length]) == string(key) {
+				return int32(slot.value - 1)
+			}
+		}
+
+		hash++
+	}
+}
+
+func (t *tableBytes) lookupKey(hash uintptr, key []byte) int32 {
+	modulo := uintptr(t.cap) - 1
+	tag := uint32(hash)
+
+	for {
+		slot := &t.slots[hash&modulo]
+
+		if slot.value == 0 {
+			return -1
+		}
+
+		if slot.hash == tag && slot.length == uint32(len(key)) {
+			if string(t.keys[slot.offset:slot.offset+slot.length]) == string(key) {
+				return int32(slot.value - 1)
+			}
+		}
+
+		hash++
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
+
+	"github.com/segmentio/parquet-go/sparse"
 )
 
 func TestTable32GroupSize(t *testing.T) {
@@ -171,6 +175,146 @@ func TestUint128TableProbeBulk(t *testing.T) {
 	}
 }
 
+func TestBytesTableProbeOneByOne(t *testing.T) {
+	const N = 500
+	table := NewBytesTable(0, 0.9)
+
+	for n := 0; n < 2; n++ {
+		// Do two passes, both should behave the same.
+		for i := 1; i <= N; i++ {
+			k := []byte(fmt.Sprintf("key-%d", i))
+			v := [1]int32{}
+
+			table.Probe(k, []uint32{0, uint32(len(k))}, v[:])
+
+			if v[0] != int32(i-1) {
+				t.Errorf("wrong value probed for key=%q: want=%d got=%d", k, i-1, v[0])
+			}
+		}
+	}
+
+	if table.Len() != N {
+		t.Errorf("wrong table length: want=%d got=%d", N, table.Len())
+	}
+}
+
+func TestBytesTableProbeBulk(t *testing.T) {
+	const N = 999
+	table := NewBytesTable(0, 0.9)
+
+	// 400 distinct keys of various lengths, including the empty key and keys
+	// that are prefixes of each other.
+	k := []byte{}
+	o := []uint32{0}
+	v := make([]int32, N)
+
+	for i := 0; i < N; i++ {
+		k = append(k, strings.Repeat("x", i%40)...)
+		if d := (i / 40) % 10; d != 0 {
+			k = strconv.AppendInt(k, int64(d), 10)
+		}
+		o = append(o, uint32(len(k)))
+	}
+
+	distinct := make(map[string]int32)
+	for i := 0; i < N; i++ {
+		key := string(k[o[i]:o[i+1]])
+		if _, ok := distinct[key]; !ok {
+			distinct[key] = int32(len(distinct))
+		}
+	}
+
+	for n := 0; n < 2; n++ {
+		table.Probe(k, o, v)
+
+		for i := range v {
+			key := k[o[i]:o[i+1]]
+			if want := distinct[string(key)]; v[i] != want {
+				t.Errorf("wrong value probed for key=%q: want=%d got=%d", key, want, v[i])
+			}
+			if index := table.Index(v[i]); string(index) != string(key) {
+				t.Errorf("wrong key at index %d: want=%q got=%q", v[i], key, index)
+			}
+		}
+
+		if t.Failed() {
+			break
+		}
+
+		for i := range v {
+			v[i] = 0
+		}
+	}
+
+	if table.Len() != len(distinct) {
+		t.Errorf("wrong table length: want=%d got=%d", len(distinct), table.Len())
+	}
+
+	table.Reset()
+
+	if table.Len() != 0 {
+		t.Errorf("table not empty after reset: %d", table.Len())
+	}
+}
+
+func TestBytesTableLookup(t *testing.T) {
+	const N = 500
+	table := NewBytesTable(0, 0.9)
+
+	k := []byte{}
+	o := []uint32{0}
+	v := make([]int32, N)
+
+	for i := 0; i < N; i++ {
+		k = strconv.AppendInt(append(k, "key-"...), int64(i), 10)
+		o = append(o, uint32(len(k)))
+	}
+
+	// Insert the keys with an even index only.
+	for i := 0; i < N; i += 2 {
+		table.Probe(k, o[i:i+2], v[:1])
+	}
+
+	table.Lookup(k, o, v)
+
+	for i := range v {
+		key := k[o[i]:o[i+1]]
+		want := int32(-1)
+		if i%2 == 0 {
+			want = int32(i / 2)
+		}
+		if v[i] != want {
+			t.Errorf("wrong value looked up for key=%q: want=%d got=%d", key, want, v[i])
+		}
+	}
+
+	if table.Len() != N/2 {
+		t.Errorf("wrong table length: want=%d got=%d", N/2, table.Len())
+	}
+}
+
+func TestBytesTableProbeArray(t *testing.T) {
+	const N = 2000
+	table := NewBytesTable(0, 0.9)
+
+	keys := make([]string, N)
+	values := make([]int32, N)
+
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i%(N/2))
+	}
+
+	if n := table.ProbeArray(sparse.MakeStringArray(keys), values); n != N/2 {
+		t.Errorf("wrong number of keys inserted: want=%d got=%d", N/2, n)
+	}
+
+	for i, v := range values {
+		if v != int32(i%(N/2)) {
+			t.Errorf("wrong value probed for key=%q: want=%d got=%d", keys[i], i%(N/2), v)
+		}
+	}
+}
+
 const (
 	benchmarkProbesPerLoop = 500
 	benchmarkMaxLoad       = 0.9
@@ -445,3 +589,100 @@ func generateUint128Table(n int) ([][16]byte, []int32) {
 
 	return keys, values
 }
+
+type bytesTable interface {
+	Reset()
+	Len() int
+	Probe([]byte, []uint32, []int32) int
+}
+
+type bytesMap map[string]int32
+
+func (m bytesMap) Reset() {
+	for k := range m {
+		delete(m, k)
+	}
+}
+
+func (m bytesMap) Len() int {
+	return len(m)
+}
+
+func (m bytesMap) Probe(keys []byte, offsets []uint32, values []int32) (n int) {
+	_ = values[:len(offsets)-1]
+
+	for i := range values {
+		k := keys[offsets[i]:offsets[i+1]]
+		v, ok := m[string(k)]
+		if !ok {
+			v = int32(len(m))
+			m[string(k)] = v
+			n++
+		}
+		values[i] = v
+	}
+
+	return n
+}
+
+func BenchmarkBytesTable(b *testing.B) {
+	benchmarkBytesTable(b, func(size int) bytesTable { return NewBytesTable(size, benchmarkMaxLoad) })
+}
+
+func BenchmarkGoBytesMap(b *testing.B) {
+	benchmarkBytesTable(b, func(size int) bytesTable { return make(bytesMap, size) })
+}
+
+func benchmarkBytesTable(b *testing.B, newTable func(size int) bytesTable) {
+	for n := 100; n <= 1e6; n *= 10 {
+		table := newTable(0)
+		keys, offsets, values := generateBytesTable(n)
+
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			benchmarkBytesLoop(b, table.Probe, keys, offsets, values)
+		})
+	}
+}
+
+func benchmarkBytesLoop(b *testing.B, f func([]byte, []uint32, []int32) int, keys []byte, offsets []uint32, values []int32) {
+	i := 0
+	j := benchmarkProbesPerLoop
+	b.SetBytes(int64(offsets[len(values)]) / int64(len(values)) * benchmarkProbesPerLoop)
+
+	_ = offsets[:len(values)+1]
+	start := time.Now()
+
+	for k := 0; k < b.N; k++ {
+		if j > len(values) {
+			j = len(values)
+		}
+		f(keys, offsets[i:j+1:j+1], values[i:j:j])
+		if j == len(values) {
+			i, j = 0, benchmarkProbesPerLoop
+		} else {
+			i, j = j, j+benchmarkProbesPerLoop
+		}
+	}
+
+	seconds := time.Since(start).Seconds()
+	b.ReportMetric(float64(benchmarkProbesPerLoop*b.N)/seconds, "probe/s")
+}
+
+// generateBytesTable generates keys that resemble the string values of log
+// records, with lengths between 8 and 40 bytes.
+func generateBytesTable(n int) ([]byte, []uint32, []int32) {
+	prng := rand.New(rand.NewSource(int64(n)))
+	keys := make([]byte, 0, 24*n)
+	offsets := make([]uint32, 1, n+1)
+	values := make([]int32, n)
+
+	for i := 0; i < n; i++ {
+		keys = append(keys, "service-"...)
+		for j := prng.Intn(32); j >= 0; j-- {
+			keys = append(keys, byte('a'+prng.Intn(26)))
+		}
+		offsets = append(offsets, uint32(len(keys)))
+	}
+
+	return keys, offsets, values
+}
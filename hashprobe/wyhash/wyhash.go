@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"math/bits"
 
+	"github.com/segmentio/parquet-go/internal/unsafecast"
 	"github.com/segmentio/parquet-go/sparse"
 )
 
@@ -36,6 +37,55 @@ func Hash128(value [16]byte, seed uintptr) uintptr {
 	return uintptr(mix(m5^16, mix(a^m2, b^uint64(seed)^m1)))
 }
 
+// HashBytes computes the hash of a byte array of arbitrary length, following
+// the same approach as the Go runtime's hashing fallback for memory areas.
+func HashBytes(value []byte, seed uintptr) uintptr {
+	var a, b uint64
+	var s = uint64(len(value))
+	var h = uint64(seed) ^ m1
+
+	switch {
+	case s == 0:
+		return uintptr(h)
+	case s < 4:
+		a = uint64(value[0])
+		a |= uint64(value[s>>1]) << 8
+		a |= uint64(value[s-1]) << 16
+	case s == 4:
+		a = uint64(binary.LittleEndian.Uint32(value))
+		b = a
+	case s < 8:
+		a = uint64(binary.LittleEndian.Uint32(value))
+		b = uint64(binary.LittleEndian.Uint32(value[s-4:]))
+	case s == 8:
+		a = binary.LittleEndian.Uint64(value)
+		b = a
+	case s <= 16:
+		a = binary.LittleEndian.Uint64(value)
+		b = binary.LittleEndian.Uint64(value[s-8:])
+	default:
+		p := value
+		if len(p) > 48 {
+			h1, h2 := h, h
+			for len(p) > 48 {
+				h = mix(binary.LittleEndian.Uint64(p[0:])^m2, binary.LittleEndian.Uint64(p[8:])^h)
+				h1 = mix(binary.LittleEndian.Uint64(p[16:])^m3, binary.LittleEndian.Uint64(p[24:])^h1)
+				h2 = mix(binary.LittleEndian.Uint64(p[32:])^m4, binary.LittleEndian.Uint64(p[40:])^h2)
+				p = p[48:]
+			}
+			h ^= h1 ^ h2
+		}
+		for len(p) > 16 {
+			h = mix(binary.LittleEndian.Uint64(p[0:])^m2, binary.LittleEndian.Uint64(p[8:])^h)
+			p = p[16:]
+		}
+		a = binary.LittleEndian.Uint64(value[s-16:])
+		b = binary.LittleEndian.Uint64(value[s-8:])
+	}
+
+	return uintptr(mix(m5^s, mix(a^m2, b^h)))
+}
+
 func MultiHash32(hashes []uintptr, values []uint32, seed uintptr) {
 	MultiHashUint32Array(hashes, sparse.MakeUint32Array(values), seed)
 }
@@ -47,3 +97,19 @@ func MultiHash64(hashes []uintptr, values []uint64, seed uintptr) {
 func MultiHash128(hashes []uintptr, values [][16]byte, seed uintptr) {
 	MultiHashUint128Array(hashes, sparse.MakeUint128Array(values), seed)
 }
+
+// MultiHashBytes computes the hashes of byte arrays stored contiguously in
+// values, the i-th byte array being values[offsets[i]:offsets[i+1]]. The
+// offsets slice must contain one more element than the hashes slice.
+func MultiHashBytes(hashes []uintptr, values []byte, offsets []uint32, seed uintptr) {
+	_ = offsets[:len(hashes)+1]
+	for i := range hashes {
+		hashes[i] = HashBytes(values[offsets[i]:offsets[i+1]], seed)
+	}
+}
+
+func MultiHashStringArray(hashes []uintptr, values sparse.StringArray, seed uintptr) {
+	for i := range hashes {
+		hashes[i] = HashBytes(unsafecast.StringToBytes(values.Index(i)), seed)
+	}
+}
@@ -1,7 +1,9 @@
 package wyhash
 
 import (
+	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
@@ -80,6 +82,67 @@ func TestMultiHash64(t *testing.T) {
 	}
 }
 
+func TestHashBytes(t *testing.T) {
+	// Byte arrays of every length up to a few times the 48 bytes loop of the
+	// algorithm, each must hash to a distinct value, including zero bytes
+	// values of different lengths.
+	value := make([]byte, 200)
+	seen := map[uintptr]string{HashBytes(nil, 1): "[]"}
+
+	for _, fill := range []byte{0, 42} {
+		for i := range value {
+			value[i] = fill
+		}
+		for n := 1; n <= len(value); n++ {
+			h := HashBytes(value[:n], 1)
+			k := fmt.Sprintf("[%d]x%d", fill, n)
+			if prev, ok := seen[h]; ok {
+				t.Fatalf("hash collision between %s and %s: %016x", prev, k, h)
+			}
+			seen[h] = k
+		}
+	}
+
+	if HashBytes([]byte("Hello World!"), 1) != HashBytes([]byte("Hello World!"), 1) {
+		t.Error("hashing the same value twice returned different hashes")
+	}
+	if HashBytes([]byte("Hello World!"), 1) == HashBytes([]byte("Hello World!"), 2) {
+		t.Error("hashing with different seeds returned the same hash")
+	}
+}
+
+func TestMultiHashBytes(t *testing.T) {
+	const N = 10
+	hashes := [N]uintptr{}
+	values := []byte{}
+	offsets := []uint32{0}
+	seed := uintptr(64)
+
+	for i := 0; i < N; i++ {
+		values = append(values, bytes.Repeat([]byte{byte(i)}, 7*i)...)
+		offsets = append(offsets, uint32(len(values)))
+	}
+
+	MultiHashBytes(hashes[:], values, offsets, seed)
+
+	for i := range hashes {
+		h := HashBytes(values[offsets[i]:offsets[i+1]], seed)
+
+		if h != hashes[i] {
+			t.Errorf("hash(%d): want=%016x got=%016x", i, h, hashes[i])
+		}
+	}
+}
+
+func BenchmarkHashBytes(b *testing.B) {
+	value := make([]byte, 32)
+	b.SetBytes(int64(len(value)))
+	benchmarkHashThroughput(b, func(seed uintptr) int {
+		HashBytes(value, seed)
+		return 1
+	})
+}
+
 func BenchmarkHash64(b *testing.B) {
 	b.SetBytes(8)
 	value := rand.Uint64()
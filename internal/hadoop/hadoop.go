@@ -0,0 +1,131 @@
+// Package hadoop implements the block framing of the compression codecs of
+// Hadoop, which is used by the LZ4 and LZO parquet compression codecs.
+//
+// The compressed data is a sequence of blocks, each block starts with the
+// big-endian 32 bits length of its uncompressed data, followed by one or more
+// chunks made of the big-endian 32 bits length of the chunk and the compressed
+// data. The uncompressed data of the chunks are concatenated to form the block.
+package hadoop
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/segmentio/parquet-go/compress"
+)
+
+// BlockSize is the maximum size of the uncompressed data written in a single
+// block. The Hadoop decompressors have a default buffer size of 256 KiB, the
+// limit leaves enough room for the compressed data of incompressible inputs.
+const BlockSize = 128 * 1024
+
+// MaxCompressionRatio is the maximum ratio between the uncompressed and the
+// compressed sizes of the blocks accepted by Decode. It is used to reject
+// corrupted headers before allocating memory for the uncompressed data.
+const MaxCompressionRatio = 255
+
+// ErrCorrupted is returned by Decode when the input is not a valid sequence of
+// blocks.
+var ErrCorrupted = errors.New("corrupted hadoop block framing")
+
+// Encode compresses src in blocks of at most BlockSize bytes with the encode
+// function, and writes the framed blocks to dst.
+//
+// The encode function has the signature of the Encode method of compression
+// codecs, it writes the compressed version of its input to the buffer passed
+// as first argument and returns it.
+func Encode(dst, src []byte, encode func(dst, src []byte) ([]byte, error)) ([]byte, error) {
+	var chunk []byte
+	var err error
+	dst = dst[:0]
+
+	for len(src) > 0 {
+		block := src
+		if len(block) > BlockSize {
+			block = block[:BlockSize]
+		}
+		src = src[len(block):]
+
+		if chunk, err = encode(chunk[:0], block); err != nil {
+			return dst, err
+		}
+
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(block)))
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(chunk)))
+		dst = append(dst, chunk...)
+	}
+
+	return dst, nil
+}
+
+// Decode writes the uncompressed data of the blocks of src to dst and returns
+// it. The decode function decompresses the chunks, it writes the uncompressed
+// data to the buffer passed as first argument which is sized to the remaining
+// length of the block, and returns the number of bytes written.
+//
+// ErrCorrupted is returned if src is not a valid sequence of blocks, or if the
+// chunks of a block do not decompress to the length of the block.
+func Decode(dst, src []byte, decode func(dst, src []byte) (int, error)) ([]byte, error) {
+	return DecodeLimit(dst, src, -1, decode)
+}
+
+// DecodeLimit is like Decode but returns compress.ErrDecodeLimit if the
+// uncompressed data exceeds limit bytes. The lengths of the blocks are checked
+// before memory is allocated for them. A negative limit means that the size of
+// the uncompressed data is not limited.
+func DecodeLimit(dst, src []byte, limit int, decode func(dst, src []byte) (int, error)) ([]byte, error) {
+	dst = dst[:0]
+
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return dst, ErrCorrupted
+		}
+		blockSize := int64(binary.BigEndian.Uint32(src))
+		src = src[4:]
+
+		if blockSize > MaxCompressionRatio*int64(len(src)) {
+			return dst, ErrCorrupted
+		}
+		if limit >= 0 && int64(len(dst))+blockSize > int64(limit) {
+			return dst, compress.ErrDecodeLimit
+		}
+
+		offset := len(dst)
+		dst = grow(dst, int(blockSize))
+		block := dst[offset:]
+
+		for n := 0; n < len(block); {
+			if len(src) < 4 {
+				return dst[:offset+n], ErrCorrupted
+			}
+			chunkSize := int64(binary.BigEndian.Uint32(src))
+			src = src[4:]
+
+			if chunkSize > int64(len(src)) {
+				return dst[:offset+n], ErrCorrupted
+			}
+			chunk := src[:chunkSize]
+			src = src[chunkSize:]
+
+			size, err := decode(block[n:], chunk)
+			if err != nil {
+				return dst[:offset+n], err
+			}
+			if size == 0 {
+				return dst[:offset+n], ErrCorrupted
+			}
+			n += size
+		}
+	}
+
+	return dst, nil
+}
+
+func grow(buf []byte, n int) []byte {
+	if size := len(buf) + n; size <= cap(buf) {
+		return buf[:size]
+	}
+	newBuf := make([]byte, len(buf)+n, 2*len(buf)+n)
+	copy(newBuf, buf)
+	return newBuf
+}
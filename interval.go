@@ -0,0 +1,171 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CalendarInterval is the Go representation of values of the parquet INTERVAL
+// type.
+//
+// An interval is made of a number of months, days, and milliseconds, which are
+// independent from each other since the number of days in a month, or of
+// milliseconds in a day (e.g. due to daylight saving time) may vary.
+//
+// The struct layout matches the parquet representation of intervals on little
+// endian platforms, values can be written without conversion.
+type CalendarInterval struct {
+	Months uint32
+	Days   uint32
+	Millis uint32
+}
+
+// String returns a representation of the interval in the ISO 8601 duration
+// format (e.g. "P1M15DT1.5S").
+func (i CalendarInterval) String() string {
+	return string(i.appendString(nil))
+}
+
+func (i CalendarInterval) appendString(b []byte) []byte {
+	b = append(b, 'P')
+	if i.Months != 0 {
+		b = strconv.AppendUint(b, uint64(i.Months), 10)
+		b = append(b, 'M')
+	}
+	if i.Days != 0 || (i.Months == 0 && i.Millis == 0) {
+		b = strconv.AppendUint(b, uint64(i.Days), 10)
+		b = append(b, 'D')
+	}
+	if i.Millis != 0 {
+		b = append(b, 'T')
+		b = strconv.AppendUint(b, uint64(i.Millis/1000), 10)
+		if ms := i.Millis % 1000; ms != 0 {
+			b = append(b, '.')
+			b = append(b, byte('0'+ms/100), byte('0'+(ms/10)%10), byte('0'+ms%10))
+			for b[len(b)-1] == '0' {
+				b = b[:len(b)-1]
+			}
+		}
+		b = append(b, 'S')
+	}
+	return b
+}
+
+// ParseCalendarInterval parses an interval from its representation in the ISO
+// 8601 duration format, as produced by CalendarInterval.String.
+//
+// Years are converted to 12 months, weeks to 7 days, and hours, minutes, and
+// seconds to milliseconds. Negative durations and fractions of units other
+// than seconds are not supported.
+func ParseCalendarInterval(s string) (CalendarInterval, error) {
+	var interval CalendarInterval
+	var timePart bool
+
+	input := s
+	if !strings.HasPrefix(s, "P") || len(s) == 1 {
+		return interval, fmt.Errorf("invalid interval: %q", input)
+	}
+	s = s[1:]
+
+	for s != "" {
+		if s[0] == 'T' && !timePart {
+			timePart, s = true, s[1:]
+			if s == "" {
+				return interval, fmt.Errorf("invalid interval: %q", input)
+			}
+			continue
+		}
+
+		i := 0
+		for i < len(s) && (('0' <= s[i] && s[i] <= '9') || s[i] == '.') {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return interval, fmt.Errorf("invalid interval: %q", input)
+		}
+		number, unit := s[:i], s[i]
+		s = s[i+1:]
+
+		if unit == 'S' && timePart {
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return interval, fmt.Errorf("invalid interval: %q: %w", input, err)
+			}
+			millis := f*1000 + 0.5
+			if millis > math.MaxUint32 || !addIntervalUnits(&interval.Millis, uint64(millis), 1) {
+				return interval, fmt.Errorf("invalid interval: %q: %w", input, strconv.ErrRange)
+			}
+			continue
+		}
+
+		n, err := strconv.ParseUint(number, 10, 32)
+		if err != nil {
+			return interval, fmt.Errorf("invalid interval: %q: %w", input, err)
+		}
+
+		var ok bool
+		switch {
+		case unit == 'Y' && !timePart:
+			ok = addIntervalUnits(&interval.Months, n, 12)
+		case unit == 'M' && !timePart:
+			ok = addIntervalUnits(&interval.Months, n, 1)
+		case unit == 'W' && !timePart:
+			ok = addIntervalUnits(&interval.Days, n, 7)
+		case unit == 'D' && !timePart:
+			ok = addIntervalUnits(&interval.Days, n, 1)
+		case unit == 'H' && timePart:
+			ok = addIntervalUnits(&interval.Millis, n, 3600000)
+		case unit == 'M' && timePart:
+			ok = addIntervalUnits(&interval.Millis, n, 60000)
+		default:
+			return interval, fmt.Errorf("invalid interval: %q: unexpected unit %q", input, unit)
+		}
+		if !ok {
+			return interval, fmt.Errorf("invalid interval: %q: %w", input, strconv.ErrRange)
+		}
+	}
+
+	return interval, nil
+}
+
+// addIntervalUnits adds n units of the given size to *field, returning false
+// if the result does not fit in 32 bits. The operands are bounded by 32 bits
+// and the size of an hour in milliseconds, the product cannot overflow.
+func addIntervalUnits(field *uint32, n, size uint64) bool {
+	sum := uint64(*field) + n*size
+	if sum > math.MaxUint32 {
+		return false
+	}
+	*field = uint32(sum)
+	return true
+}
+
+func compareCalendarInterval(i1, i2 CalendarInterval) int {
+	if cmp := compareUint32(i1.Months, i2.Months); cmp != 0 {
+		return cmp
+	}
+	if cmp := compareUint32(i1.Days, i2.Days); cmp != 0 {
+		return cmp
+	}
+	return compareUint32(i1.Millis, i2.Millis)
+}
+
+func makeCalendarInterval(b []byte) (i CalendarInterval) {
+	if len(b) == 12 {
+		i.Months = binary.LittleEndian.Uint32(b[0:])
+		i.Days = binary.LittleEndian.Uint32(b[4:])
+		i.Millis = binary.LittleEndian.Uint32(b[8:])
+	}
+	return i
+}
+
+func makeCalendarIntervalBytes(i CalendarInterval) []byte {
+	b := make([]byte, 12)
+	binary.LittleEndian.PutUint32(b[0:], i.Months)
+	binary.LittleEndian.PutUint32(b[4:], i.Days)
+	binary.LittleEndian.PutUint32(b[8:], i.Millis)
+	return b
+}
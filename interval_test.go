@@ -0,0 +1,209 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestCalendarIntervalString(t *testing.T) {
+	tests := []struct {
+		interval parquet.CalendarInterval
+		str      string
+	}{
+		{parquet.CalendarInterval{}, "P0D"},
+		{parquet.CalendarInterval{Months: 14}, "P14M"},
+		{parquet.CalendarInterval{Days: 3}, "P3D"},
+		{parquet.CalendarInterval{Millis: 1500}, "PT1.5S"},
+		{parquet.CalendarInterval{Millis: 1}, "PT0.001S"},
+		{parquet.CalendarInterval{Months: 1, Days: 15, Millis: 60000}, "P1M15DT60S"},
+	}
+
+	for _, test := range tests {
+		if s := test.interval.String(); s != test.str {
+			t.Errorf("%+v: want=%q got=%q", test.interval, test.str, s)
+		}
+		i, err := parquet.ParseCalendarInterval(test.str)
+		if err != nil {
+			t.Errorf("%q: %v", test.str, err)
+		} else if i != test.interval {
+			t.Errorf("%q: want=%+v got=%+v", test.str, test.interval, i)
+		}
+	}
+}
+
+func TestParseCalendarInterval(t *testing.T) {
+	i, err := parquet.ParseCalendarInterval("P1Y2M1W3DT4H5M6.789S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := parquet.CalendarInterval{
+		Months: 14,
+		Days:   10,
+		Millis: 4*3600000 + 5*60000 + 6789,
+	}
+	if i != want {
+		t.Errorf("want=%+v got=%+v", want, i)
+	}
+
+	for _, s := range []string{"", "P", "1D", "PT", "P1S", "PT1D", "P-1D", "P1.5D", "P1X"} {
+		if _, err := parquet.ParseCalendarInterval(s); err == nil {
+			t.Errorf("%q: expected an error but got none", s)
+		}
+	}
+
+	for _, s := range []string{
+		"P357913942Y",
+		"P4294967295Y",
+		"P4294967295M1M",
+		"P613566757W",
+		"P4294967295D1D",
+		"PT1194H",
+		"PT71583M",
+		"PT4294968S",
+		"PT4294967.2955S",
+		"PT1193H3600S",
+	} {
+		if _, err := parquet.ParseCalendarInterval(s); !errors.Is(err, strconv.ErrRange) {
+			t.Errorf("%q: expected an out of range error but got %v", s, err)
+		}
+	}
+
+	i, err = parquet.ParseCalendarInterval("P357913941Y3M613566756W3DT1193H2M47.295S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (parquet.CalendarInterval{Months: math.MaxUint32, Days: math.MaxUint32, Millis: math.MaxUint32}); i != want {
+		t.Errorf("want=%+v got=%+v", want, i)
+	}
+}
+
+type intervalRow struct {
+	ID       int64                     `parquet:"id"`
+	Duration parquet.CalendarInterval  `parquet:"duration"`
+	Raw      [12]byte                  `parquet:"raw,interval"`
+	Optional *parquet.CalendarInterval `parquet:"optional,optional"`
+}
+
+func TestIntervalReadWrite(t *testing.T) {
+	schema := parquet.SchemaOf(new(intervalRow))
+
+	const expected = `message intervalRow {
+	required int64 id (INT(64,true));
+	required fixed_len_byte_array(12) duration (INTERVAL);
+	required fixed_len_byte_array(12) raw (INTERVAL);
+	optional fixed_len_byte_array(12) optional (INTERVAL);
+}`
+	if s := schema.String(); s != expected {
+		t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	parsed, err := parquet.ParseSchema(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := parsed.String(); s != expected {
+		t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	rows := []intervalRow{
+		{ID: 1, Duration: parquet.CalendarInterval{Months: 1}, Optional: &parquet.CalendarInterval{Days: 2}},
+		{ID: 2, Duration: parquet.CalendarInterval{Days: 30}, Raw: [12]byte{8: 1}},
+		{ID: 3, Duration: parquet.CalendarInterval{Months: 1, Days: 2, Millis: 3}},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := f.Metadata().RowGroups[0].Columns
+	if stats := columns[0].MetaData.Statistics; stats.MinValue == nil || stats.MaxValue == nil {
+		t.Errorf("missing statistics on the id column: %+v", stats)
+	}
+	if columns[0].ColumnIndexOffset == 0 {
+		t.Error("missing column index on the id column")
+	}
+	for _, c := range f.Metadata().ColumnOrders[1:] {
+		if c.TypeOrder != nil {
+			t.Errorf("INTERVAL columns must not use the type defined order: %+v", c)
+		}
+	}
+	for _, c := range columns[1:] {
+		if stats := c.MetaData.Statistics; stats.MinValue != nil || stats.MaxValue != nil {
+			t.Errorf("statistics must not be written for INTERVAL columns: %+v", stats)
+		}
+		if c.ColumnIndexOffset != 0 {
+			t.Error("column index must not be written for INTERVAL columns")
+		}
+		if c.OffsetIndexOffset == 0 {
+			t.Error("missing offset index on INTERVAL column")
+		}
+	}
+	if len(f.ColumnIndexes()) != len(columns) {
+		t.Errorf("wrong number of column indexes: %d", len(f.ColumnIndexes()))
+	}
+
+	if s := f.Schema().String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, f.Schema())
+	}
+
+	reader := parquet.NewReader(f)
+	for i := range rows {
+		row := intervalRow{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(row, rows[i]) {
+			t.Errorf("row %d: want=%+v got=%+v", i, rows[i], row)
+		}
+	}
+	if err := reader.Read(new(intervalRow)); err != io.EOF {
+		t.Errorf("expected io.EOF but got %v", err)
+	}
+}
+
+func TestIntervalConvert(t *testing.T) {
+	interval := parquet.Interval().Type()
+
+	v, err := interval.ConvertValue(parquet.ByteArrayValue([]byte("P1M2DT3S")), parquet.String().Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := v.Interval(); i != (parquet.CalendarInterval{Months: 1, Days: 2, Millis: 3000}) {
+		t.Errorf("wrong interval converted from string: %+v", i)
+	}
+
+	v, err = parquet.String().Type().ConvertValue(parquet.IntervalValue(parquet.CalendarInterval{Days: 7}), interval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(v.ByteArray()); s != "P7D" {
+		t.Errorf("wrong string converted from interval: %q", s)
+	}
+
+	if _, err := interval.ConvertValue(parquet.Int64Value(1), parquet.Int64Type); err == nil {
+		t.Error("expected an error converting INT64 to INTERVAL")
+	}
+
+	if v := parquet.ValueOf(parquet.CalendarInterval{Months: 1}); v.Interval() != (parquet.CalendarInterval{Months: 1}) {
+		t.Errorf("wrong interval value: %+v", v.Interval())
+	}
+}
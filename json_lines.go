@@ -0,0 +1,1184 @@
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go/deprecated"
+)
+
+// JSONLinesError is the error type returned when JSON Lines encoding or
+// decoding fails.
+//
+// Line is the 1-based line number of the JSON document that caused the error,
+// it is zero when the error occurred while encoding rows. Path is the column
+// path, with names separated by dots, of the value that caused the error; it
+// may be empty if the error was not caused by a specific column (for example,
+// syntax errors).
+type JSONLinesError struct {
+	Line int
+	Path string
+	Err  error
+}
+
+func (e *JSONLinesError) Error() string {
+	s := "json lines"
+	if e.Line > 0 {
+		s += fmt.Sprintf(": line %d", e.Line)
+	}
+	if e.Path != "" {
+		s += ": " + e.Path
+	}
+	return s + ": " + e.Err.Error()
+}
+
+func (e *JSONLinesError) Unwrap() error { return e.Err }
+
+func jsonLinesErrorf(path columnPath, msg string, args ...interface{}) error {
+	return &JSONLinesError{Path: path.String(), Err: fmt.Errorf(msg, args...)}
+}
+
+// JSONLinesWriter is an implementation of RowWriter which renders parquet rows
+// as JSON Lines (also known as NDJSON), one JSON object per row.
+//
+// The writer uses the logical types of the schema to produce JSON values:
+//
+//	STRING, ENUM           string
+//	JSON                   embedded JSON value
+//	BSON, BYTE_ARRAY       base64 encoded string
+//	UUID                   string (e.g. "7e2d6c1a-...")
+//	FLOAT16                number
+//	INTERVAL               string in ISO 8601 format (e.g. "P1M2DT0.5S")
+//	DATE                   string (e.g. "2006-01-02")
+//	TIME                   string (e.g. "15:04:05.999")
+//	TIMESTAMP              string in RFC3339 format
+//	DECIMAL                string (e.g. "-12.345")
+//	INT(bits,false)        unsigned number
+//	LIST, repeated         array
+//	MAP                    object with keys rendered as strings
+//	group                  object with fields in schema order
+//	optional               null when the value is absent
+//
+// Floating point values that cannot be represented as JSON numbers (NaN and
+// infinities) are rendered as the strings "NaN", "+Inf", and "-Inf".
+type JSONLinesWriter struct {
+	writer  io.Writer
+	schema  *Schema
+	encode  jsonEncodeFunc
+	columns [][]Value
+	buffer  []byte
+}
+
+// NewJSONLinesWriter constructs a writer producing JSON Lines to w for rows
+// of the given schema.
+func NewJSONLinesWriter(w io.Writer, schema *Schema) *JSONLinesWriter {
+	_, encode := jsonEncodeFuncOf(0, schema, nil)
+	return &JSONLinesWriter{
+		writer:  w,
+		schema:  schema,
+		encode:  encode,
+		columns: make([][]Value, len(schema.Columns())),
+	}
+}
+
+// Schema returns the schema of rows written to w.
+func (w *JSONLinesWriter) Schema() *Schema { return w.schema }
+
+// WriteRows writes rows to w, one line per row.
+func (w *JSONLinesWriter) WriteRows(rows []Row) (int, error) {
+	for i, row := range rows {
+		for j := range w.columns {
+			w.columns[j] = nil
+		}
+
+		for j := 0; j < len(row); {
+			k := j + 1
+			c := row[j].Column()
+			for k < len(row) && row[k].Column() == c {
+				k++
+			}
+			if c < 0 || c >= len(w.columns) {
+				return i, &JSONLinesError{Err: fmt.Errorf("row value has column index %d out of range for schema with %d columns", c, len(w.columns))}
+			}
+			w.columns[c] = row[j:k:k]
+			j = k
+		}
+
+		b, err := w.encode(w.buffer[:0], levels{}, w.columns)
+		if err != nil {
+			return i, err
+		}
+		b = append(b, '\n')
+		w.buffer = b
+
+		if _, err := w.writer.Write(b); err != nil {
+			return i, err
+		}
+	}
+	return len(rows), nil
+}
+
+// JSONLinesReader is an implementation of RowReader which parses JSON Lines
+// (also known as NDJSON) into parquet rows.
+//
+// Each non-empty line of the input must contain a single JSON object matching
+// the schema, using the same representation as the one produced by
+// JSONLinesWriter. Missing or null fields are read as null values of optional
+// columns, repeated columns accept arrays, and numeric values are type checked
+// against the column types. Values of STRING and ENUM columns must be JSON
+// strings unless the ScalarsAsStrings option is enabled. Fields that do not
+// exist in the schema cause an error to be returned.
+//
+// Errors returned by the reader when the input does not match the schema are
+// of type *JSONLinesError and carry the line number and column path of the
+// value that could not be decoded.
+type JSONLinesReader struct {
+	reader *bufio.Reader
+	schema *Schema
+	decode jsonDecodeFunc
+	line   int
+	err    error
+}
+
+// NewJSONLinesReader constructs a reader parsing JSON Lines from r into rows
+// of the given schema.
+func NewJSONLinesReader(r io.Reader, schema *Schema, options ...JSONLinesReaderOption) *JSONLinesReader {
+	config := DefaultJSONLinesReaderConfig()
+	config.Apply(options...)
+	_, decode := jsonDecodeFuncOf(0, schema, nil, 0, config)
+	return &JSONLinesReader{
+		reader: bufio.NewReader(r),
+		schema: schema,
+		decode: decode,
+	}
+}
+
+// Schema returns the schema of rows read from r.
+func (r *JSONLinesReader) Schema() *Schema { return r.schema }
+
+// ReadRows reads rows from r, one row per line. The method returns io.EOF
+// when the end of the input has been reached.
+func (r *JSONLinesReader) ReadRows(rows []Row) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	columns := make([][]Value, len(r.schema.Columns()))
+
+	for i := range rows {
+		line, err := r.readLine()
+		if err != nil {
+			r.err = err
+			return i, err
+		}
+
+		var value interface{}
+		d := json.NewDecoder(bytes.NewReader(line))
+		d.UseNumber()
+
+		if err := d.Decode(&value); err != nil {
+			r.err = &JSONLinesError{Line: r.line, Err: err}
+			return i, r.err
+		}
+		if d.More() {
+			r.err = &JSONLinesError{Line: r.line, Err: errors.New("unexpected data after the end of the JSON object")}
+			return i, r.err
+		}
+		if _, ok := value.(map[string]interface{}); !ok {
+			r.err = &JSONLinesError{Line: r.line, Err: fmt.Errorf("expected JSON object but found %s", jsonTypeName(value))}
+			return i, r.err
+		}
+
+		for j := range columns {
+			columns[j] = columns[j][:0]
+		}
+
+		if err := r.decode(columns, levels{}, value); err != nil {
+			var e *JSONLinesError
+			if errors.As(err, &e) {
+				e.Line = r.line
+			} else {
+				err = &JSONLinesError{Line: r.line, Err: err}
+			}
+			r.err = err
+			return i, err
+		}
+
+		rows[i] = appendRow(rows[i][:0], columns)
+	}
+
+	return len(rows), nil
+}
+
+func (r *JSONLinesReader) readLine() ([]byte, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if len(line) > 0 || err == nil {
+			r.line++
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			return trimmed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+type jsonEncodeFunc func([]byte, levels, [][]Value) ([]byte, error)
+
+func jsonEncodeFuncOf(columnIndex int16, node Node, path columnPath) (int16, jsonEncodeFunc) {
+	switch {
+	case node.Optional():
+		return jsonEncodeFuncOfOptional(columnIndex, node, path)
+	case node.Repeated():
+		return jsonEncodeFuncOfRepeated(columnIndex, Required(node), path)
+	case isList(node):
+		return jsonEncodeFuncOfRepeated(columnIndex, listElementOf(node), path.append("list", "element"))
+	case isMap(node):
+		return jsonEncodeFuncOfMap(columnIndex, node, path)
+	case node.Leaf():
+		return jsonEncodeFuncOfLeaf(columnIndex, node, path)
+	default:
+		return jsonEncodeFuncOfGroup(columnIndex, node, path)
+	}
+}
+
+func jsonFirstValue(columns [][]Value, path columnPath) (Value, error) {
+	if len(columns) == 0 || len(columns[0]) == 0 {
+		return Value{}, jsonLinesErrorf(path, "missing column values in row")
+	}
+	return columns[0][0], nil
+}
+
+func jsonEncodeFuncOfOptional(columnIndex int16, node Node, path columnPath) (int16, jsonEncodeFunc) {
+	nextColumnIndex, encode := jsonEncodeFuncOf(columnIndex, Required(node), path)
+	return nextColumnIndex, func(b []byte, levels levels, columns [][]Value) ([]byte, error) {
+		v, err := jsonFirstValue(columns, path)
+		if err != nil {
+			return b, err
+		}
+		levels.definitionLevel++
+		if v.definitionLevel < levels.definitionLevel {
+			return append(b, "null"...), nil
+		}
+		return encode(b, levels, columns)
+	}
+}
+
+// jsonSplitRepeated calls f for each repetition of the columns at the given
+// depth, passing the slices of column values that compose the element.
+func jsonSplitRepeated(columns [][]Value, depth byte, f func([][]Value) error) error {
+	values := make([][]Value, len(columns))
+	for i, column := range columns {
+		values[i] = column[0:0:len(column)]
+	}
+
+	for {
+		done := true
+		for j, column := range values {
+			column = column[len(column):cap(column)]
+			if len(column) == 0 {
+				values[j] = column
+				continue
+			}
+			done = false
+			k := 1
+			for k < len(column) && column[k].repetitionLevel > depth {
+				k++
+			}
+			values[j] = column[:k]
+		}
+		if done {
+			return nil
+		}
+		if err := f(values); err != nil {
+			return err
+		}
+	}
+}
+
+// jsonEncodeFuncOfRepeated returns a function encoding arrays of elements,
+// the element node is passed to the function to support lists where the
+// elements may be optional.
+func jsonEncodeFuncOfRepeated(columnIndex int16, elem Node, path columnPath) (int16, jsonEncodeFunc) {
+	nextColumnIndex, encode := jsonEncodeFuncOf(columnIndex, elem, path)
+	return nextColumnIndex, func(b []byte, levels levels, columns [][]Value) ([]byte, error) {
+		v, err := jsonFirstValue(columns, path)
+		if err != nil {
+			return b, err
+		}
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		if v.definitionLevel < levels.definitionLevel {
+			return append(b, "[]"...), nil
+		}
+
+		b = append(b, '[')
+		n := 0
+		err = jsonSplitRepeated(columns, levels.repetitionDepth, func(values [][]Value) error {
+			if n > 0 {
+				b = append(b, ',')
+			}
+			b, err = encode(b, levels, values)
+			levels.repetitionLevel = levels.repetitionDepth
+			n++
+			return err
+		})
+		return append(b, ']'), err
+	}
+}
+
+func jsonEncodeFuncOfMap(columnIndex int16, node Node, path columnPath) (int16, jsonEncodeFunc) {
+	keyValue := mapKeyValueOf(node)
+	keyValuePath := path.append(keyValue.(Field).Name())
+	fields := keyValue.Fields()
+	funcs := make([]jsonEncodeFunc, len(fields))
+	columnOffsets := make([]int16, len(fields)+1)
+	keyIndex := 0
+	firstColumnIndex := columnIndex
+
+	for i, field := range fields {
+		if field.Name() == "key" {
+			keyIndex = i
+		}
+		columnIndex, funcs[i] = jsonEncodeFuncOf(columnIndex, field, keyValuePath.append(field.Name()))
+		columnOffsets[i+1] = columnIndex - firstColumnIndex
+	}
+
+	return columnIndex, func(b []byte, levels levels, columns [][]Value) ([]byte, error) {
+		v, err := jsonFirstValue(columns, path)
+		if err != nil {
+			return b, err
+		}
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		if v.definitionLevel < levels.definitionLevel {
+			return append(b, "{}"...), nil
+		}
+
+		var key []byte
+		b = append(b, '{')
+		n := 0
+		err = jsonSplitRepeated(columns, levels.repetitionDepth, func(values [][]Value) error {
+			if n > 0 {
+				b = append(b, ',')
+			}
+
+			k := columnOffsets[keyIndex]
+			key, err = funcs[keyIndex](key[:0], levels, values[k:columnOffsets[keyIndex+1]])
+			if err != nil {
+				return err
+			}
+			if len(key) > 0 && key[0] == '"' {
+				b = append(b, key...)
+			} else {
+				b = appendJSONString(b, string(key))
+			}
+			b = append(b, ':')
+
+			for i, f := range funcs {
+				if i != keyIndex {
+					if b, err = f(b, levels, values[columnOffsets[i]:columnOffsets[i+1]]); err != nil {
+						return err
+					}
+				}
+			}
+
+			levels.repetitionLevel = levels.repetitionDepth
+			n++
+			return nil
+		})
+		return append(b, '}'), err
+	}
+}
+
+func jsonEncodeFuncOfGroup(columnIndex int16, node Node, path columnPath) (int16, jsonEncodeFunc) {
+	fields := node.Fields()
+	funcs := make([]jsonEncodeFunc, len(fields))
+	names := make([][]byte, len(fields))
+	columnOffsets := make([]int16, len(fields)+1)
+	firstColumnIndex := columnIndex
+
+	for i, field := range fields {
+		columnIndex, funcs[i] = jsonEncodeFuncOf(columnIndex, field, path.append(field.Name()))
+		columnOffsets[i+1] = columnIndex - firstColumnIndex
+		names[i] = append(appendJSONString(nil, field.Name()), ':')
+	}
+
+	return columnIndex, func(b []byte, levels levels, columns [][]Value) (_ []byte, err error) {
+		b = append(b, '{')
+		for i, f := range funcs {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, names[i]...)
+			if b, err = f(b, levels, columns[columnOffsets[i]:columnOffsets[i+1]]); err != nil {
+				return b, err
+			}
+		}
+		return append(b, '}'), nil
+	}
+}
+
+func jsonEncodeFuncOfLeaf(columnIndex int16, node Node, path columnPath) (int16, jsonEncodeFunc) {
+	if columnIndex > MaxColumnIndex {
+		panic("row cannot be encoded to JSON because it has more than 127 columns")
+	}
+	typ := node.Type()
+	return columnIndex + 1, func(b []byte, levels levels, columns [][]Value) ([]byte, error) {
+		v, err := jsonFirstValue(columns, path)
+		if err != nil {
+			return b, err
+		}
+		if v.IsNull() {
+			return append(b, "null"...), nil
+		}
+		b, err = appendJSONValue(b, v, typ)
+		if err != nil {
+			err = &JSONLinesError{Path: path.String(), Err: err}
+		}
+		return b, err
+	}
+}
+
+func appendJSONValue(b []byte, v Value, t Type) ([]byte, error) {
+	lt := t.LogicalType()
+	kind := v.Kind()
+
+	if _, ok := t.(intervalType); ok {
+		b = append(b, '"')
+		b = v.Interval().appendString(b)
+		return append(b, '"'), nil
+	}
+
+	switch {
+	case lt == nil:
+	case lt.UTF8 != nil, lt.Enum != nil:
+		return appendJSONString(b, v.string()), nil
+	case lt.Json != nil:
+		if !json.Valid(v.byteArray()) {
+			return b, fmt.Errorf("invalid JSON value: %q", v.byteArray())
+		}
+		return append(b, v.byteArray()...), nil
+	case lt.UUID != nil:
+		var u uuid.UUID
+		copy(u[:], v.byteArray())
+		b = append(b, '"')
+		b = append(b, u.String()...)
+		return append(b, '"'), nil
+	case lt.Float16 != nil:
+		return appendJSONFloat(b, float64(v.Float16()), 32), nil
+	case lt.Date != nil:
+		t := unixEpoch.AddDate(0, 0, int(v.int32()))
+		b = append(b, '"')
+		b = t.AppendFormat(b, "2006-01-02")
+		return append(b, '"'), nil
+	case lt.Time != nil:
+		d := jsonInt64(v) * int64(timeUnitDuration(lt.Time.Unit))
+		b = append(b, '"')
+		b = unixEpoch.Add(time.Duration(d)).AppendFormat(b, "15:04:05.999999999")
+		return append(b, '"'), nil
+	case lt.Timestamp != nil:
+		t := timestamp(v, lt.Timestamp.Unit, time.UTC)
+		b = append(b, '"')
+		if lt.Timestamp.IsAdjustedToUTC {
+			b = t.AppendFormat(b, time.RFC3339Nano)
+		} else {
+			b = t.AppendFormat(b, jsonLocalTimestampFormat)
+		}
+		return append(b, '"'), nil
+	case lt.Decimal != nil:
+		b = append(b, '"')
+		b = appendDecimal(b, decimalUnscaledValue(v), int(lt.Decimal.Scale))
+		return append(b, '"'), nil
+	case lt.Integer != nil:
+		if !lt.Integer.IsSigned {
+			return strconv.AppendUint(b, jsonUint64(v), 10), nil
+		}
+	case lt.Unknown != nil:
+		return append(b, "null"...), nil
+	}
+
+	switch kind {
+	case Boolean:
+		return strconv.AppendBool(b, v.boolean()), nil
+	case Int32:
+		return strconv.AppendInt(b, int64(v.int32()), 10), nil
+	case Int64:
+		return strconv.AppendInt(b, v.int64(), 10), nil
+	case Int96:
+		return append(b, v.Int96().String()...), nil
+	case Float:
+		return appendJSONFloat(b, float64(v.float()), 32), nil
+	case Double:
+		return appendJSONFloat(b, v.double(), 64), nil
+	default:
+		b = append(b, '"')
+		n := len(b)
+		b = append(b, make([]byte, base64.StdEncoding.EncodedLen(len(v.byteArray())))...)
+		base64.StdEncoding.Encode(b[n:], v.byteArray())
+		return append(b, '"'), nil
+	}
+}
+
+const jsonLocalTimestampFormat = "2006-01-02T15:04:05.999999999"
+
+func jsonInt64(v Value) int64 {
+	if v.Kind() == Int32 {
+		return int64(v.int32())
+	}
+	return v.int64()
+}
+
+func jsonUint64(v Value) uint64 {
+	if v.Kind() == Int32 {
+		return uint64(v.uint32())
+	}
+	return v.uint64()
+}
+
+func appendJSONFloat(b []byte, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, `"NaN"`...)
+	case math.IsInf(f, +1):
+		return append(b, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(b, `"-Inf"`...)
+	default:
+		return strconv.AppendFloat(b, f, 'g', -1, bitSize)
+	}
+}
+
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"', c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, `�`...)
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
+
+// decimalUnscaledValue returns the unscaled integer value of a decimal stored
+// in v.
+func decimalUnscaledValue(v Value) *big.Int {
+	switch v.Kind() {
+	case Int32:
+		return big.NewInt(int64(v.int32()))
+	case Int64:
+		return big.NewInt(v.int64())
+	default:
+		b := v.byteArray()
+		i := new(big.Int).SetBytes(b)
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+		}
+		return i
+	}
+}
+
+func appendDecimal(b []byte, unscaled *big.Int, scale int) []byte {
+	if unscaled.Sign() < 0 {
+		b = append(b, '-')
+	}
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale <= 0 {
+		return append(b, digits...)
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	n := len(digits) - scale
+	b = append(b, digits[:n]...)
+	b = append(b, '.')
+	return append(b, digits[n:]...)
+}
+
+// parseDecimal parses s as a decimal number and returns its unscaled integer
+// value for the given scale.
+func parseDecimal(s string, scale int) (*big.Int, error) {
+	digits := s
+	negative := false
+	switch {
+	case strings.HasPrefix(digits, "-"):
+		negative, digits = true, digits[1:]
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	}
+
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+	if integer == "" && fraction == "" {
+		return nil, fmt.Errorf("invalid decimal value: %q", s)
+	}
+	if len(fraction) > scale {
+		if strings.TrimRight(fraction[scale:], "0") != "" {
+			return nil, fmt.Errorf("decimal value %q has more than %d digits after the decimal point", s, scale)
+		}
+		fraction = fraction[:scale]
+	}
+
+	i, ok := new(big.Int).SetString(integer+fraction+strings.Repeat("0", scale-len(fraction)), 10)
+	if !ok || strings.ContainsAny(integer+fraction, "+-") {
+		return nil, fmt.Errorf("invalid decimal value: %q", s)
+	}
+	if negative {
+		i.Neg(i)
+	}
+	return i, nil
+}
+
+type jsonDecodeFunc func([][]Value, levels, interface{}) error
+
+func jsonDecodeFuncOf(columnIndex int16, node Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	switch {
+	case node.Optional():
+		return jsonDecodeFuncOfOptional(columnIndex, node, path, maxDefinitionLevel, config)
+	case node.Repeated():
+		return jsonDecodeFuncOfRepeated(columnIndex, Required(node), path, maxDefinitionLevel, config)
+	case isList(node):
+		return jsonDecodeFuncOfRepeated(columnIndex, listElementOf(node), path.append("list", "element"), maxDefinitionLevel, config)
+	case isMap(node):
+		return jsonDecodeFuncOfMap(columnIndex, node, path, maxDefinitionLevel, config)
+	case node.Leaf():
+		return jsonDecodeFuncOfLeaf(columnIndex, node, path, maxDefinitionLevel, config)
+	default:
+		return jsonDecodeFuncOfGroup(columnIndex, node, path, maxDefinitionLevel, config)
+	}
+}
+
+func jsonDecodeFuncOfOptional(columnIndex int16, node Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	nextColumnIndex, decode := jsonDecodeFuncOf(columnIndex, Required(node), path, maxDefinitionLevel+1, config)
+	return nextColumnIndex, func(columns [][]Value, levels levels, value interface{}) error {
+		if value != nil {
+			levels.definitionLevel++
+		}
+		return decode(columns, levels, value)
+	}
+}
+
+func jsonDecodeFuncOfRepeated(columnIndex int16, elem Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	nextColumnIndex, decode := jsonDecodeFuncOf(columnIndex, elem, path, maxDefinitionLevel+1, config)
+	return nextColumnIndex, func(columns [][]Value, levels levels, value interface{}) error {
+		if value == nil {
+			return decode(columns, levels, nil)
+		}
+
+		array, ok := value.([]interface{})
+		if !ok {
+			return jsonLinesErrorf(path, "expected JSON array but found %s", jsonTypeName(value))
+		}
+		if len(array) == 0 {
+			return decode(columns, levels, nil)
+		}
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		for _, elem := range array {
+			if err := decode(columns, levels, elem); err != nil {
+				return err
+			}
+			levels.repetitionLevel = levels.repetitionDepth
+		}
+		return nil
+	}
+}
+
+func jsonDecodeFuncOfMap(columnIndex int16, node Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	keyValue := mapKeyValueOf(node)
+	keyValuePath := path.append(keyValue.(Field).Name())
+	fields := keyValue.Fields()
+	funcs := make([]jsonDecodeFunc, len(fields))
+	columnOffsets := make([]int16, len(fields)+1)
+	keyIndex := 0
+	keyType := Type(nil)
+	firstColumnIndex := columnIndex
+
+	for i, field := range fields {
+		if field.Name() == "key" {
+			keyIndex, keyType = i, field.Type()
+		}
+		columnIndex, funcs[i] = jsonDecodeFuncOf(columnIndex, field, keyValuePath.append(field.Name()), maxDefinitionLevel+1, config)
+		columnOffsets[i+1] = columnIndex - firstColumnIndex
+	}
+
+	return columnIndex, func(columns [][]Value, levels levels, value interface{}) error {
+		object, ok := value.(map[string]interface{})
+		if !ok && value != nil {
+			return jsonLinesErrorf(path, "expected JSON object but found %s", jsonTypeName(value))
+		}
+
+		if len(object) == 0 {
+			for i, f := range funcs {
+				if err := f(columns[columnOffsets[i]:columnOffsets[i+1]], levels, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		keys := make([]string, 0, len(object))
+		for k := range object {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		for _, k := range keys {
+			for i, f := range funcs {
+				var v interface{}
+				if i == keyIndex {
+					v = jsonValueOfString(k, keyType)
+				} else {
+					v = object[k]
+				}
+				if err := f(columns[columnOffsets[i]:columnOffsets[i+1]], levels, v); err != nil {
+					return err
+				}
+			}
+			levels.repetitionLevel = levels.repetitionDepth
+		}
+		return nil
+	}
+}
+
+// jsonValueOfString converts a string, such as a JSON object key, to the JSON
+// value expected when decoding values of type t.
+func jsonValueOfString(s string, t Type) interface{} {
+	if lt := t.LogicalType(); lt != nil && lt.Integer == nil {
+		return s
+	}
+	switch t.Kind() {
+	case Boolean:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case Int32, Int64, Int96, Float, Double:
+		return json.Number(s)
+	}
+	return s
+}
+
+func jsonDecodeFuncOfGroup(columnIndex int16, node Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	fields := node.Fields()
+	funcs := make([]jsonDecodeFunc, len(fields))
+	columnOffsets := make([]int16, len(fields)+1)
+	firstColumnIndex := columnIndex
+
+	for i, field := range fields {
+		columnIndex, funcs[i] = jsonDecodeFuncOf(columnIndex, field, path.append(field.Name()), maxDefinitionLevel, config)
+		columnOffsets[i+1] = columnIndex - firstColumnIndex
+	}
+
+	return columnIndex, func(columns [][]Value, levels levels, value interface{}) error {
+		object, ok := value.(map[string]interface{})
+		if !ok && value != nil {
+			return jsonLinesErrorf(path, "expected JSON object but found %s", jsonTypeName(value))
+		}
+
+		for name := range object {
+			if fieldByName(node, name) == nil {
+				return jsonLinesErrorf(path.append(name), "field does not exist in the schema")
+			}
+		}
+
+		for i, f := range funcs {
+			var v interface{}
+			if object != nil {
+				v = object[fields[i].Name()]
+			}
+			if err := f(columns[columnOffsets[i]:columnOffsets[i+1]], levels, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func jsonDecodeFuncOfLeaf(columnIndex int16, node Node, path columnPath, maxDefinitionLevel byte, config *JSONLinesReaderConfig) (int16, jsonDecodeFunc) {
+	if columnIndex > MaxColumnIndex {
+		panic("row cannot be decoded from JSON because it has more than 127 columns")
+	}
+	typ := node.Type()
+	lt := typ.LogicalType()
+	// Required JSON columns may hold the JSON null value, which cannot be told
+	// apart from a missing field.
+	isJSON := lt != nil && lt.Json != nil
+	isString := lt != nil && (lt.UTF8 != nil || lt.Enum != nil)
+	scalarsAsStrings := isString && config.ScalarsAsStrings
+	valueColumnIndex := ^columnIndex
+	return columnIndex + 1, func(columns [][]Value, levels levels, value interface{}) error {
+		v := Value{}
+
+		if value != nil {
+			if scalarsAsStrings {
+				switch x := value.(type) {
+				case json.Number:
+					value = x.String()
+				case bool:
+					value = strconv.FormatBool(x)
+				}
+			}
+			var err error
+			if v, err = parseJSONValue(value, typ); err != nil {
+				return &JSONLinesError{Path: path.String(), Err: err}
+			}
+		} else if levels.definitionLevel == maxDefinitionLevel {
+			if !isJSON {
+				return jsonLinesErrorf(path, "missing value of required column")
+			}
+			v = makeValueString(typ.Kind(), "null")
+		}
+
+		v.repetitionLevel = levels.repetitionLevel
+		v.definitionLevel = levels.definitionLevel
+		v.columnIndex = valueColumnIndex
+
+		columns[0] = append(columns[0], v)
+		return nil
+	}
+}
+
+func parseJSONValue(value interface{}, t Type) (Value, error) {
+	lt := t.LogicalType()
+
+	if _, ok := t.(intervalType); ok {
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		i, err := ParseCalendarInterval(s)
+		if err != nil {
+			return Value{}, err
+		}
+		return IntervalValue(i), nil
+	}
+
+	switch {
+	case lt == nil:
+	case lt.UTF8 != nil, lt.Enum != nil:
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueString(t.Kind(), s), nil
+	case lt.Json != nil:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueBytes(t.Kind(), b), nil
+	case lt.UUID != nil:
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		u, err := uuid.Parse(s)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueBytes(t.Kind(), u[:]), nil
+	case lt.Float16 != nil:
+		f, err := parseJSONValue(value, FloatType)
+		if err != nil {
+			return Value{}, err
+		}
+		return Float16Value(f.float()), nil
+	case lt.Date != nil:
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueInt32(int32(daysSinceUnixEpoch(d))), nil
+	case lt.Time != nil:
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		d, err := time.Parse("15:04:05", s)
+		if err != nil {
+			return Value{}, err
+		}
+		n := d.Sub(nearestMidnightLessThan(d)) / timeUnitDuration(lt.Time.Unit)
+		if t.Kind() == Int32 {
+			return makeValueInt32(int32(n)), nil
+		}
+		return makeValueInt64(int64(n)), nil
+	case lt.Timestamp != nil:
+		if n, ok := value.(json.Number); ok {
+			i, err := n.Int64()
+			if err != nil {
+				return Value{}, err
+			}
+			return makeValueInt64(i), nil
+		}
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		layout := time.RFC3339Nano
+		if !lt.Timestamp.IsAdjustedToUTC {
+			layout = jsonLocalTimestampFormat
+		}
+		ts, err := time.Parse(layout, s)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueInt64(timestampValue(ts, lt.Timestamp.Unit)), nil
+	case lt.Decimal != nil:
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		default:
+			return Value{}, fmt.Errorf("expected decimal string or number but found %s", jsonTypeName(value))
+		}
+		i, err := parseDecimal(s, int(lt.Decimal.Scale))
+		if err != nil {
+			return Value{}, err
+		}
+		return makeDecimalValue(i, t)
+	case lt.Integer != nil:
+		if !lt.Integer.IsSigned {
+			n, ok := value.(json.Number)
+			if !ok {
+				return Value{}, fmt.Errorf("expected number but found %s", jsonTypeName(value))
+			}
+			bitSize := 64
+			if t.Kind() == Int32 {
+				bitSize = 32
+			}
+			u, err := strconv.ParseUint(n.String(), 10, bitSize)
+			if err != nil {
+				return Value{}, err
+			}
+			if bitSize == 32 {
+				return makeValueUint32(uint32(u)), nil
+			}
+			return makeValueUint64(u), nil
+		}
+	case lt.Unknown != nil:
+		return Value{}, fmt.Errorf("expected null but found %s", jsonTypeName(value))
+	}
+
+	switch t.Kind() {
+	case Boolean:
+		b, ok := value.(bool)
+		if !ok {
+			return Value{}, fmt.Errorf("expected boolean but found %s", jsonTypeName(value))
+		}
+		return makeValueBoolean(b), nil
+	case Int32, Int64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return Value{}, fmt.Errorf("expected number but found %s", jsonTypeName(value))
+		}
+		bitSize := 64
+		if t.Kind() == Int32 {
+			bitSize = 32
+		}
+		i, err := strconv.ParseInt(n.String(), 10, bitSize)
+		if err != nil {
+			return Value{}, err
+		}
+		if bitSize == 32 {
+			return makeValueInt32(int32(i)), nil
+		}
+		return makeValueInt64(i), nil
+	case Int96:
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		default:
+			return Value{}, fmt.Errorf("expected number but found %s", jsonTypeName(value))
+		}
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return Value{}, fmt.Errorf("invalid INT96 value: %q", s)
+		}
+		i96, err := bigIntToInt96(i)
+		if err != nil {
+			return Value{}, err
+		}
+		return makeValueInt96(i96), nil
+	case Float, Double:
+		var f float64
+		var err error
+		bitSize := 64
+		if t.Kind() == Float {
+			bitSize = 32
+		}
+		switch v := value.(type) {
+		case json.Number:
+			f, err = strconv.ParseFloat(v.String(), bitSize)
+		case string:
+			switch v {
+			case "NaN":
+				f = math.NaN()
+			case "+Inf", "Inf", "Infinity":
+				f = math.Inf(+1)
+			case "-Inf", "-Infinity":
+				f = math.Inf(-1)
+			default:
+				err = fmt.Errorf("invalid floating point value: %q", v)
+			}
+		default:
+			err = fmt.Errorf("expected number but found %s", jsonTypeName(value))
+		}
+		if err != nil {
+			return Value{}, err
+		}
+		if bitSize == 32 {
+			return makeValueFloat(float32(f)), nil
+		}
+		return makeValueDouble(f), nil
+	default:
+		s, err := jsonString(value)
+		if err != nil {
+			return Value{}, err
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return Value{}, err
+		}
+		if t.Kind() == FixedLenByteArray && len(b) != t.Length() {
+			return Value{}, fmt.Errorf("expected %d bytes but found %d", t.Length(), len(b))
+		}
+		return makeValueBytes(t.Kind(), b), nil
+	}
+}
+
+func makeDecimalValue(i *big.Int, t Type) (Value, error) {
+	switch t.Kind() {
+	case Int32:
+		if !i.IsInt64() || i.Int64() < math.MinInt32 || i.Int64() > math.MaxInt32 {
+			return Value{}, fmt.Errorf("decimal value %s overflows INT32", i)
+		}
+		return makeValueInt32(int32(i.Int64())), nil
+	case Int64:
+		if !i.IsInt64() {
+			return Value{}, fmt.Errorf("decimal value %s overflows INT64", i)
+		}
+		return makeValueInt64(i.Int64()), nil
+	}
+
+	size := t.Length()
+	if t.Kind() == ByteArray {
+		size = (i.BitLen() + 8) / 8
+	}
+	b, err := bigIntToTwosComplement(i, size)
+	if err != nil {
+		return Value{}, err
+	}
+	return makeValueBytes(t.Kind(), b), nil
+}
+
+// bigIntToTwosComplement returns the big-endian two's complement
+// representation of i using size bytes.
+func bigIntToTwosComplement(i *big.Int, size int) ([]byte, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(8*size-1))
+	if i.Cmp(limit) >= 0 || i.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("value %s does not fit in %d bytes", i, size)
+	}
+	x := i
+	if i.Sign() < 0 {
+		x = new(big.Int).Add(i, new(big.Int).Lsh(limit, 1))
+	}
+	return x.FillBytes(make([]byte, size)), nil
+}
+
+func bigIntToInt96(i *big.Int) (deprecated.Int96, error) {
+	b, err := bigIntToTwosComplement(i, 12)
+	if err != nil {
+		return deprecated.Int96{}, err
+	}
+	return deprecated.Int96{
+		0: uint32(b[8])<<24 | uint32(b[9])<<16 | uint32(b[10])<<8 | uint32(b[11]),
+		1: uint32(b[4])<<24 | uint32(b[5])<<16 | uint32(b[6])<<8 | uint32(b[7]),
+		2: uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]),
+	}, nil
+}
+
+func jsonString(value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string but found %s", jsonTypeName(value))
+	}
+	return s, nil
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,277 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go"
+)
+
+type jsonLinesRecord struct {
+	Name      string            `parquet:"name"`
+	Nickname  *string           `parquet:"nickname,optional"`
+	Age       uint32            `parquet:"age"`
+	Score     float64           `parquet:"score"`
+	Ratio     float32           `parquet:"ratio"`
+	Active    bool              `parquet:"active"`
+	Data      []byte            `parquet:"data"`
+	ID        uuid.UUID         `parquet:"id"`
+	Birthday  int32             `parquet:"birthday,date"`
+	CreatedAt time.Time         `parquet:"created_at"`
+	Price     int64             `parquet:"price,decimal(2:9)"`
+	Tags      []string          `parquet:"tags,list"`
+	Labels    map[string]int32  `parquet:"labels"`
+	Points    []jsonLinesPoint  `parquet:"points"`
+	Attrs     map[int64]string  `parquet:"attrs,optional"`
+	Extra     string            `parquet:"extra,json"`
+	Children  map[string]string `parquet:"-"`
+}
+
+type jsonLinesPoint struct {
+	X int64  `parquet:"x"`
+	Y *int64 `parquet:"y,optional"`
+}
+
+func TestJSONLinesWriterReader(t *testing.T) {
+	nickname := "Bobby"
+	y := int64(-2)
+
+	records := []jsonLinesRecord{
+		{
+			Name:      "Bob",
+			Nickname:  &nickname,
+			Age:       42,
+			Score:     math.Inf(+1),
+			Ratio:     0.5,
+			Active:    true,
+			Data:      []byte("hello"),
+			ID:        uuid.UUID{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+			Birthday:  19000,
+			CreatedAt: time.Date(2022, time.March, 4, 5, 6, 7, 8000000, time.UTC),
+			Price:     -1234,
+			Tags:      []string{"a", "b\"c"},
+			Labels:    map[string]int32{"x": 1, "y": 2},
+			Points:    []jsonLinesPoint{{X: 1}, {X: 2, Y: &y}},
+			Attrs:     map[int64]string{10: "ten"},
+			Extra:     `{"k":[1,2]}`,
+		},
+		{
+			Name:      "Alice",
+			Data:      []byte{},
+			Score:     1.5,
+			CreatedAt: time.Unix(0, 0).UTC(),
+			Price:     5,
+			Extra:     `null`,
+		},
+	}
+
+	const expected = `{"name":"Bob","nickname":"Bobby","age":42,"score":"+Inf","ratio":0.5,"active":true,"data":"aGVsbG8=","id":"12345678-9abc-def0-1234-56789abcdef0","birthday":"2022-01-08","created_at":"2022-03-04T05:06:07.008Z","price":"-12.34","tags":["a","b\"c"],"labels":{"x":1,"y":2},"points":[{"x":1,"y":null},{"x":2,"y":-2}],"attrs":{"10":"ten"},"extra":{"k":[1,2]}}
+{"name":"Alice","nickname":null,"age":0,"score":1.5,"ratio":0,"active":false,"data":"","id":"00000000-0000-0000-0000-000000000000","birthday":"1970-01-01","created_at":"1970-01-01T00:00:00Z","price":"0.05","tags":[],"labels":{},"points":[],"attrs":null,"extra":null}
+`
+
+	schema := parquet.SchemaOf(new(jsonLinesRecord))
+	rows := make([]parquet.Row, len(records))
+	for i := range records {
+		rows[i] = schema.Deconstruct(nil, &records[i])
+	}
+
+	buf := new(bytes.Buffer)
+	w := parquet.NewJSONLinesWriter(buf, schema)
+	if n, err := w.WriteRows(rows); err != nil {
+		t.Fatal(err)
+	} else if n != len(rows) {
+		t.Fatalf("wrong number of rows written: want=%d got=%d", len(rows), n)
+	}
+
+	// The entries of maps are written in the order of the rows, which is the
+	// random iteration order of the Go maps they were deconstructed from, so
+	// the lines are compared as decoded JSON values.
+	wantLines := strings.SplitAfter(expected, "\n")
+	gotLines := strings.SplitAfter(buf.String(), "\n")
+	if len(gotLines) != len(wantLines) {
+		t.Fatalf("wrong JSON output:\nwant: %s\ngot:  %s", expected, buf.String())
+	}
+	for i := range wantLines {
+		if !jsonLinesEqual(wantLines[i], gotLines[i]) {
+			t.Fatalf("wrong JSON output:\nwant: %s\ngot:  %s", wantLines[i], gotLines[i])
+		}
+	}
+
+	r := parquet.NewJSONLinesReader(strings.NewReader(expected), schema)
+	read := make([]parquet.Row, len(rows)+1)
+	n, err := r.ReadRows(read)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+	if n != len(rows) {
+		t.Fatalf("wrong number of rows read: want=%d got=%d", len(rows), n)
+	}
+
+	// Rows are compared after being reconstructed since the order of map
+	// entries may differ.
+	for i := range rows {
+		var want, got jsonLinesRecord
+		if err := schema.Reconstruct(&want, rows[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := schema.Reconstruct(&got, read[i]); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, want, got)
+		}
+	}
+}
+
+func jsonLinesEqual(line1, line2 string) bool {
+	if strings.HasSuffix(line1, "\n") != strings.HasSuffix(line2, "\n") {
+		return false
+	}
+	if strings.TrimSpace(line1) == "" || strings.TrimSpace(line2) == "" {
+		return line1 == line2
+	}
+	var v1, v2 interface{}
+	if json.Unmarshal([]byte(line1), &v1) != nil || json.Unmarshal([]byte(line2), &v2) != nil {
+		return false
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+func TestJSONLinesReaderErrors(t *testing.T) {
+	type point struct {
+		X int32            `parquet:"x"`
+		Y []int32          `parquet:"y"`
+		Z map[string]int32 `parquet:"z"`
+	}
+
+	schema := parquet.SchemaOf(new(struct {
+		Name   string  `parquet:"name"`
+		Points []point `parquet:"points"`
+	}))
+
+	tests := []struct {
+		scenario string
+		input    string
+		line     int
+		path     string
+	}{
+		{
+			scenario: "syntax error",
+			input:    `{"name":"a","points":[]}` + "\n" + `{"name":`,
+			line:     2,
+		},
+		{
+			scenario: "missing required field",
+			input:    `{"points":[]}`,
+			line:     1,
+			path:     "name",
+		},
+		{
+			scenario: "wrong type of nested field",
+			input:    `{"name":"a"}` + "\n\n" + `{"name":"b","points":[{"x":1,"y":[1,"2"]}]}`,
+			line:     3,
+			path:     "points.y",
+		},
+		{
+			scenario: "number as string",
+			input:    `{"name":1,"points":[]}`,
+			line:     1,
+			path:     "name",
+		},
+		{
+			scenario: "integer overflow",
+			input:    `{"name":"a","points":[{"x":3000000000}]}`,
+			line:     1,
+			path:     "points.x",
+		},
+		{
+			scenario: "map value of wrong type",
+			input:    `{"name":"a","points":[{"x":1,"z":{"k":true}}]}`,
+			line:     1,
+			path:     "points.z.key_value.value",
+		},
+		{
+			scenario: "unknown field",
+			input:    `{"name":"a","points":[{"x":1,"w":0}]}`,
+			line:     1,
+			path:     "points.w",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			r := parquet.NewJSONLinesReader(strings.NewReader(test.input), schema)
+			_, err := r.ReadRows(make([]parquet.Row, 10))
+
+			var e *parquet.JSONLinesError
+			if !errors.As(err, &e) {
+				t.Fatalf("expected *parquet.JSONLinesError but got %T: %v", err, err)
+			}
+			if e.Line != test.line {
+				t.Errorf("wrong line number: want=%d got=%d (%v)", test.line, e.Line, err)
+			}
+			if e.Path != test.path {
+				t.Errorf("wrong column path: want=%q got=%q (%v)", test.path, e.Path, err)
+			}
+		})
+	}
+}
+
+func TestJSONLinesScalarsAsStrings(t *testing.T) {
+	schema := parquet.SchemaOf(new(struct {
+		Value string `parquet:"value"`
+	}))
+
+	const input = `{"value":"a"}
+{"value":1.5}
+{"value":true}
+`
+	r := parquet.NewJSONLinesReader(strings.NewReader(input), schema, parquet.ScalarsAsStrings(true))
+	rows := make([]parquet.Row, 4)
+	n, err := r.ReadRows(rows)
+	if n != 3 {
+		t.Fatalf("wrong number of rows read: want=3 got=%d (%v)", n, err)
+	}
+
+	for i, want := range []string{"a", "1.5", "true"} {
+		if got := rows[i][0].String(); got != want {
+			t.Errorf("wrong value at row %d: want=%q got=%q", i, want, got)
+		}
+	}
+}
+
+func TestJSONLinesOptionalListElements(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"values": parquet.List(parquet.Optional(parquet.Int(32))),
+	})
+
+	const input = `{"values":[1,null,3]}
+{"values":[null]}
+{"values":[]}
+`
+
+	rows := make([]parquet.Row, 4)
+	n, err := parquet.NewJSONLinesReader(strings.NewReader(input), schema).ReadRows(rows)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("wrong number of rows read: want=3 got=%d", n)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := parquet.NewJSONLinesWriter(buf, schema).WriteRows(rows[:n]); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != input {
+		t.Errorf("wrong JSON output:\nwant: %s\ngot:  %s", input, s)
+	}
+}
@@ -0,0 +1,234 @@
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InferJSONSchema reads a stream of JSON objects from r and returns a schema
+// able to represent all of them. The objects may be separated by white spaces,
+// which includes the JSON Lines format.
+//
+// Each key of the objects is mapped to a column of the schema, the type of the
+// column is determined by the values seen for the key in all the samples:
+//
+//	true, false          boolean
+//	integers             INT(64,true)
+//	numbers              DOUBLE (integers are widened to DOUBLE)
+//	RFC 3339 strings     TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)
+//	UUID strings         UUID
+//	strings              STRING
+//	arrays               LIST of the type of their elements
+//	objects              group of the keys of the objects
+//	null                 JSON (when no other type was seen)
+//
+// Scalar values of different types are widened to STRING, while values which
+// mix objects, arrays, and scalars are represented as JSON columns. Keys that
+// are missing from some of the samples, or which have null values, produce
+// optional columns.
+//
+// When the HeterogeneousObjectsAsJSON option is enabled, nested objects which
+// do not have the same set of keys in all the samples are represented as JSON
+// columns instead of groups.
+//
+// The returned schema uses the same representation of values as the
+// JSONLinesReader, which can be used to read the documents as parquet rows.
+// The ScalarsAsStrings option must be passed to the reader when the documents
+// have scalar values of different types for the same key.
+func InferJSONSchema(r io.Reader, options ...JSONSchemaOption) (*Schema, error) {
+	config := DefaultJSONSchemaConfig()
+	config.Apply(options...)
+
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	root := &jsonShape{}
+
+	for n := 1; ; n++ {
+		var value interface{}
+		if err := d.Decode(&value); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("inferring schema of JSON document %d: %w", n, err)
+		}
+		if _, ok := value.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("inferring schema of JSON document %d: expected JSON object but found %s", n, jsonTypeName(value))
+		}
+		root.observe(value)
+	}
+
+	if root.kind != jsonShapeObject {
+		return nil, fmt.Errorf("inferring schema of JSON documents: no documents found")
+	}
+
+	group := make(Group, len(root.fields))
+	for name, field := range root.fields {
+		group[name] = field.node(config)
+	}
+	return NewSchema("json", group), nil
+}
+
+type jsonShapeKind int
+
+const (
+	jsonShapeNull jsonShapeKind = iota
+	jsonShapeBoolean
+	jsonShapeInt
+	jsonShapeDouble
+	jsonShapeTimestamp
+	jsonShapeUUID
+	jsonShapeString
+	jsonShapeArray
+	jsonShapeObject
+	jsonShapeAny
+)
+
+// jsonShape accumulates the types of values seen at a location of JSON
+// documents.
+type jsonShape struct {
+	kind     jsonShapeKind
+	optional bool
+	// Fields of objects; heterogeneous is true if the objects did not all
+	// have the same set of keys.
+	fields        map[string]*jsonShape
+	numObjects    int
+	heterogeneous bool
+	// Elements of arrays, nil if only empty arrays were seen.
+	elem *jsonShape
+}
+
+func (s *jsonShape) observe(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		s.optional = true
+	case bool:
+		s.merge(jsonShapeBoolean)
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			s.merge(jsonShapeInt)
+		} else {
+			s.merge(jsonShapeDouble)
+		}
+	case string:
+		s.merge(jsonStringShapeKind(v))
+	case []interface{}:
+		if s.merge(jsonShapeArray) {
+			for _, elem := range v {
+				if s.elem == nil {
+					s.elem = &jsonShape{}
+				}
+				s.elem.observe(elem)
+			}
+		}
+	case map[string]interface{}:
+		if s.merge(jsonShapeObject) {
+			s.observeObject(v)
+		}
+	}
+}
+
+func (s *jsonShape) observeObject(object map[string]interface{}) {
+	if s.fields == nil {
+		s.fields = make(map[string]*jsonShape, len(object))
+	}
+
+	for name, value := range object {
+		field := s.fields[name]
+		if field == nil {
+			field = &jsonShape{}
+			s.fields[name] = field
+			// The key was missing from the objects seen before.
+			if s.numObjects > 0 {
+				field.optional = true
+				s.heterogeneous = true
+			}
+		}
+		field.observe(value)
+	}
+
+	for name, field := range s.fields {
+		if _, ok := object[name]; !ok {
+			field.optional = true
+			s.heterogeneous = true
+		}
+	}
+
+	s.numObjects++
+}
+
+// merge widens the kind of s to represent values of the given kind, returning
+// true if values of this kind must be further observed (arrays and objects).
+func (s *jsonShape) merge(kind jsonShapeKind) bool {
+	switch {
+	case s.kind == kind:
+	case s.kind == jsonShapeNull:
+		s.kind = kind
+	case s.kind == jsonShapeAny:
+	case kind >= jsonShapeArray || s.kind >= jsonShapeArray:
+		s.kind, s.fields, s.elem = jsonShapeAny, nil, nil
+	case (s.kind == jsonShapeInt && kind == jsonShapeDouble) || (s.kind == jsonShapeDouble && kind == jsonShapeInt):
+		s.kind = jsonShapeDouble
+	default:
+		s.kind = jsonShapeString
+	}
+	return s.kind == kind
+}
+
+func (s *jsonShape) node(config *JSONSchemaConfig) Node {
+	var node Node
+
+	switch s.kind {
+	case jsonShapeBoolean:
+		node = Leaf(BooleanType)
+	case jsonShapeInt:
+		node = Int(64)
+	case jsonShapeDouble:
+		node = Leaf(DoubleType)
+	case jsonShapeTimestamp:
+		node = Timestamp(Microsecond)
+	case jsonShapeUUID:
+		node = UUID()
+	case jsonShapeString:
+		node = String()
+	case jsonShapeArray:
+		if s.elem == nil {
+			node = List(Optional(JSON()))
+		} else {
+			node = List(s.elem.node(config))
+		}
+	case jsonShapeObject:
+		if len(s.fields) == 0 || (s.heterogeneous && config.HeterogeneousObjectsAsJSON) {
+			node = JSON()
+		} else {
+			group := make(Group, len(s.fields))
+			for name, field := range s.fields {
+				group[name] = field.node(config)
+			}
+			node = group
+		}
+	default:
+		node = JSON()
+	}
+
+	if s.optional {
+		node = Optional(node)
+	}
+	return node
+}
+
+func jsonStringShapeKind(s string) jsonShapeKind {
+	if len(s) == 36 {
+		if _, err := uuid.Parse(s); err == nil {
+			return jsonShapeUUID
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return jsonShapeTimestamp
+	}
+	return jsonShapeString
+}
@@ -0,0 +1,158 @@
+package parquet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestInferJSONSchema(t *testing.T) {
+	const input = `{"id":1,"name":"a","score":1,"at":"2022-03-04T05:06:07Z","ref":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","tags":["x"],"user":{"id":1,"email":"a@b.c"},"attrs":{"a":1},"mixed":1,"nested":[[1,2]]}
+{"id":2,"name":"b","score":2.5,"at":"2022-03-04T05:06:07.123Z","ref":"6ba7b811-9dad-11d1-80b4-00c04fd430c8","tags":[],"user":{"id":2},"attrs":{"b":"2"},"mixed":true,"extra":null,"nested":[]}
+{"id":3,"score":null,"at":"2022-03-04T05:06:07Z","ref":"not a uuid","tags":["y",null],"user":{"id":3,"email":"c@d.e"},"attrs":{"c":[]},"mixed":"1"}
+`
+
+	tests := []struct {
+		scenario string
+		options  []parquet.JSONSchemaOption
+		print    string
+	}{
+		{
+			scenario: "default",
+			print: `message json {
+	required int64 at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required group attrs {
+		optional int64 a (INT(64,true));
+		optional binary b (STRING);
+		optional group c (LIST) {
+			repeated group list {
+				optional binary element (JSON);
+			}
+		}
+	}
+	optional binary extra (JSON);
+	required int64 id (INT(64,true));
+	required binary mixed (STRING);
+	optional binary name (STRING);
+	optional group nested (LIST) {
+		repeated group list {
+			required group element (LIST) {
+				repeated group list {
+					required int64 element (INT(64,true));
+				}
+			}
+		}
+	}
+	required binary ref (STRING);
+	optional double score;
+	required group tags (LIST) {
+		repeated group list {
+			optional binary element (STRING);
+		}
+	}
+	required group user {
+		optional binary email (STRING);
+		required int64 id (INT(64,true));
+	}
+}`,
+		},
+
+		{
+			scenario: "heterogeneous objects as json",
+			options:  []parquet.JSONSchemaOption{parquet.HeterogeneousObjectsAsJSON(true)},
+			print: `message json {
+	required int64 at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required binary attrs (JSON);
+	optional binary extra (JSON);
+	required int64 id (INT(64,true));
+	required binary mixed (STRING);
+	optional binary name (STRING);
+	optional group nested (LIST) {
+		repeated group list {
+			required group element (LIST) {
+				repeated group list {
+					required int64 element (INT(64,true));
+				}
+			}
+		}
+	}
+	required binary ref (STRING);
+	optional double score;
+	required group tags (LIST) {
+		repeated group list {
+			optional binary element (STRING);
+		}
+	}
+	required binary user (JSON);
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			schema, err := parquet.InferJSONSchema(strings.NewReader(input), test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := schema.String(); s != test.print {
+				t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", test.print, s)
+			}
+
+			r := parquet.NewJSONLinesReader(strings.NewReader(input), schema, parquet.ScalarsAsStrings(true))
+			rows := make([]parquet.Row, 4)
+			if n, _ := r.ReadRows(rows); n != 3 {
+				_, err := r.ReadRows(rows)
+				t.Fatalf("wrong number of rows read: want=3 got=%d (%v)", n, err)
+			}
+		})
+	}
+}
+
+func TestInferJSONSchemaConfigOverridesOptions(t *testing.T) {
+	const input = `{"a":{"x":1}}
+{"a":{"y":2}}
+`
+	schema1, err := parquet.InferJSONSchema(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema2, err := parquet.InferJSONSchema(strings.NewReader(input),
+		parquet.HeterogeneousObjectsAsJSON(true),
+		&parquet.JSONSchemaConfig{HeterogeneousObjectsAsJSON: false},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s1, s2 := schema1.String(), schema2.String(); s1 != s2 {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", s1, s2)
+	}
+}
+
+func TestInferJSONSchemaUUIDAndTimestamp(t *testing.T) {
+	schema, err := parquet.InferJSONSchema(strings.NewReader(`
+		{"ref":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","at":"2022-03-04T05:06:07Z","n":1}
+		{"ref":"6ba7b811-9dad-11d1-80b4-00c04fd430c8","at":"2022-03-05T05:06:07.5+02:00","n":2}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `message json {
+	required int64 at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required int64 n (INT(64,true));
+	required fixed_len_byte_array(16) ref (UUID);
+}`
+
+	if s := schema.String(); s != expected {
+		t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+func TestInferJSONSchemaErrors(t *testing.T) {
+	for _, input := range []string{``, `[1,2]`, `{"a":1} {"a":`} {
+		if _, err := parquet.InferJSONSchema(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error inferring schema of %q", input)
+		}
+	}
+}
@@ -62,3 +62,10 @@ func checkIndexRange(typ string, i, min, max int) {
 func errIndexOutOfRange(typ string, i, min, max int) error {
 	return fmt.Errorf("%s out of range: %d not in [%d:%d]", typ, i, min, max)
 }
+
+func checkLimit(limit string, max, value int64) error {
+	if max > 0 && value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+	return nil
+}
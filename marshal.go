@@ -0,0 +1,288 @@
+package parquet
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// ParquetSchemaer is implemented by Go types which declare the parquet schema
+// that values of the type are represented with.
+//
+// When SchemaOf encounters a type implementing this interface, it calls the
+// ParquetSchema method on the zero-value of the type instead of inferring the
+// parquet node from the type definition. The values of types implementing
+// ParquetSchemaer must be converted from and to parquet rows by implementing
+// ParquetMarshaler and ParquetUnmarshaler.
+type ParquetSchemaer interface {
+	ParquetSchema() Node
+}
+
+// ParquetMarshaler is implemented by Go types which convert their values to
+// parquet values.
+//
+// The MarshalParquet method appends the values of the leaf columns of the node
+// returned by ParquetSchema to row and returns the extended row. The column
+// index and levels of values are relative to the node: column indexes start at
+// zero for the first leaf column of the node, and the repetition and
+// definition levels only account for the repeated and optional nodes within
+// it. The column index and levels may be left unset when the node is a
+// required leaf.
+type ParquetMarshaler interface {
+	MarshalParquet(row Row) (Row, error)
+}
+
+// ParquetUnmarshaler is implemented by Go types which set their values from
+// parquet values.
+//
+// The UnmarshalParquet method receives the values of the leaf columns of the
+// node returned by ParquetSchema, with column indexes and levels relative to
+// the node, as described in ParquetMarshaler. The row may be retained by the
+// method only until it returns.
+type ParquetUnmarshaler interface {
+	UnmarshalParquet(row Row) error
+}
+
+var (
+	parquetSchemaerType    = reflect.TypeOf((*ParquetSchemaer)(nil)).Elem()
+	parquetMarshalerType   = reflect.TypeOf((*ParquetMarshaler)(nil)).Elem()
+	parquetUnmarshalerType = reflect.TypeOf((*ParquetUnmarshaler)(nil)).Elem()
+	textMarshalerType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	binaryMarshalerType    = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+)
+
+type marshalKind int
+
+const (
+	marshalNone marshalKind = iota
+	marshalParquet
+	marshalText
+	marshalBinary
+)
+
+// marshalKindOf returns how values of t are converted to parquet values; the
+// method sets of both t and *t are considered so types with pointer receivers
+// are supported.
+//
+// ParquetSchemaer takes precedence over the native mapping of t, while the
+// encoding.TextMarshaler and encoding.BinaryMarshaler fallbacks only apply to
+// types which have no native mapping.
+func marshalKindOf(t reflect.Type) marshalKind {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return marshalNone
+	}
+	p := reflect.PtrTo(t)
+	switch {
+	case p.Implements(parquetSchemaerType):
+		return marshalParquet
+	case hasNativeNode(t):
+		return marshalNone
+	case p.Implements(textMarshalerType):
+		return marshalText
+	case p.Implements(binaryMarshalerType):
+		return marshalBinary
+	default:
+		return marshalNone
+	}
+}
+
+// hasNativeNode returns true if nodeOf can infer a parquet node from the
+// definition of t. Structs need at least one exported field, a struct with
+// only unexported fields would be represented by an empty group.
+func hasNativeNode(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String, reflect.Slice, reflect.Map:
+		return true
+	case reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Struct:
+		return len(structFieldsOf(t)) > 0
+	default:
+		return false
+	}
+}
+
+// marshalNode is the node of Go types which control their parquet
+// representation.
+type marshalNode struct {
+	Node
+	gotype reflect.Type
+	kind   marshalKind
+}
+
+func marshalNodeOf(t reflect.Type) Node {
+	var node Node
+	kind := marshalKindOf(t)
+	switch kind {
+	case marshalParquet:
+		p := reflect.PtrTo(t)
+		if !p.Implements(parquetMarshalerType) || !p.Implements(parquetUnmarshalerType) {
+			panic("go type " + t.String() + " implements parquet.ParquetSchemaer but not parquet.ParquetMarshaler and parquet.ParquetUnmarshaler")
+		}
+		node = reflect.New(t).Interface().(ParquetSchemaer).ParquetSchema()
+		if node == nil {
+			panic("go type " + t.String() + " returned a nil parquet schema")
+		}
+	case marshalText:
+		node = String()
+	case marshalBinary:
+		node = Leaf(ByteArrayType)
+	default:
+		return nil
+	}
+	return &marshalNode{Node: node, gotype: t, kind: kind}
+}
+
+func (n *marshalNode) GoType() reflect.Type { return n.gotype }
+
+func (n *marshalNode) unwrap() Node { return n.Node }
+
+// unwrapMarshalNode returns the marshalNode that node wraps, looking through
+// the fields, repetition, encoding, compression, and field ID wrappers which
+// may have been applied by nodeOf and struct tags, or nil if there are none.
+func unwrapMarshalNode(node Node) *marshalNode {
+	for node != nil {
+		if n, ok := node.(*marshalNode); ok {
+			return n
+		}
+		node = unwrapNode(node)
+	}
+	return nil
+}
+
+// addressOf returns a pointer to the value held in v, copying the value if it
+// is not addressable.
+func addressOf(v reflect.Value) interface{} {
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
+func (n *marshalNode) marshal(row Row, v reflect.Value) (Row, error) {
+	var b []byte
+	var err error
+
+	switch m := addressOf(v); n.kind {
+	case marshalParquet:
+		return m.(ParquetMarshaler).MarshalParquet(row)
+	case marshalText:
+		b, err = m.(encoding.TextMarshaler).MarshalText()
+	case marshalBinary:
+		b, err = m.(encoding.BinaryMarshaler).MarshalBinary()
+	default:
+		err = fmt.Errorf("cannot marshal go value of type %s to parquet", n.gotype)
+	}
+
+	if err != nil {
+		return row, err
+	}
+	return append(row, makeValueBytes(ByteArray, b)), nil
+}
+
+func (n *marshalNode) unmarshal(v reflect.Value, row Row) error {
+	switch n.kind {
+	case marshalParquet:
+		return v.Addr().Interface().(ParquetUnmarshaler).UnmarshalParquet(row)
+	case marshalText:
+		u, ok := v.Addr().Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return fmt.Errorf("cannot unmarshal parquet value to go value of type %s which does not implement encoding.TextUnmarshaler", n.gotype)
+		}
+		return u.UnmarshalText(row[0].byteArray())
+	case marshalBinary:
+		u, ok := v.Addr().Interface().(encoding.BinaryUnmarshaler)
+		if !ok {
+			return fmt.Errorf("cannot unmarshal parquet value to go value of type %s which does not implement encoding.BinaryUnmarshaler", n.gotype)
+		}
+		return u.UnmarshalBinary(row[0].byteArray())
+	}
+	return fmt.Errorf("cannot unmarshal parquet value to go value of type %s", n.gotype)
+}
+
+// rebaseMarshaledValue converts the levels and column index of a value
+// produced by a ParquetMarshaler, which are relative to the marshaled node, to
+// their values in the parent schema.
+func rebaseMarshaledValue(v *Value, columnIndex, numColumns int16, repetitionDepth, repetitionLevel, definitionLevel byte) error {
+	column := int16(v.Column())
+	if column < 0 {
+		column = 0
+	}
+	if column >= numColumns {
+		return fmt.Errorf("marshaled parquet value has column index %d but the node has %d leaf columns", column, numColumns)
+	}
+	if v.repetitionLevel == 0 {
+		v.repetitionLevel = repetitionLevel
+	} else {
+		v.repetitionLevel += repetitionDepth
+	}
+	v.definitionLevel += definitionLevel
+	v.columnIndex = ^(columnIndex + column)
+	return nil
+}
+
+// relativeMarshaledValue is the reverse of rebaseMarshaledValue, it converts
+// the levels and column index of a value read from the parent schema to their
+// values relative to the marshaled node.
+func relativeMarshaledValue(v Value, column int16, repetitionDepth, definitionLevel byte) Value {
+	if v.repetitionLevel > repetitionDepth {
+		v.repetitionLevel -= repetitionDepth
+	} else {
+		v.repetitionLevel = 0
+	}
+	if v.definitionLevel > definitionLevel {
+		v.definitionLevel -= definitionLevel
+	} else {
+		v.definitionLevel = 0
+	}
+	v.columnIndex = ^column
+	return v
+}
+
+//go:noinline
+func deconstructFuncOfMarshaler(columnIndex int16, node *marshalNode) (int16, deconstructFunc) {
+	nextColumnIndex, deconstructNull := deconstructFuncOfRequired(columnIndex, node.Node)
+	numColumns := nextColumnIndex - columnIndex
+	return nextColumnIndex, func(columns [][]Value, levels levels, value reflect.Value) error {
+		if !value.IsValid() {
+			return deconstructNull(columns, levels, value)
+		}
+
+		row, err := node.marshal(nil, value)
+		if err != nil {
+			return fmt.Errorf("marshaling go value of type %s to parquet: %w", node.gotype, err)
+		}
+
+		for _, v := range row {
+			if err := rebaseMarshaledValue(&v, columnIndex, numColumns, levels.repetitionDepth, levels.repetitionLevel, levels.definitionLevel); err != nil {
+				return err
+			}
+			c := ^v.columnIndex
+			columns[c] = append(columns[c], v)
+		}
+		return nil
+	}
+}
+
+//go:noinline
+func reconstructFuncOfMarshaler(columnIndex int16, node *marshalNode) (int16, reconstructFunc) {
+	nextColumnIndex := columnIndex + numLeafColumnsOf(node.Node)
+	return nextColumnIndex, func(value reflect.Value, levels levels, columns [][]Value) error {
+		row := make(Row, 0, len(columns))
+		for i, column := range columns {
+			for _, v := range column {
+				row = append(row, relativeMarshaledValue(v, int16(i), levels.repetitionDepth, levels.definitionLevel))
+			}
+		}
+		if len(row) == 0 {
+			return fmt.Errorf("no values found in parquet row for column %d", columnIndex)
+		}
+		return node.unmarshal(value, row)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestGenericMarshalHooks(t *testing.T) {
+	rows := marshalRows()
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[marshalRow](buffer)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parquet.Read[marshalRow](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("rows mismatch\nwant: %+v\ngot:  %+v", rows, got)
+	}
+
+	if _, err := writer.Write([]marshalRow{{}}); err == nil {
+		t.Error("expected an error when marshaling an invalid value")
+	}
+}
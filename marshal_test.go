@@ -0,0 +1,227 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type money struct {
+	cents    int64
+	currency string
+}
+
+func (m *money) ParquetSchema() parquet.Node {
+	return parquet.Group{
+		"amount":   parquet.Decimal(2, 18, parquet.Int64Type),
+		"currency": parquet.String(),
+	}
+}
+
+var errNoCurrency = errors.New("money value has no currency")
+
+func (m *money) MarshalParquet(row parquet.Row) (parquet.Row, error) {
+	if m.currency == "" {
+		return row, errNoCurrency
+	}
+	return append(row,
+		parquet.Int64Value(m.cents).Level(0, 0, 0),
+		parquet.ByteArrayValue([]byte(m.currency)).Level(0, 0, 1),
+	), nil
+}
+
+func (m *money) UnmarshalParquet(row parquet.Row) error {
+	for _, v := range row {
+		switch v.Column() {
+		case 0:
+			m.cents = v.Int64()
+		case 1:
+			m.currency = string(v.ByteArray())
+		}
+	}
+	return nil
+}
+
+// point is serialized in a binary format and has no parquet-specific methods.
+type point struct{ x, y int32 }
+
+func (p point) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint32(b[0:], uint32(p.x))
+	binary.LittleEndian.PutUint32(b[4:], uint32(p.y))
+	return b, nil
+}
+
+func (p *point) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("invalid point length: %d", len(b))
+	}
+	p.x = int32(binary.LittleEndian.Uint32(b[0:]))
+	p.y = int32(binary.LittleEndian.Uint32(b[4:]))
+	return nil
+}
+
+type marshalRow struct {
+	Price    money          `parquet:"price"`
+	Discount *money         `parquet:"discount"`
+	History  []money        `parquet:"history"`
+	Network  netip.Prefix   `parquet:"network"`
+	Networks []netip.Prefix `parquet:"networks"`
+	Location point          `parquet:"location"`
+}
+
+func TestMarshalHooksSchema(t *testing.T) {
+	const expected = `message marshalRow {
+	required group price {
+		required int64 amount (DECIMAL(18,2));
+		required binary currency (STRING);
+	}
+	optional group discount {
+		required int64 amount (DECIMAL(18,2));
+		required binary currency (STRING);
+	}
+	repeated group history {
+		required int64 amount (DECIMAL(18,2));
+		required binary currency (STRING);
+	}
+	required binary network (STRING);
+	repeated binary networks (STRING);
+	required binary location;
+}`
+
+	if s := parquet.SchemaOf(new(marshalRow)).String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+// severity implements encoding.TextMarshaler but has a native mapping to an
+// INT32 column, which takes precedence.
+type severity int32
+
+func (s severity) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("severity-%d", int32(s))), nil
+}
+
+// coordinates implements encoding.BinaryMarshaler but has exported fields,
+// which are mapped to a group.
+type coordinates struct {
+	Lat float64 `parquet:"lat"`
+	Lng float64 `parquet:"lng"`
+}
+
+func (c coordinates) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("coordinates are not marshaled to binary")
+}
+
+func TestMarshalHooksNativeMappingPrecedence(t *testing.T) {
+	type Row struct {
+		Severity severity    `parquet:"severity"`
+		Position coordinates `parquet:"position"`
+	}
+
+	const expected = `message Row {
+	required int32 severity (INT(32,true));
+	required group position {
+		required double lat;
+		required double lng;
+	}
+}`
+
+	schema := parquet.SchemaOf(new(Row))
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	row := Row{Severity: 3, Position: coordinates{Lat: 48.85, Lng: 2.35}}
+	got := Row{}
+	if err := schema.Reconstruct(&got, schema.Deconstruct(nil, &row)); err != nil {
+		t.Fatal(err)
+	}
+	if got != row {
+		t.Errorf("reconstructed value mismatch\nwant: %+v\ngot:  %+v", row, got)
+	}
+}
+
+func marshalRows() []marshalRow {
+	return []marshalRow{
+		{
+			Price:    money{cents: 1999, currency: "USD"},
+			Discount: &money{cents: 500, currency: "USD"},
+			History:  []money{{cents: 2499, currency: "USD"}, {cents: 2199, currency: "EUR"}},
+			Network:  netip.MustParsePrefix("10.0.0.0/8"),
+			Networks: []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16"), netip.MustParsePrefix("fd00::/8")},
+			Location: point{x: 1, y: -2},
+		},
+		{
+			Price:    money{cents: 42, currency: "JPY"},
+			History:  []money{},
+			Network:  netip.MustParsePrefix("::/0"),
+			Networks: []netip.Prefix{},
+		},
+	}
+}
+
+func TestMarshalHooks(t *testing.T) {
+	rows := marshalRows()
+	schema := parquet.SchemaOf(new(marshalRow))
+
+	for i := range rows {
+		row := schema.Deconstruct(nil, &rows[i])
+		got := marshalRow{}
+		if err := schema.Reconstruct(&got, row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows[i]) {
+			t.Errorf("row %d: reconstructed value mismatch\nwant: %+v\ngot:  %+v", i, rows[i], got)
+		}
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range rows {
+		got := marshalRow{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows[i]) {
+			t.Errorf("row %d: read value mismatch\nwant: %+v\ngot:  %+v", i, rows[i], got)
+		}
+	}
+	if err := reader.Read(new(marshalRow)); err != io.EOF {
+		t.Errorf("expected io.EOF but got %v", err)
+	}
+}
+
+func TestMarshalHooksError(t *testing.T) {
+	schema := parquet.SchemaOf(new(marshalRow))
+
+	writer := parquet.NewWriter(new(bytes.Buffer), schema)
+	if err := writer.Write(&marshalRow{}); !errors.Is(err, errNoCurrency) {
+		t.Errorf("writer: expected %v but got %v", errNoCurrency, err)
+	}
+
+	buffer := parquet.NewBuffer(schema)
+	if err := buffer.Write(&marshalRow{}); !errors.Is(err, errNoCurrency) {
+		t.Errorf("buffer: expected %v but got %v", errNoCurrency, err)
+	}
+	if n := buffer.NumRows(); n != 0 {
+		t.Errorf("buffer: expected no rows but got %d", n)
+	}
+}
@@ -121,6 +121,8 @@ func (n *encodedNode) Encoding() encoding.Encoding {
 	return n.encoding
 }
 
+func (n *encodedNode) unwrap() Node { return n.Node }
+
 // Compressed wraps the node passed as argument to use the given compression
 // codec.
 //
@@ -146,6 +148,38 @@ func (n *compressedNode) Compression() compress.Codec {
 	return n.codec
 }
 
+func (n *compressedNode) unwrap() Node { return n.Node }
+
+// FieldID wraps the node passed as argument to assign it the given field ID.
+//
+// Field IDs are written to the schema of parquet files, and are preserved when
+// reading them back. They are commonly used by table formats like Iceberg to
+// track fields across schema changes, and identify fields independently of
+// their names (see ConvertByFieldID).
+//
+// Nodes expose their field ID with an ID method, which the nodes returned by
+// FieldID and the columns of files implement:
+//
+//	if n, ok := node.(interface{ ID() int }); ok {
+//		id := n.ID()
+//		...
+//	}
+func FieldID(node Node, id int) Node {
+	return &fieldIDNode{
+		Node: node,
+		id:   id,
+	}
+}
+
+type fieldIDNode struct {
+	Node
+	id int
+}
+
+func (n *fieldIDNode) ID() int { return n.id }
+
+func (n *fieldIDNode) unwrap() Node { return n.Node }
+
 // Optional wraps the given node to make it optional.
 func Optional(node Node) Node { return &optionalNode{node} }
 
@@ -155,6 +189,7 @@ func (opt *optionalNode) Optional() bool       { return true }
 func (opt *optionalNode) Repeated() bool       { return false }
 func (opt *optionalNode) Required() bool       { return false }
 func (opt *optionalNode) GoType() reflect.Type { return reflect.PtrTo(opt.Node.GoType()) }
+func (opt *optionalNode) unwrap() Node         { return opt.Node }
 
 // Repeated wraps the given node to make it repeated.
 func Repeated(node Node) Node { return &repeatedNode{node} }
@@ -165,6 +200,7 @@ func (rep *repeatedNode) Optional() bool       { return false }
 func (rep *repeatedNode) Repeated() bool       { return true }
 func (rep *repeatedNode) Required() bool       { return false }
 func (rep *repeatedNode) GoType() reflect.Type { return reflect.SliceOf(rep.Node.GoType()) }
+func (rep *repeatedNode) unwrap() Node         { return rep.Node }
 
 // Required wraps the given node to make it required.
 func Required(node Node) Node { return &requiredNode{node} }
@@ -175,6 +211,7 @@ func (req *requiredNode) Optional() bool       { return false }
 func (req *requiredNode) Repeated() bool       { return false }
 func (req *requiredNode) Required() bool       { return true }
 func (req *requiredNode) GoType() reflect.Type { return req.Node.GoType() }
+func (req *requiredNode) unwrap() Node         { return req.Node }
 
 type node struct{}
 
@@ -291,6 +328,8 @@ type groupField struct {
 
 func (f *groupField) Name() string { return f.name }
 
+func (f *groupField) unwrap() Node { return f.Node }
+
 func (f *groupField) Value(base reflect.Value) reflect.Value {
 	return base.MapIndex(reflect.ValueOf(&f.name).Elem())
 }
@@ -391,17 +430,73 @@ func numLeafColumns(node Node, columnIndex int) int {
 }
 
 func listElementOf(node Node) Node {
+	if elem := lookupListElement(node); elem != nil {
+		return elem
+	}
+	panic("node with logical type LIST is not composed of a repeated .list.element")
+}
+
+// lookupListElement returns the element of a LIST group, or nil if the group
+// does not have a valid layout.
+func lookupListElement(node Node) Node {
 	if !node.Leaf() {
 		if list := fieldByName(node, "list"); list != nil {
 			if elem := fieldByName(list, "element"); elem != nil {
 				return elem
 			}
 		}
+		if elem := legacyListElementOf(node); elem != nil {
+			return elem
+		}
 	}
-	panic("node with logical type LIST is not composed of a repeated .list.element")
+	return nil
+}
+
+// legacyListElementOf returns the element of a LIST group which does not have
+// the standard layout, following the backward-compatibility rules of the
+// parquet format, or nil if the group does not have a single repeated field.
+func legacyListElementOf(node Node) Node {
+	fields := node.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() {
+		return nil
+	}
+	name := ""
+	if f, ok := node.(Field); ok {
+		name = f.Name()
+	}
+	switch repeated := fields[0]; {
+	case isRepeatedListElement(repeated, name):
+		return Required(repeated)
+	case len(repeated.Fields()) == 1 && !repeated.Fields()[0].Repeated():
+		return repeated.Fields()[0]
+	default:
+		return nil
+	}
+}
+
+// isRepeatedListElement returns true if the repeated field of a LIST group
+// named listName is the element of the list, which is the case for two-level
+// lists, repeated groups with multiple fields, and repeated groups with a
+// single field named "array" or with the name of the list suffixed by _tuple.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#backward-compatibility-rules
+func isRepeatedListElement(repeated Field, listName string) bool {
+	return repeated.Leaf() ||
+		len(repeated.Fields()) > 1 ||
+		repeated.Name() == "array" ||
+		(listName != "" && repeated.Name() == listName+"_tuple")
 }
 
 func mapKeyValueOf(node Node) Node {
+	if keyValue := lookupMapKeyValue(node); keyValue != nil {
+		return keyValue
+	}
+	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+}
+
+// lookupMapKeyValue returns the repeated key_value group of a MAP group, or nil
+// if the group does not have a valid layout.
+func lookupMapKeyValue(node Node) Node {
 	if !node.Leaf() && (node.Required() || node.Optional()) {
 		if keyValue := fieldByName(node, "key_value"); keyValue != nil && !keyValue.Leaf() && keyValue.Repeated() {
 			k := fieldByName(keyValue, "key")
@@ -410,8 +505,15 @@ func mapKeyValueOf(node Node) Node {
 				return keyValue
 			}
 		}
+		// Maps written by older parquet writers may use other names for the
+		// repeated group and its fields, the key and value are positional.
+		if fields := node.Fields(); len(fields) == 1 && !fields[0].Leaf() && fields[0].Repeated() {
+			if keyValue := fields[0].Fields(); len(keyValue) == 2 && keyValue[0].Required() {
+				return fields[0]
+			}
+		}
 	}
-	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+	return nil
 }
 
 func encodingOf(node Node) encoding.Encoding {
@@ -449,6 +551,39 @@ func fieldByName(node Node, name string) Field {
 	return nil
 }
 
+func fieldByID(node Node, id int) Field {
+	for _, f := range node.Fields() {
+		if fieldIDOf(f) == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// unwrapNode returns the node that node wraps, or nil if node is not a wrapper.
+//
+// Wrappers are the nodes which the package uses to change some properties of
+// other nodes, such as their repetition, encoding, compression, field ID, or
+// field name. They implement an unwrap method returning the wrapped node.
+func unwrapNode(node Node) Node {
+	if n, ok := node.(interface{ unwrap() Node }); ok {
+		return n.unwrap()
+	}
+	return nil
+}
+
+// fieldIDOf returns the field ID of node, or zero if it has none. The wrappers
+// which do not expose the ID method of the nodes they embed are looked through.
+func fieldIDOf(node Node) int {
+	for node != nil {
+		if n, ok := node.(interface{ ID() int }); ok {
+			return n.ID()
+		}
+		node = unwrapNode(node)
+	}
+	return 0
+}
+
 func nodesAreEqual(node1, node2 Node) bool {
 	if node1.Leaf() {
 		return node2.Leaf() && leafNodesAreEqual(node1, node2)
@@ -41,6 +41,8 @@ func nullIndexFuncOf(t reflect.Type) nullIndexFunc {
 	switch t {
 	case reflect.TypeOf(deprecated.Int96{}):
 		return nullIndex[deprecated.Int96]
+	case reflect.TypeOf(CalendarInterval{}):
+		return nullIndex[CalendarInterval]
 	}
 
 	switch t.Kind() {
@@ -1155,6 +1155,52 @@ func (page *fixedLenByteArrayPage) makeValueString(v string) Value {
 	return value
 }
 
+type float16Page struct{ fixedLenByteArrayPage }
+
+func newFloat16Page(typ Type, columnIndex int16, numValues int32, values encoding.Values) *float16Page {
+	return &float16Page{*newFixedLenByteArrayPage(typ, columnIndex, numValues, values)}
+}
+
+func (page *float16Page) Bounds() (min, max Value, ok bool) {
+	minValue, maxValue, ok := boundsFloat16(page.data)
+	if ok {
+		min = page.makeValueBytes(makeFloat16Bytes(minValue))
+		max = page.makeValueBytes(makeFloat16Bytes(maxValue))
+	}
+	return min, max, ok
+}
+
+func (page *float16Page) Slice(i, j int64) Page {
+	return &float16Page{*page.fixedLenByteArrayPage.Slice(i, j).(*fixedLenByteArrayPage)}
+}
+
+// intervalPage has no bounds since the order of INTERVAL values is undefined.
+type intervalPage struct{ fixedLenByteArrayPage }
+
+func newIntervalPage(typ Type, columnIndex int16, numValues int32, values encoding.Values) *intervalPage {
+	return &intervalPage{*newFixedLenByteArrayPage(typ, columnIndex, numValues, values)}
+}
+
+func (page *intervalPage) Bounds() (min, max Value, ok bool) { return }
+
+func (page *intervalPage) Slice(i, j int64) Page {
+	return &intervalPage{*page.fixedLenByteArrayPage.Slice(i, j).(*fixedLenByteArrayPage)}
+}
+
+// geospatialPage has no bounds since the order of GEOMETRY and GEOGRAPHY values
+// is undefined.
+type geospatialPage struct{ byteArrayPage }
+
+func newGeospatialPage(typ Type, columnIndex int16, numValues int32, values encoding.Values) *geospatialPage {
+	return &geospatialPage{*newByteArrayPage(typ, columnIndex, numValues, values)}
+}
+
+func (page *geospatialPage) Bounds() (min, max Value, ok bool) { return }
+
+func (page *geospatialPage) Slice(i, j int64) Page {
+	return &geospatialPage{*page.byteArrayPage.Slice(i, j).(*byteArrayPage)}
+}
+
 type uint32Page struct {
 	typ         Type
 	values      []uint32
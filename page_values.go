@@ -37,6 +37,7 @@ func (r *optionalPageValues) ReadValues(values []Value) (n int, err error) {
 		}
 
 		if n < i {
+			k := n
 			for j, err = r.values.ReadValues(values[n:i]); j > 0; j-- {
 				values[n].definitionLevel = maxDefinitionLevel
 				r.offset++
@@ -46,6 +47,10 @@ func (r *optionalPageValues) ReadValues(values []Value) (n int, err error) {
 			if err != nil && err != io.EOF {
 				return n, err
 			}
+			// The definition levels declare more values than the page has.
+			if n == k {
+				return n, io.ErrUnexpectedEOF
+			}
 			err = nil
 		}
 	}
@@ -95,6 +100,7 @@ func (r *repeatedPageValues) ReadValues(values []Value) (n int, err error) {
 
 		// Copy all the non-zero values in this run.
 		if n < i {
+			k := n
 			for j, err = r.values.ReadValues(values[n:i]); j > 0; j-- {
 				values[n].repetitionLevel = repetitionLevels[r.offset]
 				values[n].definitionLevel = maxDefinitionLevel
@@ -104,6 +110,10 @@ func (r *repeatedPageValues) ReadValues(values []Value) (n int, err error) {
 			if err != nil && err != io.EOF {
 				return n, err
 			}
+			// The definition levels declare more values than the page has.
+			if n == k {
+				return n, io.ErrUnexpectedEOF
+			}
 			err = nil
 		}
 	}
@@ -0,0 +1,739 @@
+package parquet
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// ParseSchema parses the textual representation of a parquet schema, in the
+// format produced by PrintSchema, and returns the equivalent Schema.
+//
+// The text must contain a message declaration, for example:
+//
+//	message Event {
+//		required int64 id = 1;
+//		required binary name (STRING);
+//		optional int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+//		optional fixed_len_byte_array(16) amount (DECIMAL(38,9));
+//		required group tags (LIST) {
+//			repeated group list {
+//				required binary element (STRING);
+//			}
+//		}
+//	}
+//
+// All physical types are supported, as well as logical type annotations in
+// either the form produced by PrintSchema (e.g. INT(64,true)), the short
+// forms DECIMAL(precision,scale), TIMESTAMP(unit,isAdjustedToUTC) and
+// TIME(unit,isAdjustedToUTC), or the names of legacy converted types (e.g.
+// UTF8, TIMESTAMP_MILLIS, UINT_32). Field IDs may be declared after the name
+// of a field with the "= id" syntax.
+//
+// LIST and MAP groups may use either the layout defined by the parquet format or
+// the layouts of older parquet writers (e.g. two-level lists of repeated
+// fields), as described by the backward-compatibility rules of the format. The
+// physical type of primitive fields must be compatible with their annotation.
+//
+// Unlike the Group type, the fields of groups in the returned schema retain the
+// order in which they were declared in the text, which guarantees that
+// printing the schema produces the same output as the original text.
+func ParseSchema(text string) (*Schema, error) {
+	p := &schemaParser{text: text, line: 1}
+
+	if err := p.expectKeyword("message"); err != nil {
+		return nil, err
+	}
+
+	name := ""
+	if tok := p.peek(); tok != "{" {
+		if !isSchemaName(tok) {
+			return nil, p.errorf("expected message name or '{' but found %s", quoteToken(tok))
+		}
+		name = p.next()
+	}
+
+	root, err := p.parseGroupBody(&groupType{})
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok != "" {
+		return nil, p.errorf("unexpected %s after the end of the message", quoteToken(tok))
+	}
+	return NewSchema(name, root), nil
+}
+
+type schemaParser struct {
+	text string
+	line int
+}
+
+func (p *schemaParser) errorf(msg string, args ...interface{}) error {
+	return fmt.Errorf("parsing parquet schema: line %d: %s", p.line, fmt.Sprintf(msg, args...))
+}
+
+func (p *schemaParser) skipSpaces() {
+	for len(p.text) > 0 {
+		switch c := p.text[0]; c {
+		case '\n':
+			p.line++
+			fallthrough
+		case ' ', '\t', '\r':
+			p.text = p.text[1:]
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next token without consuming it, the empty string
+// indicates that the end of the input was reached.
+func (p *schemaParser) peek() string {
+	p.skipSpaces()
+	if len(p.text) == 0 {
+		return ""
+	}
+	if strings.IndexByte(schemaSymbols, p.text[0]) >= 0 {
+		return p.text[:1]
+	}
+	i := strings.IndexAny(p.text, schemaSymbols+" \t\r\n")
+	if i < 0 {
+		i = len(p.text)
+	}
+	return p.text[:i]
+}
+
+func (p *schemaParser) next() string {
+	tok := p.peek()
+	p.text = p.text[len(tok):]
+	return tok
+}
+
+func (p *schemaParser) expect(tok string) error {
+	if next := p.next(); next != tok {
+		return p.errorf("expected %s but found %s", quoteToken(tok), quoteToken(next))
+	}
+	return nil
+}
+
+func (p *schemaParser) expectKeyword(keyword string) error {
+	if next := p.next(); !strings.EqualFold(next, keyword) {
+		return p.errorf("expected %s but found %s", quoteToken(keyword), quoteToken(next))
+	}
+	return nil
+}
+
+func (p *schemaParser) parseGroupBody(typ Type) (*groupNode, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	group := &groupNode{typ: typ}
+	names := make(map[string]struct{})
+
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, p.errorf("unexpected end of schema, missing '}'")
+		}
+		name, node, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := names[name]; exists {
+			return nil, p.errorf("duplicate field name %q", name)
+		}
+		names[name] = struct{}{}
+		group.fields = append(group.fields, &groupField{Node: node, name: name})
+	}
+
+	p.next()
+	return group, nil
+}
+
+func (p *schemaParser) parseField() (string, Node, error) {
+	var repetition func(Node) Node
+
+	switch tok := p.next(); strings.ToLower(tok) {
+	case "required":
+		repetition = Required
+	case "optional":
+		repetition = Optional
+	case "repeated":
+		repetition = Repeated
+	default:
+		return "", nil, p.errorf("expected field repetition type but found %s", quoteToken(tok))
+	}
+
+	elem := &format.SchemaElement{}
+	kind := p.next()
+	group := false
+
+	switch strings.ToLower(kind) {
+	case "group":
+		group = true
+	case "boolean":
+		elem.Type = &physicalTypes[Boolean]
+	case "int32":
+		elem.Type = &physicalTypes[Int32]
+	case "int64":
+		elem.Type = &physicalTypes[Int64]
+	case "int96":
+		elem.Type = &physicalTypes[Int96]
+	case "float":
+		elem.Type = &physicalTypes[Float]
+	case "double":
+		elem.Type = &physicalTypes[Double]
+	case "binary":
+		elem.Type = &physicalTypes[ByteArray]
+	case "fixed_len_byte_array":
+		elem.Type = &physicalTypes[FixedLenByteArray]
+		if err := p.expect("("); err != nil {
+			return "", nil, err
+		}
+		size, err := p.parseInt("fixed_len_byte_array length")
+		if err != nil {
+			return "", nil, err
+		}
+		if size <= 0 {
+			return "", nil, p.errorf("invalid fixed_len_byte_array length: %d", size)
+		}
+		length := int32(size)
+		elem.TypeLength = &length
+		if err := p.expect(")"); err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, p.errorf("expected field type but found %s", quoteToken(kind))
+	}
+
+	name := p.next()
+	if !isSchemaName(name) {
+		return "", nil, p.errorf("expected field name but found %s", quoteToken(name))
+	}
+
+	// The field ID and annotation may appear in any order, PrintSchema
+	// emits the annotation first.
+	for done := false; !done; {
+		switch p.peek() {
+		case "(":
+			if elem.LogicalType != nil || elem.ConvertedType != nil {
+				return "", nil, p.errorf("field %q has more than one annotation", name)
+			}
+			if err := p.parseAnnotation(elem); err != nil {
+				return "", nil, err
+			}
+		case "=":
+			p.next()
+			id, err := p.parseInt("field id")
+			if err != nil {
+				return "", nil, err
+			}
+			if id <= 0 {
+				// Zero means that fields have no ID, like in struct tags.
+				return "", nil, p.errorf("field %q has invalid field id %d, field ids must be positive", name, id)
+			}
+			elem.FieldID = int32(id)
+		default:
+			done = true
+		}
+	}
+
+	if group {
+		if lt := elem.LogicalType; lt != nil && lt.List == nil && lt.Map == nil && lt.Variant == nil {
+			return "", nil, p.errorf("group %q cannot have annotation %s", name, elem.LogicalType)
+		}
+		if ct := elem.ConvertedType; ct != nil && *ct != deprecated.MapKeyValue {
+			return "", nil, p.errorf("group %q cannot have annotation %s", name, convertedTypeName(*ct))
+		}
+		typ := Type(&groupType{})
+		if elem.LogicalType != nil {
+			typ = schemaElementTypeOf(elem)
+		}
+		node, err := p.parseGroupBody(typ)
+		if err != nil {
+			return "", nil, err
+		}
+		// LIST and MAP groups may use the layouts of older parquet writers,
+		// which are recognized when reading files, the name of the group is
+		// needed to identify the element of some legacy lists.
+		switch field := (&groupField{Node: node, name: name}); {
+		case isList(node) && lookupListElement(field) == nil:
+			return "", nil, p.errorf("group %q of type LIST must contain a single repeated field", name)
+		case isMap(node) && lookupMapKeyValue(field) == nil:
+			return "", nil, p.errorf("group %q of type MAP must contain a repeated group with a required key field and a value field", name)
+		case isVariant(node) && !isStandardVariant(node):
+			return "", nil, p.errorf("group %q of type VARIANT must contain a required binary metadata field and a binary value or typed_value field", name)
+		}
+		return name, withFieldID(repetition(node), elem), nil
+	}
+
+	if err := p.expect(";"); err != nil {
+		return "", nil, err
+	}
+	if lt := elem.LogicalType; lt != nil && (lt.List != nil || lt.Map != nil || lt.Variant != nil) {
+		return "", nil, p.errorf("primitive field %q cannot have annotation %s", name, lt)
+	}
+	if ct := elem.ConvertedType; ct != nil && *ct == deprecated.MapKeyValue {
+		return "", nil, p.errorf("primitive field %q cannot have annotation MAP_KEY_VALUE", name)
+	}
+
+	if err := p.checkAnnotation(name, kind, elem); err != nil {
+		return "", nil, err
+	}
+
+	typ := schemaElementTypeOf(elem)
+	if typ.Kind() != Kind(*elem.Type) || (typ.Kind() == FixedLenByteArray && typ.Length() != int(*elem.TypeLength)) {
+		annotation := ""
+		if lt := typ.LogicalType(); lt != nil {
+			annotation = lt.String()
+		}
+		return "", nil, p.errorf("field %q of type %s cannot have annotation %s", name, strings.ToLower(kind), annotation)
+	}
+	return name, withFieldID(repetition(Leaf(typ)), elem), nil
+}
+
+// checkAnnotation validates that the annotation of a primitive field can be
+// applied to its physical type, which schemaElementTypeOf assumes.
+func (p *schemaParser) checkAnnotation(name, kind string, elem *format.SchemaElement) error {
+	physicalType := Kind(*elem.Type)
+	length := 0
+	if elem.TypeLength != nil {
+		length = int(*elem.TypeLength)
+	}
+
+	valid, annotation := true, ""
+
+	if lt := elem.LogicalType; lt != nil {
+		annotation = lt.String()
+
+		switch {
+		case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil, lt.Bson != nil, lt.Geometry != nil, lt.Geography != nil:
+			valid = physicalType == ByteArray
+		case lt.UUID != nil:
+			valid = physicalType == FixedLenByteArray && length == 16
+		case lt.Float16 != nil:
+			valid = physicalType == FixedLenByteArray && length == 2
+		case lt.Date != nil:
+			valid = physicalType == Int32
+		case lt.Time != nil:
+			if lt.Time.Unit.Millis != nil {
+				valid = physicalType == Int32
+			} else {
+				valid = physicalType == Int64
+			}
+		case lt.Timestamp != nil:
+			valid = physicalType == Int64
+		case lt.Integer != nil:
+			if lt.Integer.BitWidth == 64 {
+				valid = physicalType == Int64
+			} else {
+				valid = physicalType == Int32
+			}
+		case lt.Decimal != nil:
+			precision, maxPrecision := int(lt.Decimal.Precision), 0
+			switch physicalType {
+			case Int32:
+				maxPrecision = 9
+			case Int64:
+				maxPrecision = 18
+			case FixedLenByteArray:
+				if decimalFixedLenByteArraySize(precision) > length {
+					return p.errorf("field %q of type %s(%d) cannot hold the precision of %s", name, strings.ToLower(kind), length, annotation)
+				}
+			case ByteArray:
+			default:
+				valid = false
+			}
+			if maxPrecision > 0 && precision > maxPrecision {
+				return p.errorf("field %q of type %s cannot hold the precision of %s, the maximum precision is %d", name, strings.ToLower(kind), annotation, maxPrecision)
+			}
+		}
+	} else if ct := elem.ConvertedType; ct != nil {
+		annotation = convertedTypeName(*ct)
+
+		switch *ct {
+		case deprecated.UTF8:
+			valid = physicalType == ByteArray
+		case deprecated.Int8, deprecated.Int16, deprecated.Int32,
+			deprecated.Uint8, deprecated.Uint16, deprecated.Uint32,
+			deprecated.TimeMillis:
+			valid = physicalType == Int32
+		case deprecated.Int64, deprecated.Uint64,
+			deprecated.TimeMicros, deprecated.TimestampMillis, deprecated.TimestampMicros:
+			valid = physicalType == Int64
+		case deprecated.Interval:
+			valid = physicalType == FixedLenByteArray && length == 12
+		}
+	}
+
+	if !valid {
+		if physicalType == FixedLenByteArray {
+			kind = fmt.Sprintf("%s(%d)", kind, length)
+		}
+		return p.errorf("field %q of type %s cannot have annotation %s", name, strings.ToLower(kind), annotation)
+	}
+	return nil
+}
+
+func withFieldID(node Node, elem *format.SchemaElement) Node {
+	if elem.FieldID != 0 {
+		node = FieldID(node, int(elem.FieldID))
+	}
+	return node
+}
+
+func (p *schemaParser) parseInt(what string) (int, error) {
+	tok := p.next()
+	i, err := strconv.ParseInt(tok, 10, 32)
+	if err != nil {
+		return 0, p.errorf("invalid %s: %s", what, quoteToken(tok))
+	}
+	return int(i), nil
+}
+
+type schemaArg struct{ key, value string }
+
+func (p *schemaParser) parseAnnotation(elem *format.SchemaElement) error {
+	p.next() // (
+	name := strings.ToUpper(p.next())
+
+	var args []schemaArg
+	if p.peek() == "(" {
+		p.next()
+		for {
+			arg := schemaArg{value: p.next()}
+			if p.peek() == "=" {
+				p.next()
+				arg.key, arg.value = arg.value, p.next()
+			}
+			args = append(args, arg)
+			if tok := p.next(); tok == ")" {
+				break
+			} else if tok != "," {
+				return p.errorf("expected ',' or ')' in arguments of %s but found %s", name, quoteToken(tok))
+			}
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	lt := &format.LogicalType{}
+	ct := deprecated.ConvertedType(-1)
+	noArgs := true
+
+	switch name {
+	case "STRING":
+		lt.UTF8 = new(format.StringType)
+	case "ENUM":
+		lt.Enum = new(format.EnumType)
+	case "JSON":
+		lt.Json = new(format.JsonType)
+	case "BSON":
+		lt.Bson = new(format.BsonType)
+	case "UUID":
+		lt.UUID = new(format.UUIDType)
+	case "FLOAT16":
+		lt.Float16 = new(format.Float16Type)
+	case "DATE":
+		lt.Date = new(format.DateType)
+	case "LIST":
+		lt.List = new(format.ListType)
+	case "MAP":
+		lt.Map = new(format.MapType)
+	case "NULL", "UNKNOWN":
+		lt.Unknown = new(format.NullType)
+	default:
+		if t, ok := convertedTypeNames[name]; ok {
+			ct = t
+		} else {
+			noArgs = false
+		}
+	}
+
+	if noArgs {
+		if len(args) != 0 {
+			return p.errorf("annotation %s does not accept arguments", name)
+		}
+	} else {
+		switch name {
+		case "DECIMAL":
+			precision, scale, err := p.parseDecimalArgs(args)
+			if err != nil {
+				return err
+			}
+			lt.Decimal = &format.DecimalType{Precision: precision, Scale: scale}
+			elem.Precision, elem.Scale = &lt.Decimal.Precision, &lt.Decimal.Scale
+		case "TIMESTAMP":
+			unit, utc, err := p.parseTimeArgs(name, args)
+			if err != nil {
+				return err
+			}
+			lt.Timestamp = &format.TimestampType{IsAdjustedToUTC: utc, Unit: unit}
+		case "TIME":
+			unit, utc, err := p.parseTimeArgs(name, args)
+			if err != nil {
+				return err
+			}
+			lt.Time = &format.TimeType{IsAdjustedToUTC: utc, Unit: unit}
+		case "VARIANT":
+			version, err := p.parseVariantArgs(args)
+			if err != nil {
+				return err
+			}
+			lt.Variant = &format.VariantType{SpecificationVersion: version}
+		case "GEOMETRY":
+			crs, err := p.parseGeometryArgs(args)
+			if err != nil {
+				return err
+			}
+			lt.Geometry = &format.GeometryType{CRS: crs}
+		case "GEOGRAPHY":
+			crs, algorithm, err := p.parseGeographyArgs(args)
+			if err != nil {
+				return err
+			}
+			lt.Geography = &format.GeographyType{CRS: crs, Algorithm: algorithm}
+		case "INT":
+			bitWidth, signed, err := p.parseIntArgs(args)
+			if err != nil {
+				return err
+			}
+			lt.Integer = &format.IntType{BitWidth: bitWidth, IsSigned: signed}
+		default:
+			return p.errorf("unsupported annotation: %s", name)
+		}
+	}
+
+	if ct >= 0 {
+		elem.ConvertedType = &ct
+	} else {
+		elem.LogicalType = lt
+	}
+	return nil
+}
+
+func (p *schemaParser) parseDecimalArgs(args []schemaArg) (precision, scale int32, err error) {
+	if len(args) != 2 {
+		return 0, 0, p.errorf("DECIMAL annotation expects two arguments (precision,scale)")
+	}
+	values := [2]int32{}
+	for i, arg := range args {
+		v, err := strconv.ParseInt(arg.value, 10, 32)
+		if err != nil || v < 0 {
+			return 0, 0, p.errorf("invalid DECIMAL argument: %s", quoteToken(arg.value))
+		}
+		switch arg.key {
+		case "":
+			values[i] = int32(v)
+		case "precision":
+			values[0] = int32(v)
+		case "scale":
+			values[1] = int32(v)
+		default:
+			return 0, 0, p.errorf("invalid DECIMAL argument: %s", quoteToken(arg.key))
+		}
+	}
+	precision, scale = values[0], values[1]
+	if precision == 0 || scale > precision {
+		return 0, 0, p.errorf("invalid DECIMAL precision and scale: (%d,%d)", precision, scale)
+	}
+	return precision, scale, nil
+}
+
+func (p *schemaParser) parseVariantArgs(args []schemaArg) (int8, error) {
+	switch len(args) {
+	case 0:
+		return 0, nil
+	case 1:
+		if key := args[0].key; key != "" && key != "specification_version" {
+			return 0, p.errorf("invalid VARIANT argument: %s", quoteToken(key))
+		}
+		v, err := strconv.ParseInt(args[0].value, 10, 8)
+		if err != nil || v <= 0 {
+			return 0, p.errorf("invalid VARIANT specification version: %s", quoteToken(args[0].value))
+		}
+		return int8(v), nil
+	default:
+		return 0, p.errorf("VARIANT annotation expects at most one argument (specification_version)")
+	}
+}
+
+func (p *schemaParser) parseGeometryArgs(args []schemaArg) (string, error) {
+	switch len(args) {
+	case 0:
+		return "", nil
+	case 1:
+		if key := args[0].key; key != "" && key != "crs" {
+			return "", p.errorf("invalid GEOMETRY argument: %s", quoteToken(key))
+		}
+		return args[0].value, nil
+	default:
+		return "", p.errorf("GEOMETRY annotation expects at most one argument (crs)")
+	}
+}
+
+func (p *schemaParser) parseGeographyArgs(args []schemaArg) (crs string, algorithm *format.EdgeInterpolationAlgorithm, err error) {
+	if len(args) > 2 {
+		return "", nil, p.errorf("GEOGRAPHY annotation expects at most two arguments (crs,algorithm)")
+	}
+	for i, arg := range args {
+		key := arg.key
+		if key == "" {
+			key = [...]string{"crs", "algorithm"}[i]
+		}
+		switch key {
+		case "crs":
+			crs = arg.value
+		case "algorithm":
+			a, ok := lookupEdgeInterpolationAlgorithm(arg.value)
+			if !ok {
+				return "", nil, p.errorf("invalid GEOGRAPHY edge interpolation algorithm: %s", quoteToken(arg.value))
+			}
+			algorithm = &a
+		default:
+			return "", nil, p.errorf("invalid GEOGRAPHY argument: %s", quoteToken(key))
+		}
+	}
+	return crs, algorithm, nil
+}
+
+func (p *schemaParser) parseTimeArgs(name string, args []schemaArg) (unit format.TimeUnit, utc bool, err error) {
+	if len(args) != 2 {
+		return unit, false, p.errorf("%s annotation expects two arguments (unit,isAdjustedToUTC)", name)
+	}
+	hasUnit, hasUTC := false, false
+
+	for _, arg := range args {
+		key := arg.key
+		if key == "" {
+			if hasUnit {
+				key = "isAdjustedToUTC"
+			} else {
+				key = "unit"
+			}
+		}
+		switch key {
+		case "unit":
+			switch strings.ToUpper(arg.value) {
+			case "MILLIS":
+				unit = Millisecond.TimeUnit()
+			case "MICROS":
+				unit = Microsecond.TimeUnit()
+			case "NANOS":
+				unit = Nanosecond.TimeUnit()
+			default:
+				return unit, false, p.errorf("invalid %s unit: %s", name, quoteToken(arg.value))
+			}
+			hasUnit = true
+		case "isAdjustedToUTC":
+			if utc, err = strconv.ParseBool(arg.value); err != nil {
+				return unit, false, p.errorf("invalid %s isAdjustedToUTC value: %s", name, quoteToken(arg.value))
+			}
+			hasUTC = true
+		default:
+			return unit, false, p.errorf("invalid %s argument: %s", name, quoteToken(arg.key))
+		}
+	}
+
+	if !hasUnit || !hasUTC {
+		return unit, false, p.errorf("%s annotation expects two arguments (unit,isAdjustedToUTC)", name)
+	}
+	return unit, utc, nil
+}
+
+func (p *schemaParser) parseIntArgs(args []schemaArg) (bitWidth int8, signed bool, err error) {
+	if len(args) != 2 || args[0].key != "" || args[1].key != "" {
+		return 0, false, p.errorf("INT annotation expects two arguments (bitWidth,isSigned)")
+	}
+	switch args[0].value {
+	case "8", "16", "32", "64":
+		v, _ := strconv.Atoi(args[0].value)
+		bitWidth = int8(v)
+	default:
+		return 0, false, p.errorf("invalid INT bit width: %s", quoteToken(args[0].value))
+	}
+	if signed, err = strconv.ParseBool(args[1].value); err != nil {
+		return 0, false, p.errorf("invalid INT signedness: %s", quoteToken(args[1].value))
+	}
+	return bitWidth, signed, nil
+}
+
+func isStandardList(node Node) bool {
+	list := fieldByName(node, "list")
+	return list != nil && !list.Leaf() && list.Repeated() &&
+		len(list.Fields()) == 1 && fieldByName(list, "element") != nil
+}
+
+func convertedTypeName(ct deprecated.ConvertedType) string {
+	for name, t := range convertedTypeNames {
+		if t == ct {
+			return name
+		}
+	}
+	return strconv.Itoa(int(ct))
+}
+
+var convertedTypeNames = map[string]deprecated.ConvertedType{
+	"UTF8":             deprecated.UTF8,
+	"MAP_KEY_VALUE":    deprecated.MapKeyValue,
+	"TIMESTAMP_MILLIS": deprecated.TimestampMillis,
+	"TIMESTAMP_MICROS": deprecated.TimestampMicros,
+	"TIME_MILLIS":      deprecated.TimeMillis,
+	"TIME_MICROS":      deprecated.TimeMicros,
+	"INT_8":            deprecated.Int8,
+	"INT_16":           deprecated.Int16,
+	"INT_32":           deprecated.Int32,
+	"INT_64":           deprecated.Int64,
+	"UINT_8":           deprecated.Uint8,
+	"UINT_16":          deprecated.Uint16,
+	"UINT_32":          deprecated.Uint32,
+	"UINT_64":          deprecated.Uint64,
+	"INTERVAL":         deprecated.Interval,
+}
+
+const schemaSymbols = "{}();=,"
+
+func isSchemaName(tok string) bool {
+	return tok != "" && strings.IndexByte(schemaSymbols, tok[0]) < 0
+}
+
+func quoteToken(tok string) string {
+	if tok == "" {
+		return "end of schema"
+	}
+	return strconv.Quote(tok)
+}
+
+// groupNode is a group of fields which retains the order in which they were
+// declared, it is used to represent schemas parsed from text.
+type groupNode struct {
+	typ    Type
+	fields []Field
+}
+
+func (g *groupNode) String() string { return sprint("", g) }
+
+func (g *groupNode) Type() Type { return g.typ }
+
+func (g *groupNode) Optional() bool { return false }
+
+func (g *groupNode) Repeated() bool { return false }
+
+func (g *groupNode) Required() bool { return true }
+
+func (g *groupNode) Leaf() bool { return false }
+
+func (g *groupNode) Fields() []Field { return g.fields }
+
+func (g *groupNode) Encoding() encoding.Encoding { return nil }
+
+func (g *groupNode) Compression() compress.Codec { return nil }
+
+func (g *groupNode) GoType() reflect.Type { return goTypeOfGroup(g) }
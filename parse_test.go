@@ -0,0 +1,192 @@
+package parquet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestParseSchema(t *testing.T) {
+	tests := []string{
+		`message {
+}`,
+
+		`message Event {
+	required boolean a;
+	required int32 b;
+	required int64 c;
+	required int96 d;
+	required float e;
+	required double f;
+	required binary g;
+	required fixed_len_byte_array(12) h;
+}`,
+
+		`message Event {
+	required int64 id (INT(64,true));
+	optional binary name (STRING);
+	optional binary color (ENUM);
+	optional binary doc (JSON);
+	optional binary raw (BSON);
+	required fixed_len_byte_array(16) uuid (UUID);
+	required int32 day (DATE);
+	required int32 time_millis (TIME(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 time_nanos (TIME(isAdjustedToUTC=false,unit=NANOS));
+	required int64 created_at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required int64 updated_at (TIMESTAMP(isAdjustedToUTC=false,unit=MILLIS));
+	required int32 small_price (DECIMAL(9,2));
+	required int64 price (DECIMAL(18,4));
+	required fixed_len_byte_array(16) big_price (DECIMAL(38,10));
+	required binary any_price (DECIMAL(50,10));
+	required int32 u8 (INT(8,false));
+	required int32 i16 (INT(16,true));
+	required int32 u32 (INT(32,false));
+	required int64 u64 (INT(64,false));
+	required fixed_len_byte_array(2) half (FLOAT16);
+}`,
+
+		`message AddressBook {
+	required binary owner (STRING);
+	repeated binary ownerPhoneNumbers (STRING);
+	repeated group contacts {
+		required binary name (STRING);
+		optional binary phoneNumber (STRING);
+	}
+	optional group tags (LIST) {
+		repeated group list {
+			optional binary element (STRING);
+		}
+	}
+	required group attributes (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			optional group value {
+				required int64 z (INT(64,true));
+				required int64 a (INT(64,true));
+			}
+		}
+	}
+}`,
+
+		`message Legacy {
+	required group ids (LIST) {
+		repeated int32 array;
+	}
+	optional group names (LIST) {
+		repeated binary str (STRING);
+	}
+	optional group points (LIST) {
+		repeated group points_tuple {
+			required double x;
+		}
+	}
+	required group attributes (MAP) {
+		repeated group map {
+			required binary key (STRING);
+			optional int32 value;
+		}
+	}
+}`,
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			schema, err := parquet.ParseSchema(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := schema.String(); s != test {
+				t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", test, s)
+			}
+		})
+	}
+}
+
+func TestParseSchemaAlternativeSyntax(t *testing.T) {
+	schema, err := parquet.ParseSchema(`
+		MESSAGE spark_schema {
+			REQUIRED INT64 id = 1;
+			optional binary name (UTF8) = 2;
+			optional int64 ts = 3 (TIMESTAMP(MICROS,true));
+			optional int64 ts_millis (TIMESTAMP_MILLIS);
+			optional int32 t (TIME(MILLIS,false));
+			optional int32 u (UINT_16);
+			optional fixed_len_byte_array(5) d (DECIMAL(precision=10,scale=2));
+		}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `message spark_schema {
+	required int64 id = 1;
+	optional binary name (STRING) = 2;
+	optional int64 ts (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)) = 3;
+	optional int64 ts_millis (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	optional int32 t (TIME(isAdjustedToUTC=false,unit=MILLIS));
+	optional int32 u (INT(16,false));
+	optional fixed_len_byte_array(5) d (DECIMAL(10,2));
+}`
+
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+func TestParseSchemaOfGoValue(t *testing.T) {
+	schema := parquet.SchemaOf(new(AddressBook2))
+
+	parsed, err := parquet.ParseSchema(schema.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != schema.String() {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", schema, parsed)
+	}
+	if parsed.Name() != schema.Name() {
+		t.Errorf("wrong schema name: want=%q got=%q", schema.Name(), parsed.Name())
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		text  string
+		error string
+	}{
+		{`group {}`, `line 1: expected "message"`},
+		{"message {\n\trequired int64 a;\n\trequired int64 a;\n}", `line 3: duplicate field name "a"`},
+		{"message {\n\trequired int128 a;\n}", `line 2: expected field type but found "int128"`},
+		{`message { required int32 a (STRING); }`, `field "a" of type int32 cannot have annotation STRING`},
+		{`message { required fixed_len_byte_array(8) a (UUID); }`, `cannot have annotation UUID`},
+		{`message { required int64 a (DECIMAL(3)); }`, `DECIMAL annotation expects two arguments`},
+		{`message { required int64 a (TIMESTAMP(SECONDS,true)); }`, `invalid TIMESTAMP unit: "SECONDS"`},
+		{`message { required int64 a (WHAT); }`, `unsupported annotation: WHAT`},
+		{`message { required boolean a (DECIMAL(5,2)); }`, `field "a" of type boolean cannot have annotation DECIMAL(5,2)`},
+		{`message { required int32 a (DECIMAL(20,2)); }`, `field "a" of type int32 cannot hold the precision of DECIMAL(20,2)`},
+		{`message { required int64 a (DECIMAL(19,2)); }`, `field "a" of type int64 cannot hold the precision of DECIMAL(19,2)`},
+		{`message { required fixed_len_byte_array(4) a (DECIMAL(10,2)); }`, `field "a" of type fixed_len_byte_array(4) cannot hold the precision of DECIMAL(10,2)`},
+		{`message { required int32 a (INTERVAL); }`, `field "a" of type int32 cannot have annotation INTERVAL`},
+		{`message { required int64 a (TIME(MILLIS,true)); }`, `field "a" of type int64 cannot have annotation TIME`},
+		{`message { required int64 a (DATE); }`, `field "a" of type int64 cannot have annotation DATE`},
+		{`message { required int64 a (UINT_32); }`, `field "a" of type int64 cannot have annotation UINT_32`},
+		{`message { required group a (LIST) { required int32 b; } }`, `group "a" of type LIST must contain`},
+		{`message { required group a (MAP) { repeated group b { optional int32 key; } } }`, `group "a" of type MAP must contain`},
+		{`message { required group a (STRING) { } }`, `group "a" cannot have annotation STRING`},
+		{`message { required int64 a = 0; }`, `field "a" has invalid field id 0`},
+		{`message { required int64 a }`, `expected ";" but found "}"`},
+		{`message { required int64 a; `, `missing '}'`},
+		{`message { } }`, `unexpected "}" after the end of the message`},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			_, err := parquet.ParseSchema(test.text)
+			if err == nil {
+				t.Fatal("expected an error but got none")
+			}
+			if !strings.Contains(err.Error(), test.error) {
+				t.Errorf("error mismatch:\nwant: %s\ngot:  %s", test.error, err)
+			}
+		})
+	}
+}
@@ -94,6 +94,11 @@ func printSchemaWithIndent(w io.StringWriter, name string, node Node, indent *pr
 			w.WriteString(")")
 		}
 
+		if id := fieldIDOf(node); id != 0 {
+			w.WriteString(" = ")
+			w.WriteString(strconv.Itoa(id))
+		}
+
 		w.WriteString(";")
 	} else {
 		w.WriteString("group")
@@ -109,6 +114,11 @@ func printSchemaWithIndent(w io.StringWriter, name string, node Node, indent *pr
 			w.WriteString(")")
 		}
 
+		if id := fieldIDOf(node); id != 0 {
+			w.WriteString(" = ")
+			w.WriteString(strconv.Itoa(id))
+		}
+
 		w.WriteString(" {")
 		indent.writeNewLine(w)
 		indent.push()
@@ -128,6 +138,10 @@ func annotationOf(node Node) string {
 	if logicalType := node.Type().LogicalType(); logicalType != nil {
 		return logicalType.String()
 	}
+	// Types like INTERVAL only exist as converted types.
+	if convertedType := node.Type().ConvertedType(); convertedType != nil {
+		return convertedTypeName(*convertedType)
+	}
 	return ""
 }
 
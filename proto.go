@@ -0,0 +1,856 @@
+package parquet
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// SchemaOfProto constructs a parquet schema from a protobuf message descriptor.
+//
+// Fields of the message are mapped to parquet columns in the order they are
+// declared in the descriptor, using the following rules:
+//
+//	Protobuf type               | Parquet type
+//	--------------------------- | ------------
+//	bool                        | BOOLEAN
+//	int32, sint32, sfixed32     | INT(32,true)
+//	int64, sint64, sfixed64     | INT(64,true)
+//	uint32, fixed32             | INT(32,false)
+//	uint64, fixed64             | INT(64,false)
+//	float                       | FLOAT
+//	double                      | DOUBLE
+//	string                      | STRING
+//	bytes                       | BYTE_ARRAY
+//	enum                        | ENUM (the name of the enum value)
+//	message                     | group
+//	google.protobuf.Timestamp   | TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)
+//	google.protobuf.Duration    | INT(64,true) (microseconds)
+//	google.protobuf.*Value      | the wrapped type, optional
+//	google.protobuf.Struct      | JSON
+//	google.protobuf.Value       | JSON
+//	google.protobuf.ListValue   | JSON
+//
+// Timestamps and durations are stored in microseconds, which represent the
+// whole range of valid protobuf values (nanoseconds overflow 64 bits outside
+// of the years 1678 to 2262), the sub-microsecond digits are truncated.
+//
+// Repeated fields become repeated parquet columns, map fields use the MAP
+// logical type, and fields with explicit presence (messages, oneof members,
+// and optional scalars) become optional columns. Members of a oneof are each
+// represented by their own optional column, at most one of them holds a value
+// in any given row.
+//
+// The returned schema can be used to write and read proto.Message values with
+// Writer, Reader, GenericWriter and GenericReader. When the generic type
+// parameter of these implements proto.Message, the schema is automatically
+// derived from the message descriptor, for example:
+//
+//	writer := parquet.NewGenericWriter[*pb.Event](output)
+//
+// The function panics if the message is recursive, since parquet schemas
+// cannot represent unbounded nesting.
+func SchemaOfProto(desc protoreflect.MessageDescriptor) *Schema {
+	root := protoMessageNodeOf(desc, nil)
+	mapping, columns := columnMappingOf(root)
+	_, deconstruct := protoDeconstructFuncOfMessage(0, desc)
+	_, reconstruct := protoReconstructFuncOfMessage(0, desc)
+	return &Schema{
+		name: string(desc.Name()),
+		root: root,
+		deconstruct: func(columns [][]Value, levels levels, value reflect.Value) error {
+			var m protoreflect.Message
+			if value.IsValid() {
+				m = protoMessageOf(value)
+			}
+			return deconstruct(columns, levels, m)
+		},
+		reconstruct: func(value reflect.Value, levels levels, columns [][]Value) error {
+			m := protoMessageOf(value)
+			if m.Descriptor() == nil {
+				// Zero-value of dynamicpb.Message, which happens when the
+				// program reads into []*dynamicpb.Message with a GenericReader.
+				if _, ok := value.Interface().(dynamicpb.Message); !ok {
+					return fmt.Errorf("cannot reconstruct protobuf message of type %s without descriptor", value.Type())
+				}
+				value.Set(reflect.ValueOf(dynamicpb.NewMessage(desc)).Elem())
+				m = protoMessageOf(value)
+			}
+			proto.Reset(m.Interface())
+			return reconstruct(m, levels, columns)
+		},
+		mapping: mapping,
+		columns: columns,
+	}
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+func isProtoMessage(t reflect.Type) bool {
+	return t.Implements(protoMessageType) || reflect.PtrTo(t).Implements(protoMessageType)
+}
+
+func protoMessageOf(v reflect.Value) protoreflect.Message {
+	if v.Kind() != reflect.Ptr {
+		if v.CanAddr() {
+			v = v.Addr()
+		} else {
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p
+		}
+	}
+	m, ok := v.Interface().(proto.Message)
+	if !ok {
+		panic("cannot use go value of type " + v.Type().String() + " as a protobuf message")
+	}
+	return m.ProtoReflect()
+}
+
+func schemaOfProtoType(t reflect.Type) *Schema {
+	m := reflect.New(t).Interface().(proto.Message)
+	return SchemaOfProto(m.ProtoReflect().Descriptor())
+}
+
+const (
+	protoTimestamp = "google.protobuf.Timestamp"
+	protoDuration  = "google.protobuf.Duration"
+	protoStruct    = "google.protobuf.Struct"
+	protoValue     = "google.protobuf.Value"
+	protoListValue = "google.protobuf.ListValue"
+)
+
+func isProtoWrapper(desc protoreflect.MessageDescriptor) bool {
+	switch desc.FullName() {
+	case "google.protobuf.DoubleValue",
+		"google.protobuf.FloatValue",
+		"google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value",
+		"google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue",
+		"google.protobuf.StringValue",
+		"google.protobuf.BytesValue":
+		return true
+	default:
+		return false
+	}
+}
+
+// protoMessageNode is the parquet node representing a protobuf message, it
+// retains the declaration order of fields in the message descriptor.
+type protoMessageNode struct {
+	fields []protoField
+}
+
+func protoMessageNodeOf(desc protoreflect.MessageDescriptor, seen []protoreflect.FullName) *protoMessageNode {
+	for _, name := range seen {
+		if name == desc.FullName() {
+			panic("cannot create parquet schema from recursive protobuf message " + string(name))
+		}
+	}
+	seen = append(seen, desc.FullName())
+
+	fields := desc.Fields()
+	n := &protoMessageNode{
+		fields: make([]protoField, fields.Len()),
+	}
+	for i := range n.fields {
+		fd := fields.Get(i)
+		n.fields[i] = protoField{
+			Node: protoFieldNodeOf(fd, seen),
+			desc: fd,
+		}
+	}
+	return n
+}
+
+func protoFieldNodeOf(fd protoreflect.FieldDescriptor, seen []protoreflect.FullName) Node {
+	switch {
+	case fd.IsMap():
+		return Map(protoTypeNodeOf(fd.MapKey(), seen), protoTypeNodeOf(fd.MapValue(), seen))
+	case fd.IsList():
+		return Repeated(protoTypeNodeOf(fd, seen))
+	case fd.HasPresence():
+		return Optional(protoTypeNodeOf(fd, seen))
+	default:
+		return Required(protoTypeNodeOf(fd, seen))
+	}
+}
+
+func protoTypeNodeOf(fd protoreflect.FieldDescriptor, seen []protoreflect.FullName) Node {
+	if protoIsGroup(fd) {
+		return protoMessageNodeOf(fd.Message(), seen)
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return Leaf(BooleanType)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return Int(32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return Int(64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Uint(32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return Uint(64)
+	case protoreflect.FloatKind:
+		return Leaf(FloatType)
+	case protoreflect.DoubleKind:
+		return Leaf(DoubleType)
+	case protoreflect.StringKind:
+		return String()
+	case protoreflect.BytesKind:
+		return Leaf(ByteArrayType)
+	case protoreflect.EnumKind:
+		return Enum()
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		desc := fd.Message()
+		switch desc.FullName() {
+		case protoTimestamp:
+			return Timestamp(Microsecond)
+		case protoDuration:
+			return Int(64)
+		case protoStruct, protoValue, protoListValue:
+			return JSON()
+		}
+		return protoTypeNodeOf(desc.Fields().ByNumber(1), seen) // wrappers
+	default:
+		panic("cannot create parquet node from protobuf field of kind " + fd.Kind().String())
+	}
+}
+
+func (n *protoMessageNode) String() string { return sprint("", n) }
+
+func (n *protoMessageNode) Type() Type { return groupType{} }
+
+func (n *protoMessageNode) Optional() bool { return false }
+
+func (n *protoMessageNode) Repeated() bool { return false }
+
+func (n *protoMessageNode) Required() bool { return true }
+
+func (n *protoMessageNode) Leaf() bool { return false }
+
+func (n *protoMessageNode) Encoding() encoding.Encoding { return nil }
+
+func (n *protoMessageNode) Compression() compress.Codec { return nil }
+
+func (n *protoMessageNode) GoType() reflect.Type { return goTypeOfGroup(n) }
+
+func (n *protoMessageNode) Fields() []Field {
+	fields := make([]Field, len(n.fields))
+	for i := range n.fields {
+		fields[i] = &n.fields[i]
+	}
+	return fields
+}
+
+type protoField struct {
+	Node
+	desc protoreflect.FieldDescriptor
+}
+
+func (f *protoField) Name() string { return string(f.desc.Name()) }
+
+func (f *protoField) unwrap() Node { return f.Node }
+
+func (f *protoField) Value(base reflect.Value) reflect.Value {
+	return base.MapIndex(reflect.ValueOf(f.Name()))
+}
+
+// protoIsGroup returns true if values of the field are represented by a
+// parquet group, which is the case of all messages except well-known types.
+func protoIsGroup(fd protoreflect.FieldDescriptor) bool {
+	desc := protoMessageOfField(fd)
+	if desc == nil {
+		return false
+	}
+	switch desc.FullName() {
+	case protoTimestamp, protoDuration, protoStruct, protoValue, protoListValue:
+		return false
+	}
+	return !isProtoWrapper(desc)
+}
+
+// protoMessageOfField returns the descriptor of the message type of the field,
+// or nil if the field is a scalar type.
+func protoMessageOfField(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return fd.Message()
+	}
+	return nil
+}
+
+// =============================================================================
+// Deconstruction of protobuf messages into parquet rows.
+//
+// The functions follow the same model than the deconstructFunc functions in
+// row.go, but operate on protoreflect values instead of reflect.Value.
+// =============================================================================
+
+type protoDeconstructFunc func([][]Value, levels, protoreflect.Message) error
+
+type protoDeconstructValueFunc func([][]Value, levels, protoreflect.Value) error
+
+//go:noinline
+func protoDeconstructFuncOfMessage(columnIndex int16, desc protoreflect.MessageDescriptor) (int16, protoDeconstructFunc) {
+	fields := desc.Fields()
+	funcs := make([]protoDeconstructFunc, fields.Len())
+	for i := range funcs {
+		columnIndex, funcs[i] = protoDeconstructFuncOfField(columnIndex, fields.Get(i))
+	}
+	return columnIndex, func(columns [][]Value, levels levels, m protoreflect.Message) error {
+		for _, f := range funcs {
+			if err := f(columns, levels, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func protoDeconstructFuncOfField(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructFunc) {
+	switch {
+	case fd.IsMap():
+		return protoDeconstructFuncOfMap(columnIndex, fd)
+	case fd.IsList():
+		return protoDeconstructFuncOfList(columnIndex, fd)
+	case fd.HasPresence():
+		return protoDeconstructFuncOfOptional(columnIndex, fd)
+	default:
+		return protoDeconstructFuncOfRequired(columnIndex, fd)
+	}
+}
+
+//go:noinline
+func protoDeconstructFuncOfRequired(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructFunc) {
+	columnIndex, deconstruct := protoDeconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(columns [][]Value, levels levels, m protoreflect.Message) error {
+		v := protoreflect.Value{}
+		if m != nil {
+			v = m.Get(fd)
+		}
+		return deconstruct(columns, levels, v)
+	}
+}
+
+//go:noinline
+func protoDeconstructFuncOfOptional(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructFunc) {
+	columnIndex, deconstruct := protoDeconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(columns [][]Value, levels levels, m protoreflect.Message) error {
+		v := protoreflect.Value{}
+		if m != nil && m.Has(fd) {
+			v = m.Get(fd)
+			levels.definitionLevel++
+		}
+		return deconstruct(columns, levels, v)
+	}
+}
+
+//go:noinline
+func protoDeconstructFuncOfList(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructFunc) {
+	columnIndex, deconstruct := protoDeconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(columns [][]Value, levels levels, m protoreflect.Message) error {
+		var list protoreflect.List
+		if m != nil {
+			list = m.Get(fd).List()
+		}
+		if list == nil || list.Len() == 0 {
+			return deconstruct(columns, levels, protoreflect.Value{})
+		}
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		for i, n := 0, list.Len(); i < n; i++ {
+			if err := deconstruct(columns, levels, list.Get(i)); err != nil {
+				return err
+			}
+			levels.repetitionLevel = levels.repetitionDepth
+		}
+		return nil
+	}
+}
+
+//go:noinline
+func protoDeconstructFuncOfMap(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructFunc) {
+	columnIndex, deconstructKey := protoDeconstructFuncOfValue(columnIndex, fd.MapKey())
+	columnIndex, deconstructValue := protoDeconstructFuncOfValue(columnIndex, fd.MapValue())
+	return columnIndex, func(columns [][]Value, levels levels, m protoreflect.Message) error {
+		var entries protoreflect.Map
+		if m != nil {
+			entries = m.Get(fd).Map()
+		}
+		if entries == nil || entries.Len() == 0 {
+			if err := deconstructKey(columns, levels, protoreflect.Value{}); err != nil {
+				return err
+			}
+			return deconstructValue(columns, levels, protoreflect.Value{})
+		}
+
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		// Iteration over protobuf maps is randomized, keys are sorted so the
+		// same message always produces the same parquet row.
+		keys := make([]protoreflect.MapKey, 0, entries.Len())
+		entries.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool {
+			return protoMapKeyLess(keys[i], keys[j])
+		})
+
+		for _, k := range keys {
+			if err := deconstructKey(columns, levels, k.Value()); err != nil {
+				return err
+			}
+			if err := deconstructValue(columns, levels, entries.Get(k)); err != nil {
+				return err
+			}
+			levels.repetitionLevel = levels.repetitionDepth
+		}
+		return nil
+	}
+}
+
+func protoMapKeyLess(k1, k2 protoreflect.MapKey) bool {
+	switch v1 := k1.Interface().(type) {
+	case bool:
+		return !v1 && k2.Bool()
+	case int32, int64:
+		return k1.Int() < k2.Int()
+	case uint32, uint64:
+		return k1.Uint() < k2.Uint()
+	default:
+		return k1.String() < k2.String()
+	}
+}
+
+func protoDeconstructFuncOfValue(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructValueFunc) {
+	if protoIsGroup(fd) {
+		columnIndex, deconstruct := protoDeconstructFuncOfMessage(columnIndex, fd.Message())
+		return columnIndex, func(columns [][]Value, levels levels, value protoreflect.Value) error {
+			var m protoreflect.Message
+			if value.IsValid() {
+				m = value.Message()
+			}
+			return deconstruct(columns, levels, m)
+		}
+	}
+	return protoDeconstructFuncOfLeaf(columnIndex, fd)
+}
+
+//go:noinline
+func protoDeconstructFuncOfLeaf(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoDeconstructValueFunc) {
+	if columnIndex > MaxColumnIndex {
+		panic("row cannot be deconstructed because it has more than 127 columns")
+	}
+	valueOf := protoValueFuncOf(fd)
+	valueColumnIndex := ^columnIndex
+	return columnIndex + 1, func(columns [][]Value, levels levels, value protoreflect.Value) error {
+		v := Value{}
+
+		if value.IsValid() {
+			var err error
+			if v, err = valueOf(value); err != nil {
+				return fmt.Errorf("converting protobuf field %s to parquet: %w", fd.FullName(), err)
+			}
+		}
+
+		v.repetitionLevel = levels.repetitionLevel
+		v.definitionLevel = levels.definitionLevel
+		v.columnIndex = valueColumnIndex
+
+		columns[columnIndex] = append(columns[columnIndex], v)
+		return nil
+	}
+}
+
+// protoMaxSeconds is the bound of the seconds of valid durations, timestamps
+// are between the years 1 and 9999 which is a smaller range.
+const protoMaxSeconds = 315576000000
+
+// protoMicros returns the number of microseconds of a timestamp or duration.
+// An error is returned if the message is invalid because the seconds are out
+// of range, like protojson.Marshal does for those messages.
+func protoMicros(seconds, nanos int64) (int64, error) {
+	if seconds < -protoMaxSeconds || seconds > protoMaxSeconds {
+		return 0, fmt.Errorf("seconds out of range: %d", seconds)
+	}
+	return seconds*1e6 + nanos/1e3, nil
+}
+
+type protoValueFunc func(protoreflect.Value) (Value, error)
+
+func protoValueFuncOf(fd protoreflect.FieldDescriptor) protoValueFunc {
+	if desc := protoMessageOfField(fd); desc != nil {
+		switch desc.FullName() {
+		case protoTimestamp, protoDuration:
+			seconds, nanos := desc.Fields().ByNumber(1), desc.Fields().ByNumber(2)
+			return func(v protoreflect.Value) (Value, error) {
+				m := v.Message()
+				micros, err := protoMicros(m.Get(seconds).Int(), m.Get(nanos).Int())
+				return makeValueInt64(micros), err
+			}
+		case protoStruct, protoValue, protoListValue:
+			return func(v protoreflect.Value) (Value, error) {
+				b, err := protojson.Marshal(v.Message().Interface())
+				if err != nil {
+					return Value{}, err
+				}
+				return makeValueBytes(ByteArray, b), nil
+			}
+		default: // wrappers
+			field := desc.Fields().ByNumber(1)
+			valueOf := protoValueFuncOf(field)
+			return func(v protoreflect.Value) (Value, error) {
+				return valueOf(v.Message().Get(field))
+			}
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueBoolean(v.Bool()), nil }
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueInt32(int32(v.Int())), nil }
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueInt64(v.Int()), nil }
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueUint32(uint32(v.Uint())), nil }
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueUint64(v.Uint()), nil }
+	case protoreflect.FloatKind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueFloat(float32(v.Float())), nil }
+	case protoreflect.DoubleKind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueDouble(v.Float()), nil }
+	case protoreflect.StringKind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueString(ByteArray, v.String()), nil }
+	case protoreflect.BytesKind:
+		return func(v protoreflect.Value) (Value, error) { return makeValueBytes(ByteArray, v.Bytes()), nil }
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		return func(v protoreflect.Value) (Value, error) {
+			if ev := values.ByNumber(v.Enum()); ev != nil {
+				return makeValueString(ByteArray, string(ev.Name())), nil
+			}
+			// Unknown enum values are preserved using their numeric
+			// representation so they can be restored when reading.
+			return makeValueString(ByteArray, strconv.Itoa(int(v.Enum()))), nil
+		}
+	default:
+		panic("cannot create parquet value from protobuf field of kind " + fd.Kind().String())
+	}
+}
+
+// =============================================================================
+// Reconstruction of protobuf messages from parquet rows.
+// =============================================================================
+
+type protoReconstructFunc func(protoreflect.Message, levels, [][]Value) error
+
+// protoReconstructValueFunc receives a newly allocated protobuf value which
+// message types are reconstructed into, and returns the value to assign to
+// the parent message, list, or map.
+type protoReconstructValueFunc func(protoreflect.Value, levels, [][]Value) (protoreflect.Value, error)
+
+//go:noinline
+func protoReconstructFuncOfMessage(columnIndex int16, desc protoreflect.MessageDescriptor) (int16, protoReconstructFunc) {
+	fields := desc.Fields()
+	funcs := make([]protoReconstructFunc, fields.Len())
+	columnOffsets := make([]int16, fields.Len())
+	firstColumnIndex := columnIndex
+
+	for i := range funcs {
+		columnIndex, funcs[i] = protoReconstructFuncOfField(columnIndex, fields.Get(i))
+		columnOffsets[i] = columnIndex - firstColumnIndex
+	}
+
+	return columnIndex, func(m protoreflect.Message, levels levels, columns [][]Value) error {
+		off := int16(0)
+
+		for i, f := range funcs {
+			end := columnOffsets[i]
+			if err := f(m, levels, columns[off:end:end]); err != nil {
+				return fmt.Errorf("%s → %w", fields.Get(i).Name(), err)
+			}
+			off = end
+		}
+
+		return nil
+	}
+}
+
+func protoReconstructFuncOfField(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructFunc) {
+	switch {
+	case fd.IsMap():
+		return protoReconstructFuncOfMap(columnIndex, fd)
+	case fd.IsList():
+		return protoReconstructFuncOfList(columnIndex, fd)
+	case fd.HasPresence():
+		return protoReconstructFuncOfOptional(columnIndex, fd)
+	default:
+		return protoReconstructFuncOfRequired(columnIndex, fd)
+	}
+}
+
+//go:noinline
+func protoReconstructFuncOfRequired(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructFunc) {
+	columnIndex, reconstruct := protoReconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(m protoreflect.Message, levels levels, columns [][]Value) error {
+		v, err := reconstruct(m.NewField(fd), levels, columns)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+		return nil
+	}
+}
+
+//go:noinline
+func protoReconstructFuncOfOptional(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructFunc) {
+	columnIndex, reconstruct := protoReconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(m protoreflect.Message, levels levels, columns [][]Value) error {
+		levels.definitionLevel++
+
+		if columns[0][0].definitionLevel < levels.definitionLevel {
+			return nil
+		}
+
+		v, err := reconstruct(m.NewField(fd), levels, columns)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+		return nil
+	}
+}
+
+// protoRepeatedValues splits the column values in the sequence of values for
+// each element of a repeated field, calling do for each of them.
+func protoRepeatedValues(levels levels, columns [][]Value, do func([][]Value) error) error {
+	values := make([][]Value, len(columns))
+	column := columns[0]
+	n := 0
+
+	for i, column := range columns {
+		values[i] = column[0:0:len(column)]
+	}
+
+	for i := 0; i < len(column); {
+		i++
+		n++
+
+		for i < len(column) && column[i].repetitionLevel > levels.repetitionDepth {
+			i++
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j, column := range values {
+			column = column[:cap(column)]
+			if len(column) == 0 {
+				continue
+			}
+
+			k := 1
+			for k < len(column) && column[k].repetitionLevel > levels.repetitionDepth {
+				k++
+			}
+
+			values[j] = column[:k]
+		}
+
+		if err := do(values); err != nil {
+			return err
+		}
+
+		for j, column := range values {
+			values[j] = column[len(column):len(column):cap(column)]
+		}
+	}
+
+	return nil
+}
+
+//go:noinline
+func protoReconstructFuncOfList(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructFunc) {
+	columnIndex, reconstruct := protoReconstructFuncOfValue(columnIndex, fd)
+	return columnIndex, func(m protoreflect.Message, levels levels, columns [][]Value) error {
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		if columns[0][0].definitionLevel < levels.definitionLevel {
+			return nil
+		}
+
+		list := m.Mutable(fd).List()
+		return protoRepeatedValues(levels, columns, func(values [][]Value) error {
+			v, err := reconstruct(list.NewElement(), levels, values)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+			levels.repetitionLevel = levels.repetitionDepth
+			return nil
+		})
+	}
+}
+
+//go:noinline
+func protoReconstructFuncOfMap(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructFunc) {
+	columnIndex, reconstructKey := protoReconstructFuncOfValue(columnIndex, fd.MapKey())
+	columnIndex, reconstructValue := protoReconstructFuncOfValue(columnIndex, fd.MapValue())
+	return columnIndex, func(m protoreflect.Message, levels levels, columns [][]Value) error {
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		if columns[0][0].definitionLevel < levels.definitionLevel {
+			return nil
+		}
+
+		entries := m.Mutable(fd).Map()
+		return protoRepeatedValues(levels, columns, func(values [][]Value) error {
+			k, err := reconstructKey(protoreflect.Value{}, levels, values[:1])
+			if err != nil {
+				return fmt.Errorf("key → %w", err)
+			}
+			v, err := reconstructValue(entries.NewValue(), levels, values[1:])
+			if err != nil {
+				return fmt.Errorf("value → %w", err)
+			}
+			entries.Set(k.MapKey(), v)
+			levels.repetitionLevel = levels.repetitionDepth
+			return nil
+		})
+	}
+}
+
+func protoReconstructFuncOfValue(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructValueFunc) {
+	if protoIsGroup(fd) {
+		columnIndex, reconstruct := protoReconstructFuncOfMessage(columnIndex, fd.Message())
+		return columnIndex, func(value protoreflect.Value, levels levels, columns [][]Value) (protoreflect.Value, error) {
+			return value, reconstruct(value.Message(), levels, columns)
+		}
+	}
+	return protoReconstructFuncOfLeaf(columnIndex, fd)
+}
+
+//go:noinline
+func protoReconstructFuncOfLeaf(columnIndex int16, fd protoreflect.FieldDescriptor) (int16, protoReconstructValueFunc) {
+	assign := protoAssignFuncOf(fd)
+	return columnIndex + 1, func(value protoreflect.Value, _ levels, columns [][]Value) (protoreflect.Value, error) {
+		column := columns[0]
+		if len(column) == 0 {
+			return value, fmt.Errorf("no values found in parquet row for column %d", columnIndex)
+		}
+		return assign(value, column[0])
+	}
+}
+
+func protoAssignFuncOf(fd protoreflect.FieldDescriptor) func(protoreflect.Value, Value) (protoreflect.Value, error) {
+	if desc := protoMessageOfField(fd); desc != nil {
+		switch desc.FullName() {
+		case protoTimestamp:
+			seconds, nanos := desc.Fields().ByNumber(1), desc.Fields().ByNumber(2)
+			return func(dst protoreflect.Value, src Value) (protoreflect.Value, error) {
+				m := dst.Message()
+				t := time.UnixMicro(src.int64())
+				m.Set(seconds, protoreflect.ValueOfInt64(t.Unix()))
+				m.Set(nanos, protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+				return dst, nil
+			}
+		case protoDuration:
+			seconds, nanos := desc.Fields().ByNumber(1), desc.Fields().ByNumber(2)
+			return func(dst protoreflect.Value, src Value) (protoreflect.Value, error) {
+				m := dst.Message()
+				micros := src.int64()
+				m.Set(seconds, protoreflect.ValueOfInt64(micros/1e6))
+				m.Set(nanos, protoreflect.ValueOfInt32(int32(micros%1e6)*1e3))
+				return dst, nil
+			}
+		case protoStruct, protoValue, protoListValue:
+			return func(dst protoreflect.Value, src Value) (protoreflect.Value, error) {
+				return dst, protojson.Unmarshal(src.byteArray(), dst.Message().Interface())
+			}
+		default: // wrappers
+			field := desc.Fields().ByNumber(1)
+			assign := protoAssignFuncOf(field)
+			return func(dst protoreflect.Value, src Value) (protoreflect.Value, error) {
+				m := dst.Message()
+				v, err := assign(protoreflect.Value{}, src)
+				if err != nil {
+					return dst, err
+				}
+				m.Set(field, v)
+				return dst, nil
+			}
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBool(v.boolean()), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfInt32(v.int32()), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfInt64(v.int64()), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfUint32(v.uint32()), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfUint64(v.uint64()), nil
+		}
+	case protoreflect.FloatKind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfFloat32(v.float()), nil
+		}
+	case protoreflect.DoubleKind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfFloat64(v.double()), nil
+		}
+	case protoreflect.StringKind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfString(string(v.byteArray())), nil
+		}
+	case protoreflect.BytesKind:
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBytes(copyBytes(v.byteArray())), nil
+		}
+	case protoreflect.EnumKind:
+		enum := fd.Enum()
+		values := enum.Values()
+		return func(_ protoreflect.Value, v Value) (protoreflect.Value, error) {
+			name := v.string()
+			if ev := values.ByName(protoreflect.Name(name)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+			n, err := strconv.ParseInt(name, 10, 32)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("%q is not a value of the protobuf enum %s", name, enum.FullName())
+			}
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+	default:
+		panic("cannot assign parquet value to protobuf field of kind " + fd.Kind().String())
+	}
+}
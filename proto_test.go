@@ -0,0 +1,394 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// eventDescriptor builds the descriptor of the following message:
+//
+//	enum Level { DEBUG = 0; INFO = 1; ERROR = 2; }
+//
+//	message Event {
+//	  message Tag { string key = 1; repeated string values = 2; }
+//	  int64 id = 1;
+//	  Level level = 2;
+//	  google.protobuf.Timestamp time = 3;
+//	  google.protobuf.Duration elapsed = 4;
+//	  google.protobuf.StringValue user = 5;
+//	  repeated Tag tags = 6;
+//	  map<string, int32> counters = 7;
+//	  oneof payload { string text = 8; bytes data = 9; }
+//	  google.protobuf.Struct extra = 10;
+//	  optional double score = 11;
+//	}
+func eventDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	const (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	)
+
+	text := field("text", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, "")
+	text.OneofIndex = proto.Int32(0)
+	data := field("data", 9, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional, "")
+	data.OneofIndex = proto.Int32(0)
+	score := field("score", 11, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional, "")
+	score.OneofIndex = proto.Int32(1)
+	score.Proto3Optional = proto.Bool(true)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("event.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/protobuf/timestamp.proto",
+			"google/protobuf/duration.proto",
+			"google/protobuf/wrappers.proto",
+			"google/protobuf/struct.proto",
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Level"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("DEBUG"), Number: proto.Int32(0)},
+				{Name: proto.String("INFO"), Number: proto.Int32(1)},
+				{Name: proto.String("ERROR"), Number: proto.Int32(2)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				field("level", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".test.Level"),
+				field("time", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+				field("elapsed", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Duration"),
+				field("user", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.StringValue"),
+				field("tags", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Event.Tag"),
+				field("counters", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Event.CountersEntry"),
+				text,
+				data,
+				field("extra", 10, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Struct"),
+				score,
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{
+				{Name: proto.String("payload")},
+				{Name: proto.String("_score")},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Tag"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("values", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+					},
+				},
+				{
+					Name: proto.String("CountersEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+		}},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages().ByName("Event")
+}
+
+func TestSchemaOfProto(t *testing.T) {
+	schema := parquet.SchemaOfProto(eventDescriptor(t))
+
+	const expected = `message Event {
+	required int64 id (INT(64,true));
+	required binary level (ENUM);
+	optional int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	optional int64 elapsed (INT(64,true));
+	optional binary user (STRING);
+	repeated group tags {
+		required binary key (STRING);
+		repeated binary values (STRING);
+	}
+	required group counters (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			required int32 value (INT(32,true));
+		}
+	}
+	optional binary text (STRING);
+	optional binary data;
+	optional binary extra (JSON);
+	optional double score;
+}`
+
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+func TestSchemaOfProtoRecursive(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic when creating a schema from a recursive message")
+		}
+	}()
+	// google.protobuf.DescriptorProto references itself via nested_type.
+	parquet.SchemaOfProto((&descriptorpb.DescriptorProto{}).ProtoReflect().Descriptor())
+}
+
+func TestProtoDynamicMessages(t *testing.T) {
+	desc := eventDescriptor(t)
+	schema := parquet.SchemaOfProto(desc)
+	fields := desc.Fields()
+
+	newEvent := func(do func(m *dynamicpb.Message)) *dynamicpb.Message {
+		m := dynamicpb.NewMessage(desc)
+		do(m)
+		return m
+	}
+
+	extra, _ := structpb.NewStruct(map[string]interface{}{"answer": 42.0})
+
+	events := []*dynamicpb.Message{
+		newEvent(func(m *dynamicpb.Message) {
+			m.Set(fields.ByName("id"), protoreflect.ValueOfInt64(1))
+			m.Set(fields.ByName("level"), protoreflect.ValueOfEnum(2))
+			m.Set(fields.ByName("time"), protoreflect.ValueOfMessage(timestamppb.New(time.Unix(1234, 5678000)).ProtoReflect()))
+			m.Set(fields.ByName("elapsed"), protoreflect.ValueOfMessage(durationpb.New(-1500*time.Millisecond).ProtoReflect()))
+			m.Set(fields.ByName("user"), protoreflect.ValueOfMessage(wrapperspb.String("Luke").ProtoReflect()))
+			m.Set(fields.ByName("text"), protoreflect.ValueOfString("hello"))
+			m.Set(fields.ByName("extra"), protoreflect.ValueOfMessage(extra.ProtoReflect()))
+			m.Set(fields.ByName("score"), protoreflect.ValueOfFloat64(0))
+
+			tags := m.Mutable(fields.ByName("tags")).List()
+			for _, key := range []string{"a", "b"} {
+				tag := tags.NewElement()
+				tagFields := tag.Message().Descriptor().Fields()
+				tag.Message().Set(tagFields.ByName("key"), protoreflect.ValueOfString(key))
+				values := tag.Message().Mutable(tagFields.ByName("values")).List()
+				values.Append(protoreflect.ValueOfString(key + "1"))
+				values.Append(protoreflect.ValueOfString(key + "2"))
+				tags.Append(tag)
+			}
+
+			counters := m.Mutable(fields.ByName("counters")).Map()
+			counters.Set(protoreflect.ValueOfString("x").MapKey(), protoreflect.ValueOfInt32(1))
+			counters.Set(protoreflect.ValueOfString("y").MapKey(), protoreflect.ValueOfInt32(2))
+		}),
+
+		newEvent(func(m *dynamicpb.Message) {
+			m.Set(fields.ByName("id"), protoreflect.ValueOfInt64(2))
+			m.Set(fields.ByName("data"), protoreflect.ValueOfBytes([]byte("world")))
+			m.Set(fields.ByName("user"), protoreflect.ValueOfMessage(wrapperspb.String("").ProtoReflect()))
+		}),
+
+		newEvent(func(m *dynamicpb.Message) {}),
+	}
+
+	buf := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[*dynamicpb.Message](buf, schema)
+	if _, err := w.Write(events); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := parquet.NewGenericReader[*dynamicpb.Message](bytes.NewReader(buf.Bytes()), schema)
+	found := make([]*dynamicpb.Message, len(events))
+	n, err := r.Read(found)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if n != len(events) {
+		t.Fatalf("wrong number of messages read: want=%d got=%d", len(events), n)
+	}
+
+	for i := range events {
+		if !proto.Equal(events[i], found[i]) {
+			t.Errorf("message at index %d mismatch:\nwant = %v\ngot  = %v", i, events[i], found[i])
+		}
+	}
+}
+
+func TestProtoTimestampDurationRange(t *testing.T) {
+	desc := eventDescriptor(t)
+	schema := parquet.SchemaOfProto(desc)
+	fields := desc.Fields()
+
+	tests := []struct {
+		time, elapsed         proto.Message
+		wantTime, wantElapsed proto.Message
+	}{
+		{
+			time:    timestamppb.New(time.Time{}),
+			elapsed: &durationpb.Duration{Seconds: 315576000000, Nanos: 999999000},
+		},
+		{
+			time:    timestamppb.New(time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)),
+			elapsed: &durationpb.Duration{Seconds: -315576000000, Nanos: -999999000},
+		},
+		{
+			time:        &timestamppb.Timestamp{Seconds: -1, Nanos: 1999},
+			elapsed:     &durationpb.Duration{Seconds: -1, Nanos: -1999},
+			wantTime:    &timestamppb.Timestamp{Seconds: -1, Nanos: 1000},
+			wantElapsed: &durationpb.Duration{Seconds: -1, Nanos: -1000},
+		},
+	}
+
+	events := make([]*dynamicpb.Message, len(tests))
+	for i, test := range tests {
+		events[i] = dynamicpb.NewMessage(desc)
+		events[i].Set(fields.ByName("time"), protoreflect.ValueOfMessage(test.time.ProtoReflect()))
+		events[i].Set(fields.ByName("elapsed"), protoreflect.ValueOfMessage(test.elapsed.ProtoReflect()))
+	}
+
+	buf := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[*dynamicpb.Message](buf, schema)
+	if _, err := w.Write(events); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := parquet.NewGenericReader[*dynamicpb.Message](bytes.NewReader(buf.Bytes()), schema)
+	found := make([]*dynamicpb.Message, len(events))
+	if n, err := r.Read(found); n != len(events) {
+		t.Fatalf("wrong number of messages read: want=%d got=%d (%v)", len(events), n, err)
+	}
+
+	for i, test := range tests {
+		wantTime, wantElapsed := test.wantTime, test.wantElapsed
+		if wantTime == nil {
+			wantTime, wantElapsed = test.time, test.elapsed
+		}
+		if got := found[i].Get(fields.ByName("time")).Message().Interface(); !proto.Equal(got, wantTime) {
+			t.Errorf("time mismatch at index %d: want=%v got=%v", i, wantTime, got)
+		}
+		if got := found[i].Get(fields.ByName("elapsed")).Message().Interface(); !proto.Equal(got, wantElapsed) {
+			t.Errorf("elapsed mismatch at index %d: want=%v got=%v", i, wantElapsed, got)
+		}
+	}
+}
+
+func TestProtoInvalidMessages(t *testing.T) {
+	desc := eventDescriptor(t)
+	schema := parquet.SchemaOfProto(desc)
+	fields := desc.Fields()
+
+	tests := []struct {
+		scenario string
+		field    protoreflect.Name
+		value    proto.Message
+	}{
+		{
+			scenario: "timestamp out of range",
+			field:    "time",
+			value:    &timestamppb.Timestamp{Seconds: math.MaxInt64},
+		},
+		{
+			scenario: "duration out of range",
+			field:    "elapsed",
+			value:    &durationpb.Duration{Seconds: -315576000001},
+		},
+		{
+			scenario: "struct with a NaN number",
+			field:    "extra",
+			value:    &structpb.Struct{Fields: map[string]*structpb.Value{"nan": structpb.NewNumberValue(math.NaN())}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			m := dynamicpb.NewMessage(desc)
+			m.Set(fields.ByName(test.field), protoreflect.ValueOfMessage(test.value.ProtoReflect()))
+
+			w := parquet.NewGenericWriter[*dynamicpb.Message](new(bytes.Buffer), schema)
+			if n, err := w.Write([]*dynamicpb.Message{m}); err == nil {
+				t.Errorf("expected an error but %d message(s) were written", n)
+			}
+		})
+	}
+}
+
+func TestProtoGeneratedMessages(t *testing.T) {
+	types := []*typepb.Type{
+		{
+			Name: "Point",
+			Fields: []*typepb.Field{
+				{Kind: typepb.Field_TYPE_DOUBLE, Cardinality: typepb.Field_CARDINALITY_OPTIONAL, Number: 1, Name: "x"},
+				{Kind: typepb.Field_TYPE_DOUBLE, Cardinality: typepb.Field_CARDINALITY_OPTIONAL, Number: 2, Name: "y"},
+			},
+			Syntax: typepb.Syntax_SYNTAX_PROTO3,
+		},
+		{
+			Name:          "Empty",
+			Oneofs:        []string{"a", "b"},
+			SourceContext: nil,
+			Syntax:        typepb.Syntax_SYNTAX_PROTO2,
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[*typepb.Type](buf)
+	if _, err := w.Write(types); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := parquet.NewGenericReader[*typepb.Type](bytes.NewReader(buf.Bytes()))
+	found := make([]*typepb.Type, len(types))
+	n, err := r.Read(found)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if n != len(types) {
+		t.Fatalf("wrong number of messages read: want=%d got=%d", len(types), n)
+	}
+
+	for i := range types {
+		if !proto.Equal(types[i], found[i]) {
+			t.Errorf("message at index %d mismatch:\nwant = %v\ngot  = %v", i, types[i], found[i])
+		}
+	}
+}
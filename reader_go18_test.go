@@ -290,3 +290,51 @@ func benchmarkGenericReader[Row generator[Row]](b *testing.B) {
 		})
 	})
 }
+
+func TestGenericReaderLegacyNestedTypes(t *testing.T) {
+	t.Run("list with non-standard element name", func(t *testing.T) {
+		type Row struct {
+			Int64List []*int64 `parquet:"int64_list,list"`
+			UTF8List  []string `parquet:"utf8_list"`
+		}
+
+		rows, err := parquet.ReadFile[Row]("testdata/list_columns.parquet")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []Row{
+			{Int64List: []*int64{newInt64(1), newInt64(2), newInt64(3)}, UTF8List: []string{"abc", "efg", "hij"}},
+			{Int64List: []*int64{nil, newInt64(1)}, UTF8List: []string{}},
+			{Int64List: []*int64{newInt64(4)}, UTF8List: []string{"efg", "", "hij", "xyz"}},
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("rows mismatch:\nwant: %+v\ngot:  %+v", want, rows)
+		}
+	})
+
+	t.Run("map with repeated group annotated MAP_KEY_VALUE", func(t *testing.T) {
+		type Row struct {
+			ID       int64             `parquet:"id"`
+			IntArray []int32           `parquet:"int_array"`
+			IntMap   map[string]*int32 `parquet:"int_map"`
+		}
+
+		rows, err := parquet.ReadFile[Row]("testdata/nullable.impala.parquet")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 7 {
+			t.Fatalf("wrong number of rows: want=7 got=%d", len(rows))
+		}
+
+		one, two, hundred := int32(1), int32(2), int32(100)
+		want := []Row{
+			{ID: 1, IntArray: []int32{1, 2, 3}, IntMap: map[string]*int32{"k1": &one, "k2": &hundred}},
+			{ID: 2, IntArray: []int32{0, 1, 2, 0, 3, 0}, IntMap: map[string]*int32{"k1": &two, "k2": nil}},
+		}
+		if !reflect.DeepEqual(rows[:2], want) {
+			t.Errorf("rows mismatch:\nwant: %+v\ngot:  %+v", want, rows[:2])
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package parquet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/encoding/plain"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type countingCodec struct {
+	compress.Codec
+	encode int
+	decode int
+}
+
+func (c *countingCodec) String() string { return "COUNTING" }
+
+func (c *countingCodec) Encode(dst, src []byte) ([]byte, error) {
+	c.encode++
+	return c.Codec.Encode(dst, src)
+}
+
+func (c *countingCodec) Decode(dst, src []byte) ([]byte, error) {
+	c.decode++
+	return c.Codec.Decode(dst, src)
+}
+
+// The alias prevents the embedded field from hiding the Encoding method.
+type plainEncoding = plain.Encoding
+
+type countingEncoding struct {
+	plainEncoding
+	encode int
+	decode int
+}
+
+func (e *countingEncoding) String() string { return "COUNTING_PLAIN" }
+
+func (e *countingEncoding) EncodeInt64(dst []byte, src []int64) ([]byte, error) {
+	e.encode++
+	return e.plainEncoding.EncodeInt64(dst, src)
+}
+
+func (e *countingEncoding) DecodeInt64(dst []int64, src []byte) ([]int64, error) {
+	e.decode++
+	return e.plainEncoding.DecodeInt64(dst, src)
+}
+
+func registerTestCompressionCodec(t *testing.T, codec compress.Codec) {
+	RegisterCompressionCodec(codec)
+	t.Cleanup(func() {
+		registeredCompressionCodecsMutex.Lock()
+		defer registeredCompressionCodecsMutex.Unlock()
+		codecs := make(map[format.CompressionCodec]compress.Codec)
+		for code, c := range loadRegisteredCompressionCodecs() {
+			if code != codec.CompressionCodec() {
+				codecs[code] = c
+			}
+		}
+		registeredCompressionCodecs.Store(codecs)
+	})
+}
+
+func registerTestEncoding(t *testing.T, enc *countingEncoding) {
+	RegisterEncoding(enc)
+	t.Cleanup(func() {
+		registeredEncodingsMutex.Lock()
+		defer registeredEncodingsMutex.Unlock()
+		encodings := make(map[format.Encoding]encoding.Encoding)
+		for code, e := range loadRegisteredEncodings() {
+			if code != enc.Encoding() {
+				encodings[code] = e
+			}
+		}
+		registeredEncodings.Store(encodings)
+	})
+}
+
+func TestRegisterCompressionCodec(t *testing.T) {
+	codec := &countingCodec{Codec: &Snappy}
+	registerTestCompressionCodec(t, codec)
+
+	if c := LookupCompressionCodec(format.Snappy); c != codec {
+		t.Fatalf("registered codec not returned by lookup: %s", c)
+	}
+	if c := LookupCompressionCodec(format.Gzip); c != &Gzip {
+		t.Fatalf("built-in codec not returned by lookup: %s", c)
+	}
+
+	type rowType struct {
+		A int64 `parquet:"a,counting"`
+		B int64 `parquet:"b,snappy"`
+	}
+	for _, column := range []string{"a", "b"} {
+		leaf, _ := SchemaOf(rowType{}).Lookup(column)
+		if c := leaf.Node.Compression(); c != codec {
+			t.Errorf("column %q does not use the registered codec: %s", column, c)
+		}
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := NewWriter(buffer, SchemaOf(rowType{}))
+	if err := writer.Write(rowType{A: 1, B: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if codec.encode == 0 {
+		t.Error("registered codec was not used to compress pages")
+	}
+
+	f, err := OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got rowType
+	if err := NewReader(f).Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != (rowType{A: 1, B: 2}) {
+		t.Errorf("row mismatch: %+v", got)
+	}
+	if codec.decode == 0 {
+		t.Error("registered codec was not used to decompress pages")
+	}
+}
+
+func TestRegisterEncoding(t *testing.T) {
+	enc := new(countingEncoding)
+	registerTestEncoding(t, enc)
+
+	if e := LookupEncoding(format.Plain); e != encoding.Encoding(enc) {
+		t.Fatalf("registered encoding not returned by lookup: %s", e)
+	}
+
+	type rowType struct {
+		A int64 `parquet:"a,counting_plain"`
+	}
+	leaf, _ := SchemaOf(rowType{}).Lookup("a")
+	if e := leaf.Node.Encoding(); e != encoding.Encoding(enc) {
+		t.Errorf("column does not use the registered encoding: %s", e)
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := NewWriter(buffer, SchemaOf(rowType{}))
+	if err := writer.Write(rowType{A: 42}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if enc.encode == 0 {
+		t.Error("registered encoding was not used to encode pages")
+	}
+
+	var got rowType
+	if err := NewReader(bytes.NewReader(buffer.Bytes())).Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.A != 42 {
+		t.Errorf("value mismatch: %d", got.A)
+	}
+	if enc.decode == 0 {
+		t.Error("registered encoding was not used to decode pages")
+	}
+}
+
+// renamedCodec gives a registered codec the name of another codec.
+type renamedCodec struct {
+	compress.Codec
+	name string
+}
+
+func (c *renamedCodec) String() string { return c.name }
+
+func TestRegisterDuplicateNames(t *testing.T) {
+	registerTestCompressionCodec(t, &countingCodec{Codec: &Snappy})
+	registerTestEncoding(t, new(countingEncoding))
+
+	// Registering again for the same code replaces the previous entry.
+	registerTestCompressionCodec(t, &countingCodec{Codec: &Snappy})
+	registerTestEncoding(t, new(countingEncoding))
+
+	t.Run("codec", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("registering a codec with a duplicate name did not panic")
+			}
+		}()
+		RegisterCompressionCodec(&renamedCodec{Codec: &Gzip, name: "counting"})
+	})
+
+	t.Run("encoding", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("registering an encoding with a duplicate name did not panic")
+			}
+		}()
+		RegisterEncoding(&renamedEncoding{encodingInterface: &DeltaBinaryPacked, name: "Counting_Plain"})
+	})
+
+	if c := LookupCompressionCodec(format.Gzip); c != &Gzip {
+		t.Errorf("codec registered despite the duplicate name: %s", c)
+	}
+	if e := LookupEncoding(format.DeltaBinaryPacked); e != encoding.Encoding(&DeltaBinaryPacked) {
+		t.Errorf("encoding registered despite the duplicate name: %s", e)
+	}
+}
+
+type encodingInterface = encoding.Encoding
+
+type renamedEncoding struct {
+	encodingInterface
+	name string
+}
+
+func (e *renamedEncoding) String() string { return e.name }
+
+func TestUnknownTagOption(t *testing.T) {
+	type rowType struct {
+		A int64 `parquet:"a,notacodec"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown tag option did not panic")
+		}
+	}()
+	schemaOf(reflect.TypeOf(rowType{}))
+}
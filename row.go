@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"time"
 )
 
 const (
@@ -404,8 +405,9 @@ type levels struct {
 // deconstructFunc accepts a row, the current levels, the value to deserialize
 // the current column onto, and returns the row minus the deserialied value(s)
 // It recurses until it hits a leaf node, then deserializes that value
-// individually as the base case.
-type deconstructFunc func([][]Value, levels, reflect.Value)
+// individually as the base case. An error is returned if a Go value cannot be
+// converted to parquet values, for example when a marshaler fails.
+type deconstructFunc func([][]Value, levels, reflect.Value) error
 
 func deconstructFuncOf(columnIndex int16, node Node) (int16, deconstructFunc) {
 	switch {
@@ -425,9 +427,16 @@ func deconstructFuncOf(columnIndex int16, node Node) (int16, deconstructFunc) {
 //go:noinline
 func deconstructFuncOfOptional(columnIndex int16, node Node) (int16, deconstructFunc) {
 	columnIndex, deconstruct := deconstructFuncOf(columnIndex, Required(node))
-	return columnIndex, func(columns [][]Value, levels levels, value reflect.Value) {
+	return columnIndex, func(columns [][]Value, levels levels, value reflect.Value) error {
 		if value.IsValid() {
-			if value.IsZero() {
+			if value.Kind() == reflect.Struct && isNullSQLType(value.Type()) {
+				var valid bool
+				if value, valid = nullSQLValue(value); valid {
+					levels.definitionLevel++
+				} else {
+					value = reflect.Value{}
+				}
+			} else if value.IsZero() {
 				value = reflect.Value{}
 			} else {
 				if value.Kind() == reflect.Ptr {
@@ -436,30 +445,39 @@ func deconstructFuncOfOptional(columnIndex int16, node Node) (int16, deconstruct
 				levels.definitionLevel++
 			}
 		}
-		deconstruct(columns, levels, value)
+		return deconstruct(columns, levels, value)
 	}
 }
 
 //go:noinline
 func deconstructFuncOfRepeated(columnIndex int16, node Node) (int16, deconstructFunc) {
 	columnIndex, deconstruct := deconstructFuncOf(columnIndex, Required(node))
-	return columnIndex, func(columns [][]Value, levels levels, value reflect.Value) {
+	return columnIndex, deconstructRepeated(deconstruct)
+}
+
+func deconstructRepeated(deconstruct deconstructFunc) deconstructFunc {
+	return func(columns [][]Value, levels levels, value reflect.Value) error {
 		if !value.IsValid() || value.Len() == 0 {
-			deconstruct(columns, levels, reflect.Value{})
-			return
+			return deconstruct(columns, levels, reflect.Value{})
 		}
 
 		levels.repetitionDepth++
 		levels.definitionLevel++
 
 		for i, n := 0, value.Len(); i < n; i++ {
-			deconstruct(columns, levels, value.Index(i))
+			if err := deconstruct(columns, levels, value.Index(i)); err != nil {
+				return err
+			}
 			levels.repetitionLevel = levels.repetitionDepth
 		}
+		return nil
 	}
 }
 
 func deconstructFuncOfRequired(columnIndex int16, node Node) (int16, deconstructFunc) {
+	if m := unwrapMarshalNode(node); m != nil {
+		return deconstructFuncOfMarshaler(columnIndex, m)
+	}
 	switch {
 	case node.Leaf():
 		return deconstructFuncOfLeaf(columnIndex, node)
@@ -469,7 +487,10 @@ func deconstructFuncOfRequired(columnIndex int16, node Node) (int16, deconstruct
 }
 
 func deconstructFuncOfList(columnIndex int16, node Node) (int16, deconstructFunc) {
-	return deconstructFuncOf(columnIndex, Repeated(listElementOf(node)))
+	// The element retains its repetition type so the definition level of
+	// optional elements is accounted for within the repeated list group.
+	columnIndex, deconstruct := deconstructFuncOf(columnIndex, listElementOf(node))
+	return columnIndex, deconstructRepeated(deconstruct)
 }
 
 //go:noinline
@@ -480,10 +501,9 @@ func deconstructFuncOfMap(columnIndex int16, node Node) (int16, deconstructFunc)
 	keyType := keyValueElem.Field(0).Type
 	valueType := keyValueElem.Field(1).Type
 	nextColumnIndex, deconstruct := deconstructFuncOf(columnIndex, schemaOf(keyValueElem))
-	return nextColumnIndex, func(columns [][]Value, levels levels, mapValue reflect.Value) {
+	return nextColumnIndex, func(columns [][]Value, levels levels, mapValue reflect.Value) error {
 		if !mapValue.IsValid() || mapValue.Len() == 0 {
-			deconstruct(columns, levels, reflect.Value{})
-			return
+			return deconstruct(columns, levels, reflect.Value{})
 		}
 
 		levels.repetitionDepth++
@@ -496,9 +516,12 @@ func deconstructFuncOfMap(columnIndex int16, node Node) (int16, deconstructFunc)
 		for _, key := range mapValue.MapKeys() {
 			k.Set(key.Convert(keyType))
 			v.Set(mapValue.MapIndex(key).Convert(valueType))
-			deconstruct(columns, levels, elem)
+			if err := deconstruct(columns, levels, elem); err != nil {
+				return err
+			}
 			levels.repetitionLevel = levels.repetitionDepth
 		}
+		return nil
 	}
 }
 
@@ -509,16 +532,21 @@ func deconstructFuncOfGroup(columnIndex int16, node Node) (int16, deconstructFun
 	for i, field := range fields {
 		columnIndex, funcs[i] = deconstructFuncOf(columnIndex, field)
 	}
-	return columnIndex, func(columns [][]Value, levels levels, value reflect.Value) {
+	return columnIndex, func(columns [][]Value, levels levels, value reflect.Value) error {
 		if value.IsValid() {
 			for i, f := range funcs {
-				f(columns, levels, fields[i].Value(value))
+				if err := f(columns, levels, fields[i].Value(value)); err != nil {
+					return err
+				}
 			}
 		} else {
 			for _, f := range funcs {
-				f(columns, levels, value)
+				if err := f(columns, levels, value); err != nil {
+					return err
+				}
 			}
 		}
+		return nil
 	}
 }
 
@@ -530,12 +558,22 @@ func deconstructFuncOfLeaf(columnIndex int16, node Node) (int16, deconstructFunc
 	typ := node.Type()
 	kind := typ.Kind()
 	lt := typ.LogicalType()
+	ts, _ := typ.(*timestampType)
 	valueColumnIndex := ^columnIndex
-	return columnIndex + 1, func(columns [][]Value, levels levels, value reflect.Value) {
+	return columnIndex + 1, func(columns [][]Value, levels levels, value reflect.Value) error {
 		v := Value{}
 
 		if value.IsValid() {
-			v = makeValue(kind, lt, value)
+			if ts != nil && value.Type() == reflect.TypeOf(time.Time{}) {
+				v = makeValueInt64(ts.valueOf(value.Interface().(time.Time)))
+			} else if sv, ok, err := makeStdlibValue(typ, value); ok {
+				if err != nil {
+					return fmt.Errorf("converting go value of type %s to parquet: %w", value.Type(), err)
+				}
+				v = sv
+			} else {
+				v = makeValue(kind, lt, value)
+			}
 		}
 
 		v.repetitionLevel = levels.repetitionLevel
@@ -543,6 +581,7 @@ func deconstructFuncOfLeaf(columnIndex int16, node Node) (int16, deconstructFunc
 		v.columnIndex = valueColumnIndex
 
 		columns[columnIndex] = append(columns[columnIndex], v)
+		return nil
 	}
 }
 
@@ -589,6 +628,11 @@ func reconstructFuncOfOptional(columnIndex int16, node Node) (int16, reconstruct
 			value = value.Elem()
 		}
 
+		if value.Kind() == reflect.Struct && isNullSQLType(value.Type()) {
+			value.Field(1).SetBool(true)
+			value = value.Field(0)
+		}
+
 		return reconstruct(value, levels, columns)
 	}
 }
@@ -606,7 +650,11 @@ func setMakeSlice(v reflect.Value, n int) reflect.Value {
 //go:noinline
 func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstructFunc) {
 	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, Required(node))
-	return nextColumnIndex, func(value reflect.Value, levels levels, columns [][]Value) error {
+	return nextColumnIndex, reconstructRepeated(reconstruct)
+}
+
+func reconstructRepeated(reconstruct reconstructFunc) reconstructFunc {
+	return func(value reflect.Value, levels levels, columns [][]Value) error {
 		levels.repetitionDepth++
 		levels.definitionLevel++
 
@@ -665,6 +713,9 @@ func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstruct
 }
 
 func reconstructFuncOfRequired(columnIndex int16, node Node) (int16, reconstructFunc) {
+	if m := unwrapMarshalNode(node); m != nil {
+		return reconstructFuncOfMarshaler(columnIndex, m)
+	}
 	switch {
 	case node.Leaf():
 		return reconstructFuncOfLeaf(columnIndex, node)
@@ -674,7 +725,9 @@ func reconstructFuncOfRequired(columnIndex int16, node Node) (int16, reconstruct
 }
 
 func reconstructFuncOfList(columnIndex int16, node Node) (int16, reconstructFunc) {
-	return reconstructFuncOf(columnIndex, Repeated(listElementOf(node)))
+	// See deconstructFuncOfList for why the element keeps its repetition type.
+	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, listElementOf(node))
+	return nextColumnIndex, reconstructRepeated(reconstruct)
 }
 
 //go:noinline
@@ -813,6 +866,9 @@ func reconstructFuncOfLeaf(columnIndex int16, node Node) (int16, reconstructFunc
 		if len(column) == 0 {
 			return fmt.Errorf("no values found in parquet row for column %d", columnIndex)
 		}
+		if ok, err := assignStdlibValue(typ, value, column[0]); ok {
+			return err
+		}
 		return typ.AssignValue(value, column[0])
 	}
 }
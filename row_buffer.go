@@ -151,7 +151,11 @@ func (buf *RowBuffer[T]) Rows() Rows {
 func (buf *RowBuffer[T]) Write(rows []T) (int, error) {
 	for i := range rows {
 		off := len(buf.values)
-		buf.values = buf.schema.Deconstruct(buf.values, &rows[i])
+		values, err := buf.schema.deconstructRow(buf.values, &rows[i])
+		if err != nil {
+			return i, err
+		}
+		buf.values = values
 		end := len(buf.values)
 		row := buf.values[off:end:end]
 		buf.alloc.capture(row)
@@ -11,8 +11,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/compress/zstd"
 	"github.com/segmentio/parquet-go/deprecated"
 	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
 )
 
 // Schema represents a parquet schema created from a Go value.
@@ -47,17 +49,28 @@ type Schema struct {
 //	gzip      | sets the parquet column compression codec to gzip
 //	brotli    | sets the parquet column compression codec to brotli
 //	lz4       | sets the parquet column compression codec to lz4
-//	zstd      | sets the parquet column compression codec to zstd
+//	zstd      | sets the parquet column compression codec to zstd, with an optional level (e.g. zstd(9))
 //	plain     | enables the plain encoding (no-op default)
 //	dict      | enables dictionary encoding on the parquet column
-//	delta     | enables delta encoding on the parquet column
+//	delta     | enables delta encoding on the parquet column, integers of monotonically increasing columns may use delta(monotonic)
 //	list      | for slice types, use the parquet LIST logical type
 //	enum      | for string types, use the parquet ENUM logical type
 //	uuid      | for string and [16]byte types, use the parquet UUID logical type
-//	decimal   | for int32, int64 and [n]byte types, use the parquet DECIMAL logical type
+//	interval  | for [12]byte types, use the parquet INTERVAL type (implied for CalendarInterval)
+//	decimal   | for int32, int64, [n]byte, big.Int and big.Float types, use the parquet DECIMAL logical type
 //	date      | for int32 types use the DATE logical type
 //	timestamp | for int64 types use the TIMESTAMP logical type with, by default, millisecond precision
-//	split     | for float32/float64, use the BYTE_STREAM_SPLIT encoding
+//	time      | for time.Duration types use the TIME logical type with, by default, millisecond precision
+//	string    | for net.IP and netip.Addr types, use the parquet STRING logical type
+//	geometry  | for []byte and string types holding WKB values, use the parquet GEOMETRY logical type
+//	geography | for []byte and string types holding WKB values, use the parquet GEOGRAPHY logical type
+//	split     | for float32/float64, integer and [N]byte types, use the BYTE_STREAM_SPLIT encoding
+//	id(n)     | sets the field ID of the parquet column (e.g. id(7))
+//
+// The compression codecs and encodings installed with RegisterCompressionCodec
+// and RegisterEncoding replace the built-in implementations selected by the
+// options above, and can also be selected by the lower case version of their
+// names (e.g. "lzo" for a codec named "LZO").
 //
 // # The date logical type is an int32 value of the number of days since the unix epoch
 //
@@ -68,6 +81,15 @@ type Schema struct {
 //	  TimestrampMicros int64 `parquet:"timestamp_micros,timestamp(microsecond)"
 //	}
 //
+// Timestamps are adjusted to UTC by default, the "local" argument declares
+// timestamps which represent the time of a wall clock (e.g. TIMESTAMP_NTZ
+// columns in Spark). The wall clock is read and written in UTC, use
+// LocalTimestamp to construct columns in another location. Example:
+//
+//	type Message struct {
+//	  CreatedAt time.Time `parquet:"created_at,timestamp(microsecond,local)"
+//	}
+//
 // The decimal tag must be followed by two integer parameters, the first integer
 // representing the scale and the second the precision; for example:
 //
@@ -75,6 +97,16 @@ type Schema struct {
 //		Cost int64 `parquet:"cost,decimal(0:3)"`
 //	}
 //
+// The geometry and geography tags accept the coordinate reference system of
+// the values as optional argument, the geography tag also accepts the edge
+// interpolation algorithm (spherical, vincenty, thomas, andoyer or karney);
+// for example:
+//
+//	type Place struct {
+//		Location []byte `parquet:"location,geometry(EPSG:4326)"`
+//		Area     []byte `parquet:"area,geography(OGC:CRS84,vincenty)"`
+//	}
+//
 // Invalid combination of struct tags and Go types, or repeating options will
 // cause the function to panic.
 //
@@ -95,6 +127,22 @@ type Schema struct {
 //	}
 //
 // The schema name is the Go type name of the value.
+//
+// Some types of the standard library have a native mapping to parquet columns:
+// the sql.Null* types are represented by optional columns of the type that
+// they wrap, net.IP and netip.Addr values by 16 bytes FIXED_LEN_BYTE_ARRAY
+// columns, and time.Duration values by INT64 columns holding nanoseconds.
+//
+// Go types may control their parquet representation by implementing the
+// ParquetSchemaer, ParquetMarshaler, and ParquetUnmarshaler interfaces. As a
+// fallback for types which have no native mapping (e.g. structs with only
+// unexported fields), types implementing encoding.TextMarshaler are represented
+// by STRING columns, and types implementing encoding.BinaryMarshaler by
+// BYTE_ARRAY columns; their values are read back with the
+// encoding.TextUnmarshaler and encoding.BinaryUnmarshaler interfaces.
+//
+// If the Go value implements proto.Message, the schema is constructed from the
+// message descriptor as described in SchemaOfProto.
 func SchemaOf(model interface{}) *Schema {
 	return schemaOf(dereference(reflect.TypeOf(model)))
 }
@@ -110,7 +158,11 @@ func schemaOf(model reflect.Type) *Schema {
 	if model.Kind() != reflect.Struct {
 		panic("cannot construct parquet schema from value of type " + model.String())
 	}
-	schema = NewSchema(model.Name(), nodeOf(model, nil))
+	if isProtoMessage(model) {
+		schema = schemaOfProtoType(model)
+	} else {
+		schema = NewSchema(model.Name(), nodeOf(model, nil))
+	}
 	if actual, loaded := cachedSchemas.LoadOrStore(model, schema); loaded {
 		schema = actual.(*Schema)
 	}
@@ -209,11 +261,23 @@ func (s *Schema) Compression() compress.Codec { return s.root.Compression() }
 // GoType returns the Go type that best represents the schema.
 func (s *Schema) GoType() reflect.Type { return s.root.GoType() }
 
+func (s *Schema) unwrap() Node { return s.root }
+
 // Deconstruct deconstructs a Go value and appends it to a row.
 //
 // The method panics is the structure of the go value does not match the
-// parquet schema.
+// parquet schema, or if the value cannot be converted to parquet values (for
+// example when a MarshalParquet method returns an error). Writers and buffers
+// return those errors instead.
 func (s *Schema) Deconstruct(row Row, value interface{}) Row {
+	row, err := s.deconstructRow(row, value)
+	if err != nil {
+		panic(err)
+	}
+	return row
+}
+
+func (s *Schema) deconstructRow(row Row, value interface{}) (Row, error) {
 	columns := make([][]Value, len(s.columns))
 	values := make([]Value, len(s.columns))
 
@@ -221,11 +285,13 @@ func (s *Schema) Deconstruct(row Row, value interface{}) Row {
 		columns[i] = values[i : i : i+1]
 	}
 
-	s.deconstructValueToColumns(columns, reflect.ValueOf(value))
-	return appendRow(row, columns)
+	if err := s.deconstructValueToColumns(columns, reflect.ValueOf(value)); err != nil {
+		return row, err
+	}
+	return appendRow(row, columns), nil
 }
 
-func (s *Schema) deconstructValueToColumns(columns [][]Value, value reflect.Value) {
+func (s *Schema) deconstructValueToColumns(columns [][]Value, value reflect.Value) error {
 	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
 		if value.IsNil() {
 			value = reflect.Value{}
@@ -233,7 +299,7 @@ func (s *Schema) deconstructValueToColumns(columns [][]Value, value reflect.Valu
 		}
 		value = value.Elem()
 	}
-	s.deconstruct(columns, levels{}, value)
+	return s.deconstruct(columns, levels{}, value)
 }
 
 // Reconstruct reconstructs a Go value from a row.
@@ -428,6 +494,8 @@ type structField struct {
 
 func (f *structField) Name() string { return f.name }
 
+func (f *structField) unwrap() Node { return f.Node }
+
 func (f *structField) Value(base reflect.Value) reflect.Value {
 	switch base.Kind() {
 	case reflect.Map:
@@ -491,8 +559,20 @@ func nodeOf(t reflect.Type, tag []string) Node {
 		return Leaf(Int96Type)
 	case reflect.TypeOf(uuid.UUID{}):
 		return UUID()
+	case reflect.TypeOf(CalendarInterval{}):
+		return Interval()
 	case reflect.TypeOf(time.Time{}):
 		return Timestamp(Nanosecond)
+	case netIPType, netipAddrType:
+		return Leaf(FixedLenByteArrayType(16))
+	}
+
+	if isNullSQLType(t) {
+		return Optional(nodeOf(t.Field(0).Type, nil))
+	}
+
+	if n := marshalNodeOf(t); n != nil {
+		return n
 	}
 
 	var n Node
@@ -579,13 +659,23 @@ func nodeOf(t reflect.Type, tag []string) Node {
 	return &goNode{Node: n, gotype: t}
 }
 
+// split splits s at the first comma which is not enclosed in parentheses,
+// so that options such as decimal(2,10) are not split apart.
 func split(s string) (head, tail string) {
-	if i := strings.IndexByte(s, ','); i < 0 {
-		head = s
-	} else {
-		head, tail = s[:i], s[i+1:]
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				return s[:i], s[i+1:]
+			}
+		}
 	}
-	return
+	return s, ""
 }
 
 func splitOptionArgs(s string) (option, args string) {
@@ -620,29 +710,119 @@ func parseDecimalArgs(args string) (scale, precision int, err error) {
 	return int(s), int(p), nil
 }
 
-func parseTimestampArgs(args string) (TimeUnit, error) {
+func parseGeospatialArgs(args string) (crs string, algorithm format.EdgeInterpolationAlgorithm, err error) {
 	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
-		return nil, fmt.Errorf("malformed timestamp args: %s", args)
+		return "", 0, fmt.Errorf("malformed geospatial args: %s", args)
 	}
+	args = strings.TrimPrefix(args, "(")
+	args = strings.TrimSuffix(args, ")")
 
+	crs, algorithmArg := args, ""
+	if i := strings.IndexByte(args, ','); i >= 0 {
+		crs, algorithmArg = args[:i], args[i+1:]
+	}
+	if algorithmArg != "" {
+		var ok bool
+		if algorithm, ok = lookupEdgeInterpolationAlgorithm(algorithmArg); !ok {
+			return "", 0, fmt.Errorf("unknown edge interpolation algorithm: %s", algorithmArg)
+		}
+	}
+	return crs, algorithm, nil
+}
+
+func parseIDArgs(args string) (int, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return 0, fmt.Errorf("malformed id args: %s", args)
+	}
 	args = strings.TrimPrefix(args, "(")
 	args = strings.TrimSuffix(args, ")")
+	id, err := strconv.ParseInt(args, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("invalid field id: %d", id)
+	}
+	return int(id), nil
+}
 
-	if len(args) == 0 {
-		return Millisecond, nil
+func parseZstdArgs(args string) (int, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return 0, fmt.Errorf("malformed zstd args: %s", args)
+	}
+	args = strings.TrimPrefix(args, "(")
+	args = strings.TrimSuffix(args, ")")
+	level, err := strconv.Atoi(args)
+	if err != nil {
+		return 0, err
+	}
+	if level < 1 || level > 22 {
+		return 0, fmt.Errorf("invalid zstd level: %d", level)
+	}
+	return level, nil
+}
+
+func parseDeltaArgs(args string) (encoding.Encoding, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("malformed delta args: %s", args)
+	}
+	switch args = args[1 : len(args)-1]; args {
+	case "monotonic":
+		return &DeltaBinaryPackedMonotonic, nil
+	default:
+		return nil, fmt.Errorf("invalid delta args: %s", args)
+	}
+}
+
+func parseTimeArgs(args string) (unit TimeUnit, err error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("malformed time args: %s", args)
 	}
 
-	switch args {
-	case "millisecond":
+	switch unitArg := args[1 : len(args)-1]; unitArg {
+	case "", "millisecond":
 		return Millisecond, nil
 	case "microsecond":
 		return Microsecond, nil
 	case "nanosecond":
 		return Nanosecond, nil
 	default:
+		return nil, fmt.Errorf("unknown time unit: %s", unitArg)
+	}
+}
+
+func parseTimestampArgs(args string) (unit TimeUnit, isAdjustedToUTC bool, err error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, false, fmt.Errorf("malformed timestamp args: %s", args)
 	}
 
-	return nil, fmt.Errorf("unknown time unit: %s", args)
+	args = strings.TrimPrefix(args, "(")
+	args = strings.TrimSuffix(args, ")")
+
+	unitArg, adjustmentArg := args, ""
+	if i := strings.IndexByte(args, ','); i >= 0 {
+		unitArg, adjustmentArg = args[:i], args[i+1:]
+	}
+
+	switch adjustmentArg {
+	case "", "utc":
+		isAdjustedToUTC = true
+	case "local":
+		isAdjustedToUTC = false
+	default:
+		return nil, false, fmt.Errorf("unknown timestamp adjustment: %s", adjustmentArg)
+	}
+
+	switch unitArg {
+	case "", "millisecond":
+		return Millisecond, isAdjustedToUTC, nil
+	case "microsecond":
+		return Microsecond, isAdjustedToUTC, nil
+	case "nanosecond":
+		return Nanosecond, isAdjustedToUTC, nil
+	default:
+		return nil, false, fmt.Errorf("unknown time unit: %s", unitArg)
+	}
 }
 
 type goNode struct {
@@ -652,6 +832,8 @@ type goNode struct {
 
 func (n *goNode) GoType() reflect.Type { return n.gotype }
 
+func (n *goNode) unwrap() Node { return n.Node }
+
 var (
 	_ RowGroupOption = (*Schema)(nil)
 	_ ReaderOption   = (*Schema)(nil)
@@ -659,12 +841,23 @@ var (
 )
 
 func makeNodeOf(t reflect.Type, name string, tag []string) Node {
+	if isNullSQLType(t) {
+		// The sql.Null* types are optional by definition, the tags of the
+		// field apply to the value that they wrap.
+		node := makeNodeOf(t.Field(0).Type, name, tag)
+		if !node.Optional() {
+			node = Optional(node)
+		}
+		return node
+	}
+
 	var (
 		node       Node
 		optional   bool
 		list       bool
 		encoded    encoding.Encoding
 		compressed compress.Codec
+		fieldID    int
 	)
 
 	setNode := func(n Node) {
@@ -702,6 +895,13 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 		compressed = c
 	}
 
+	setFieldID := func(id int) {
+		if fieldID != 0 {
+			throwInvalidNode(t, "struct field has field id declared multiple times", name, tag...)
+		}
+		fieldID = id
+	}
+
 	forEachTagOption(tag, func(option, args string) {
 		if t.Kind() == reflect.Map {
 			node = nodeOf(t, tag)
@@ -714,47 +914,70 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 			setOptional()
 
 		case "snappy":
-			setCompression(&Snappy)
+			setCompression(LookupCompressionCodec(format.Snappy))
 
 		case "gzip":
-			setCompression(&Gzip)
+			setCompression(LookupCompressionCodec(format.Gzip))
 
 		case "brotli":
-			setCompression(&Brotli)
+			setCompression(LookupCompressionCodec(format.Brotli))
 
 		case "lz4":
-			setCompression(&Lz4Raw)
+			setCompression(LookupCompressionCodec(format.Lz4Raw))
 
 		case "zstd":
-			setCompression(&Zstd)
+			if args == "()" {
+				setCompression(LookupCompressionCodec(format.Zstd))
+			} else {
+				level, err := parseZstdArgs(args)
+				if err != nil {
+					throwInvalidTag(t, name, option+args)
+				}
+				setCompression(&zstd.Codec{Level: zstd.LevelFromZstd(level)})
+			}
 
 		case "uncompressed":
-			setCompression(&Uncompressed)
+			setCompression(LookupCompressionCodec(format.Uncompressed))
 
 		case "plain":
-			setEncoding(&Plain)
+			setEncoding(LookupEncoding(format.Plain))
 
 		case "dict":
-			setEncoding(&RLEDictionary)
+			setEncoding(LookupEncoding(format.RLEDictionary))
 
 		case "json":
 			setNode(JSON())
 
+		case "id":
+			id, err := parseIDArgs(args)
+			if err != nil {
+				throwInvalidTag(t, name, option+args)
+			}
+			setFieldID(id)
+
 		case "delta":
 			switch t.Kind() {
 			case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
-				setEncoding(&DeltaBinaryPacked)
+				if args == "()" {
+					setEncoding(LookupEncoding(format.DeltaBinaryPacked))
+				} else {
+					enc, err := parseDeltaArgs(args)
+					if err != nil {
+						throwInvalidTag(t, name, option+args)
+					}
+					setEncoding(enc)
+				}
 			case reflect.String:
-				setEncoding(&DeltaByteArray)
+				setEncoding(LookupEncoding(format.DeltaByteArray))
 			case reflect.Slice:
 				if t.Elem().Kind() == reflect.Uint8 { // []byte?
-					setEncoding(&DeltaByteArray)
+					setEncoding(LookupEncoding(format.DeltaByteArray))
 				} else {
 					throwInvalidTag(t, name, option)
 				}
 			case reflect.Array:
 				if t.Elem().Kind() == reflect.Uint8 { // [N]byte?
-					setEncoding(&DeltaByteArray)
+					setEncoding(LookupEncoding(format.DeltaByteArray))
 				} else {
 					throwInvalidTag(t, name, option)
 				}
@@ -764,8 +987,16 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 
 		case "split":
 			switch t.Kind() {
-			case reflect.Float32, reflect.Float64:
-				setEncoding(&ByteStreamSplit)
+			case reflect.Float32, reflect.Float64,
+				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				setEncoding(LookupEncoding(format.ByteStreamSplit))
+			case reflect.Array:
+				if t.Elem().Kind() == reflect.Uint8 { // [N]byte?
+					setEncoding(LookupEncoding(format.ByteStreamSplit))
+				} else {
+					throwInvalidTag(t, name, option)
+				}
 			default:
 				throwInvalidTag(t, name, option)
 			}
@@ -798,6 +1029,16 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 				throwInvalidTag(t, name, option)
 			}
 
+		case "interval":
+			switch {
+			case t == reflect.TypeOf(CalendarInterval{}):
+				setNode(Interval())
+			case t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 && t.Len() == 12:
+				setNode(Interval())
+			default:
+				throwInvalidTag(t, name, option)
+			}
+
 		case "decimal":
 			scale, precision, err := parseDecimalArgs(args)
 			if err != nil {
@@ -812,7 +1053,10 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 			case reflect.Array, reflect.Slice:
 				baseType = FixedLenByteArrayType(decimalFixedLenByteArraySize(precision))
 			default:
-				throwInvalidTag(t, name, option)
+				if !isBigNumberType(t) {
+					throwInvalidTag(t, name, option)
+				}
+				baseType = FixedLenByteArrayType(decimalFixedLenByteArraySize(precision))
 			}
 
 			setNode(Decimal(scale, precision, baseType))
@@ -823,28 +1067,71 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 			default:
 				throwInvalidTag(t, name, option)
 			}
+		case "time":
+			switch t {
+			case durationType:
+				timeUnit, err := parseTimeArgs(args)
+				if err != nil {
+					throwInvalidTag(t, name, option+args)
+				}
+				setNode(Time(timeUnit))
+			default:
+				throwInvalidTag(t, name, option)
+			}
+		case "string":
+			switch t {
+			case netIPType, netipAddrType:
+				setNode(String())
+			default:
+				throwInvalidTag(t, name, option)
+			}
+
+		case "geometry", "geography":
+			switch {
+			case t.Kind() == reflect.String:
+			case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			default:
+				throwInvalidTag(t, name, option)
+			}
+			crs, algorithm, err := parseGeospatialArgs(args)
+			if err != nil {
+				throwInvalidTag(t, name, option+args)
+			}
+			if option == "geography" {
+				setNode(Geography(crs, algorithm))
+			} else if algorithm != format.Spherical {
+				throwInvalidTag(t, name, option+args)
+			} else {
+				setNode(Geometry(crs))
+			}
 		case "timestamp":
 			switch t.Kind() {
 			case reflect.Int64:
-				timeUnit, err := parseTimestampArgs(args)
+				timeUnit, isAdjustedToUTC, err := parseTimestampArgs(args)
 				if err != nil {
 					throwInvalidTag(t, name, option)
 				}
-				setNode(Timestamp(timeUnit))
+				setNode(TimestampAdjusted(timeUnit, isAdjustedToUTC))
 			default:
 				switch t {
 				case reflect.TypeOf(time.Time{}):
-					timeUnit, err := parseTimestampArgs(args)
+					timeUnit, isAdjustedToUTC, err := parseTimestampArgs(args)
 					if err != nil {
 						throwInvalidTag(t, name, option)
 					}
-					setNode(Timestamp(timeUnit))
+					setNode(TimestampAdjusted(timeUnit, isAdjustedToUTC))
 				default:
 					throwInvalidTag(t, name, option)
 				}
 			}
 		default:
-			throwUnknownTag(t, name, option)
+			if codec := lookupCompressionCodecByName(option); codec != nil {
+				setCompression(codec)
+			} else if enc := lookupEncodingByName(option); enc != nil {
+				setEncoding(enc)
+			} else {
+				throwUnknownTag(t, name, option)
+			}
 		}
 	})
 
@@ -866,6 +1153,10 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 
 	if node == nil {
 		node = nodeOf(t, tag)
+	} else if t.Kind() == reflect.Ptr && isBigNumberType(t) {
+		// Pointers to big numbers with a decimal tag are nil when the
+		// value is null.
+		optional = true
 	}
 
 	if compressed != nil {
@@ -891,6 +1182,10 @@ func makeNodeOf(t reflect.Type, name string, tag []string) Node {
 		node = Optional(node)
 	}
 
+	if fieldID != 0 {
+		node = FieldID(node, fieldID)
+	}
+
 	return node
 }
 
@@ -4,12 +4,15 @@ import (
 	"testing"
 
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
 )
 
 func TestSchemaOf(t *testing.T) {
 	tests := []struct {
 		value interface{}
 		print string
+		// Encodings of the columns which are not encoded with PLAIN.
+		encodings map[string]format.Encoding
 	}{
 		{
 			value: new(struct{ Name string }),
@@ -64,6 +67,40 @@ func TestSchemaOf(t *testing.T) {
 	required float short;
 	required double long;
 }`,
+			encodings: map[string]format.Encoding{
+				"short": format.ByteStreamSplit,
+				"long":  format.ByteStreamSplit,
+			},
+		},
+
+		{
+			value: new(struct {
+				Int8   int8     `parquet:"int8,split"`
+				Int16  int16    `parquet:"int16,split"`
+				Int32  int32    `parquet:"int32,split"`
+				Uint8  uint8    `parquet:"uint8,split"`
+				Uint16 uint16   `parquet:"uint16,split"`
+				Uint64 uint64   `parquet:"uint64,split"`
+				Bytes  [16]byte `parquet:"bytes,split"`
+			}),
+			print: `message {
+	required int32 int8 (INT(8,true));
+	required int32 int16 (INT(16,true));
+	required int32 int32 (INT(32,true));
+	required int32 uint8 (INT(8,false));
+	required int32 uint16 (INT(16,false));
+	required int64 uint64 (INT(64,false));
+	required fixed_len_byte_array(16) bytes;
+}`,
+			encodings: map[string]format.Encoding{
+				"int8":   format.ByteStreamSplit,
+				"int16":  format.ByteStreamSplit,
+				"int32":  format.ByteStreamSplit,
+				"uint8":  format.ByteStreamSplit,
+				"uint16": format.ByteStreamSplit,
+				"uint64": format.ByteStreamSplit,
+				"bytes":  format.ByteStreamSplit,
+			},
 		},
 
 		{
@@ -190,6 +227,16 @@ func TestSchemaOf(t *testing.T) {
 			if s := schema.String(); s != test.print {
 				t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", test.print, s)
 			}
+
+			for name, want := range test.encodings {
+				leaf, ok := schema.Lookup(name)
+				if !ok {
+					t.Fatalf("column %q not found", name)
+				}
+				if enc := leaf.Node.Encoding(); enc == nil || enc.Encoding() != want {
+					t.Errorf("column %q: want=%s got=%v", name, want, enc)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,222 @@
+package parquet
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// This file contains the mapping of common types of the Go standard library to
+// parquet values:
+//
+//	sql.Null*     | optional column of the type of the wrapped value
+//	time.Duration | INT64, or TIME when the field has a time(unit) tag
+//	net.IP        | FIXED_LEN_BYTE_ARRAY(16), or STRING with a string tag
+//	netip.Addr    | FIXED_LEN_BYTE_ARRAY(16), or STRING with a string tag
+//	big.Int       | DECIMAL when the field has a decimal(s:p) tag
+//	big.Float     | DECIMAL when the field has a decimal(s:p) tag
+//
+// IP addresses are stored in their 16 bytes form, IPv4 addresses are mapped to
+// IPv6 and unmapped when read back into a netip.Addr. Without tags, big.Int and
+// big.Float values use their encoding.TextMarshaler implementation.
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	netIPType     = reflect.TypeOf(net.IP(nil))
+	netipAddrType = reflect.TypeOf(netip.Addr{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	bigFloatType  = reflect.TypeOf(big.Float{})
+)
+
+// isNullSQLType returns true if t is one of the sql.Null* types of the standard
+// library, which are structs with the value in their first field and a Valid
+// boolean in the second.
+func isNullSQLType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// nullSQLValue returns the value wrapped in v, and whether it is valid.
+func nullSQLValue(v reflect.Value) (reflect.Value, bool) {
+	return v.Field(0), v.Field(1).Bool()
+}
+
+// isBigNumberType returns true if t is big.Int, big.Float or a pointer to one
+// of them.
+func isBigNumberType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == bigIntType || t == bigFloatType
+}
+
+// hasStdlibMapping returns true if Go values of type t are converted to values
+// of the parquet type typ by makeStdlibValue and assignStdlibValue.
+func hasStdlibMapping(t reflect.Type, typ Type) bool {
+	switch t {
+	case durationType:
+		_, ok := typ.(*timeType)
+		return ok
+	case netIPType, netipAddrType:
+		return true
+	case bigIntType, bigFloatType:
+		_, ok := typ.(*decimalType)
+		return ok
+	default:
+		return false
+	}
+}
+
+// makeStdlibValue converts v to a value of the parquet type typ. The boolean
+// is false if the type of v has no native mapping to typ.
+func makeStdlibValue(typ Type, v reflect.Value) (Value, bool, error) {
+	if !hasStdlibMapping(v.Type(), typ) {
+		return Value{}, false, nil
+	}
+	var val Value
+	var err error
+	switch x := v.Interface().(type) {
+	case time.Duration:
+		val = makeDurationValue(typ.(*timeType), x)
+	case net.IP:
+		val = makeIPValue(typ, x)
+	case netip.Addr:
+		val = makeAddrValue(typ, x)
+	case big.Int:
+		val, err = makeDecimalValue(&x, typ)
+	case big.Float:
+		val, err = makeDecimalValue(bigFloatUnscaledValue(&x, typ.(*decimalType)), typ)
+	}
+	return val, true, err
+}
+
+// assignStdlibValue sets dst to the value of src, which has the parquet type
+// typ. The boolean is false if the type of dst has no native mapping to typ.
+func assignStdlibValue(typ Type, dst reflect.Value, src Value) (bool, error) {
+	if !hasStdlibMapping(dst.Type(), typ) {
+		return false, nil
+	}
+	switch dst.Type() {
+	case durationType:
+		d := int64(timeUnitDuration(typ.(*timeType).Unit))
+		if typ.Kind() == Int32 {
+			dst.SetInt(int64(src.int32()) * d)
+		} else {
+			dst.SetInt(src.int64() * d)
+		}
+	case netIPType:
+		ip, err := parseIPValue(typ, src)
+		if err != nil {
+			return true, err
+		}
+		dst.Set(reflect.ValueOf(ip))
+	case netipAddrType:
+		addr, err := parseAddrValue(typ, src)
+		if err != nil {
+			return true, err
+		}
+		dst.Set(reflect.ValueOf(addr))
+	case bigIntType:
+		dst.Set(reflect.ValueOf(decimalUnscaledValue(src)).Elem())
+	case bigFloatType:
+		dst.Set(reflect.ValueOf(bigFloatOfDecimal(src, typ.(*decimalType))).Elem())
+	}
+	return true, nil
+}
+
+func makeDurationValue(t *timeType, d time.Duration) Value {
+	v := int64(d / timeUnitDuration(t.Unit))
+	if t.Kind() == Int32 {
+		return makeValueInt32(int32(v))
+	}
+	return makeValueInt64(v)
+}
+
+func makeIPValue(t Type, ip net.IP) Value {
+	if t.Kind() == FixedLenByteArray {
+		b := make([]byte, 16)
+		copy(b, ip.To16())
+		return makeValueBytes(FixedLenByteArray, b)
+	}
+	if len(ip) == 0 {
+		return makeValueString(ByteArray, "")
+	}
+	return makeValueString(ByteArray, ip.String())
+}
+
+func makeAddrValue(t Type, addr netip.Addr) Value {
+	if t.Kind() == FixedLenByteArray {
+		b := addr.As16()
+		return makeValueBytes(FixedLenByteArray, b[:])
+	}
+	if !addr.IsValid() {
+		return makeValueString(ByteArray, "")
+	}
+	return makeValueString(ByteArray, addr.String())
+}
+
+func parseIPValue(t Type, v Value) (net.IP, error) {
+	b := v.byteArray()
+	if t.Kind() == FixedLenByteArray {
+		if len(b) != 16 {
+			return nil, fmt.Errorf("cannot assign parquet value of length %d to go value of type net.IP", len(b))
+		}
+		return net.IP(copyBytes(b)), nil
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	ip := net.ParseIP(string(b))
+	if ip == nil {
+		return nil, fmt.Errorf("cannot assign parquet value %q to go value of type net.IP", b)
+	}
+	return ip, nil
+}
+
+func parseAddrValue(t Type, v Value) (netip.Addr, error) {
+	b := v.byteArray()
+	if t.Kind() == FixedLenByteArray {
+		if len(b) != 16 {
+			return netip.Addr{}, fmt.Errorf("cannot assign parquet value of length %d to go value of type netip.Addr", len(b))
+		}
+		return netip.AddrFrom16(*(*[16]byte)(b)).Unmap(), nil
+	}
+	if len(b) == 0 {
+		return netip.Addr{}, nil
+	}
+	return netip.ParseAddr(string(b))
+}
+
+func decimalScaleFactor(t *decimalType) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.decimal.Scale)), nil)
+}
+
+// bigFloatUnscaledValue returns the unscaled integer value of f for the scale
+// of the decimal type t, rounded to the nearest integer.
+func bigFloatUnscaledValue(f *big.Float, t *decimalType) *big.Int {
+	x := new(big.Float).SetPrec(f.Prec() + 64)
+	x.Mul(f, new(big.Float).SetInt(decimalScaleFactor(t)))
+	half := big.NewFloat(0.5)
+	if x.Sign() < 0 {
+		x.Sub(x, half)
+	} else {
+		x.Add(x, half)
+	}
+	i, _ := x.Int(nil)
+	return i
+}
+
+func bigFloatOfDecimal(v Value, t *decimalType) *big.Float {
+	unscaled := decimalUnscaledValue(v)
+	prec := uint(unscaled.BitLen()) + 64
+	f := new(big.Float).SetPrec(prec).SetInt(unscaled)
+	return f.Quo(f, new(big.Float).SetPrec(prec).SetInt(decimalScaleFactor(t)))
+}
@@ -0,0 +1,29 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestGenericStdlibTypes(t *testing.T) {
+	rows := stdlibRows()
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[stdlibRow](buffer)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parquet.Read[stdlibRow](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStdlibRowsEqual(t, rows, got)
+}
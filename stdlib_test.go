@@ -0,0 +1,151 @@
+package parquet_test
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type stdlibRow struct {
+	Name      sql.NullString  `parquet:"name"`
+	Age       sql.NullInt64   `parquet:"age"`
+	Score     sql.NullFloat64 `parquet:"score"`
+	Active    sql.NullBool    `parquet:"active"`
+	UpdatedAt sql.NullTime    `parquet:"updated_at,timestamp(millisecond)"`
+	Timeout   time.Duration   `parquet:"timeout"`
+	Elapsed   time.Duration   `parquet:"elapsed,time(microsecond)"`
+	Addr      netip.Addr      `parquet:"addr"`
+	Peer      net.IP          `parquet:"peer"`
+	Host      netip.Addr      `parquet:"host,string"`
+	Amount    *big.Int        `parquet:"amount,decimal(2:20)"`
+	Ratio     big.Float       `parquet:"ratio,decimal(4:10)"`
+}
+
+func TestStdlibTypesSchema(t *testing.T) {
+	const expected = `message stdlibRow {
+	optional binary name (STRING);
+	optional int64 age (INT(64,true));
+	optional double score;
+	optional boolean active;
+	optional int64 updated_at (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 timeout (INT(64,true));
+	required int64 elapsed (TIME(isAdjustedToUTC=true,unit=MICROS));
+	required fixed_len_byte_array(16) addr;
+	required fixed_len_byte_array(16) peer;
+	required binary host (STRING);
+	optional fixed_len_byte_array(9) amount (DECIMAL(20,2));
+	required fixed_len_byte_array(5) ratio (DECIMAL(10,4));
+}`
+
+	if s := parquet.SchemaOf(new(stdlibRow)).String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+func stdlibRows() []stdlibRow {
+	return []stdlibRow{
+		{
+			Name:      sql.NullString{String: "Luke", Valid: true},
+			Age:       sql.NullInt64{Int64: 42, Valid: true},
+			Score:     sql.NullFloat64{Float64: 0, Valid: true},
+			Active:    sql.NullBool{Bool: false, Valid: true},
+			UpdatedAt: sql.NullTime{Time: time.Date(2022, 8, 1, 12, 30, 0, 0, time.UTC), Valid: true},
+			Timeout:   3 * time.Second,
+			Elapsed:   1500 * time.Microsecond,
+			Addr:      netip.MustParseAddr("10.1.2.3"),
+			Peer:      net.ParseIP("2001:db8::1"),
+			Host:      netip.MustParseAddr("fe80::1"),
+			Amount:    big.NewInt(-123456789012345),
+			Ratio:     *big.NewFloat(12.5),
+		},
+		{
+			Addr:   netip.MustParseAddr("::1"),
+			Peer:   net.ParseIP("192.168.1.1"),
+			Host:   netip.MustParseAddr("127.0.0.1"),
+			Amount: nil,
+			Ratio:  *big.NewFloat(-0.25),
+		},
+	}
+}
+
+// assertStdlibRowsEqual compares big numbers by value since their internal
+// representation is not preserved by the round trip.
+func assertStdlibRowsEqual(t *testing.T, want, got []stdlibRow) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("number of rows mismatch: want=%d got=%d", len(want), len(got))
+	}
+	for i := range want {
+		w, g := want[i], got[i]
+		if (w.Amount == nil) != (g.Amount == nil) || (w.Amount != nil && w.Amount.Cmp(g.Amount) != 0) {
+			t.Errorf("row %d: amount mismatch: want=%v got=%v", i, w.Amount, g.Amount)
+		}
+		if w.Ratio.Cmp(&g.Ratio) != 0 {
+			t.Errorf("row %d: ratio mismatch: want=%v got=%v", i, &w.Ratio, &g.Ratio)
+		}
+		w.Amount, g.Amount = nil, nil
+		w.Ratio, g.Ratio = big.Float{}, big.Float{}
+		if !reflect.DeepEqual(w, g) {
+			t.Errorf("row %d: value mismatch\nwant: %+v\ngot:  %+v", i, w, g)
+		}
+	}
+}
+
+func TestStdlibTypes(t *testing.T) {
+	rows := stdlibRows()
+	schema := parquet.SchemaOf(new(stdlibRow))
+
+	for i := range rows {
+		row := schema.Deconstruct(nil, &rows[i])
+		got := stdlibRow{}
+		if err := schema.Reconstruct(&got, row); err != nil {
+			t.Fatal(err)
+		}
+		assertStdlibRowsEqual(t, rows[i:i+1], []stdlibRow{got})
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	got := make([]stdlibRow, len(rows))
+	for i := range got {
+		if err := reader.Read(&got[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertStdlibRowsEqual(t, rows, got)
+	if err := reader.Read(new(stdlibRow)); err != io.EOF {
+		t.Errorf("expected io.EOF but got %v", err)
+	}
+}
+
+func TestStdlibTypesInvalidValue(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic when writing a decimal value which overflows its precision")
+		}
+	}()
+	type row struct {
+		Amount big.Int `parquet:"amount,decimal(0:2)"`
+	}
+	r := row{}
+	r.Amount.Lsh(big.NewInt(1), 100)
+	parquet.SchemaOf(new(row)).Deconstruct(nil, &r)
+}
@@ -0,0 +1,112 @@
+package parquet_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type localTimestampRow struct {
+	Local time.Time `parquet:"local,timestamp(microsecond,local)"`
+	UTC   time.Time `parquet:"utc,timestamp(microsecond)"`
+}
+
+func TestLocalTimestamp(t *testing.T) {
+	schema := parquet.SchemaOf(new(localTimestampRow))
+
+	const expected = `message localTimestampRow {
+	required int64 local (TIMESTAMP(isAdjustedToUTC=false,unit=MICROS));
+	required int64 utc (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+}`
+	if s := schema.String(); s != expected {
+		t.Fatalf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	// The wall clock of local timestamps must not depend on the time zone of
+	// the program, only on the location configured on the column.
+	defer func(loc *time.Location) { time.Local = loc }(time.Local)
+	time.Local = time.FixedZone("UTC-5", -5*3600)
+
+	now := time.Date(2022, 12, 13, 1, 43, 33, 123456000, time.FixedZone("UTC+2", 2*3600))
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Write(&localTimestampRow{Local: now, UTC: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := f.Schema().String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+	if ct := f.Metadata().Schema[1].ConvertedType; ct != nil {
+		t.Errorf("local timestamps must not have a converted type: %v", *ct)
+	}
+
+	rows := make([]parquet.Row, 1)
+	if n, _ := parquet.NewReader(f).ReadRows(rows); n != 1 {
+		t.Fatalf("wrong number of rows read: %d", n)
+	}
+	wallClock := time.Date(2022, 12, 12, 23, 43, 33, 123456000, time.UTC)
+	if v := rows[0][0].Int64(); v != wallClock.UnixMicro() {
+		t.Errorf("local timestamp must hold the wall clock time: want=%d got=%d", wallClock.UnixMicro(), v)
+	}
+	if v := rows[0][1].Int64(); v != now.UnixMicro() {
+		t.Errorf("utc timestamp must hold the unix time: want=%d got=%d", now.UnixMicro(), v)
+	}
+
+	row := localTimestampRow{}
+	if err := parquet.NewReader(f).Read(&row); err != nil {
+		t.Fatal(err)
+	}
+	if !row.Local.Equal(now) || row.Local.Location() != time.UTC {
+		t.Errorf("wrong local timestamp: want=%v got=%v", now, row.Local)
+	}
+	if !row.UTC.Equal(now) || row.UTC.Location() != time.UTC {
+		t.Errorf("wrong utc timestamp: want=%v got=%v", now.UTC(), row.UTC)
+	}
+}
+
+func TestLocalTimestampLocation(t *testing.T) {
+	utcPlus2 := time.FixedZone("UTC+2", 2*3600)
+	typ := parquet.LocalTimestamp(parquet.Millisecond, utcPlus2).Type()
+	wallClock := time.Date(2022, 12, 13, 1, 43, 33, 123000000, time.UTC)
+
+	var tm time.Time
+	if err := typ.AssignValue(reflect.ValueOf(&tm).Elem(), parquet.Int64Value(wallClock.UnixMilli())); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2022, 12, 13, 1, 43, 33, 123000000, utcPlus2)
+	if !tm.Equal(want) || tm.Location() != utcPlus2 {
+		t.Errorf("wrong time: want=%v got=%v", want, tm)
+	}
+
+	defer func(loc *time.Location) { time.Local = loc }(time.Local)
+	time.Local = utcPlus2
+
+	timeType := parquet.TimeAdjusted(parquet.Millisecond, false).Type()
+	timestampType := parquet.Timestamp(parquet.Millisecond).Type()
+	v, err := timeType.ConvertValue(parquet.Int64Value(wallClock.UnixMilli()), timestampType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if millis := int32((1*3600+43*60+33)*1000 + 123); v.Int32() != millis {
+		t.Errorf("local time without a location must use UTC: want=%d got=%d", millis, v.Int32())
+	}
+
+	for _, tag := range []string{"timestamp(,local)", "timestamp(nanosecond,utc)"} {
+		parquet.SchemaOf(reflect.New(reflect.StructOf([]reflect.StructField{{
+			Name: "A",
+			Type: reflect.TypeOf(time.Time{}),
+			Tag:  reflect.StructTag(`parquet:"a,` + tag + `"`),
+		}})).Interface())
+	}
+}
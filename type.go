@@ -86,8 +86,10 @@ type Type interface {
 	// The method panics if it is called on a group type.
 	Compare(a, b Value) int
 
-	// ColumnOrder returns the type's column order. For group types, this method
-	// returns nil.
+	// ColumnOrder returns the type's column order. For group types, and types
+	// for which the parquet format leaves the order undefined (e.g. INTERVAL),
+	// this method returns nil. Writers do not produce column indexes for types
+	// with an undefined order.
 	//
 	// The order describes the comparison logic implemented by the Less method.
 	//
@@ -128,6 +130,9 @@ type Type interface {
 	//
 	// A value of zero or less means no limits.
 	//
+	// Types with an undefined column order return an indexer which discards
+	// the pages.
+	//
 	// The method panics if it is called on a group type.
 	NewColumnIndexer(sizeLimit int) ColumnIndexer
 
@@ -650,6 +655,8 @@ func (t floatType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch typ.(type) {
 	case *stringType:
 		return convertStringToFloat(val)
+	case *float16Type:
+		return convertFloat16ToFloat(val)
 	}
 	switch typ.Kind() {
 	case Boolean:
@@ -731,6 +738,8 @@ func (t doubleType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch typ.(type) {
 	case *stringType:
 		return convertStringToDouble(val)
+	case *float16Type:
+		return convertFloat16ToDouble(val)
 	}
 	switch typ.Kind() {
 	case Boolean:
@@ -1341,12 +1350,15 @@ func (t *stringType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch t2 := typ.(type) {
 	case *dateType:
 		return convertDateToString(val)
+	case *float16Type:
+		return convertFloat16ToString(val)
+	case intervalType:
+		return convertIntervalToString(val)
 	case *timeType:
-		tz := t2.tz()
 		if t2.Unit.Micros != nil {
-			return convertTimeMicrosToString(val, tz)
+			return convertTimeMicrosToString(val, time.UTC)
 		} else {
-			return convertTimeMillisToString(val, tz)
+			return convertTimeMillisToString(val, time.UTC)
 		}
 	}
 	switch typ.Kind() {
@@ -1440,6 +1452,212 @@ func (t *uuidType) ConvertValue(val Value, typ Type) (Value, error) {
 	return be128Type{}.ConvertValue(val, typ)
 }
 
+// Float16 constructs a leaf node of FLOAT16 logical type, representing IEEE
+// 754 half-precision floating point values stored as 2 bytes little endian
+// fixed-length byte arrays.
+//
+// Values are ordered by their numeric value, the column statistics and page
+// indexes exclude NaN values and record -0 and +0 for zero bounds.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#float16
+func Float16() Node { return Leaf(&float16Type{}) }
+
+var float16BaseType = fixedLenByteArrayType{length: 2}
+
+type float16Type format.Float16Type
+
+func (t *float16Type) String() string { return (*format.Float16Type)(t).String() }
+
+func (t *float16Type) Kind() Kind { return float16BaseType.Kind() }
+
+func (t *float16Type) Length() int { return float16BaseType.Length() }
+
+func (t *float16Type) EstimateSize(n int) int { return float16BaseType.EstimateSize(n) }
+
+func (t *float16Type) EstimateNumValues(n int) int { return float16BaseType.EstimateNumValues(n) }
+
+func (t *float16Type) Compare(a, b Value) int {
+	return compareFloat16(float16Bits(a.byteArray()), float16Bits(b.byteArray()))
+}
+
+func (t *float16Type) ColumnOrder() *format.ColumnOrder { return &typeDefinedColumnOrder }
+
+func (t *float16Type) PhysicalType() *format.Type { return &physicalTypes[FixedLenByteArray] }
+
+func (t *float16Type) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Float16: (*format.Float16Type)(t)}
+}
+
+func (t *float16Type) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *float16Type) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return newFloat16ColumnIndexer()
+}
+
+func (t *float16Type) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newFloat16Dictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *float16Type) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newFloat16ColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t *float16Type) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newFloat16Page(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *float16Type) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return float16BaseType.NewValues(values, offsets)
+}
+
+func (t *float16Type) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return float16BaseType.Encode(dst, src, enc)
+}
+
+func (t *float16Type) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return float16BaseType.Decode(dst, src, enc)
+}
+
+func (t *float16Type) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return float16BaseType.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t *float16Type) AssignValue(dst reflect.Value, src Value) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(src.Float16()))
+		return nil
+	}
+	return float16BaseType.AssignValue(dst, src)
+}
+
+func (t *float16Type) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case *float16Type:
+		return val, nil
+	case *stringType:
+		return convertStringToFloat16(val)
+	}
+	switch typ.Kind() {
+	case Boolean:
+		return convertBooleanToFloat16(val)
+	case Int32:
+		return convertInt32ToFloat16(val)
+	case Int64:
+		return convertInt64ToFloat16(val)
+	case Float:
+		return convertFloatToFloat16(val)
+	case Double:
+		return convertDoubleToFloat16(val)
+	default:
+		return float16BaseType.ConvertValue(val, typ)
+	}
+}
+
+// Interval constructs a leaf node of INTERVAL type, representing durations
+// made of months, days, and milliseconds, stored as FIXED_LEN_BYTE_ARRAY(12).
+// Values of this type are mapped to the CalendarInterval Go type.
+//
+// The sort order of intervals is undefined, no statistics are written for
+// INTERVAL columns, nor does the writer generate column indexes for them.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#interval
+func Interval() Node { return Leaf(intervalType{}) }
+
+var intervalBaseType = fixedLenByteArrayType{length: 12}
+
+type intervalType struct{}
+
+func (t intervalType) String() string { return "INTERVAL" }
+
+func (t intervalType) Kind() Kind { return intervalBaseType.Kind() }
+
+func (t intervalType) Length() int { return intervalBaseType.Length() }
+
+func (t intervalType) EstimateSize(n int) int { return intervalBaseType.EstimateSize(n) }
+
+func (t intervalType) EstimateNumValues(n int) int { return intervalBaseType.EstimateNumValues(n) }
+
+// Compare orders intervals by months, days, then milliseconds. The parquet
+// format does not define an order for intervals (e.g. 1 month and 30 days
+// cannot be compared), this order is only intended to group equal values.
+func (t intervalType) Compare(a, b Value) int {
+	return compareCalendarInterval(a.Interval(), b.Interval())
+}
+
+// ColumnOrder returns nil because the parquet format leaves the order of INTERVAL
+// values undefined, readers must not use the statistics of these columns.
+func (t intervalType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t intervalType) PhysicalType() *format.Type { return &physicalTypes[FixedLenByteArray] }
+
+func (t intervalType) LogicalType() *format.LogicalType { return nil }
+
+func (t intervalType) ConvertedType() *deprecated.ConvertedType {
+	return &convertedTypes[deprecated.Interval]
+}
+
+func (t intervalType) NewColumnIndexer(sizeLimit int) ColumnIndexer { return noopColumnIndexer{} }
+
+func (t intervalType) NewDictionary(columnIndex, numValues int, data encoding.Values) Dictionary {
+	return newIntervalDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t intervalType) NewColumnBuffer(columnIndex, numValues int) ColumnBuffer {
+	return newIntervalColumnBuffer(t, makeColumnIndex(columnIndex), makeNumValues(numValues))
+}
+
+func (t intervalType) NewPage(columnIndex, numValues int, data encoding.Values) Page {
+	return newIntervalPage(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t intervalType) NewValues(values []byte, offsets []uint32) encoding.Values {
+	return intervalBaseType.NewValues(values, offsets)
+}
+
+func (t intervalType) Encode(dst []byte, src encoding.Values, enc encoding.Encoding) ([]byte, error) {
+	return intervalBaseType.Encode(dst, src, enc)
+}
+
+func (t intervalType) Decode(dst encoding.Values, src []byte, enc encoding.Encoding) (encoding.Values, error) {
+	return intervalBaseType.Decode(dst, src, enc)
+}
+
+func (t intervalType) EstimateDecodeSize(numValues int, src []byte, enc encoding.Encoding) int {
+	return intervalBaseType.EstimateDecodeSize(numValues, src, enc)
+}
+
+func (t intervalType) GoType() reflect.Type { return reflect.TypeOf(CalendarInterval{}) }
+
+func (t intervalType) AssignValue(dst reflect.Value, src Value) error {
+	switch dst.Type() {
+	case reflect.TypeOf(CalendarInterval{}):
+		dst.Set(reflect.ValueOf(src.Interval()))
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(src.Interval().String())
+		return nil
+	}
+	return intervalBaseType.AssignValue(dst, src)
+}
+
+func (t intervalType) ConvertValue(val Value, typ Type) (Value, error) {
+	switch typ.(type) {
+	case intervalType:
+		return val, nil
+	case *stringType:
+		return convertStringToInterval(val)
+	}
+	switch typ.Kind() {
+	case ByteArray, FixedLenByteArray:
+		return intervalBaseType.ConvertValue(val, typ)
+	default:
+		return val, invalidConversion(val, typ.String(), "INTERVAL")
+	}
+}
+
 // Enum constructs a leaf node with a logical type representing enumerations.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#enum
@@ -1758,7 +1976,7 @@ func (t *dateType) ConvertValue(val Value, typ Type) (Value, error) {
 	case *stringType:
 		return convertStringToDate(val, time.UTC)
 	case *timestampType:
-		return convertTimestampToDate(val, src.Unit, src.tz())
+		return convertTimestampToDate(val, src.Unit, time.UTC)
 	}
 	return int32Type{}.ConvertValue(val, typ)
 }
@@ -1803,17 +2021,50 @@ func (u *nanosecond) TimeUnit() format.TimeUnit {
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#time
 func Time(unit TimeUnit) Node {
-	return Leaf(&timeType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+	return TimeAdjusted(unit, true)
 }
 
-type timeType format.TimeType
+// TimeAdjusted constructs a leaf node of TIME logical type with the
+// isAdjustedToUTC property set to the given value.
+//
+// Times which are not adjusted to UTC represent the time of day of a wall
+// clock, which is read and written in UTC unless a location is configured
+// with LocalTime, like the wall clock of timestamps.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#time
+func TimeAdjusted(unit TimeUnit, isAdjustedToUTC bool) Node {
+	return Leaf(&timeType{
+		TimeType: format.TimeType{
+			IsAdjustedToUTC: isAdjustedToUTC,
+			Unit:            unit.TimeUnit(),
+		},
+	})
+}
 
-func (t *timeType) tz() *time.Location {
-	if t.IsAdjustedToUTC {
+// LocalTime constructs a leaf node of TIME logical type which is not adjusted
+// to UTC, and where values represent the time of day of a wall clock in loc.
+//
+// The location is used when converting to or from times and timestamps which
+// are adjusted to UTC. A nil location means UTC, which is also the default of
+// times constructed with TimeAdjusted.
+func LocalTime(unit TimeUnit, loc *time.Location) Node {
+	return Leaf(&timeType{
+		TimeType: format.TimeType{Unit: unit.TimeUnit()},
+		loc:      loc,
+	})
+}
+
+type timeType struct {
+	format.TimeType
+	loc *time.Location
+}
+
+// location returns the location of the wall clock that values of t represent.
+func (t *timeType) location() *time.Location {
+	if t.IsAdjustedToUTC || t.loc == nil {
 		return time.UTC
-	} else {
-		return time.Local
 	}
+	return t.loc
 }
 
 func (t *timeType) baseType() Type {
@@ -1828,7 +2079,7 @@ func (t *timeType) useInt32() bool { return t.Unit.Millis != nil }
 
 func (t *timeType) useInt64() bool { return t.Unit.Micros != nil }
 
-func (t *timeType) String() string { return (*format.TimeType)(t).String() }
+func (t *timeType) String() string { return t.TimeType.String() }
 
 func (t *timeType) Kind() Kind { return t.baseType().Kind() }
 
@@ -1845,10 +2096,13 @@ func (t *timeType) ColumnOrder() *format.ColumnOrder { return t.baseType().Colum
 func (t *timeType) PhysicalType() *format.Type { return t.baseType().PhysicalType() }
 
 func (t *timeType) LogicalType() *format.LogicalType {
-	return &format.LogicalType{Time: (*format.TimeType)(t)}
+	return &format.LogicalType{Time: &t.TimeType}
 }
 
 func (t *timeType) ConvertedType() *deprecated.ConvertedType {
+	if !t.IsAdjustedToUTC {
+		return nil
+	}
 	switch {
 	case t.useInt32():
 		return &convertedTypes[deprecated.TimeMillis]
@@ -1898,18 +2152,17 @@ func (t *timeType) AssignValue(dst reflect.Value, src Value) error {
 func (t *timeType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch src := typ.(type) {
 	case *stringType:
-		tz := t.tz()
 		if t.Unit.Micros != nil {
-			return convertStringToTimeMicros(val, tz)
+			return convertStringToTimeMicros(val, time.UTC)
 		} else {
-			return convertStringToTimeMillis(val, tz)
+			return convertStringToTimeMillis(val, time.UTC)
 		}
 	case *timestampType:
-		tz := t.tz()
+		wallClock := src.wallClockOf(val, t.IsAdjustedToUTC, t.location())
 		if t.Unit.Micros != nil {
-			return convertTimestampToTimeMicros(val, src.Unit, src.tz(), tz)
+			return convertTimestampToTimeMicros(val, wallClock)
 		} else {
-			return convertTimestampToTimeMillis(val, src.Unit, src.tz(), tz)
+			return convertTimestampToTimeMillis(val, wallClock)
 		}
 	}
 	return t.baseType().ConvertValue(val, typ)
@@ -1919,20 +2172,86 @@ func (t *timeType) ConvertValue(val Value, typ Type) (Value, error) {
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#timestamp
 func Timestamp(unit TimeUnit) Node {
-	return Leaf(&timestampType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+	return TimestampAdjusted(unit, true)
 }
 
-type timestampType format.TimestampType
+// TimestampAdjusted constructs a leaf node of TIMESTAMP logical type with the
+// isAdjustedToUTC property set to the given value.
+//
+// Timestamps which are not adjusted to UTC (e.g. TIMESTAMP_NTZ in Spark, or
+// naive datetimes in pandas) represent the date and time of a wall clock,
+// which is read and written in UTC unless a location is configured with
+// LocalTimestamp. The time zone of the program is never used since it would
+// make the values depend on the machine reading or writing them.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#timestamp
+func TimestampAdjusted(unit TimeUnit, isAdjustedToUTC bool) Node {
+	return Leaf(&timestampType{
+		TimestampType: format.TimestampType{
+			IsAdjustedToUTC: isAdjustedToUTC,
+			Unit:            unit.TimeUnit(),
+		},
+	})
+}
+
+// LocalTimestamp constructs a leaf node of TIMESTAMP logical type which is not
+// adjusted to UTC, and where values represent the date and time of a wall
+// clock in loc.
+//
+// Values of local timestamps are read as time.Time values in loc, and the
+// location is used when converting to or from timestamps adjusted to UTC.
+// When writing time.Time values, the wall clock time of the value in loc is
+// stored. A nil location means UTC, which is also the default of timestamps
+// constructed with TimestampAdjusted.
+func LocalTimestamp(unit TimeUnit, loc *time.Location) Node {
+	return Leaf(&timestampType{
+		TimestampType: format.TimestampType{Unit: unit.TimeUnit()},
+		loc:           loc,
+	})
+}
+
+type timestampType struct {
+	format.TimestampType
+	loc *time.Location
+}
 
-func (t *timestampType) tz() *time.Location {
-	if t.IsAdjustedToUTC {
+// location returns the location of the wall clock that values of t represent.
+func (t *timestampType) location() *time.Location {
+	if t.IsAdjustedToUTC || t.loc == nil {
 		return time.UTC
-	} else {
-		return time.Local
 	}
+	return t.loc
+}
+
+// timeOf returns the time represented by the timestamp value v.
+func (t *timestampType) timeOf(v int64) time.Time {
+	tm := unixEpoch.Add(time.Duration(v) * timeUnitDuration(t.Unit))
+	if !t.IsAdjustedToUTC {
+		tm = wallClockIn(tm, t.location())
+	}
+	return tm
+}
+
+// valueOf returns the timestamp value representing tm.
+func (t *timestampType) valueOf(tm time.Time) int64 {
+	if !t.IsAdjustedToUTC {
+		tm = wallClockIn(tm.In(t.location()), time.UTC)
+	}
+	return timestampValue(tm, t.Unit)
+}
+
+// wallClockOf returns a time in UTC holding the wall clock time that the
+// timestamp value v represents for a time or timestamp with the given
+// isAdjustedToUTC property and location. The wall clock is preserved when
+// both are local, the location is used to convert between UTC and local.
+func (t *timestampType) wallClockOf(v Value, isAdjustedToUTC bool, loc *time.Location) time.Time {
+	if t.IsAdjustedToUTC == isAdjustedToUTC {
+		return unixEpoch.Add(time.Duration(v.int64()) * timeUnitDuration(t.Unit))
+	}
+	return wallClockIn(t.timeOf(v.int64()).In(loc), time.UTC)
 }
 
-func (t *timestampType) String() string { return (*format.TimestampType)(t).String() }
+func (t *timestampType) String() string { return t.TimestampType.String() }
 
 func (t *timestampType) Kind() Kind { return int64Type{}.Kind() }
 
@@ -1949,10 +2268,13 @@ func (t *timestampType) ColumnOrder() *format.ColumnOrder { return int64Type{}.C
 func (t *timestampType) PhysicalType() *format.Type { return int64Type{}.PhysicalType() }
 
 func (t *timestampType) LogicalType() *format.LogicalType {
-	return &format.LogicalType{Timestamp: (*format.TimestampType)(t)}
+	return &format.LogicalType{Timestamp: &t.TimestampType}
 }
 
 func (t *timestampType) ConvertedType() *deprecated.ConvertedType {
+	if !t.IsAdjustedToUTC {
+		return nil
+	}
 	switch {
 	case t.Unit.Millis != nil:
 		return &convertedTypes[deprecated.TimestampMillis]
@@ -1998,22 +2320,7 @@ func (t *timestampType) EstimateDecodeSize(numValues int, src []byte, enc encodi
 func (t *timestampType) AssignValue(dst reflect.Value, src Value) error {
 	switch dst.Type() {
 	case reflect.TypeOf(time.Time{}):
-		unit := Nanosecond.TimeUnit()
-		lt := t.LogicalType()
-		if lt != nil && lt.Timestamp != nil {
-			unit = lt.Timestamp.Unit
-		}
-
-		nanos := src.int64()
-		switch {
-		case unit.Millis != nil:
-			nanos = nanos * 1e6
-		case unit.Micros != nil:
-			nanos = nanos * 1e3
-		}
-
-		val := time.Unix(0, nanos).UTC()
-		dst.Set(reflect.ValueOf(val))
+		dst.Set(reflect.ValueOf(t.timeOf(src.int64())))
 		return nil
 	default:
 		return int64Type{}.AssignValue(dst, src)
@@ -2023,9 +2330,15 @@ func (t *timestampType) AssignValue(dst reflect.Value, src Value) error {
 func (t *timestampType) ConvertValue(val Value, typ Type) (Value, error) {
 	switch src := typ.(type) {
 	case *timestampType:
+		if src.IsAdjustedToUTC != t.IsAdjustedToUTC {
+			return val.convertToInt64(t.valueOf(src.timeOf(val.int64()))), nil
+		}
 		return convertTimestampToTimestamp(val, src.Unit, t.Unit)
 	case *dateType:
-		return convertDateToTimestamp(val, t.Unit, t.tz())
+		return convertDateToTimestamp(val, t.Unit, time.UTC)
+	}
+	if typ.Kind() == Int96 && !val.IsNull() {
+		return convertInt96ToTimestamp(val, t.Unit)
 	}
 	return int64Type{}.ConvertValue(val, typ)
 }
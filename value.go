@@ -180,6 +180,8 @@ func ValueOf(v interface{}) Value {
 		return makeValueBytes(FixedLenByteArray, value[:])
 	case deprecated.Int96:
 		return makeValueInt96(value)
+	case CalendarInterval:
+		return IntervalValue(value)
 	case time.Time:
 		k = Int64
 	}
@@ -244,6 +246,19 @@ func FloatValue(value float32) Value { return makeValueFloat(value) }
 // argument.
 func DoubleValue(value float64) Value { return makeValueDouble(value) }
 
+// Float16Value constructs a FIXED_LEN_BYTE_ARRAY parquet value holding the
+// FLOAT16 representation of the float32 passed as argument, rounded to the
+// nearest half-precision value.
+func Float16Value(value float32) Value {
+	return makeValueBytes(FixedLenByteArray, makeFloat16Bytes(float32ToFloat16(value)))
+}
+
+// IntervalValue constructs a FIXED_LEN_BYTE_ARRAY parquet value holding the
+// INTERVAL representation of the CalendarInterval passed as argument.
+func IntervalValue(value CalendarInterval) Value {
+	return makeValueBytes(FixedLenByteArray, makeCalendarIntervalBytes(value))
+}
+
 // ByteArrayValue constructs a BYTE_ARRAY parquet value from the byte slice
 // passed as argument.
 func ByteArrayValue(value []byte) Value { return makeValueBytes(ByteArray, value) }
@@ -271,6 +286,8 @@ func makeValue(k Kind, lt *format.LogicalType, v reflect.Value) Value {
 			val = t.UnixNano()
 		}
 		return makeValueInt64(val)
+	case reflect.TypeOf(CalendarInterval{}):
+		return IntervalValue(v.Interface().(CalendarInterval))
 	}
 
 	switch k {
@@ -519,6 +536,10 @@ func (v Value) convertToFixedLenByteArray(x []byte) Value {
 	return v
 }
 
+func (v Value) convertToFloat16(x float32) Value {
+	return v.convertToFixedLenByteArray(makeFloat16Bytes(float32ToFloat16(x)))
+}
+
 // Kind returns the kind of v, which represents its parquet physical type.
 func (v Value) Kind() Kind { return ^Kind(v.kind) }
 
@@ -552,6 +573,14 @@ func (v Value) Float() float32 { return v.float() }
 // Double returns v as a float64, assuming the underlying type is DOUBLE.
 func (v Value) Double() float64 { return v.double() }
 
+// Float16 returns v as a float32, assuming the underlying type is
+// FIXED_LEN_BYTE_ARRAY(2) holding a FLOAT16 value.
+func (v Value) Float16() float32 { return float16ToFloat32(float16Bits(v.byteArray())) }
+
+// Interval returns v as a CalendarInterval, assuming the underlying type is
+// FIXED_LEN_BYTE_ARRAY(12) holding an INTERVAL value.
+func (v Value) Interval() CalendarInterval { return makeCalendarInterval(v.byteArray()) }
+
 // Uint32 returns v as a uint32, assuming the underlying type is INT32.
 func (v Value) Uint32() uint32 { return v.uint32() }
 
@@ -0,0 +1,204 @@
+package parquet
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// Variant constructs a node of VARIANT logical type.
+//
+// Variant columns hold semi-structured values, similar to JSON documents, in a
+// binary encoding which does not need to be parsed to access nested fields.
+// The node is a group of two required binary fields: metadata, holding the
+// dictionary of field names used by the value, and the value itself.
+//
+// Files written by other parquet implementations may contain variant columns
+// which are shredded, where parts of the values are stored in typed_value
+// columns. When reading those files with a schema containing a variant which
+// is not shredded (for example a VariantValue field of a Go struct), the
+// values are reassembled from the shredded columns.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantEncoding.md
+func Variant() Node {
+	return variantNode{Group{
+		"metadata": Required(Leaf(ByteArrayType)),
+		"value":    Required(Leaf(ByteArrayType)),
+	}}
+}
+
+type variantNode struct{ Group }
+
+func (variantNode) Type() Type { return &variantType{SpecificationVersion: variantVersion} }
+
+func isVariant(node Node) bool {
+	logicalType := node.Type().LogicalType()
+	return logicalType != nil && logicalType.Variant != nil
+}
+
+// isStandardVariant returns true if the group node has the layout of a
+// variant: a required binary metadata field, and at least one of the value and
+// typed_value fields.
+func isStandardVariant(node Node) bool {
+	metadata := fieldByName(node, "metadata")
+	if metadata == nil || !metadata.Required() || !metadata.Leaf() || metadata.Type().Kind() != ByteArray {
+		return false
+	}
+	value := fieldByName(node, "value")
+	if value != nil && (value.Repeated() || !value.Leaf() || value.Type().Kind() != ByteArray) {
+		return false
+	}
+	return value != nil || fieldByName(node, "typed_value") != nil
+}
+
+type variantType format.VariantType
+
+func (t *variantType) String() string { return (*format.VariantType)(t).String() }
+
+func (t *variantType) Kind() Kind { panic("cannot call Kind on parquet VARIANT type") }
+
+func (t *variantType) Length() int { return 0 }
+
+func (t *variantType) EstimateSize(int) int { return 0 }
+
+func (t *variantType) EstimateNumValues(int) int { return 0 }
+
+func (t *variantType) Compare(Value, Value) int {
+	panic("cannot compare values on parquet VARIANT type")
+}
+
+func (t *variantType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *variantType) PhysicalType() *format.Type { return nil }
+
+func (t *variantType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Variant: (*format.VariantType)(t)}
+}
+
+func (t *variantType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *variantType) NewColumnIndexer(int) ColumnIndexer {
+	panic("cannot create column indexer from parquet VARIANT type")
+}
+
+func (t *variantType) NewDictionary(int, int, encoding.Values) Dictionary {
+	panic("cannot create dictionary from parquet VARIANT type")
+}
+
+func (t *variantType) NewColumnBuffer(int, int) ColumnBuffer {
+	panic("cannot create column buffer from parquet VARIANT type")
+}
+
+func (t *variantType) NewPage(int, int, encoding.Values) Page {
+	panic("cannot create page from parquet VARIANT type")
+}
+
+func (t *variantType) NewValues(values []byte, _ []uint32) encoding.Values {
+	panic("cannot create values from parquet VARIANT type")
+}
+
+func (t *variantType) Encode(_ []byte, _ encoding.Values, _ encoding.Encoding) ([]byte, error) {
+	panic("cannot encode parquet VARIANT type")
+}
+
+func (t *variantType) Decode(_ encoding.Values, _ []byte, _ encoding.Encoding) (encoding.Values, error) {
+	panic("cannot decode parquet VARIANT type")
+}
+
+func (t *variantType) EstimateDecodeSize(_ int, _ []byte, _ encoding.Encoding) int {
+	panic("cannot estimate decode size of parquet VARIANT type")
+}
+
+func (t *variantType) AssignValue(reflect.Value, Value) error {
+	panic("cannot assign value to a parquet VARIANT type")
+}
+
+func (t *variantType) ConvertValue(Value, Type) (Value, error) {
+	panic("cannot convert value to a parquet VARIANT type")
+}
+
+// VariantValue is the Go representation of values of the parquet VARIANT type.
+//
+// The Metadata and Value fields hold the variant binary encoding of the value.
+// The zero value represents the variant null value.
+//
+// VariantValue implements ParquetSchemaer, ParquetMarshaler and
+// ParquetUnmarshaler, struct fields of this type are represented by a Variant
+// node in the schema generated by SchemaOf.
+type VariantValue struct {
+	Metadata []byte
+	Value    []byte
+}
+
+// MakeVariant constructs a variant value from the Go value v.
+//
+// Maps with string keys are converted to variant objects, slices and arrays to
+// variant arrays (except for byte slices and arrays, which are converted to
+// binary values), and pointers and interfaces to the value they point to, or
+// null when they are nil. Integers are stored in the smallest variant integer
+// type that can hold them, time.Time values are stored as timestamps adjusted
+// to UTC, and json.Number values as integers, decimals or doubles depending on
+// their format, which makes it possible to convert JSON documents decoded with
+// json.Decoder.UseNumber without loss of precision.
+func MakeVariant(v interface{}) (VariantValue, error) {
+	e := variantEncoder{}
+	value, err := e.encode(nil, reflect.ValueOf(v))
+	if err != nil {
+		return VariantValue{}, err
+	}
+	return VariantValue{Metadata: e.metadata(), Value: value}, nil
+}
+
+// IsNull returns true if v is the variant null value.
+func (v VariantValue) IsNull() bool {
+	return len(v.Value) == 0 || v.Value[0] == variantNullValue[0]
+}
+
+// Interface returns the Go representation of the variant value.
+//
+// Objects are returned as map[string]interface{}, arrays as []interface{},
+// integers as int64, decimals as json.Number, dates and timestamps as
+// time.Time in UTC, times as time.Duration, binary values as []byte, and UUIDs
+// as uuid.UUID.
+func (v VariantValue) Interface() (interface{}, error) {
+	if len(v.Value) == 0 {
+		return nil, nil
+	}
+	metadata, err := parseVariantMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVariant(&metadata, v.Value)
+}
+
+func (v *VariantValue) ParquetSchema() Node { return Variant() }
+
+func (v *VariantValue) MarshalParquet(row Row) (Row, error) {
+	metadata, value := v.Metadata, v.Value
+	if len(value) == 0 {
+		metadata, value = variantEmptyMetadata, variantNullValue
+	}
+	if len(metadata) == 0 {
+		return row, fmt.Errorf("variant value has no metadata")
+	}
+	return append(row,
+		makeValueBytes(ByteArray, metadata).Level(0, 0, 0),
+		makeValueBytes(ByteArray, value).Level(0, 0, 1),
+	), nil
+}
+
+func (v *VariantValue) UnmarshalParquet(row Row) error {
+	v.Metadata, v.Value = nil, nil
+	for _, value := range row {
+		switch value.Column() {
+		case 0:
+			v.Metadata = copyBytes(value.byteArray())
+		case 1:
+			v.Value = copyBytes(value.byteArray())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,778 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// This file implements the variant binary encoding described in
+// https://github.com/apache/parquet-format/blob/master/VariantEncoding.md
+//
+// A variant is made of two byte sequences: the metadata, which holds a
+// dictionary of the field names of all objects in the value, and the value
+// itself, where objects reference their field names by index in the metadata
+// dictionary.
+
+const variantVersion = 1
+
+// Basic types, stored in the two low bits of the value header.
+const (
+	variantPrimitive   = 0
+	variantShortString = 1
+	variantObject      = 2
+	variantArray       = 3
+)
+
+// Primitive types, stored in the six high bits of the value header when the
+// basic type is variantPrimitive.
+const (
+	variantNull               = 0
+	variantTrue               = 1
+	variantFalse              = 2
+	variantInt8               = 3
+	variantInt16              = 4
+	variantInt32              = 5
+	variantInt64              = 6
+	variantDouble             = 7
+	variantDecimal4           = 8
+	variantDecimal8           = 9
+	variantDecimal16          = 10
+	variantDate               = 11
+	variantTimestampMicros    = 12
+	variantTimestampNTZMicros = 13
+	variantFloat              = 14
+	variantBinary             = 15
+	variantString             = 16
+	variantTimeNTZMicros      = 17
+	variantTimestampNanos     = 18
+	variantTimestampNTZNanos  = 19
+	variantUUID               = 20
+)
+
+const variantMaxShortStringLength = 63
+
+var (
+	errVariantTruncated = errors.New("invalid variant: value is truncated")
+
+	// variantEmptyMetadata is the metadata of variants which contain no
+	// objects.
+	variantEmptyMetadata = []byte{variantVersion, 0, 0}
+	// variantNullValue is the encoding of the variant null value.
+	variantNullValue = []byte{variantPrimitive | variantNull<<2}
+
+	variantPrimitiveSizes = [...]int{
+		variantNull:               0,
+		variantTrue:               0,
+		variantFalse:              0,
+		variantInt8:               1,
+		variantInt16:              2,
+		variantInt32:              4,
+		variantInt64:              8,
+		variantDouble:             8,
+		variantDecimal4:           1 + 4,
+		variantDecimal8:           1 + 8,
+		variantDecimal16:          1 + 16,
+		variantDate:               4,
+		variantTimestampMicros:    8,
+		variantTimestampNTZMicros: 8,
+		variantFloat:              4,
+		variantBinary:             -1,
+		variantString:             -1,
+		variantTimeNTZMicros:      8,
+		variantTimestampNanos:     8,
+		variantTimestampNTZNanos:  8,
+		variantUUID:               16,
+	}
+)
+
+func readVariantUint(b []byte, size int) int {
+	v := 0
+	for i := size - 1; i >= 0; i-- {
+		v = v<<8 | int(b[i])
+	}
+	return v
+}
+
+func appendVariantUint(b []byte, v, size int) []byte {
+	for i := 0; i < size; i++ {
+		b = append(b, byte(v>>(8*uint(i))))
+	}
+	return b
+}
+
+// variantIntSize returns the number of bytes needed to represent v, between 1
+// and 4.
+func variantIntSize(v int) int {
+	switch {
+	case v <= math.MaxUint8:
+		return 1
+	case v <= math.MaxUint16:
+		return 2
+	case v <= 1<<24-1:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// variantMetadata is the decoded form of the metadata of a variant.
+type variantMetadata struct {
+	names []string
+}
+
+func parseVariantMetadata(b []byte) (variantMetadata, error) {
+	if len(b) == 0 {
+		return variantMetadata{}, errVariantTruncated
+	}
+	header := b[0]
+	if version := header & 0x0F; version != variantVersion {
+		return variantMetadata{}, fmt.Errorf("invalid variant: unsupported metadata version %d", version)
+	}
+	offsetSize := int(header>>6) + 1
+	b = b[1:]
+	if len(b) < offsetSize {
+		return variantMetadata{}, errVariantTruncated
+	}
+	n := readVariantUint(b, offsetSize)
+	b = b[offsetSize:]
+	if n+1 > len(b)/offsetSize {
+		return variantMetadata{}, errVariantTruncated
+	}
+	offsets, data := b[:(n+1)*offsetSize], b[(n+1)*offsetSize:]
+	names := make([]string, n)
+	start := readVariantUint(offsets, offsetSize)
+	for i := range names {
+		end := readVariantUint(offsets[(i+1)*offsetSize:], offsetSize)
+		if start > end || end > len(data) {
+			return variantMetadata{}, fmt.Errorf("invalid variant: metadata string %d has offsets [%d:%d] out of bounds", i, start, end)
+		}
+		names[i] = string(data[start:end])
+		start = end
+	}
+	return variantMetadata{names: names}, nil
+}
+
+func (m *variantMetadata) name(id int) (string, error) {
+	if id < 0 || id >= len(m.names) {
+		return "", fmt.Errorf("invalid variant: field id %d out of range of the metadata dictionary of size %d", id, len(m.names))
+	}
+	return m.names[id], nil
+}
+
+// variantValueSize returns the size of the variant value at the beginning of
+// b, which may be followed by other data.
+func variantValueSize(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, errVariantTruncated
+	}
+	header := b[0]
+	size := 0
+	switch basicType, valueHeader := header&3, int(header>>2); basicType {
+	case variantPrimitive:
+		if valueHeader >= len(variantPrimitiveSizes) {
+			return 0, fmt.Errorf("invalid variant: unknown primitive type %d", valueHeader)
+		}
+		if size = variantPrimitiveSizes[valueHeader]; size < 0 {
+			if len(b) < 5 {
+				return 0, errVariantTruncated
+			}
+			size = 4 + int(binary.LittleEndian.Uint32(b[1:]))
+		}
+		size++
+	case variantShortString:
+		size = 1 + valueHeader
+	case variantObject, variantArray:
+		_, _, offsets, offsetSize, data, err := parseVariantContainer(b)
+		if err != nil {
+			return 0, err
+		}
+		size = len(b) - len(data) + readVariantUint(offsets[len(offsets)-offsetSize:], offsetSize)
+	}
+	if size > len(b) {
+		return 0, errVariantTruncated
+	}
+	return size, nil
+}
+
+// parseVariantContainer splits the object or array at the beginning of b into
+// its number of elements, field ids (nil for arrays), element offsets, and
+// the data section holding the values.
+func parseVariantContainer(b []byte) (n int, ids []byte, offsets []byte, offsetSize int, data []byte, err error) {
+	header := b[0]
+	valueHeader := int(header >> 2)
+	offsetSize = valueHeader&3 + 1
+	idSize := 0
+	isLarge := false
+	if header&3 == variantObject {
+		idSize = (valueHeader>>2)&3 + 1
+		isLarge = (valueHeader>>4)&1 != 0
+	} else {
+		isLarge = (valueHeader>>2)&1 != 0
+	}
+
+	b = b[1:]
+	countSize := 1
+	if isLarge {
+		countSize = 4
+	}
+	if len(b) < countSize {
+		return 0, nil, nil, 0, nil, errVariantTruncated
+	}
+	n = readVariantUint(b, countSize)
+	b = b[countSize:]
+
+	if n > len(b)/(idSize+offsetSize) {
+		return 0, nil, nil, 0, nil, errVariantTruncated
+	}
+	ids, b = b[:n*idSize], b[n*idSize:]
+	if len(b) < (n+1)*offsetSize {
+		return 0, nil, nil, 0, nil, errVariantTruncated
+	}
+	offsets, data = b[:(n+1)*offsetSize], b[(n+1)*offsetSize:]
+	if idSize == 0 {
+		ids = nil
+	}
+	return n, ids, offsets, offsetSize, data, nil
+}
+
+// variantElement returns the i-th value of an object or array.
+func variantElement(offsets []byte, offsetSize int, data []byte, i int) ([]byte, error) {
+	offset := readVariantUint(offsets[i*offsetSize:], offsetSize)
+	if offset > len(data) {
+		return nil, errVariantTruncated
+	}
+	value := data[offset:]
+	size, err := variantValueSize(value)
+	if err != nil {
+		return nil, err
+	}
+	return value[:size], nil
+}
+
+// variantObjectField is a field of an object being encoded.
+type variantObjectField struct {
+	id    int
+	name  string
+	value []byte
+}
+
+// variantEncoder encodes values in the variant binary format, accumulating the
+// field names of objects in the metadata dictionary.
+type variantEncoder struct {
+	names []string
+	ids   map[string]int
+}
+
+func (e *variantEncoder) fieldID(name string) int {
+	id, ok := e.ids[name]
+	if !ok {
+		if e.ids == nil {
+			e.ids = make(map[string]int)
+		}
+		id = len(e.names)
+		e.ids[name] = id
+		e.names = append(e.names, name)
+	}
+	return id
+}
+
+// metadata returns the encoding of the metadata dictionary.
+func (e *variantEncoder) metadata() []byte {
+	if len(e.names) == 0 {
+		return append([]byte(nil), variantEmptyMetadata...)
+	}
+	size := 0
+	sorted := true
+	for i, name := range e.names {
+		size += len(name)
+		if i > 0 && e.names[i-1] >= name {
+			sorted = false
+		}
+	}
+	offsetSize := variantIntSize(size)
+	if n := variantIntSize(len(e.names)); n > offsetSize {
+		offsetSize = n
+	}
+	header := byte(variantVersion) | byte(offsetSize-1)<<6
+	if sorted {
+		header |= 1 << 4
+	}
+	b := make([]byte, 0, 1+(len(e.names)+2)*offsetSize+size)
+	b = append(b, header)
+	b = appendVariantUint(b, len(e.names), offsetSize)
+	offset := 0
+	for _, name := range e.names {
+		b = appendVariantUint(b, offset, offsetSize)
+		offset += len(name)
+	}
+	b = appendVariantUint(b, offset, offsetSize)
+	for _, name := range e.names {
+		b = append(b, name...)
+	}
+	return b
+}
+
+func (e *variantEncoder) appendObject(b []byte, fields []variantObjectField) []byte {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+	maxID, size := 0, 0
+	for i := range fields {
+		fields[i].id = e.fieldID(fields[i].name)
+		if fields[i].id > maxID {
+			maxID = fields[i].id
+		}
+		size += len(fields[i].value)
+	}
+	idSize := variantIntSize(maxID)
+	offsetSize := variantIntSize(size)
+	isLarge := len(fields) > math.MaxUint8
+
+	valueHeader := byte(offsetSize-1) | byte(idSize-1)<<2
+	if isLarge {
+		valueHeader |= 1 << 4
+	}
+	b = append(b, variantObject|valueHeader<<2)
+	b = appendVariantCount(b, len(fields), isLarge)
+	for _, f := range fields {
+		b = appendVariantUint(b, f.id, idSize)
+	}
+	offset := 0
+	for _, f := range fields {
+		b = appendVariantUint(b, offset, offsetSize)
+		offset += len(f.value)
+	}
+	b = appendVariantUint(b, offset, offsetSize)
+	for _, f := range fields {
+		b = append(b, f.value...)
+	}
+	return b
+}
+
+func appendVariantArray(b []byte, elements [][]byte) []byte {
+	size := 0
+	for _, elem := range elements {
+		size += len(elem)
+	}
+	offsetSize := variantIntSize(size)
+	isLarge := len(elements) > math.MaxUint8
+
+	valueHeader := byte(offsetSize - 1)
+	if isLarge {
+		valueHeader |= 1 << 2
+	}
+	b = append(b, variantArray|valueHeader<<2)
+	b = appendVariantCount(b, len(elements), isLarge)
+	offset := 0
+	for _, elem := range elements {
+		b = appendVariantUint(b, offset, offsetSize)
+		offset += len(elem)
+	}
+	b = appendVariantUint(b, offset, offsetSize)
+	for _, elem := range elements {
+		b = append(b, elem...)
+	}
+	return b
+}
+
+func appendVariantCount(b []byte, n int, isLarge bool) []byte {
+	if isLarge {
+		return appendVariantUint(b, n, 4)
+	}
+	return append(b, byte(n))
+}
+
+func appendVariantPrimitive(b []byte, primitiveType byte) []byte {
+	return append(b, variantPrimitive|primitiveType<<2)
+}
+
+func appendVariantBool(b []byte, v bool) []byte {
+	if v {
+		return appendVariantPrimitive(b, variantTrue)
+	}
+	return appendVariantPrimitive(b, variantFalse)
+}
+
+func appendVariantInt(b []byte, v int64) []byte {
+	switch {
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return append(appendVariantPrimitive(b, variantInt8), byte(v))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return binary.LittleEndian.AppendUint16(appendVariantPrimitive(b, variantInt16), uint16(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return binary.LittleEndian.AppendUint32(appendVariantPrimitive(b, variantInt32), uint32(v))
+	default:
+		return binary.LittleEndian.AppendUint64(appendVariantPrimitive(b, variantInt64), uint64(v))
+	}
+}
+
+func appendVariantFloat(b []byte, v float32) []byte {
+	return binary.LittleEndian.AppendUint32(appendVariantPrimitive(b, variantFloat), math.Float32bits(v))
+}
+
+func appendVariantDouble(b []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(appendVariantPrimitive(b, variantDouble), math.Float64bits(v))
+}
+
+func appendVariantString(b []byte, s string) []byte {
+	if len(s) <= variantMaxShortStringLength {
+		b = append(b, variantShortString|byte(len(s))<<2)
+		return append(b, s...)
+	}
+	b = binary.LittleEndian.AppendUint32(appendVariantPrimitive(b, variantString), uint32(len(s)))
+	return append(b, s...)
+}
+
+func appendVariantBinary(b []byte, v []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(appendVariantPrimitive(b, variantBinary), uint32(len(v)))
+	return append(b, v...)
+}
+
+func appendVariantInt64(b []byte, primitiveType byte, v int64) []byte {
+	return binary.LittleEndian.AppendUint64(appendVariantPrimitive(b, primitiveType), uint64(v))
+}
+
+func appendVariantDate(b []byte, days int32) []byte {
+	return binary.LittleEndian.AppendUint32(appendVariantPrimitive(b, variantDate), uint32(days))
+}
+
+func appendVariantUUID(b []byte, v uuid.UUID) []byte {
+	return append(appendVariantPrimitive(b, variantUUID), v[:]...)
+}
+
+// appendVariantTime appends t as a timestamp adjusted to UTC, with microsecond
+// precision when it does not lose information.
+func appendVariantTime(b []byte, t time.Time) []byte {
+	if t.Nanosecond()%1000 == 0 {
+		return appendVariantInt64(b, variantTimestampMicros, t.UnixMicro())
+	}
+	return appendVariantInt64(b, variantTimestampNanos, t.UnixNano())
+}
+
+// appendVariantDecimal appends the decimal number of the given unscaled value
+// and scale, using the smallest of the decimal encodings that can hold it.
+func appendVariantDecimal(b []byte, unscaled *big.Int, scale int) ([]byte, error) {
+	if scale < 0 || scale > 38 {
+		return b, fmt.Errorf("decimal scale %d is out of the range supported by variants", scale)
+	}
+	switch {
+	case unscaled.IsInt64() && unscaled.Int64() >= math.MinInt32 && unscaled.Int64() <= math.MaxInt32:
+		b = append(appendVariantPrimitive(b, variantDecimal4), byte(scale))
+		return binary.LittleEndian.AppendUint32(b, uint32(unscaled.Int64())), nil
+	case unscaled.IsInt64():
+		b = append(appendVariantPrimitive(b, variantDecimal8), byte(scale))
+		return binary.LittleEndian.AppendUint64(b, uint64(unscaled.Int64())), nil
+	default:
+		be, err := bigIntToTwosComplement(unscaled, 16)
+		if err != nil {
+			return b, err
+		}
+		b = append(appendVariantPrimitive(b, variantDecimal16), byte(scale))
+		for i := len(be) - 1; i >= 0; i-- {
+			b = append(b, be[i])
+		}
+		return b, nil
+	}
+}
+
+// appendVariantNumber appends the variant representation of a JSON number, preserving
+// integers and decimal numbers when possible.
+func appendVariantNumber(b []byte, n json.Number) ([]byte, error) {
+	s := n.String()
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return appendVariantInt(b, i), nil
+	}
+	if !strings.ContainsAny(s, "eE") {
+		scale := 0
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			scale = len(s) - (i + 1)
+		}
+		if unscaled, err := parseDecimal(s, scale); err == nil && len(new(big.Int).Abs(unscaled).String()) <= 38 {
+			if b, err := appendVariantDecimal(b, unscaled, scale); err == nil {
+				return b, nil
+			}
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return b, err
+	}
+	return appendVariantDouble(b, f), nil
+}
+
+// encode appends the variant representation of the Go value v to b.
+func (e *variantEncoder) encode(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(b, variantNullValue...), nil
+	}
+
+	switch v.Type() {
+	case reflect.TypeOf(json.Number("")):
+		return appendVariantNumber(b, json.Number(v.String()))
+	case reflect.TypeOf(time.Time{}):
+		return appendVariantTime(b, v.Interface().(time.Time)), nil
+	case reflect.TypeOf(uuid.UUID{}):
+		return appendVariantUUID(b, v.Interface().(uuid.UUID)), nil
+	case reflect.TypeOf(VariantValue{}):
+		variant := v.Interface().(VariantValue)
+		if variant.Value == nil {
+			return append(b, variantNullValue...), nil
+		}
+		metadata, err := parseVariantMetadata(variant.Metadata)
+		if err != nil {
+			return b, err
+		}
+		return e.appendCopy(b, &metadata, variant.Value)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return appendVariantBool(b, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendVariantInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return appendVariantDecimal(b, new(big.Int).SetUint64(u), 0)
+		} else {
+			return appendVariantInt(b, int64(u)), nil
+		}
+	case reflect.Float32:
+		return appendVariantFloat(b, float32(v.Float())), nil
+	case reflect.Float64:
+		return appendVariantDouble(b, v.Float()), nil
+	case reflect.String:
+		return appendVariantString(b, v.String()), nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return append(b, variantNullValue...), nil
+		}
+		return e.encode(b, v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Kind() == reflect.Slice {
+				if v.IsNil() {
+					return append(b, variantNullValue...), nil
+				}
+				return appendVariantBinary(b, v.Bytes()), nil
+			}
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return appendVariantBinary(b, data), nil
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(b, variantNullValue...), nil
+		}
+		elements := make([][]byte, v.Len())
+		for i := range elements {
+			elem, err := e.encode(nil, v.Index(i))
+			if err != nil {
+				return b, err
+			}
+			elements[i] = elem
+		}
+		return appendVariantArray(b, elements), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return b, fmt.Errorf("cannot encode go map of type %s as variant object: keys must be strings", v.Type())
+		}
+		if v.IsNil() {
+			return append(b, variantNullValue...), nil
+		}
+		fields := make([]variantObjectField, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			value, err := e.encode(nil, it.Value())
+			if err != nil {
+				return b, fmt.Errorf("%s → %w", it.Key().String(), err)
+			}
+			fields = append(fields, variantObjectField{name: it.Key().String(), value: value})
+		}
+		return e.appendObject(b, fields), nil
+	}
+
+	return b, fmt.Errorf("cannot encode go value of type %s as variant", v.Type())
+}
+
+// appendCopy appends the variant value of b, which references field names in
+// the metadata m, to dst, rewriting the field ids of objects to reference the
+// metadata dictionary of e.
+func (e *variantEncoder) appendCopy(dst []byte, m *variantMetadata, b []byte) ([]byte, error) {
+	size, err := variantValueSize(b)
+	if err != nil {
+		return dst, err
+	}
+	b = b[:size]
+
+	switch b[0] & 3 {
+	case variantObject:
+		fields, err := e.copyFields(m, b)
+		if err != nil {
+			return dst, err
+		}
+		return e.appendObject(dst, fields), nil
+	case variantArray:
+		n, _, offsets, offsetSize, data, err := parseVariantContainer(b)
+		if err != nil {
+			return dst, err
+		}
+		elements := make([][]byte, n)
+		for i := range elements {
+			elem, err := variantElement(offsets, offsetSize, data, i)
+			if err != nil {
+				return dst, err
+			}
+			if elements[i], err = e.appendCopy(nil, m, elem); err != nil {
+				return dst, err
+			}
+		}
+		return appendVariantArray(dst, elements), nil
+	default:
+		return append(dst, b...), nil
+	}
+}
+
+// copyFields returns the fields of the object b, with values re-encoded to
+// reference the metadata dictionary of e.
+func (e *variantEncoder) copyFields(m *variantMetadata, b []byte) ([]variantObjectField, error) {
+	n, ids, offsets, offsetSize, data, err := parseVariantContainer(b)
+	if err != nil {
+		return nil, err
+	}
+	idSize := 0
+	if n > 0 {
+		idSize = len(ids) / n
+	}
+	fields := make([]variantObjectField, n)
+	for i := range fields {
+		name, err := m.name(readVariantUint(ids[i*idSize:], idSize))
+		if err != nil {
+			return nil, err
+		}
+		elem, err := variantElement(offsets, offsetSize, data, i)
+		if err != nil {
+			return nil, err
+		}
+		value, err := e.appendCopy(nil, m, elem)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = variantObjectField{name: name, value: value}
+	}
+	return fields, nil
+}
+
+// decodeVariant returns the Go representation of the variant value b.
+func decodeVariant(m *variantMetadata, b []byte) (interface{}, error) {
+	size, err := variantValueSize(b)
+	if err != nil {
+		return nil, err
+	}
+	b = b[:size]
+
+	switch basicType, valueHeader := b[0]&3, b[0]>>2; basicType {
+	case variantShortString:
+		return string(b[1:]), nil
+	case variantObject:
+		n, ids, offsets, offsetSize, data, err := parseVariantContainer(b)
+		if err != nil {
+			return nil, err
+		}
+		idSize := 0
+		if n > 0 {
+			idSize = len(ids) / n
+		}
+		object := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			name, err := m.name(readVariantUint(ids[i*idSize:], idSize))
+			if err != nil {
+				return nil, err
+			}
+			elem, err := variantElement(offsets, offsetSize, data, i)
+			if err != nil {
+				return nil, err
+			}
+			if object[name], err = decodeVariant(m, elem); err != nil {
+				return nil, fmt.Errorf("%s → %w", name, err)
+			}
+		}
+		return object, nil
+	case variantArray:
+		n, _, offsets, offsetSize, data, err := parseVariantContainer(b)
+		if err != nil {
+			return nil, err
+		}
+		array := make([]interface{}, n)
+		for i := range array {
+			elem, err := variantElement(offsets, offsetSize, data, i)
+			if err != nil {
+				return nil, err
+			}
+			if array[i], err = decodeVariant(m, elem); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	default:
+		return decodeVariantPrimitive(valueHeader, b[1:])
+	}
+}
+
+func decodeVariantPrimitive(primitiveType byte, b []byte) (interface{}, error) {
+	switch primitiveType {
+	case variantNull:
+		return nil, nil
+	case variantTrue:
+		return true, nil
+	case variantFalse:
+		return false, nil
+	case variantInt8:
+		return int64(int8(b[0])), nil
+	case variantInt16:
+		return int64(int16(binary.LittleEndian.Uint16(b))), nil
+	case variantInt32:
+		return int64(int32(binary.LittleEndian.Uint32(b))), nil
+	case variantInt64:
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case variantDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case variantFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case variantDecimal4, variantDecimal8, variantDecimal16:
+		unscaled := variantDecimalUnscaledValue(b[1:])
+		return json.Number(appendDecimal(nil, unscaled, int(b[0]))), nil
+	case variantDate:
+		return unixEpoch.AddDate(0, 0, int(int32(binary.LittleEndian.Uint32(b)))), nil
+	case variantTimestampMicros, variantTimestampNTZMicros:
+		return time.UnixMicro(int64(binary.LittleEndian.Uint64(b))).UTC(), nil
+	case variantTimestampNanos, variantTimestampNTZNanos:
+		return time.Unix(0, int64(binary.LittleEndian.Uint64(b))).UTC(), nil
+	case variantTimeNTZMicros:
+		return time.Duration(binary.LittleEndian.Uint64(b)) * time.Microsecond, nil
+	case variantBinary:
+		return append([]byte{}, b[4:]...), nil
+	case variantString:
+		return string(b[4:]), nil
+	case variantUUID:
+		return uuid.UUID(*(*[16]byte)(b)), nil
+	default:
+		return nil, fmt.Errorf("invalid variant: unknown primitive type %d", primitiveType)
+	}
+}
+
+// variantDecimalUnscaledValue decodes the little endian two's complement
+// unscaled value of a decimal.
+func variantDecimalUnscaledValue(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return decimalUnscaledValue(makeValueBytes(FixedLenByteArray, be))
+}
@@ -0,0 +1,408 @@
+package parquet
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// This file implements the reassembly of variant values which were shredded
+// into typed columns, as described in
+// https://github.com/apache/parquet-format/blob/master/VariantShredding.md
+//
+// A shredded variant group contains, next to the metadata, an optional binary
+// value field and an optional typed_value field. The typed_value field is one
+// of:
+//
+//   - a primitive column, holding values of a single variant type,
+//   - a LIST of groups of value and typed_value fields, holding arrays,
+//   - a group of groups of value and typed_value fields, holding objects.
+//
+// The value field holds the variant values which did not match the shredding
+// schema; for objects, it may hold the fields which were not shredded.
+
+// variantReassembleFunc reassembles the variant value of a group of value and
+// typed_value fields, returning nil if neither of them is set.
+type variantReassembleFunc func(e *variantEncoder, m *variantMetadata, levels levels, columns [][]Value) ([]byte, error)
+
+// variantObjectFunc reassembles the fields of a shredded object.
+type variantObjectFunc func(e *variantEncoder, m *variantMetadata, levels levels, columns [][]Value) ([]variantObjectField, error)
+
+// variantReassembly reconstructs the metadata and value columns of a variant
+// from the columns of a shredded variant group.
+type variantReassembly struct {
+	// Range of columns of the variant group in the source schema.
+	columnIndex int
+	numColumns  int
+	// Column holding the metadata, relative to columnIndex.
+	metadataColumn int
+	// Levels of the variant group in the source schema.
+	repetitionDepth byte
+	definitionLevel byte
+	reassemble      variantReassembleFunc
+}
+
+func isShreddedVariant(node Node) bool {
+	return isVariant(node) && fieldByName(node, "typed_value") != nil
+}
+
+func newVariantReassembly(node Node, path columnPath, mapping columnMappingGroup) (*variantReassembly, error) {
+	reassemble, err := variantReassembleFuncOf(node)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r := &variantReassembly{columnIndex: -1, reassemble: reassemble}
+	metadata := mapping.lookup(path.append("metadata"))
+
+	forEachLeafColumnOf(node, func(leaf leafColumn) {
+		column := mapping.lookup(path.append(leaf.path...))
+		if r.columnIndex < 0 {
+			r.columnIndex = int(column.columnIndex)
+		}
+		r.numColumns++
+	})
+
+	r.metadataColumn = int(metadata.columnIndex) - r.columnIndex
+	r.repetitionDepth = metadata.maxRepetitionLevel
+	r.definitionLevel = metadata.maxDefinitionLevel
+	return r, nil
+}
+
+// reassembleValues appends the values of the metadata and value columns of the
+// variants found in the columns of a source row.
+func (r *variantReassembly) reassembleValues(metadata, values []Value, columns [][]Value) ([]Value, []Value, error) {
+	columns = columns[r.columnIndex : r.columnIndex+r.numColumns]
+
+	err := splitRepeatedColumns(columns, r.repetitionDepth, func(columns [][]Value) error {
+		meta := columns[r.metadataColumn][0]
+		if meta.IsNull() {
+			metadata = append(metadata, meta)
+			values = append(values, meta)
+			return nil
+		}
+
+		m, err := parseVariantMetadata(meta.byteArray())
+		if err != nil {
+			return err
+		}
+		e := variantEncoder{}
+		b, err := r.reassemble(&e, &m, levels{
+			repetitionDepth: r.repetitionDepth,
+			definitionLevel: r.definitionLevel,
+		}, columns)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			b = variantNullValue
+		}
+
+		metadataValue := makeValueBytes(ByteArray, e.metadata())
+		valueValue := makeValueBytes(ByteArray, b)
+		for _, v := range [...]*Value{&metadataValue, &valueValue} {
+			v.repetitionLevel = meta.repetitionLevel
+			v.definitionLevel = meta.definitionLevel
+			v.columnIndex = meta.columnIndex
+		}
+		metadata = append(metadata, metadataValue)
+		values = append(values, valueValue)
+		return nil
+	})
+
+	return metadata, values, err
+}
+
+// splitRepeatedColumns calls do for each repetition of the values in columns
+// at the given repetition depth.
+func splitRepeatedColumns(columns [][]Value, repetitionDepth byte, do func([][]Value) error) error {
+	values := make([][]Value, len(columns))
+	offsets := make([]int, len(columns))
+
+	for offsets[0] < len(columns[0]) {
+		for j, column := range columns {
+			i := offsets[j]
+			if i >= len(column) {
+				return fmt.Errorf("shredded variant columns have mismatching numbers of values")
+			}
+			k := i + 1
+			for k < len(column) && column[k].repetitionLevel > repetitionDepth {
+				k++
+			}
+			values[j] = column[i:k]
+			offsets[j] = k
+		}
+		if err := do(values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// variantReassembleFuncOf returns the function reassembling the variant values
+// of a group of value and typed_value fields.
+func variantReassembleFuncOf(node Node) (variantReassembleFunc, error) {
+	if node.Leaf() {
+		return nil, fmt.Errorf("shredded variant field must be a group of value and typed_value fields")
+	}
+
+	valueColumn, typedColumn, typedEnd := -1, -1, -1
+	valueOptional, typedOptional := false, false
+	var typedValue variantReassembleFunc
+	var typedObject variantObjectFunc
+	columnIndex := 0
+
+	for _, field := range node.Fields() {
+		numColumns := int(numLeafColumnsOf(field))
+		switch field.Name() {
+		case "value":
+			if !field.Leaf() || field.Type().Kind() != ByteArray {
+				return nil, fmt.Errorf("shredded variant value field must be binary")
+			}
+			valueColumn = columnIndex
+			valueOptional = field.Optional()
+		case "typed_value":
+			var err error
+			switch {
+			case field.Leaf():
+				typedValue = variantReassembleFuncOfPrimitive(field.Type())
+			case isList(field):
+				typedValue, err = variantReassembleFuncOfArray(field)
+			default:
+				typedObject, err = variantReassembleFuncOfObject(field)
+			}
+			if err != nil {
+				return nil, err
+			}
+			typedColumn, typedEnd = columnIndex, columnIndex+numColumns
+			typedOptional = field.Optional()
+		}
+		columnIndex += numColumns
+	}
+
+	return func(e *variantEncoder, m *variantMetadata, levels levels, columns [][]Value) ([]byte, error) {
+		var value []byte
+		if valueColumn >= 0 {
+			if v := columns[valueColumn][0]; !valueOptional || v.definitionLevel > levels.definitionLevel {
+				value = v.byteArray()
+			}
+		}
+
+		if typedColumn >= 0 {
+			typedLevels := levels
+			if typedOptional {
+				typedLevels.definitionLevel++
+			}
+			typedColumns := columns[typedColumn:typedEnd]
+
+			if typedColumns[0][0].definitionLevel >= typedLevels.definitionLevel {
+				if typedObject == nil {
+					if value != nil {
+						return nil, fmt.Errorf("shredded variant has both value and typed_value set")
+					}
+					return typedValue(e, m, typedLevels, typedColumns)
+				}
+
+				fields, err := typedObject(e, m, typedLevels, typedColumns)
+				if err != nil {
+					return nil, err
+				}
+				if value != nil {
+					// Partially shredded object, the fields which were not
+					// shredded are stored in the value column.
+					if len(value) == 0 || value[0]&3 != variantObject {
+						return nil, fmt.Errorf("shredded variant object has a value which is not an object")
+					}
+					rest, err := e.copyFields(m, value)
+					if err != nil {
+						return nil, err
+					}
+					fields = append(fields, rest...)
+				}
+				return e.appendObject(nil, fields), nil
+			}
+		}
+
+		if value != nil {
+			return e.appendCopy(nil, m, value)
+		}
+		return nil, nil
+	}, nil
+}
+
+func variantReassembleFuncOfPrimitive(typ Type) variantReassembleFunc {
+	return func(_ *variantEncoder, _ *variantMetadata, _ levels, columns [][]Value) ([]byte, error) {
+		return appendVariantParquetValue(nil, typ, columns[0][0])
+	}
+}
+
+func variantReassembleFuncOfArray(node Node) (variantReassembleFunc, error) {
+	fields := node.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() || len(fields[0].Fields()) != 1 {
+		return nil, fmt.Errorf("shredded variant array must be a LIST of groups of value and typed_value fields")
+	}
+	element := fields[0].Fields()[0]
+	elementOptional := element.Optional()
+	reassemble, err := variantReassembleFuncOf(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *variantEncoder, m *variantMetadata, levels levels, columns [][]Value) ([]byte, error) {
+		levels.repetitionDepth++
+		levels.definitionLevel++
+
+		if columns[0][0].definitionLevel < levels.definitionLevel {
+			return appendVariantArray(nil, nil), nil
+		}
+
+		var elements [][]byte
+		err := splitRepeatedColumns(columns, levels.repetitionDepth, func(columns [][]Value) error {
+			elementLevels := levels
+			if elementOptional {
+				elementLevels.definitionLevel++
+			}
+			var b []byte
+			if columns[0][0].definitionLevel >= elementLevels.definitionLevel {
+				var err error
+				if b, err = reassemble(e, m, elementLevels, columns); err != nil {
+					return err
+				}
+			}
+			if b == nil {
+				b = variantNullValue
+			}
+			elements = append(elements, b)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return appendVariantArray(nil, elements), nil
+	}, nil
+}
+
+func variantReassembleFuncOfObject(node Node) (variantObjectFunc, error) {
+	fields := node.Fields()
+	funcs := make([]variantReassembleFunc, len(fields))
+	columnOffsets := make([]int, len(fields)+1)
+
+	for i, field := range fields {
+		f, err := variantReassembleFuncOf(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name(), err)
+		}
+		funcs[i] = f
+		columnOffsets[i+1] = columnOffsets[i] + int(numLeafColumnsOf(field))
+	}
+
+	return func(e *variantEncoder, m *variantMetadata, levels levels, columns [][]Value) ([]variantObjectField, error) {
+		object := make([]variantObjectField, 0, len(fields))
+
+		for i, field := range fields {
+			fieldColumns := columns[columnOffsets[i]:columnOffsets[i+1]]
+			fieldLevels := levels
+			if field.Optional() {
+				fieldLevels.definitionLevel++
+				if fieldColumns[0][0].definitionLevel < fieldLevels.definitionLevel {
+					continue
+				}
+			}
+			b, err := funcs[i](e, m, fieldLevels, fieldColumns)
+			if err != nil {
+				return nil, fmt.Errorf("%s → %w", field.Name(), err)
+			}
+			if b != nil {
+				object = append(object, variantObjectField{name: field.Name(), value: b})
+			}
+		}
+
+		return object, nil
+	}, nil
+}
+
+// appendVariantParquetValue appends the variant representation of the parquet
+// value v of type typ to b.
+func appendVariantParquetValue(b []byte, typ Type, v Value) ([]byte, error) {
+	if v.IsNull() {
+		return append(b, variantNullValue...), nil
+	}
+
+	lt := typ.LogicalType()
+	if lt == nil {
+		lt = new(format.LogicalType)
+	}
+
+	switch typ.Kind() {
+	case Boolean:
+		return appendVariantBool(b, v.boolean()), nil
+
+	case Int32:
+		switch {
+		case lt.Date != nil:
+			return appendVariantDate(b, v.int32()), nil
+		case lt.Decimal != nil:
+			return appendVariantDecimal(b, big.NewInt(int64(v.int32())), int(lt.Decimal.Scale))
+		case lt.Time != nil:
+			return appendVariantInt64(b, variantTimeNTZMicros, int64(v.int32())*1e3), nil
+		case lt.Integer != nil && !lt.Integer.IsSigned:
+			return appendVariantInt(b, int64(uint32(v.int32()))), nil
+		default:
+			return appendVariantInt(b, int64(v.int32())), nil
+		}
+
+	case Int64:
+		switch {
+		case lt.Decimal != nil:
+			return appendVariantDecimal(b, big.NewInt(v.int64()), int(lt.Decimal.Scale))
+		case lt.Timestamp != nil:
+			t := lt.Timestamp
+			switch {
+			case t.Unit.Nanos != nil && t.IsAdjustedToUTC:
+				return appendVariantInt64(b, variantTimestampNanos, v.int64()), nil
+			case t.Unit.Nanos != nil:
+				return appendVariantInt64(b, variantTimestampNTZNanos, v.int64()), nil
+			}
+			micros := v.int64()
+			if t.Unit.Millis != nil {
+				micros *= 1e3
+			}
+			if t.IsAdjustedToUTC {
+				return appendVariantInt64(b, variantTimestampMicros, micros), nil
+			}
+			return appendVariantInt64(b, variantTimestampNTZMicros, micros), nil
+		case lt.Time != nil:
+			micros := v.int64()
+			if lt.Time.Unit.Nanos != nil {
+				micros /= 1e3
+			}
+			return appendVariantInt64(b, variantTimeNTZMicros, micros), nil
+		case lt.Integer != nil && !lt.Integer.IsSigned && v.uint64() > math.MaxInt64:
+			return appendVariantDecimal(b, new(big.Int).SetUint64(v.uint64()), 0)
+		default:
+			return appendVariantInt(b, v.int64()), nil
+		}
+
+	case Float:
+		return appendVariantFloat(b, v.float()), nil
+
+	case Double:
+		return appendVariantDouble(b, v.double()), nil
+
+	case ByteArray, FixedLenByteArray:
+		switch {
+		case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil:
+			return appendVariantString(b, string(v.byteArray())), nil
+		case lt.UUID != nil:
+			return append(appendVariantPrimitive(b, variantUUID), v.byteArray()...), nil
+		case lt.Decimal != nil:
+			return appendVariantDecimal(b, decimalUnscaledValue(v), int(lt.Decimal.Scale))
+		default:
+			return appendVariantBinary(b, v.byteArray()), nil
+		}
+	}
+
+	return b, fmt.Errorf("cannot convert parquet value of type %s to variant", typ)
+}
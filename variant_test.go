@@ -0,0 +1,380 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go"
+)
+
+type variantRow struct {
+	ID      int64                 `parquet:"id"`
+	Payload *parquet.VariantValue `parquet:"payload"`
+}
+
+func TestVariantSchema(t *testing.T) {
+	const expected = `message variantRow {
+	required int64 id (INT(64,true));
+	optional group payload (VARIANT(1)) {
+		required binary metadata;
+		required binary value;
+	}
+}`
+
+	schema := parquet.SchemaOf(new(variantRow))
+	if s := schema.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+
+	parsed, err := parquet.ParseSchema(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := parsed.String(); s != expected {
+		t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", expected, s)
+	}
+}
+
+func TestVariantInvalidSchema(t *testing.T) {
+	_, err := parquet.ParseSchema(`message m {
+	required group v (VARIANT) {
+		required int32 metadata;
+		required binary value;
+	}
+}`)
+	if err == nil || !strings.Contains(err.Error(), "VARIANT") {
+		t.Errorf("expected an error for a VARIANT group without binary metadata but got %v", err)
+	}
+}
+
+func TestMakeVariantEncoding(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		metadata []byte
+		encoded  []byte
+	}{
+		{
+			value:    nil,
+			metadata: []byte{0x01, 0x00, 0x00},
+			encoded:  []byte{0x00},
+		},
+		{
+			value:    int64(1),
+			metadata: []byte{0x01, 0x00, 0x00},
+			encoded:  []byte{0x0C, 0x01},
+		},
+		{
+			value:    "hi",
+			metadata: []byte{0x01, 0x00, 0x00},
+			encoded:  []byte{0x09, 'h', 'i'},
+		},
+		{
+			value:    map[string]interface{}{"a": true},
+			metadata: []byte{0x11, 0x01, 0x00, 0x01, 'a'},
+			encoded:  []byte{0x02, 0x01, 0x00, 0x00, 0x01, 0x04},
+		},
+		{
+			value:    []interface{}{false, nil},
+			metadata: []byte{0x01, 0x00, 0x00},
+			encoded:  []byte{0x03, 0x02, 0x00, 0x01, 0x02, 0x08, 0x00},
+		},
+	}
+
+	for _, test := range tests {
+		v, err := parquet.MakeVariant(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(v.Metadata, test.metadata) {
+			t.Errorf("%v: metadata mismatch\nwant: %x\ngot:  %x", test.value, test.metadata, v.Metadata)
+		}
+		if !bytes.Equal(v.Value, test.encoded) {
+			t.Errorf("%v: value mismatch\nwant: %x\ngot:  %x", test.value, test.encoded, v.Value)
+		}
+	}
+}
+
+func TestMakeVariant(t *testing.T) {
+	id := uuid.MustParse("f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+	now := time.Date(2023, 6, 1, 12, 30, 15, 123456000, time.UTC)
+	long := strings.Repeat("x", 100)
+
+	tests := []struct {
+		value  interface{}
+		result interface{}
+	}{
+		{value: true, result: true},
+		{value: int8(-3), result: int64(-3)},
+		{value: 40000, result: int64(40000)},
+		{value: int64(1) << 40, result: int64(1) << 40},
+		{value: uint64(1 << 63), result: json.Number("9223372036854775808")},
+		{value: float32(1.5), result: float32(1.5)},
+		{value: 2.25, result: 2.25},
+		{value: long, result: long},
+		{value: []byte("raw"), result: []byte("raw")},
+		{value: now, result: now},
+		{value: now.Add(789), result: now.Add(789)},
+		{value: id, result: id},
+		{value: json.Number("42"), result: int64(42)},
+		{value: json.Number("-12.50"), result: json.Number("-12.50")},
+		{value: json.Number("1e3"), result: 1000.0},
+		{value: []string{"a", "b"}, result: []interface{}{"a", "b"}},
+		{
+			value: map[string]interface{}{
+				"name": "click",
+				"tags": []interface{}{"a", int64(1), nil},
+				"user": map[string]interface{}{"id": 7, "name": long},
+			},
+			result: map[string]interface{}{
+				"name": "click",
+				"tags": []interface{}{"a", int64(1), nil},
+				"user": map[string]interface{}{"id": int64(7), "name": long},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		v, err := parquet.MakeVariant(test.value)
+		if err != nil {
+			t.Fatalf("%v: %v", test.value, err)
+		}
+		result, err := v.Interface()
+		if err != nil {
+			t.Fatalf("%v: %v", test.value, err)
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("variant value mismatch\nwant: %#v\ngot:  %#v", test.result, result)
+		}
+	}
+
+	if _, err := parquet.MakeVariant(struct{}{}); err == nil {
+		t.Error("expected an error when making a variant from a struct")
+	}
+	if _, err := parquet.MakeVariant(map[int]string{}); err == nil {
+		t.Error("expected an error when making a variant from a map with non-string keys")
+	}
+}
+
+func TestVariantInvalid(t *testing.T) {
+	v, err := parquet.MakeVariant(map[string]interface{}{"a": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range v.Value {
+		truncated := parquet.VariantValue{Metadata: v.Metadata, Value: v.Value[:i]}
+		if _, err := truncated.Interface(); i > 0 && err == nil {
+			t.Errorf("expected an error decoding a variant value truncated to %d bytes", i)
+		}
+	}
+	if _, err := (parquet.VariantValue{Metadata: []byte{0x02, 0, 0}, Value: v.Value}).Interface(); err == nil {
+		t.Error("expected an error decoding a variant with an unsupported metadata version")
+	}
+}
+
+func makeVariant(t *testing.T, v interface{}) *parquet.VariantValue {
+	t.Helper()
+	variant, err := parquet.MakeVariant(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &variant
+}
+
+func TestVariantWriteRead(t *testing.T) {
+	rows := []variantRow{
+		{ID: 1, Payload: makeVariant(t, map[string]interface{}{"event": "click", "x": 10, "y": 20})},
+		{ID: 2, Payload: makeVariant(t, []interface{}{"a", 1.5})},
+		{ID: 3},
+		{ID: 4, Payload: makeVariant(t, nil)},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range rows {
+		row := variantRow{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(row, rows[i]) {
+			t.Errorf("row %d: value mismatch\nwant: %+v\ngot:  %+v", i, rows[i], row)
+		}
+	}
+	if err := reader.Read(new(variantRow)); err != io.EOF {
+		t.Errorf("expected io.EOF but got %v", err)
+	}
+}
+
+func TestVariantShreddedRead(t *testing.T) {
+	schema, err := parquet.ParseSchema(`message events {
+	required int64 id;
+	optional group payload (VARIANT(1)) {
+		required binary metadata;
+		optional binary value;
+		optional group typed_value {
+			required group name {
+				optional binary value;
+				optional binary typed_value (STRING);
+			}
+			required group tags {
+				optional binary value;
+				optional group typed_value (LIST) {
+					repeated group list {
+						required group element {
+							optional binary value;
+							optional binary typed_value (STRING);
+						}
+					}
+				}
+			}
+			required group score {
+				optional binary value;
+				optional int64 typed_value;
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extra := makeVariant(t, map[string]interface{}{"extra": 1.5})
+	text := makeVariant(t, "not an object")
+	high := makeVariant(t, "high")
+	null := parquet.Value{}
+
+	rows := []parquet.Row{
+		// Shredded object with a field which is not part of the shredding
+		// schema stored in the value column.
+		{
+			parquet.Int64Value(1).Level(0, 0, 0),
+			parquet.ByteArrayValue(extra.Metadata).Level(0, 1, 1),
+			parquet.ByteArrayValue(extra.Value).Level(0, 2, 2),
+			null.Level(0, 2, 3),
+			parquet.ByteArrayValue([]byte("click")).Level(0, 3, 4),
+			null.Level(0, 2, 5),
+			null.Level(0, 4, 6),
+			null.Level(1, 4, 6),
+			parquet.ByteArrayValue([]byte("a")).Level(0, 5, 7),
+			parquet.ByteArrayValue([]byte("b")).Level(1, 5, 7),
+			null.Level(0, 2, 8),
+			parquet.Int64Value(10).Level(0, 3, 9),
+		},
+		// Value which does not match the shredding schema.
+		{
+			parquet.Int64Value(2).Level(0, 0, 0),
+			parquet.ByteArrayValue(text.Metadata).Level(0, 1, 1),
+			parquet.ByteArrayValue(text.Value).Level(0, 2, 2),
+			null.Level(0, 1, 3),
+			null.Level(0, 1, 4),
+			null.Level(0, 1, 5),
+			null.Level(0, 1, 6),
+			null.Level(0, 1, 7),
+			null.Level(0, 1, 8),
+			null.Level(0, 1, 9),
+		},
+		// Null variant.
+		{
+			parquet.Int64Value(3).Level(0, 0, 0),
+			null.Level(0, 0, 1),
+			null.Level(0, 0, 2),
+			null.Level(0, 0, 3),
+			null.Level(0, 0, 4),
+			null.Level(0, 0, 5),
+			null.Level(0, 0, 6),
+			null.Level(0, 0, 7),
+			null.Level(0, 0, 8),
+			null.Level(0, 0, 9),
+		},
+		// Object with a missing field, an empty array, and a field which does
+		// not match the type of its typed_value column.
+		{
+			parquet.Int64Value(4).Level(0, 0, 0),
+			parquet.ByteArrayValue(high.Metadata).Level(0, 1, 1),
+			null.Level(0, 1, 2),
+			null.Level(0, 2, 3),
+			null.Level(0, 2, 4),
+			null.Level(0, 2, 5),
+			null.Level(0, 3, 6),
+			null.Level(0, 3, 7),
+			parquet.ByteArrayValue(high.Value).Level(0, 3, 8),
+			null.Level(0, 2, 9),
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if _, err := writer.WriteRows(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		id      int64
+		payload interface{}
+		null    bool
+	}{
+		{
+			id: 1,
+			payload: map[string]interface{}{
+				"name":  "click",
+				"tags":  []interface{}{"a", "b"},
+				"score": int64(10),
+				"extra": 1.5,
+			},
+		},
+		{id: 2, payload: "not an object"},
+		{id: 3, null: true},
+		{
+			id: 4,
+			payload: map[string]interface{}{
+				"tags":  []interface{}{},
+				"score": "high",
+			},
+		},
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for _, want := range expected {
+		row := variantRow{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != want.id {
+			t.Errorf("id mismatch: want=%d got=%d", want.id, row.ID)
+		}
+		if want.null {
+			if row.Payload != nil {
+				t.Errorf("row %d: expected a null payload but got %+v", want.id, row.Payload)
+			}
+			continue
+		}
+		if row.Payload == nil {
+			t.Fatalf("row %d: unexpected null payload", want.id)
+		}
+		payload, err := row.Payload.Interface()
+		if err != nil {
+			t.Fatalf("row %d: %v", want.id, err)
+		}
+		if !reflect.DeepEqual(payload, want.payload) {
+			t.Errorf("row %d: payload mismatch\nwant: %#v\ngot:  %#v", want.id, want.payload, payload)
+		}
+	}
+}
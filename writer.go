@@ -141,8 +141,12 @@ func (w *Writer) Write(row interface{}) error {
 		w.rowbuf = w.rowbuf[:1]
 	}
 	defer clearRows(w.rowbuf)
-	w.rowbuf[0] = w.schema.Deconstruct(w.rowbuf[0][:0], row)
-	_, err := w.WriteRows(w.rowbuf)
+	var err error
+	w.rowbuf[0], err = w.schema.deconstructRow(w.rowbuf[0][:0], row)
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteRows(w.rowbuf)
 	return err
 }
 
@@ -241,6 +245,16 @@ type writer struct {
 	numRows int64
 	maxRows int64
 
+	// When the file schema differs from the schema of the writer because of
+	// the compatibility profile, rows are converted to the file schema.
+	conv    Conversion
+	convbuf []Row
+
+	// Error returned by the methods writing to the file when the writer could
+	// not be configured, for example if the rows cannot be converted to the
+	// file schema of the compatibility profile.
+	err error
+
 	createdBy string
 	metadata  []format.KeyValue
 
@@ -271,14 +285,37 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	for k, v := range config.KeyValueMetadata {
 		w.metadata = append(w.metadata, format.KeyValue{Key: k, Value: v})
 	}
+	if len(config.ZstdDictionary) != 0 {
+		w.metadata = append(w.metadata, format.KeyValue{
+			Key:   zstdDictionaryKey,
+			Value: encodeZstdDictionary(config.ZstdDictionary),
+		})
+	}
 	sortKeyValueMetadata(w.metadata)
 	w.sortingColumns = make([]format.SortingColumn, len(config.Sorting.SortingColumns))
 
-	config.Schema.forEachNode(func(name string, node Node) {
+	// Bloom filters and sorting columns are configured with the column paths of
+	// the writer schema, which may differ from the paths of the file schema when
+	// a compatibility profile changes the layout of lists or maps.
+	columnPaths := make([]columnPath, 0, numLeafColumnsOf(config.Schema))
+	forEachLeafColumnOf(config.Schema, func(leaf leafColumn) {
+		columnPaths = append(columnPaths, leaf.path)
+	})
+
+	schema := legacySchemaOf(config.Schema, config.Compatibility)
+	if schema != config.Schema {
+		conv, err := Convert(schema, config.Schema)
+		if err != nil {
+			w.err = fmt.Errorf("cannot convert rows to the file schema of the compatibility profile: %w", err)
+		}
+		w.conv = conv
+	}
+
+	schema.forEachNode(func(name string, node Node) {
 		nodeType := node.Type()
 
 		repetitionType := (*format.FieldRepetitionType)(nil)
-		if node != config.Schema { // the root has no repetition type
+		if node != schema { // the root has no repetition type
 			repetitionType = fieldRepetitionTypePtrOf(node)
 		}
 
@@ -307,11 +344,16 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			Scale:          scale,
 			Precision:      precision,
 			LogicalType:    logicalType,
+			FieldID:        int32(fieldIDOf(node)),
 		})
 	})
 
+	// Older readers do not support data pages in version 2, and rely on the
+	// statistics of pages instead of column indexes.
+	compatible := config.Compatibility != 0
+
 	dataPageType := format.DataPage
-	if config.DataPageVersion == 2 {
+	if config.DataPageVersion == 2 && !compatible {
 		dataPageType = format.DataPageV2
 	}
 
@@ -319,6 +361,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	if defaultCompression == nil {
 		defaultCompression = &Uncompressed
 	}
+	zstdDictionary := &zstdDictionary{dict: config.ZstdDictionary}
 
 	// Those buffers are scratch space used to generate the page header and
 	// content, they are shared by all column chunks because they are only
@@ -326,8 +369,12 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	// not done concurrently.
 	buffers := new(writerBuffers)
 
-	forEachLeafColumnOf(config.Schema, func(leaf leafColumn) {
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
 		encoding := encodingOf(leaf.node)
+		if compatible && !isLegacyEncoding(encoding) {
+			encoding = &Plain
+		}
+		path := columnPaths[leaf.columnIndex]
 		dictionary := Dictionary(nil)
 		columnType := leaf.node.Type()
 		columnIndex := int(leaf.columnIndex)
@@ -336,6 +383,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		if compression == nil {
 			compression = defaultCompression
 		}
+		compression = zstdDictionary.codecOf(compression)
 
 		if isDictionaryEncoding(encoding) {
 			dictBuffer := columnType.NewValues(
@@ -352,7 +400,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			columnPath:         leaf.path,
 			columnType:         columnType,
 			columnIndex:        columnType.NewColumnIndexer(config.ColumnIndexSizeLimit),
-			columnFilter:       searchBloomFilterColumn(config.BloomFilters, leaf.path),
+			columnFilter:       searchBloomFilterColumn(config.BloomFilters, path),
 			compression:        compression,
 			dictionary:         dictionary,
 			dataPageType:       dataPageType,
@@ -360,7 +408,8 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			maxDefinitionLevel: leaf.maxDefinitionLevel,
 			bufferIndex:        int32(leaf.columnIndex),
 			bufferSize:         int32(float64(config.PageBufferSize) * 0.98),
-			writePageStats:     config.DataPageStatistics,
+			writePageStats:     config.DataPageStatistics || compatible,
+			writeMinMaxStats:   compatible && hasSignedSortOrder(columnType),
 			encodings:          make([]format.Encoding, 0, 3),
 			// Data pages in version 2 can omit compression when dictionary
 			// encoding is employed; only the dictionary page needs to be
@@ -368,10 +417,15 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			// RLE/Bit-Pack encoding which doesn't benefit from an extra
 			// compression layer.
 			isCompressed: isCompressed(compression) && (dataPageType != format.DataPageV2 || dictionary == nil),
+			minSavings:   config.MinCompressionSavings,
 		}
 
 		c.header.encoder.Reset(c.header.protocol.NewWriter(&buffers.header))
 
+		if isGeospatial(leaf.node.Type()) {
+			c.geospatial = newGeospatialBounds()
+		}
+
 		if leaf.maxDefinitionLevel > 0 {
 			c.encodings = addEncoding(c.encodings, format.RLE)
 		}
@@ -386,7 +440,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 
 		w.columns = append(w.columns, c)
 
-		if sortingIndex := searchSortingColumn(config.Sorting.SortingColumns, leaf.path); sortingIndex < len(w.sortingColumns) {
+		if sortingIndex := searchSortingColumn(config.Sorting.SortingColumns, path); sortingIndex < len(w.sortingColumns) {
 			w.sortingColumns[sortingIndex] = format.SortingColumn{
 				ColumnIdx:  int32(leaf.columnIndex),
 				Descending: config.Sorting.SortingColumns[sortingIndex].Descending(),
@@ -428,7 +482,11 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	}
 
 	for i, c := range w.columns {
-		w.columnOrders[i] = *c.columnType.ColumnOrder()
+		// Columns of types without a defined order keep the zero value, which
+		// readers interpret as an undefined order.
+		if columnOrder := c.columnType.ColumnOrder(); columnOrder != nil {
+			w.columnOrders[i] = *columnOrder
+		}
 	}
 
 	return w
@@ -459,6 +517,9 @@ func (w *writer) reset(writer io.Writer) {
 }
 
 func (w *writer) close() error {
+	if w.err != nil {
+		return w.err
+	}
 	if err := w.writeFileHeader(); err != nil {
 		return err
 	}
@@ -517,6 +578,12 @@ func (w *writer) writeFileFooter() error {
 	for i, columnIndexes := range w.columnIndexes {
 		rowGroup := &w.rowGroups[i]
 		for j := range columnIndexes {
+			if w.columns[j].columnType.ColumnOrder() == nil {
+				// Min and max values of types with an undefined order
+				// (e.g. INTERVAL) are meaningless, these columns have no
+				// column index.
+				continue
+			}
 			column := &rowGroup.Columns[j]
 			column.ColumnIndexOffset = w.writer.offset
 			if err := encoder.Encode(&columnIndexes[j]); err != nil {
@@ -566,6 +633,9 @@ func (w *writer) writeFileFooter() error {
 }
 
 func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []SortingColumn) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
 	numRows := w.columns[0].totalRowCount()
 	if numRows == 0 {
 		return 0, nil
@@ -610,6 +680,9 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 
 	for i, c := range w.columns {
 		w.columnIndex[i] = format.ColumnIndex(c.columnIndex.ColumnIndex())
+		if c.geospatial != nil {
+			c.columnChunk.MetaData.GeospatialStatistics = c.geospatial.statistics()
+		}
 
 		if c.dictionary != nil {
 			c.columnChunk.MetaData.DictionaryPageOffset = w.writer.offset
@@ -700,11 +773,20 @@ func (w *writer) WriteRows(rows []Row) (int, error) {
 			}
 		}()
 
+		batch := rows[start:end]
+		if w.conv != nil {
+			var err error
+			if batch, err = w.convertRows(batch); err != nil {
+				return 0, err
+			}
+			defer clearRows(batch)
+		}
+
 		// TODO: if an error occurs in this method the writer may be left in an
 		// partially functional state. Applications are not expected to continue
 		// using the writer after getting an error, but maybe we could ensure that
 		// we are preventing further use as well?
-		for _, row := range rows[start:end] {
+		for _, row := range batch {
 			row.Range(func(columnIndex int, columnValues []Value) bool {
 				w.values[columnIndex] = append(w.values[columnIndex], columnValues...)
 				return true
@@ -723,7 +805,24 @@ func (w *writer) WriteRows(rows []Row) (int, error) {
 	})
 }
 
+// convertRows converts rows to the schema of the file. The rows are copied to
+// avoid mutating the values of the application.
+func (w *writer) convertRows(rows []Row) ([]Row, error) {
+	if cap(w.convbuf) < len(rows) {
+		w.convbuf = append(w.convbuf[:cap(w.convbuf)], make([]Row, len(rows)-cap(w.convbuf))...)
+	}
+	convbuf := w.convbuf[:len(rows)]
+	for i, row := range rows {
+		convbuf[i] = append(convbuf[i][:0], row...)
+	}
+	_, err := w.conv.Convert(convbuf)
+	return convbuf, err
+}
+
 func (w *writer) writeRows(numRows int, write func(i, j int) (int, error)) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
 	written := 0
 
 	for written < numRows {
@@ -769,6 +868,9 @@ func (w *writer) writeRows(numRows int, write func(i, j int) (int, error)) (int,
 // The WriteValues method is intended to work in pair with WritePage to allow
 // programs to target writing values to specific columns of of the writer.
 func (w *writer) WriteValues(values []Value) (numValues int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
 	return w.columns[values[0].Column()].WriteValues(values)
 }
 
@@ -863,6 +965,12 @@ func (wb *writerBuffers) compress(codec compress.Codec) (err error) {
 	return err
 }
 
+// uncompress reverts the last call to compress, size is the length of the page
+// buffer before it was compressed.
+func (wb *writerBuffers) uncompress(size int) {
+	wb.page, wb.scratch = wb.scratch[:size], wb.page[:0]
+}
+
 func (wb *writerBuffers) swapPageAndScratchBuffers() {
 	wb.page, wb.scratch = wb.scratch, wb.page[:0]
 }
@@ -891,16 +999,19 @@ type writerColumn struct {
 		encoder  thrift.Encoder
 	}
 
-	filter         []byte
-	numRows        int64
-	bufferIndex    int32
-	bufferSize     int32
-	writePageStats bool
-	isCompressed   bool
-	encodings      []format.Encoding
+	filter           []byte
+	numRows          int64
+	bufferIndex      int32
+	bufferSize       int32
+	writePageStats   bool
+	writeMinMaxStats bool
+	isCompressed     bool
+	minSavings       int
+	encodings        []format.Encoding
 
 	columnChunk *format.ColumnChunk
 	offsetIndex *format.OffsetIndex
+	geospatial  *geospatialBounds
 }
 
 func (c *writerColumn) reset() {
@@ -934,7 +1045,11 @@ func (c *writerColumn) reset() {
 	c.columnChunk.MetaData.Statistics = format.Statistics{}
 	c.columnChunk.MetaData.EncodingStats = c.columnChunk.MetaData.EncodingStats[:0]
 	c.columnChunk.MetaData.BloomFilterOffset = 0
+	c.columnChunk.MetaData.GeospatialStatistics = nil
 	c.offsetIndex.PageLocations = c.offsetIndex.PageLocations[:0]
+	if c.geospatial != nil {
+		c.geospatial.reset()
+	}
 }
 
 func (c *writerColumn) totalRowCount() int64 {
@@ -1124,14 +1239,22 @@ func (c *writerColumn) writeDataPage(page Page) (int64, error) {
 		return 0, fmt.Errorf("encoding parquet data page: %w", err)
 	}
 	if c.dataPageType == format.DataPage {
-		buf.prependLevelsToDataPageV1(c.maxDefinitionLevel, c.maxDefinitionLevel)
+		buf.prependLevelsToDataPageV1(c.maxRepetitionLevel, c.maxDefinitionLevel)
 	}
 
 	uncompressedPageSize := buf.size()
-	if c.isCompressed {
+	isCompressed := c.isCompressed
+	if isCompressed {
+		size := len(buf.page)
 		if err := buf.compress(c.compression); err != nil {
 			return 0, fmt.Errorf("compressing parquet data page: %w", err)
 		}
+		// Data pages in version 2 can be stored uncompressed when compression
+		// does not save enough space, readers then skip decompressing them.
+		if c.dataPageType == format.DataPageV2 && c.minSavings > 0 && 100*(size-len(buf.page)) < c.minSavings*size {
+			buf.uncompress(size)
+			isCompressed = false
+		}
 	}
 
 	if page.Dictionary() == nil && len(c.filter) > 0 {
@@ -1176,7 +1299,7 @@ func (c *writerColumn) writeDataPage(page Page) (int64, error) {
 			Encoding:                   c.encoding.Encoding(),
 			DefinitionLevelsByteLength: int32(len(buf.definitions)),
 			RepetitionLevelsByteLength: int32(len(buf.repetitions)),
-			IsCompressed:               &c.isCompressed,
+			IsCompressed:               &isCompressed,
 			Statistics:                 statistics,
 		}
 	}
@@ -1313,6 +1436,9 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 		c.columnIndex.IndexPage(numValues, numNulls, minValue, maxValue)
 		c.columnChunk.MetaData.NumValues += numValues
 		c.columnChunk.MetaData.Statistics.NullCount += numNulls
+		if c.geospatial != nil {
+			c.geospatial.updatePage(page)
+		}
 
 		if pageHasBounds {
 			var existingMaxValue, existingMinValue Value
@@ -1329,6 +1455,11 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 			if existingMinValue.isNull() || c.columnType.Compare(minValue, existingMinValue) < 0 {
 				c.columnChunk.MetaData.Statistics.MinValue = minValue.Bytes()
 			}
+
+			if c.writeMinMaxStats {
+				c.columnChunk.MetaData.Statistics.Min = c.columnChunk.MetaData.Statistics.MinValue // deprecated
+				c.columnChunk.MetaData.Statistics.Max = c.columnChunk.MetaData.Statistics.MaxValue // deprecated
+			}
 		}
 
 		c.offsetIndex.PageLocations = append(c.offsetIndex.PageLocations, format.PageLocation{
@@ -89,7 +89,7 @@ func NewGenericWriter[T any](output io.Writer, options ...WriterOption) *Generic
 		panic("generic writer must be instantiated with schema or concrete type.")
 	}
 
-	return &GenericWriter[T]{
+	w := &GenericWriter[T]{
 		base: Writer{
 			output: output,
 			config: config,
@@ -98,6 +98,14 @@ func NewGenericWriter[T any](output io.Writer, options ...WriterOption) *Generic
 		},
 		write: writeFuncOf[T](t, config.Schema),
 	}
+
+	// The optimized write functions produce values directly in the column
+	// buffers, which are of the file schema when a compatibility profile
+	// requires converting the rows.
+	if w.base.writer.conv != nil && t != nil {
+		w.write = (*GenericWriter[T]).writeRows
+	}
+	return w
 }
 
 type writeFunc[T any] func(*GenericWriter[T], []T) (int, error)
@@ -114,6 +122,9 @@ func writeFuncOf[T any](t reflect.Type, schema *Schema) writeFunc[T] {
 		return makeWriteFunc[T](t, schema)
 
 	case reflect.Pointer:
+		if isProtoMessage(t) {
+			return (*GenericWriter[T]).writeRows
+		}
 		if e := t.Elem(); e.Kind() == reflect.Struct {
 			return makeWriteFunc[T](t, schema)
 		}
@@ -211,7 +222,11 @@ func (w *GenericWriter[T]) writeRows(rows []T) (int, error) {
 
 	schema := w.base.Schema()
 	for i := range rows {
-		w.base.rowbuf[i] = schema.Deconstruct(w.base.rowbuf[i], &rows[i])
+		var err error
+		w.base.rowbuf[i], err = schema.deconstructRow(w.base.rowbuf[i], &rows[i])
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	return w.base.WriteRows(w.base.rowbuf)
@@ -3,13 +3,21 @@
 package parquet_test
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/segmentio/encoding/thrift"
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/compress/zstd"
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/format"
 )
 
 func BenchmarkGenericWriter(b *testing.B) {
@@ -393,3 +401,263 @@ func TestGenericSetKeyValueMetadata(t *testing.T) {
 		t.Errorf("expected %q, got %q", testValue, value)
 	}
 }
+
+// writeGenericFile writes rows to a parquet file with a GenericWriter configured
+// with the given options, and opens the file that was produced.
+func writeGenericFile[Row any](t *testing.T, rows []Row, options ...parquet.WriterOption) *parquet.File {
+	t.Helper()
+	output := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[Row](output, options...)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// readGenericFile reads all the rows of f with a GenericReader.
+func readGenericFile[Row any](t *testing.T, f *parquet.File) []Row {
+	t.Helper()
+	rows := make([]Row, f.NumRows())
+	if n, err := parquet.NewGenericReader[Row](f).Read(rows); n != len(rows) {
+		t.Fatalf("reading rows: %d/%d: %v", n, len(rows), err)
+	}
+	return rows
+}
+
+func TestGenericWriterCompatibilityProfile(t *testing.T) {
+	type Row struct {
+		Time   time.Time        `parquet:"time,timestamp(millisecond)"`
+		Values []int64          `parquet:"values,list"`
+		Tags   map[string]int64 `parquet:"tags"`
+	}
+
+	rows := []Row{
+		{Time: time.UnixMilli(1_000_000_000_123).UTC(), Values: []int64{1, 2}, Tags: map[string]int64{"A": 1}},
+		{Time: time.UnixMilli(-1).UTC(), Values: []int64{3}, Tags: map[string]int64{"B": 2, "C": 3}},
+	}
+
+	tests := []struct {
+		profile parquet.Compatibility
+		columns [][]string
+	}{
+		{
+			profile: parquet.Spark2,
+			columns: [][]string{{"time"}, {"values", "array"}, {"tags", "map", "key"}, {"tags", "map", "value"}},
+		},
+		{
+			profile: parquet.Hive,
+			columns: [][]string{{"time"}, {"values", "bag", "array_element"}, {"tags", "map", "key"}, {"tags", "map", "value"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(int(test.profile)), func(t *testing.T) {
+			f := writeGenericFile(t, rows, parquet.CompatibilityProfile(test.profile))
+			if columns := f.Schema().Columns(); !reflect.DeepEqual(columns, test.columns) {
+				t.Errorf("unexpected columns: %q", columns)
+			}
+			numMapKeyValue := 0
+			for _, element := range f.Metadata().Schema {
+				if element.ConvertedType != nil && *element.ConvertedType == deprecated.MapKeyValue {
+					numMapKeyValue++
+				}
+			}
+			if numMapKeyValue != 1 {
+				t.Errorf("wrong number of groups annotated with MAP_KEY_VALUE: %d", numMapKeyValue)
+			}
+
+			values := readGenericFile[Row](t, f)
+			for i := range rows {
+				if !values[i].Time.Equal(rows[i].Time) {
+					t.Errorf("time mismatch at row %d: want=%s got=%s", i, rows[i].Time, values[i].Time)
+				}
+				if !reflect.DeepEqual(values[i].Values, rows[i].Values) {
+					t.Errorf("values mismatch at row %d: want=%v got=%v", i, rows[i].Values, values[i].Values)
+				}
+				if !reflect.DeepEqual(values[i].Tags, rows[i].Tags) {
+					t.Errorf("tags mismatch at row %d: want=%v got=%v", i, rows[i].Tags, values[i].Tags)
+				}
+			}
+		})
+	}
+}
+
+func TestGenericWriterByteStreamSplit(t *testing.T) {
+	type Row struct {
+		Int32 int32    `parquet:"int32,split"`
+		Int64 int64    `parquet:"int64,split"`
+		Bytes [12]byte `parquet:"bytes,split"`
+	}
+
+	rows := make([]Row, 100)
+	for i := range rows {
+		rows[i].Int32 = int32(i) * 1000
+		rows[i].Int64 = -int64(i) << 40
+		rows[i].Bytes = [12]byte{0: byte(i), 11: byte(i >> 1)}
+	}
+
+	f := writeGenericFile(t, rows)
+	for _, columnChunk := range f.Metadata().RowGroups[0].Columns {
+		if encoding := columnChunk.MetaData.Encoding; !reflect.DeepEqual(encoding, []format.Encoding{format.ByteStreamSplit}) {
+			t.Errorf("column %q has encodings %v", columnChunk.MetaData.PathInSchema, encoding)
+		}
+	}
+
+	if values := readGenericFile[Row](t, f); !reflect.DeepEqual(values, rows) {
+		t.Error("rows read from the file do not match the rows written")
+	}
+}
+
+func TestGenericWriterLegacyCompressionCodecs(t *testing.T) {
+	type Row struct {
+		Name  string `parquet:"name"`
+		Value int64  `parquet:"value"`
+	}
+
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i] = Row{Name: "name", Value: int64(i % 10)}
+	}
+
+	for _, codec := range []compress.Codec{&parquet.Lz4, &parquet.Lzo} {
+		t.Run(codec.String(), func(t *testing.T) {
+			f := writeGenericFile(t, rows, parquet.Compression(codec))
+			for _, columnChunk := range f.Metadata().RowGroups[0].Columns {
+				if columnChunk.MetaData.Codec != codec.CompressionCodec() {
+					t.Errorf("column %q compressed with %s", columnChunk.MetaData.PathInSchema, columnChunk.MetaData.Codec)
+				}
+			}
+
+			if values := readGenericFile[Row](t, f); !reflect.DeepEqual(values, rows) {
+				t.Error("rows read from the file do not match the rows written")
+			}
+		})
+	}
+}
+
+func TestGenericWriterZstdDictionary(t *testing.T) {
+	type Row struct {
+		Event string `parquet:"event,zstd"`
+		Path  string `parquet:"path,zstd(19)"`
+	}
+
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, 5000)
+	for i := range rows {
+		rows[i] = Row{
+			Event: fmt.Sprintf(`{"user_id":%d,"event":"page_view","properties":{"referrer":"https://www.example.com/"}}`, prng.Int63()),
+			Path:  fmt.Sprintf("/products/%d/reviews?page=%d", prng.Intn(1000), prng.Intn(10)),
+		}
+	}
+
+	leaf, _ := parquet.SchemaOf(Row{}).Lookup("path")
+	if codec, ok := leaf.Node.Compression().(*zstd.Codec); !ok || codec.Level != zstd.SpeedBestCompression {
+		t.Fatalf("wrong compression codec for zstd(19): %#v", leaf.Node.Compression())
+	}
+
+	withoutDict := writeGenericFile(t, rows, parquet.PageBufferSize(2048))
+	pages := withoutDict.RowGroups()[0].ColumnChunks()[0].Pages()
+	dict, err := parquet.TrainZstdDictionary(pages, 4096)
+	pages.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := writeGenericFile(t, rows, parquet.PageBufferSize(2048), parquet.ZstdDictionary(dict))
+	if f.Size() >= withoutDict.Size() {
+		t.Errorf("file size did not improve with the dictionary: %d >= %d", f.Size(), withoutDict.Size())
+	}
+	if _, ok := f.Lookup("parquet-go.zstd.dictionary"); !ok {
+		t.Error("zstd dictionary not found in the file metadata")
+	}
+
+	if values := readGenericFile[Row](t, f); !reflect.DeepEqual(values, rows) {
+		t.Error("rows read from the file do not match the rows written")
+	}
+}
+
+func TestGenericWriterDeltaMonotonic(t *testing.T) {
+	type Row struct {
+		ID   int64 `parquet:"id,delta(monotonic)"`
+		Time int32 `parquet:"time,delta(monotonic)"`
+	}
+
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, 5000)
+	for i := range rows {
+		rows[i] = Row{
+			ID:   int64(i + 1),
+			Time: int32(1e9 + i*1000 + prng.Intn(100)),
+		}
+	}
+
+	leaf, _ := parquet.SchemaOf(Row{}).Lookup("time")
+	if leaf.Node.Encoding() != &parquet.DeltaBinaryPackedMonotonic {
+		t.Fatalf("wrong encoding for delta(monotonic): %#v", leaf.Node.Encoding())
+	}
+
+	f := writeGenericFile(t, rows)
+	if values := readGenericFile[Row](t, f); !reflect.DeepEqual(values, rows) {
+		t.Error("rows read from the file do not match the rows written")
+	}
+}
+
+func TestGenericWriterMinCompressionSavings(t *testing.T) {
+	type Row struct {
+		Blob []byte `parquet:"blob"`
+		Text string `parquet:"text"`
+	}
+
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i].Blob = make([]byte, 100)
+		prng.Read(rows[i].Blob)
+		rows[i].Text = fmt.Sprintf("row number %d of the text column", i)
+	}
+
+	f := writeGenericFile(t, rows,
+		parquet.Compression(&parquet.Zstd),
+		parquet.MinCompressionSavings(10),
+		parquet.PageBufferSize(4096),
+	)
+
+	for i, want := range []bool{false, true} {
+		metadata := f.Metadata().RowGroups[0].Columns[i].MetaData
+		chunk := io.NewSectionReader(f, metadata.DataPageOffset, metadata.TotalCompressedSize)
+		reader := bufio.NewReader(chunk)
+		decoder := thrift.NewDecoder(new(thrift.CompactProtocol).NewReader(reader))
+
+		numPages := 0
+		for {
+			header := format.PageHeader{}
+			if err := decoder.Decode(&header); err != nil {
+				if err == io.EOF {
+					break
+				}
+				t.Fatal(err)
+			}
+			if got := *header.DataPageHeaderV2.IsCompressed; got != want {
+				t.Errorf("page %d of column %q: is_compressed=%t", numPages, metadata.PathInSchema, got)
+			}
+			if _, err := reader.Discard(int(header.CompressedPageSize)); err != nil {
+				t.Fatal(err)
+			}
+			numPages++
+		}
+		if numPages < 2 {
+			t.Errorf("column %q has only %d pages", metadata.PathInSchema, numPages)
+		}
+	}
+
+	if values := readGenericFile[Row](t, f); !reflect.DeepEqual(values, rows) {
+		t.Error("rows read from the file do not match the rows written")
+	}
+}
@@ -708,6 +708,46 @@ func TestWriterMaxRowsPerRowGroup(t *testing.T) {
 	}
 }
 
+func TestWriterDataPageV1OptionalColumn(t *testing.T) {
+	type Row struct {
+		Value *int32 `parquet:"value,optional"`
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, parquet.DataPageVersion(v1))
+
+	for i := 0; i < 100; i++ {
+		row := Row{}
+		if i%3 != 0 {
+			value := int32(i)
+			row.Value = &value
+		}
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(output.Bytes()))
+	defer reader.Close()
+
+	for i := 0; i < 100; i++ {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		switch {
+		case i%3 == 0 && row.Value != nil:
+			t.Errorf("row %d: want null value, got %d", i, *row.Value)
+		case i%3 != 0 && (row.Value == nil || *row.Value != int32(i)):
+			t.Errorf("row %d: want %d, got %v", i, i, row.Value)
+		}
+	}
+}
+
 func TestSetKeyValueMetadata(t *testing.T) {
 	testKey := "test-key"
 	testValue := "test-value"